@@ -73,7 +73,7 @@ type NewsItem struct {
 // Ticket represents a support ticket
 type Ticket struct {
 	ID          uint   `json:"id" example:"1"`
-	UserID      uint   `json:"user_id" example:"1"`
+	UserID      string `json:"user_id" example:"3f1c2a9e-8b7d-4e6f-9a1b-2c3d4e5f6a7b"`
 	Subject     string `json:"subject" example:"Technical Issue"`
 	Description string `json:"description" example:"Detailed description of the issue..."`
 	Status      string `json:"status" example:"open"`
@@ -85,7 +85,7 @@ type Ticket struct {
 type TicketResponse struct {
 	ID        uint   `json:"id" example:"1"`
 	TicketID  uint   `json:"ticket_id" example:"1"`
-	AdminID   uint   `json:"admin_id" example:"1"`
+	AdminID   string `json:"admin_id" example:"3f1c2a9e-8b7d-4e6f-9a1b-2c3d4e5f6a7b"`
 	Content   string `json:"content" example:"Response to your ticket..."`
 	CreatedAt string `json:"created_at" example:"2024-01-01T00:00:00Z"`
 }
@@ -2,6 +2,8 @@ package services
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -168,7 +170,10 @@ func SetupServices(router *gin.Engine, cfg *config.Config) {
 		authTickets.Use(middleware.AuthMiddleware(cfg))
 		{
 			authTickets.GET("/user", ticketProxy)
+			authTickets.GET("/user/claimable", ticketProxy)
+			authTickets.POST("/user/claim", ticketProxy)
 			authTickets.GET("/user/:id/history", ticketProxy)
+			authTickets.POST("/user/:id/reopen", ticketProxy)
 		}
 
 		// Admin routes
@@ -177,10 +182,24 @@ func SetupServices(router *gin.Engine, cfg *config.Config) {
 		{
 			adminTickets.GET("", ticketProxy)
 			adminTickets.PUT("/:id/status", ticketProxy)
+			adminTickets.PUT("/:id/assign", ticketProxy)
 			adminTickets.GET("/search", ticketProxy)
+			adminTickets.POST("/spam/release", ticketProxy)
+			adminTickets.POST("/spam/purge", ticketProxy)
+			adminTickets.GET("/:id/attachment/preview", ticketProxy)
+			adminTickets.GET("/:id/transcript", ticketProxy)
 		}
 	}
 
+	// Ticket Category Routes (intake field schemas are public, changes are admin-only)
+	categoryGroup := router.Group("/api/v1/categories")
+	{
+		categoryProxy := createProxy(cfg.TicketService)
+		categoryGroup.GET("", categoryProxy)
+		categoryGroup.GET("/:category/fields", categoryProxy)
+		categoryGroup.PUT("/:category/fields", middleware.AuthMiddleware(cfg), middleware.AdminOnly(), categoryProxy)
+	}
+
 	// Ticket Responses Routes
 	responseGroup := router.Group("/api/v1/responses")
 	responseGroup.Use(middleware.AuthMiddleware(cfg), middleware.AdminOnly())
@@ -188,6 +207,88 @@ func SetupServices(router *gin.Engine, cfg *config.Config) {
 		responseProxy := createProxy(cfg.TicketService)
 		responseGroup.POST("/ticket/:id", responseProxy)
 		responseGroup.GET("/ticket/:id", responseProxy)
+		responseGroup.PUT("/:id", responseProxy)
+		responseGroup.DELETE("/:id", responseProxy)
+		responseGroup.GET("/:id/revisions", responseProxy)
+		responseGroup.GET("/:id/attachment/preview", responseProxy)
+	}
+
+	// Ticket Event Stream (Server-Sent Events, long-lived connection)
+	eventGroup := router.Group("/api/v1/events")
+	eventGroup.Use(middleware.AuthMiddleware(cfg))
+	{
+		eventGroup.GET("/stream", createStreamProxy(cfg.TicketService))
+	}
+
+	// Resumable Uploads (tus); chunks are streamed to the ticket service without buffering
+	uploadGroup := router.Group("/api/v1/uploads")
+	{
+		uploadProxy := createStreamProxy(cfg.TicketService)
+		uploadGroup.OPTIONS("", uploadProxy)
+
+		authUploads := uploadGroup.Group("")
+		authUploads.Use(middleware.AuthMiddleware(cfg))
+		{
+			authUploads.POST("", uploadProxy)
+			authUploads.HEAD("/:id", uploadProxy)
+			authUploads.PATCH("/:id", uploadProxy)
+			authUploads.DELETE("/:id", uploadProxy)
+		}
+	}
+
+	// Ticket Survey Routes (public, authorized by signed link)
+	surveyGroup := router.Group("/api/v1/surveys")
+	{
+		surveyProxy := createProxy(cfg.TicketService)
+		surveyGroup.GET("/:token", surveyProxy)
+		surveyGroup.POST("/:token", surveyProxy)
+	}
+
+	// Ticket Attachments (public, authorized by signed link; local storage backend only)
+	fileGroup := router.Group("/api/v1/files")
+	{
+		fileGroup.GET("/*key", createProxy(cfg.TicketService))
+	}
+
+	// Ticket Analytics Routes
+	analyticsGroup := router.Group("/api/v1/analytics")
+	analyticsGroup.Use(middleware.AuthMiddleware(cfg), middleware.AdminOnly())
+	{
+		analyticsProxy := createProxy(cfg.TicketService)
+		analyticsGroup.GET("/csat", analyticsProxy)
+		analyticsGroup.GET("/csat/admins", analyticsProxy)
+		analyticsGroup.GET("/reopens", analyticsProxy)
+	}
+
+	// Personal Data Requests (export/erase by applicant email)
+	privacyGroup := router.Group("/api/v1/privacy")
+	privacyGroup.Use(middleware.AuthMiddleware(cfg), middleware.AdminOnly())
+	{
+		privacyProxy := createProxy(cfg.TicketService)
+		privacyGroup.GET("/export", privacyProxy)
+		privacyGroup.POST("/erase", privacyProxy)
+	}
+
+	// Webhook Subscriptions (root admin only)
+	webhookGroup := router.Group("/api/v1/webhooks")
+	webhookGroup.Use(middleware.AuthMiddleware(cfg), middleware.RootAdminOnly())
+	{
+		webhookProxy := createProxy(cfg.TicketService)
+		webhookGroup.GET("", webhookProxy)
+		webhookGroup.POST("", webhookProxy)
+		webhookGroup.GET("/:id", webhookProxy)
+		webhookGroup.PUT("/:id", webhookProxy)
+		webhookGroup.DELETE("/:id", webhookProxy)
+		webhookGroup.GET("/:id/deliveries", webhookProxy)
+		webhookGroup.GET("/deliveries/:id", webhookProxy)
+		webhookGroup.POST("/deliveries/:id/replay", webhookProxy)
+	}
+
+	// Ticket Audit Log Routes
+	auditGroup := router.Group("/api/v1/audit")
+	auditGroup.Use(middleware.AuthMiddleware(cfg), middleware.AdminOnly())
+	{
+		auditGroup.GET("", createProxy(cfg.TicketService))
 	}
 }
 
@@ -251,3 +352,28 @@ func createProxy(service config.ServiceConfig) gin.HandlerFunc {
 		}
 	}
 }
+
+// createStreamProxy проксирует долгоживущие потоковые ответы (SSE):
+// тело запроса не буферизуется, а каждая порция ответа сразу отправляется клиенту
+func createStreamProxy(service config.ServiceConfig) gin.HandlerFunc {
+	targetURL, err := url.Parse(fmt.Sprintf("http://%s:%s", service.Host, service.Port))
+	if err != nil {
+		panic(fmt.Sprintf("invalid stream proxy target: %v", err))
+	}
+
+	proxy := httputil.NewSingleHostReverseProxy(targetURL)
+	proxy.FlushInterval = -1
+	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		// Клиент закрыл соединение - это штатное завершение потока
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(`{"error":"service unavailable"}`))
+	}
+
+	return func(c *gin.Context) {
+		proxy.ServeHTTP(c.Writer, c.Request)
+	}
+}
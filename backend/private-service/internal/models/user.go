@@ -31,5 +31,8 @@ type User struct {
 type CustomClaims struct {
 	UserID string `json:"user_id"`
 	Role   string `json:"role"`
+	// Email передается другим сервисам; EmailVerified означает, что адрес подтвержден при активации аккаунта
+	Email         string `json:"email,omitempty"`
+	EmailVerified bool   `json:"email_verified"`
 	jwt.RegisteredClaims
 }
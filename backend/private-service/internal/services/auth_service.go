@@ -165,8 +165,10 @@ func (s *authService) Login(email, password string) (*TokenPair, error) {
 
 func (s *authService) generateJWTToken(user *models.User, expiry time.Duration) (string, error) {
 	claims := &models.CustomClaims{
-		UserID: user.ID.String(),
-		Role:   string(user.Role),
+		UserID:        user.ID.String(),
+		Role:          string(user.Role),
+		Email:         user.Email,
+		EmailVerified: user.IsActive,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -0,0 +1,1139 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: ticket/v1/ticket.proto
+
+package ticketv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type TicketStatus int32
+
+const (
+	TicketStatus_TICKET_STATUS_UNSPECIFIED TicketStatus = 0
+	TicketStatus_TICKET_STATUS_NEW         TicketStatus = 1
+	TicketStatus_TICKET_STATUS_IN_PROGRESS TicketStatus = 2
+	TicketStatus_TICKET_STATUS_WAITING     TicketStatus = 3
+	TicketStatus_TICKET_STATUS_CLOSED      TicketStatus = 4
+	TicketStatus_TICKET_STATUS_SPAM        TicketStatus = 5
+)
+
+// Enum value maps for TicketStatus.
+var (
+	TicketStatus_name = map[int32]string{
+		0: "TICKET_STATUS_UNSPECIFIED",
+		1: "TICKET_STATUS_NEW",
+		2: "TICKET_STATUS_IN_PROGRESS",
+		3: "TICKET_STATUS_WAITING",
+		4: "TICKET_STATUS_CLOSED",
+		5: "TICKET_STATUS_SPAM",
+	}
+	TicketStatus_value = map[string]int32{
+		"TICKET_STATUS_UNSPECIFIED": 0,
+		"TICKET_STATUS_NEW":         1,
+		"TICKET_STATUS_IN_PROGRESS": 2,
+		"TICKET_STATUS_WAITING":     3,
+		"TICKET_STATUS_CLOSED":      4,
+		"TICKET_STATUS_SPAM":        5,
+	}
+)
+
+func (x TicketStatus) Enum() *TicketStatus {
+	p := new(TicketStatus)
+	*p = x
+	return p
+}
+
+func (x TicketStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TicketStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_ticket_v1_ticket_proto_enumTypes[0].Descriptor()
+}
+
+func (TicketStatus) Type() protoreflect.EnumType {
+	return &file_ticket_v1_ticket_proto_enumTypes[0]
+}
+
+func (x TicketStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TicketStatus.Descriptor instead.
+func (TicketStatus) EnumDescriptor() ([]byte, []int) {
+	return file_ticket_v1_ticket_proto_rawDescGZIP(), []int{0}
+}
+
+type TicketCategory int32
+
+const (
+	TicketCategory_TICKET_CATEGORY_UNSPECIFIED   TicketCategory = 0
+	TicketCategory_TICKET_CATEGORY_GENERAL       TicketCategory = 1
+	TicketCategory_TICKET_CATEGORY_RECOGNITION   TicketCategory = 2
+	TicketCategory_TICKET_CATEGORY_LEGALISATION  TicketCategory = 3
+	TicketCategory_TICKET_CATEGORY_ACCREDITATION TicketCategory = 4
+	TicketCategory_TICKET_CATEGORY_BOLOGNA       TicketCategory = 5
+)
+
+// Enum value maps for TicketCategory.
+var (
+	TicketCategory_name = map[int32]string{
+		0: "TICKET_CATEGORY_UNSPECIFIED",
+		1: "TICKET_CATEGORY_GENERAL",
+		2: "TICKET_CATEGORY_RECOGNITION",
+		3: "TICKET_CATEGORY_LEGALISATION",
+		4: "TICKET_CATEGORY_ACCREDITATION",
+		5: "TICKET_CATEGORY_BOLOGNA",
+	}
+	TicketCategory_value = map[string]int32{
+		"TICKET_CATEGORY_UNSPECIFIED":   0,
+		"TICKET_CATEGORY_GENERAL":       1,
+		"TICKET_CATEGORY_RECOGNITION":   2,
+		"TICKET_CATEGORY_LEGALISATION":  3,
+		"TICKET_CATEGORY_ACCREDITATION": 4,
+		"TICKET_CATEGORY_BOLOGNA":       5,
+	}
+)
+
+func (x TicketCategory) Enum() *TicketCategory {
+	p := new(TicketCategory)
+	*p = x
+	return p
+}
+
+func (x TicketCategory) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TicketCategory) Descriptor() protoreflect.EnumDescriptor {
+	return file_ticket_v1_ticket_proto_enumTypes[1].Descriptor()
+}
+
+func (TicketCategory) Type() protoreflect.EnumType {
+	return &file_ticket_v1_ticket_proto_enumTypes[1]
+}
+
+func (x TicketCategory) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TicketCategory.Descriptor instead.
+func (TicketCategory) EnumDescriptor() ([]byte, []int) {
+	return file_ticket_v1_ticket_proto_rawDescGZIP(), []int{1}
+}
+
+type Ticket struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// user_id UUID пользователя; у гостевых тикетов пустой
+	UserId       string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Category     TicketCategory         `protobuf:"varint,3,opt,name=category,proto3,enum=ticket.v1.TicketCategory" json:"category,omitempty"`
+	Subject      string                 `protobuf:"bytes,4,opt,name=subject,proto3" json:"subject,omitempty"`
+	Question     string                 `protobuf:"bytes,5,opt,name=question,proto3" json:"question,omitempty"`
+	FullName     string                 `protobuf:"bytes,6,opt,name=full_name,json=fullName,proto3" json:"full_name,omitempty"`
+	Email        string                 `protobuf:"bytes,7,opt,name=email,proto3" json:"email,omitempty"`
+	Phone        *string                `protobuf:"bytes,8,opt,name=phone,proto3,oneof" json:"phone,omitempty"`
+	TelegramId   *string                `protobuf:"bytes,9,opt,name=telegram_id,json=telegramId,proto3,oneof" json:"telegram_id,omitempty"`
+	FileUrl      *string                `protobuf:"bytes,10,opt,name=file_url,json=fileUrl,proto3,oneof" json:"file_url,omitempty"`
+	FileName     *string                `protobuf:"bytes,11,opt,name=file_name,json=fileName,proto3,oneof" json:"file_name,omitempty"`
+	FileChecked  bool                   `protobuf:"varint,12,opt,name=file_checked,json=fileChecked,proto3" json:"file_checked,omitempty"`
+	Status       TicketStatus           `protobuf:"varint,13,opt,name=status,proto3,enum=ticket.v1.TicketStatus" json:"status,omitempty"`
+	NotifyEmail  bool                   `protobuf:"varint,14,opt,name=notify_email,json=notifyEmail,proto3" json:"notify_email,omitempty"`
+	NotifyTg     bool                   `protobuf:"varint,15,opt,name=notify_tg,json=notifyTg,proto3" json:"notify_tg,omitempty"`
+	AssignedTo   *string                `protobuf:"bytes,16,opt,name=assigned_to,json=assignedTo,proto3,oneof" json:"assigned_to,omitempty"`
+	WaitingSince *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=waiting_since,json=waitingSince,proto3" json:"waiting_since,omitempty"`
+	ClosedAt     *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=closed_at,json=closedAt,proto3" json:"closed_at,omitempty"`
+	CreatedAt    *timestamppb.Timestamp `protobuf:"bytes,19,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt    *timestamppb.Timestamp `protobuf:"bytes,20,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// reopen_count сколько раз заявитель открывал тикет повторно
+	ReopenCount int32 `protobuf:"varint,21,opt,name=reopen_count,json=reopenCount,proto3" json:"reopen_count,omitempty"`
+	// previous_ticket_id закрытый тикет, вместо повторного открытия которого создан этот
+	PreviousTicketId *int64 `protobuf:"varint,22,opt,name=previous_ticket_id,json=previousTicketId,proto3,oneof" json:"previous_ticket_id,omitempty"`
+	// fields дополнительные поля заявки по схеме категории
+	Fields        *structpb.Struct `protobuf:"bytes,23,opt,name=fields,proto3" json:"fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Ticket) Reset() {
+	*x = Ticket{}
+	mi := &file_ticket_v1_ticket_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Ticket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ticket) ProtoMessage() {}
+
+func (x *Ticket) ProtoReflect() protoreflect.Message {
+	mi := &file_ticket_v1_ticket_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ticket.ProtoReflect.Descriptor instead.
+func (*Ticket) Descriptor() ([]byte, []int) {
+	return file_ticket_v1_ticket_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Ticket) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Ticket) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Ticket) GetCategory() TicketCategory {
+	if x != nil {
+		return x.Category
+	}
+	return TicketCategory_TICKET_CATEGORY_UNSPECIFIED
+}
+
+func (x *Ticket) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *Ticket) GetQuestion() string {
+	if x != nil {
+		return x.Question
+	}
+	return ""
+}
+
+func (x *Ticket) GetFullName() string {
+	if x != nil {
+		return x.FullName
+	}
+	return ""
+}
+
+func (x *Ticket) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Ticket) GetPhone() string {
+	if x != nil && x.Phone != nil {
+		return *x.Phone
+	}
+	return ""
+}
+
+func (x *Ticket) GetTelegramId() string {
+	if x != nil && x.TelegramId != nil {
+		return *x.TelegramId
+	}
+	return ""
+}
+
+func (x *Ticket) GetFileUrl() string {
+	if x != nil && x.FileUrl != nil {
+		return *x.FileUrl
+	}
+	return ""
+}
+
+func (x *Ticket) GetFileName() string {
+	if x != nil && x.FileName != nil {
+		return *x.FileName
+	}
+	return ""
+}
+
+func (x *Ticket) GetFileChecked() bool {
+	if x != nil {
+		return x.FileChecked
+	}
+	return false
+}
+
+func (x *Ticket) GetStatus() TicketStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TicketStatus_TICKET_STATUS_UNSPECIFIED
+}
+
+func (x *Ticket) GetNotifyEmail() bool {
+	if x != nil {
+		return x.NotifyEmail
+	}
+	return false
+}
+
+func (x *Ticket) GetNotifyTg() bool {
+	if x != nil {
+		return x.NotifyTg
+	}
+	return false
+}
+
+func (x *Ticket) GetAssignedTo() string {
+	if x != nil && x.AssignedTo != nil {
+		return *x.AssignedTo
+	}
+	return ""
+}
+
+func (x *Ticket) GetWaitingSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.WaitingSince
+	}
+	return nil
+}
+
+func (x *Ticket) GetClosedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ClosedAt
+	}
+	return nil
+}
+
+func (x *Ticket) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Ticket) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Ticket) GetReopenCount() int32 {
+	if x != nil {
+		return x.ReopenCount
+	}
+	return 0
+}
+
+func (x *Ticket) GetPreviousTicketId() int64 {
+	if x != nil && x.PreviousTicketId != nil {
+		return *x.PreviousTicketId
+	}
+	return 0
+}
+
+func (x *Ticket) GetFields() *structpb.Struct {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type Response struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	TicketId      int64                  `protobuf:"varint,2,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	AdminId       string                 `protobuf:"bytes,3,opt,name=admin_id,json=adminId,proto3" json:"admin_id,omitempty"`
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	FileUrl       *string                `protobuf:"bytes,5,opt,name=file_url,json=fileUrl,proto3,oneof" json:"file_url,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Response) Reset() {
+	*x = Response{}
+	mi := &file_ticket_v1_ticket_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Response) ProtoMessage() {}
+
+func (x *Response) ProtoReflect() protoreflect.Message {
+	mi := &file_ticket_v1_ticket_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Response.ProtoReflect.Descriptor instead.
+func (*Response) Descriptor() ([]byte, []int) {
+	return file_ticket_v1_ticket_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Response) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Response) GetTicketId() int64 {
+	if x != nil {
+		return x.TicketId
+	}
+	return 0
+}
+
+func (x *Response) GetAdminId() string {
+	if x != nil {
+		return x.AdminId
+	}
+	return ""
+}
+
+func (x *Response) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Response) GetFileUrl() string {
+	if x != nil && x.FileUrl != nil {
+		return *x.FileUrl
+	}
+	return ""
+}
+
+func (x *Response) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// CreateTicketRequest без вложений: файлы загружаются через REST API
+type CreateTicketRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// UNSPECIFIED означает general
+	Category TicketCategory `protobuf:"varint,1,opt,name=category,proto3,enum=ticket.v1.TicketCategory" json:"category,omitempty"`
+	Subject  string         `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	Question string         `protobuf:"bytes,3,opt,name=question,proto3" json:"question,omitempty"`
+	FullName string         `protobuf:"bytes,4,opt,name=full_name,json=fullName,proto3" json:"full_name,omitempty"`
+	// email обязателен для гостевых тикетов
+	Email       string  `protobuf:"bytes,5,opt,name=email,proto3" json:"email,omitempty"`
+	Phone       *string `protobuf:"bytes,6,opt,name=phone,proto3,oneof" json:"phone,omitempty"`
+	TelegramId  *string `protobuf:"bytes,7,opt,name=telegram_id,json=telegramId,proto3,oneof" json:"telegram_id,omitempty"`
+	NotifyEmail bool    `protobuf:"varint,8,opt,name=notify_email,json=notifyEmail,proto3" json:"notify_email,omitempty"`
+	NotifyTg    bool    `protobuf:"varint,9,opt,name=notify_tg,json=notifyTg,proto3" json:"notify_tg,omitempty"`
+	// fields проверяются по схеме дополнительных полей категории, как в REST API
+	Fields        *structpb.Struct `protobuf:"bytes,10,opt,name=fields,proto3" json:"fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTicketRequest) Reset() {
+	*x = CreateTicketRequest{}
+	mi := &file_ticket_v1_ticket_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTicketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTicketRequest) ProtoMessage() {}
+
+func (x *CreateTicketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ticket_v1_ticket_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTicketRequest.ProtoReflect.Descriptor instead.
+func (*CreateTicketRequest) Descriptor() ([]byte, []int) {
+	return file_ticket_v1_ticket_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateTicketRequest) GetCategory() TicketCategory {
+	if x != nil {
+		return x.Category
+	}
+	return TicketCategory_TICKET_CATEGORY_UNSPECIFIED
+}
+
+func (x *CreateTicketRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *CreateTicketRequest) GetQuestion() string {
+	if x != nil {
+		return x.Question
+	}
+	return ""
+}
+
+func (x *CreateTicketRequest) GetFullName() string {
+	if x != nil {
+		return x.FullName
+	}
+	return ""
+}
+
+func (x *CreateTicketRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *CreateTicketRequest) GetPhone() string {
+	if x != nil && x.Phone != nil {
+		return *x.Phone
+	}
+	return ""
+}
+
+func (x *CreateTicketRequest) GetTelegramId() string {
+	if x != nil && x.TelegramId != nil {
+		return *x.TelegramId
+	}
+	return ""
+}
+
+func (x *CreateTicketRequest) GetNotifyEmail() bool {
+	if x != nil {
+		return x.NotifyEmail
+	}
+	return false
+}
+
+func (x *CreateTicketRequest) GetNotifyTg() bool {
+	if x != nil {
+		return x.NotifyTg
+	}
+	return false
+}
+
+func (x *CreateTicketRequest) GetFields() *structpb.Struct {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type GetTicketRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTicketRequest) Reset() {
+	*x = GetTicketRequest{}
+	mi := &file_ticket_v1_ticket_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTicketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTicketRequest) ProtoMessage() {}
+
+func (x *GetTicketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ticket_v1_ticket_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTicketRequest.ProtoReflect.Descriptor instead.
+func (*GetTicketRequest) Descriptor() ([]byte, []int) {
+	return file_ticket_v1_ticket_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetTicketRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListTicketsRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Page     int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// UNSPECIFIED - все статусы, кроме spam
+	Status TicketStatus `protobuf:"varint,3,opt,name=status,proto3,enum=ticket.v1.TicketStatus" json:"status,omitempty"`
+	// user_id ограничивает выборку тикетами пользователя
+	UserId *string `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	// fields отбирает тикеты, у которых дополнительные поля равны заданным значениям
+	Fields        map[string]string `protobuf:"bytes,5,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTicketsRequest) Reset() {
+	*x = ListTicketsRequest{}
+	mi := &file_ticket_v1_ticket_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTicketsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTicketsRequest) ProtoMessage() {}
+
+func (x *ListTicketsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ticket_v1_ticket_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTicketsRequest.ProtoReflect.Descriptor instead.
+func (*ListTicketsRequest) Descriptor() ([]byte, []int) {
+	return file_ticket_v1_ticket_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListTicketsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListTicketsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListTicketsRequest) GetStatus() TicketStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TicketStatus_TICKET_STATUS_UNSPECIFIED
+}
+
+func (x *ListTicketsRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *ListTicketsRequest) GetFields() map[string]string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type SearchTicketsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Fields        map[string]string      `protobuf:"bytes,4,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchTicketsRequest) Reset() {
+	*x = SearchTicketsRequest{}
+	mi := &file_ticket_v1_ticket_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchTicketsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchTicketsRequest) ProtoMessage() {}
+
+func (x *SearchTicketsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ticket_v1_ticket_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchTicketsRequest.ProtoReflect.Descriptor instead.
+func (*SearchTicketsRequest) Descriptor() ([]byte, []int) {
+	return file_ticket_v1_ticket_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SearchTicketsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchTicketsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *SearchTicketsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchTicketsRequest) GetFields() map[string]string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type ListTicketsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tickets       []*Ticket              `protobuf:"bytes,1,rep,name=tickets,proto3" json:"tickets,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTicketsResponse) Reset() {
+	*x = ListTicketsResponse{}
+	mi := &file_ticket_v1_ticket_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTicketsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTicketsResponse) ProtoMessage() {}
+
+func (x *ListTicketsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ticket_v1_ticket_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTicketsResponse.ProtoReflect.Descriptor instead.
+func (*ListTicketsResponse) Descriptor() ([]byte, []int) {
+	return file_ticket_v1_ticket_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListTicketsResponse) GetTickets() []*Ticket {
+	if x != nil {
+		return x.Tickets
+	}
+	return nil
+}
+
+func (x *ListTicketsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type AddResponseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TicketId      int64                  `protobuf:"varint,1,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddResponseRequest) Reset() {
+	*x = AddResponseRequest{}
+	mi := &file_ticket_v1_ticket_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddResponseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddResponseRequest) ProtoMessage() {}
+
+func (x *AddResponseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ticket_v1_ticket_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddResponseRequest.ProtoReflect.Descriptor instead.
+func (*AddResponseRequest) Descriptor() ([]byte, []int) {
+	return file_ticket_v1_ticket_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AddResponseRequest) GetTicketId() int64 {
+	if x != nil {
+		return x.TicketId
+	}
+	return 0
+}
+
+func (x *AddResponseRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type UpdateStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status        TicketStatus           `protobuf:"varint,2,opt,name=status,proto3,enum=ticket.v1.TicketStatus" json:"status,omitempty"`
+	Comment       *string                `protobuf:"bytes,3,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateStatusRequest) Reset() {
+	*x = UpdateStatusRequest{}
+	mi := &file_ticket_v1_ticket_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateStatusRequest) ProtoMessage() {}
+
+func (x *UpdateStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ticket_v1_ticket_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateStatusRequest) Descriptor() ([]byte, []int) {
+	return file_ticket_v1_ticket_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateStatusRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateStatusRequest) GetStatus() TicketStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TicketStatus_TICKET_STATUS_UNSPECIFIED
+}
+
+func (x *UpdateStatusRequest) GetComment() string {
+	if x != nil && x.Comment != nil {
+		return *x.Comment
+	}
+	return ""
+}
+
+var File_ticket_v1_ticket_proto protoreflect.FileDescriptor
+
+const file_ticket_v1_ticket_proto_rawDesc = "" +
+	"\n" +
+	"\x16ticket/v1/ticket.proto\x12\tticket.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe1\a\n" +
+	"\x06Ticket\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x125\n" +
+	"\bcategory\x18\x03 \x01(\x0e2\x19.ticket.v1.TicketCategoryR\bcategory\x12\x18\n" +
+	"\asubject\x18\x04 \x01(\tR\asubject\x12\x1a\n" +
+	"\bquestion\x18\x05 \x01(\tR\bquestion\x12\x1b\n" +
+	"\tfull_name\x18\x06 \x01(\tR\bfullName\x12\x14\n" +
+	"\x05email\x18\a \x01(\tR\x05email\x12\x19\n" +
+	"\x05phone\x18\b \x01(\tH\x00R\x05phone\x88\x01\x01\x12$\n" +
+	"\vtelegram_id\x18\t \x01(\tH\x01R\n" +
+	"telegramId\x88\x01\x01\x12\x1e\n" +
+	"\bfile_url\x18\n" +
+	" \x01(\tH\x02R\afileUrl\x88\x01\x01\x12 \n" +
+	"\tfile_name\x18\v \x01(\tH\x03R\bfileName\x88\x01\x01\x12!\n" +
+	"\ffile_checked\x18\f \x01(\bR\vfileChecked\x12/\n" +
+	"\x06status\x18\r \x01(\x0e2\x17.ticket.v1.TicketStatusR\x06status\x12!\n" +
+	"\fnotify_email\x18\x0e \x01(\bR\vnotifyEmail\x12\x1b\n" +
+	"\tnotify_tg\x18\x0f \x01(\bR\bnotifyTg\x12$\n" +
+	"\vassigned_to\x18\x10 \x01(\tH\x04R\n" +
+	"assignedTo\x88\x01\x01\x12?\n" +
+	"\rwaiting_since\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\fwaitingSince\x127\n" +
+	"\tclosed_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\bclosedAt\x129\n" +
+	"\n" +
+	"created_at\x18\x13 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x14 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12!\n" +
+	"\freopen_count\x18\x15 \x01(\x05R\vreopenCount\x121\n" +
+	"\x12previous_ticket_id\x18\x16 \x01(\x03H\x05R\x10previousTicketId\x88\x01\x01\x12/\n" +
+	"\x06fields\x18\x17 \x01(\v2\x17.google.protobuf.StructR\x06fieldsB\b\n" +
+	"\x06_phoneB\x0e\n" +
+	"\f_telegram_idB\v\n" +
+	"\t_file_urlB\f\n" +
+	"\n" +
+	"_file_nameB\x0e\n" +
+	"\f_assigned_toB\x15\n" +
+	"\x13_previous_ticket_id\"\xd4\x01\n" +
+	"\bResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1b\n" +
+	"\tticket_id\x18\x02 \x01(\x03R\bticketId\x12\x19\n" +
+	"\badmin_id\x18\x03 \x01(\tR\aadminId\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12\x1e\n" +
+	"\bfile_url\x18\x05 \x01(\tH\x00R\afileUrl\x88\x01\x01\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAtB\v\n" +
+	"\t_file_url\"\x81\x03\n" +
+	"\x13CreateTicketRequest\x125\n" +
+	"\bcategory\x18\x01 \x01(\x0e2\x19.ticket.v1.TicketCategoryR\bcategory\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12\x1a\n" +
+	"\bquestion\x18\x03 \x01(\tR\bquestion\x12\x1b\n" +
+	"\tfull_name\x18\x04 \x01(\tR\bfullName\x12\x14\n" +
+	"\x05email\x18\x05 \x01(\tR\x05email\x12\x19\n" +
+	"\x05phone\x18\x06 \x01(\tH\x00R\x05phone\x88\x01\x01\x12$\n" +
+	"\vtelegram_id\x18\a \x01(\tH\x01R\n" +
+	"telegramId\x88\x01\x01\x12!\n" +
+	"\fnotify_email\x18\b \x01(\bR\vnotifyEmail\x12\x1b\n" +
+	"\tnotify_tg\x18\t \x01(\bR\bnotifyTg\x12/\n" +
+	"\x06fields\x18\n" +
+	" \x01(\v2\x17.google.protobuf.StructR\x06fieldsB\b\n" +
+	"\x06_phoneB\x0e\n" +
+	"\f_telegram_id\"\"\n" +
+	"\x10GetTicketRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x9e\x02\n" +
+	"\x12ListTicketsRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12/\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x17.ticket.v1.TicketStatusR\x06status\x12\x1c\n" +
+	"\auser_id\x18\x04 \x01(\tH\x00R\x06userId\x88\x01\x01\x12A\n" +
+	"\x06fields\x18\x05 \x03(\v2).ticket.v1.ListTicketsRequest.FieldsEntryR\x06fields\x1a9\n" +
+	"\vFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\n" +
+	"\n" +
+	"\b_user_id\"\xdd\x01\n" +
+	"\x14SearchTicketsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12C\n" +
+	"\x06fields\x18\x04 \x03(\v2+.ticket.v1.SearchTicketsRequest.FieldsEntryR\x06fields\x1a9\n" +
+	"\vFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"X\n" +
+	"\x13ListTicketsResponse\x12+\n" +
+	"\atickets\x18\x01 \x03(\v2\x11.ticket.v1.TicketR\atickets\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\"K\n" +
+	"\x12AddResponseRequest\x12\x1b\n" +
+	"\tticket_id\x18\x01 \x01(\x03R\bticketId\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x81\x01\n" +
+	"\x13UpdateStatusRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12/\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x17.ticket.v1.TicketStatusR\x06status\x12\x1d\n" +
+	"\acomment\x18\x03 \x01(\tH\x00R\acomment\x88\x01\x01B\n" +
+	"\n" +
+	"\b_comment*\xb0\x01\n" +
+	"\fTicketStatus\x12\x1d\n" +
+	"\x19TICKET_STATUS_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11TICKET_STATUS_NEW\x10\x01\x12\x1d\n" +
+	"\x19TICKET_STATUS_IN_PROGRESS\x10\x02\x12\x19\n" +
+	"\x15TICKET_STATUS_WAITING\x10\x03\x12\x18\n" +
+	"\x14TICKET_STATUS_CLOSED\x10\x04\x12\x16\n" +
+	"\x12TICKET_STATUS_SPAM\x10\x05*\xd1\x01\n" +
+	"\x0eTicketCategory\x12\x1f\n" +
+	"\x1bTICKET_CATEGORY_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17TICKET_CATEGORY_GENERAL\x10\x01\x12\x1f\n" +
+	"\x1bTICKET_CATEGORY_RECOGNITION\x10\x02\x12 \n" +
+	"\x1cTICKET_CATEGORY_LEGALISATION\x10\x03\x12!\n" +
+	"\x1dTICKET_CATEGORY_ACCREDITATION\x10\x04\x12\x1b\n" +
+	"\x17TICKET_CATEGORY_BOLOGNA\x10\x052\xb5\x03\n" +
+	"\rTicketService\x12A\n" +
+	"\fCreateTicket\x12\x1e.ticket.v1.CreateTicketRequest\x1a\x11.ticket.v1.Ticket\x12;\n" +
+	"\tGetTicket\x12\x1b.ticket.v1.GetTicketRequest\x1a\x11.ticket.v1.Ticket\x12L\n" +
+	"\vListTickets\x12\x1d.ticket.v1.ListTicketsRequest\x1a\x1e.ticket.v1.ListTicketsResponse\x12P\n" +
+	"\rSearchTickets\x12\x1f.ticket.v1.SearchTicketsRequest\x1a\x1e.ticket.v1.ListTicketsResponse\x12A\n" +
+	"\vAddResponse\x12\x1d.ticket.v1.AddResponseRequest\x1a\x13.ticket.v1.Response\x12A\n" +
+	"\fUpdateStatus\x12\x1e.ticket.v1.UpdateStatusRequest\x1a\x11.ticket.v1.TicketB'Z%ticket-service/api/ticket/v1;ticketv1b\x06proto3"
+
+var (
+	file_ticket_v1_ticket_proto_rawDescOnce sync.Once
+	file_ticket_v1_ticket_proto_rawDescData []byte
+)
+
+func file_ticket_v1_ticket_proto_rawDescGZIP() []byte {
+	file_ticket_v1_ticket_proto_rawDescOnce.Do(func() {
+		file_ticket_v1_ticket_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_ticket_v1_ticket_proto_rawDesc), len(file_ticket_v1_ticket_proto_rawDesc)))
+	})
+	return file_ticket_v1_ticket_proto_rawDescData
+}
+
+var file_ticket_v1_ticket_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_ticket_v1_ticket_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_ticket_v1_ticket_proto_goTypes = []any{
+	(TicketStatus)(0),             // 0: ticket.v1.TicketStatus
+	(TicketCategory)(0),           // 1: ticket.v1.TicketCategory
+	(*Ticket)(nil),                // 2: ticket.v1.Ticket
+	(*Response)(nil),              // 3: ticket.v1.Response
+	(*CreateTicketRequest)(nil),   // 4: ticket.v1.CreateTicketRequest
+	(*GetTicketRequest)(nil),      // 5: ticket.v1.GetTicketRequest
+	(*ListTicketsRequest)(nil),    // 6: ticket.v1.ListTicketsRequest
+	(*SearchTicketsRequest)(nil),  // 7: ticket.v1.SearchTicketsRequest
+	(*ListTicketsResponse)(nil),   // 8: ticket.v1.ListTicketsResponse
+	(*AddResponseRequest)(nil),    // 9: ticket.v1.AddResponseRequest
+	(*UpdateStatusRequest)(nil),   // 10: ticket.v1.UpdateStatusRequest
+	nil,                           // 11: ticket.v1.ListTicketsRequest.FieldsEntry
+	nil,                           // 12: ticket.v1.SearchTicketsRequest.FieldsEntry
+	(*timestamppb.Timestamp)(nil), // 13: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),       // 14: google.protobuf.Struct
+}
+var file_ticket_v1_ticket_proto_depIdxs = []int32{
+	1,  // 0: ticket.v1.Ticket.category:type_name -> ticket.v1.TicketCategory
+	0,  // 1: ticket.v1.Ticket.status:type_name -> ticket.v1.TicketStatus
+	13, // 2: ticket.v1.Ticket.waiting_since:type_name -> google.protobuf.Timestamp
+	13, // 3: ticket.v1.Ticket.closed_at:type_name -> google.protobuf.Timestamp
+	13, // 4: ticket.v1.Ticket.created_at:type_name -> google.protobuf.Timestamp
+	13, // 5: ticket.v1.Ticket.updated_at:type_name -> google.protobuf.Timestamp
+	14, // 6: ticket.v1.Ticket.fields:type_name -> google.protobuf.Struct
+	13, // 7: ticket.v1.Response.created_at:type_name -> google.protobuf.Timestamp
+	1,  // 8: ticket.v1.CreateTicketRequest.category:type_name -> ticket.v1.TicketCategory
+	14, // 9: ticket.v1.CreateTicketRequest.fields:type_name -> google.protobuf.Struct
+	0,  // 10: ticket.v1.ListTicketsRequest.status:type_name -> ticket.v1.TicketStatus
+	11, // 11: ticket.v1.ListTicketsRequest.fields:type_name -> ticket.v1.ListTicketsRequest.FieldsEntry
+	12, // 12: ticket.v1.SearchTicketsRequest.fields:type_name -> ticket.v1.SearchTicketsRequest.FieldsEntry
+	2,  // 13: ticket.v1.ListTicketsResponse.tickets:type_name -> ticket.v1.Ticket
+	0,  // 14: ticket.v1.UpdateStatusRequest.status:type_name -> ticket.v1.TicketStatus
+	4,  // 15: ticket.v1.TicketService.CreateTicket:input_type -> ticket.v1.CreateTicketRequest
+	5,  // 16: ticket.v1.TicketService.GetTicket:input_type -> ticket.v1.GetTicketRequest
+	6,  // 17: ticket.v1.TicketService.ListTickets:input_type -> ticket.v1.ListTicketsRequest
+	7,  // 18: ticket.v1.TicketService.SearchTickets:input_type -> ticket.v1.SearchTicketsRequest
+	9,  // 19: ticket.v1.TicketService.AddResponse:input_type -> ticket.v1.AddResponseRequest
+	10, // 20: ticket.v1.TicketService.UpdateStatus:input_type -> ticket.v1.UpdateStatusRequest
+	2,  // 21: ticket.v1.TicketService.CreateTicket:output_type -> ticket.v1.Ticket
+	2,  // 22: ticket.v1.TicketService.GetTicket:output_type -> ticket.v1.Ticket
+	8,  // 23: ticket.v1.TicketService.ListTickets:output_type -> ticket.v1.ListTicketsResponse
+	8,  // 24: ticket.v1.TicketService.SearchTickets:output_type -> ticket.v1.ListTicketsResponse
+	3,  // 25: ticket.v1.TicketService.AddResponse:output_type -> ticket.v1.Response
+	2,  // 26: ticket.v1.TicketService.UpdateStatus:output_type -> ticket.v1.Ticket
+	21, // [21:27] is the sub-list for method output_type
+	15, // [15:21] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_ticket_v1_ticket_proto_init() }
+func file_ticket_v1_ticket_proto_init() {
+	if File_ticket_v1_ticket_proto != nil {
+		return
+	}
+	file_ticket_v1_ticket_proto_msgTypes[0].OneofWrappers = []any{}
+	file_ticket_v1_ticket_proto_msgTypes[1].OneofWrappers = []any{}
+	file_ticket_v1_ticket_proto_msgTypes[2].OneofWrappers = []any{}
+	file_ticket_v1_ticket_proto_msgTypes[4].OneofWrappers = []any{}
+	file_ticket_v1_ticket_proto_msgTypes[8].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ticket_v1_ticket_proto_rawDesc), len(file_ticket_v1_ticket_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_ticket_v1_ticket_proto_goTypes,
+		DependencyIndexes: file_ticket_v1_ticket_proto_depIdxs,
+		EnumInfos:         file_ticket_v1_ticket_proto_enumTypes,
+		MessageInfos:      file_ticket_v1_ticket_proto_msgTypes,
+	}.Build()
+	File_ticket_v1_ticket_proto = out.File
+	file_ticket_v1_ticket_proto_goTypes = nil
+	file_ticket_v1_ticket_proto_depIdxs = nil
+}
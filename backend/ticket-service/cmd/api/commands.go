@@ -0,0 +1,337 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"ticket-service/internal/config"
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/services"
+	"ticket-service/internal/infrastructure/antivirus/clamav"
+	"ticket-service/internal/infrastructure/database/postgres"
+	"ticket-service/internal/infrastructure/storage"
+	"ticket-service/internal/infrastructure/storage/envelope"
+	"ticket-service/internal/logger"
+	"ticket-service/migrations"
+)
+
+const usage = `Usage: ticket-service [command]
+
+Commands:
+  serve                      запустить HTTP и gRPC API (по умолчанию)
+  migrate up                 применить все миграции
+  migrate down [N]           откатить N последних миграций (по умолчанию 1)
+  migrate status             показать версию схемы и непримененные миграции
+  migrate force VERSION      записать версию схемы без выполнения миграций
+  reindex                    перестроить индексы поиска тикетов
+  rescan                     повторно проверить вложения антивирусом
+  export [-status S] [-output FILE]
+                             выгрузить тикеты с историей и ответами в JSON Lines
+  purge                      обезличить тикеты с истекшим сроком хранения
+                             и удалить старый журнал доставок вебхуков
+  gc                         удалить вложения, на которые не ссылается
+                             ни один тикет или ответ
+  encrypt                    зашифровать вложения, сохраненные без шифрования,
+                             и перешифровать ключи данных активным мастер-ключом
+`
+
+// runCommand выполняет служебную команду и возвращает ошибку для ненулевого кода завершения
+func runCommand(cfg *config.Config, command string, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	switch command {
+	case "migrate":
+		return runMigrate(ctx, cfg, args)
+	case "reindex":
+		return runReindex(ctx, cfg)
+	case "rescan":
+		return runRescan(ctx, cfg)
+	case "export":
+		return runExport(ctx, cfg, args)
+	case "purge":
+		return runPurge(ctx, cfg)
+	case "gc":
+		return runGC(ctx, cfg)
+	case "encrypt":
+		return runEncrypt(ctx, cfg)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return nil
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
+
+// prepareSchema проверяет схему при запуске сервера согласно DB_MIGRATIONS_MODE
+func prepareSchema(pool *pgxpool.Pool, mode string) error {
+	if mode == config.MigrationsModeOff {
+		return nil
+	}
+
+	migrator, err := postgres.NewMigrator(pool, migrations.FS)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	if mode == config.MigrationsModeAuto {
+		if err := migrator.Up(); err != nil {
+			return err
+		}
+	}
+	return migrator.EnsureCurrent()
+}
+
+func openPool(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	pool, err := pgxpool.New(ctx, cfg.GetDSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return pool, nil
+}
+
+// openCurrentPool подключается к базе для служебных команд, которые работают только с актуальной схемой
+func openCurrentPool(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	pool, err := openPool(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := prepareSchema(pool, config.MigrationsModeCheck); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return pool, nil
+}
+
+func runMigrate(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("migrate requires a subcommand: up, down, status or force")
+	}
+
+	pool, err := openPool(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	migrator, err := postgres.NewMigrator(pool, migrations.FS)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	switch args[0] {
+	case "up":
+		if err := migrator.Up(); err != nil {
+			return err
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		if err := migrator.Down(steps); err != nil {
+			return err
+		}
+	case "force":
+		if len(args) < 2 {
+			return errors.New("migrate force requires a version")
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		if err := migrator.Force(version); err != nil {
+			return err
+		}
+	case "status":
+	default:
+		return fmt.Errorf("unknown migrate subcommand %q", args[0])
+	}
+
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("version: %d\ndirty: %t\nlatest: %d\npending: %v\n", status.Version, status.Dirty, status.Latest, status.Pending)
+	return nil
+}
+
+func runReindex(ctx context.Context, cfg *config.Config) error {
+	pool, err := openCurrentPool(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	if err := postgres.ReindexSearch(ctx, pool); err != nil {
+		return err
+	}
+	logger.Info("Search indexes rebuilt")
+	return nil
+}
+
+func runRescan(ctx context.Context, cfg *config.Config) error {
+	pool, err := openCurrentPool(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	fileService, err := newFileStore(pool, cfg)
+	if err != nil {
+		return err
+	}
+	clamavService := clamav.NewClamAVService(cfg.GetClamAVAddr(), 30*time.Second)
+	if clamavService == nil || !clamavService.IsAvailable(ctx) {
+		return services.ErrAntivirusNotAvailable
+	}
+
+	result, err := newMaintenanceService(pool, clamavService, fileService).RescanAttachments(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("scanned: %d\ninfected: %d\nfailed: %d\n", result.Scanned, result.Infected, result.Failed)
+	return nil
+}
+
+func runExport(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	status := flags.String("status", "", "выгрузить только тикеты в этом статусе")
+	output := flags.String("output", "", "файл для выгрузки, по умолчанию stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	pool, err := openCurrentPool(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	exported, err := newMaintenanceService(pool, nil, nil).ExportTickets(ctx, w, models.TicketStatus(*status))
+	if err != nil {
+		return err
+	}
+	logger.Info("Export finished", "tickets", exported)
+	return nil
+}
+
+func runPurge(ctx context.Context, cfg *config.Config) error {
+	pool, err := openCurrentPool(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	fileService, err := newFileStore(pool, cfg)
+	if err != nil {
+		return err
+	}
+
+	privacyService := services.NewPrivacyService(
+		postgres.NewTicketRepository(pool),
+		postgres.NewHistoryRepository(pool),
+		postgres.NewResponseRepository(pool),
+		postgres.NewPrivacyRepository(pool),
+		fileService,
+		services.NewAuditService(postgres.NewAuditRepository(pool)),
+		services.RetentionPolicy{AnonymizeAfter: cfg.Retention.AnonymizeAfter},
+		retentionPolicies(cfg),
+	)
+	if err := privacyService.ApplyRetention(ctx); err != nil {
+		return err
+	}
+
+	if cfg.Webhook.DeliveryRetention > 0 {
+		webhookService := services.NewWebhookService(postgres.NewWebhookRepository(pool), nil, services.WebhookPolicy{})
+		if _, err := webhookService.PurgeDeliveries(ctx, cfg.Webhook.DeliveryRetention); err != nil {
+			return err
+		}
+	}
+
+	logger.Info("Expired data purged")
+	return nil
+}
+
+func runGC(ctx context.Context, cfg *config.Config) error {
+	pool, err := openCurrentPool(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	fileStore, err := newFileStore(pool, cfg)
+	if err != nil {
+		return err
+	}
+	return fileStore.CollectGarbage(ctx)
+}
+
+func runEncrypt(ctx context.Context, cfg *config.Config) error {
+	objectStorage, _, err := storage.Factory(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize file storage: %w", err)
+	}
+	encrypted, ok := objectStorage.(*envelope.Service)
+	if !ok {
+		return errors.New("storage encryption is disabled: STORAGE_ENCRYPTION_KEYS is not set")
+	}
+
+	result, err := encrypted.EncryptAll(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("encrypted: %d\nrewrapped: %d\nunchanged: %d\nfailed: %d\n", result.Encrypted, result.Rewrapped, result.Unchanged, result.Failed)
+	if result.Failed > 0 {
+		return fmt.Errorf("failed to encrypt %d objects", result.Failed)
+	}
+	return nil
+}
+
+func newFileStore(pool *pgxpool.Pool, cfg *config.Config) (*services.FileStore, error) {
+	objectStorage, _, err := storage.Factory(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize file storage: %w", err)
+	}
+	return services.NewFileStore(objectStorage, postgres.NewFileObjectRepository(pool), postgres.NewUnitOfWork(pool), cfg.Storage.GCGrace), nil
+}
+
+func newMaintenanceService(pool *pgxpool.Pool, antivirusService services.IAntivirusService, fileService services.IFileService) *services.MaintenanceService {
+	return services.NewMaintenanceService(
+		postgres.NewTicketRepository(pool),
+		postgres.NewHistoryRepository(pool),
+		postgres.NewResponseRepository(pool),
+		antivirusService,
+		fileService,
+		services.NewAuditService(postgres.NewAuditRepository(pool)),
+	)
+}
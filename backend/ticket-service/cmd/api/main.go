@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -15,17 +16,27 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	_ "ticket-service/docs" // Import Swagger docs
+	"ticket-service/internal/auth"
 	"ticket-service/internal/config"
+	"ticket-service/internal/delivery/grpcapi"
 	"ticket-service/internal/delivery/http/handlers"
+	"ticket-service/internal/delivery/http/middleware"
 	"ticket-service/internal/delivery/http/router"
+	"ticket-service/internal/domain/models"
 	"ticket-service/internal/domain/services"
 	"ticket-service/internal/infrastructure/antivirus/clamav"
 	"ticket-service/internal/infrastructure/cache"
+	"ticket-service/internal/infrastructure/captcha"
 	"ticket-service/internal/infrastructure/database/postgres"
+	"ticket-service/internal/infrastructure/events"
 	"ticket-service/internal/infrastructure/notification/email"
-	"ticket-service/internal/infrastructure/storage/s3"
+	"ticket-service/internal/infrastructure/preview"
+	"ticket-service/internal/infrastructure/storage"
+	"ticket-service/internal/infrastructure/transcript"
+	"ticket-service/internal/infrastructure/webhook"
 	"ticket-service/internal/logger"
 	"ticket-service/internal/metrics"
+	"ticket-service/internal/scheduler"
 )
 
 // @title Ticket Service API
@@ -43,13 +54,27 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Инициализация логгера
+	logger.Init()
+
+	// Без аргументов и с командой serve запускается сервер, остальные команды выполняются и завершаются
+	if len(os.Args) > 1 && os.Args[1] != "serve" {
+		if err := runCommand(cfg, os.Args[1], os.Args[2:]); err != nil {
+			logger.Error("Command failed", "command", os.Args[1], "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	runServer(cfg)
+}
+
+// runServer запускает HTTP и gRPC API с фоновыми задачами и ждет сигнала завершения
+func runServer(cfg *config.Config) {
 	gin.SetMode(gin.ReleaseMode)
 	gin.DefaultWriter = io.Discard
 	gin.DefaultErrorWriter = io.Discard
 
-	// Инициализация логгера
-	logger.Init()
-
 	// Start metrics server
 	metrics.StartMetricsServer("2114")
 
@@ -61,6 +86,12 @@ func main() {
 	}
 	defer pool.Close()
 
+	// Схема проверяется до подключения остальных зависимостей и приема запросов
+	if err := prepareSchema(pool, cfg.Database.MigrationsMode); err != nil {
+		logger.Error("Database schema is not ready", "error", err)
+		os.Exit(1)
+	}
+
 	// Инициализация Redis
 	redisClient, err := cache.NewRedisClient(cfg)
 	if err != nil {
@@ -69,10 +100,10 @@ func main() {
 	}
 	defer redisClient.Close()
 
-	// Инициализация S3 клиента
-	fileService, err := s3.Factory(cfg)
+	// Инициализация хранилища вложений
+	objectStorage, fileURLVerifier, err := storage.Factory(cfg)
 	if err != nil {
-		logger.Error("Failed to initialize S3 client", "error", err)
+		logger.Error("Failed to initialize file storage", "error", err, "backend", cfg.Storage.Backend)
 		os.Exit(1)
 	}
 
@@ -94,38 +125,171 @@ func main() {
 	ticketRepo := postgres.NewTicketRepository(pool)
 	historyRepo := postgres.NewHistoryRepository(pool)
 	responseRepo := postgres.NewResponseRepository(pool)
+	surveyRepo := postgres.NewSurveyRepository(pool)
+	auditRepo := postgres.NewAuditRepository(pool)
+	privacyRepo := postgres.NewPrivacyRepository(pool)
+	webhookRepo := postgres.NewWebhookRepository(pool)
+	fileObjectRepo := postgres.NewFileObjectRepository(pool)
+	intakeRepo := postgres.NewIntakeRepository(pool)
+	unitOfWork := postgres.NewUnitOfWork(pool)
 
 	// Проверка инициализации репозиториев
-	if ticketRepo == nil || historyRepo == nil || responseRepo == nil {
+	if ticketRepo == nil || historyRepo == nil || responseRepo == nil || surveyRepo == nil || auditRepo == nil || privacyRepo == nil || webhookRepo == nil || fileObjectRepo == nil || intakeRepo == nil {
 		logger.Error("Failed to initialize repositories")
 		os.Exit(1)
 	}
 
+	// Фоновые процессы останавливаются при завершении работы сервера
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	// События рассылаются между репликами через Redis pub/sub
+	eventBroker := events.NewBroker(redisClient)
+	go eventBroker.Run(backgroundCtx)
+
 	// Инициализация сервисов
-	ticketService := services.NewTicketService(ticketRepo, historyRepo, responseRepo, clamavService, fileService)
+	auditService := services.NewAuditService(auditRepo)
+
+	intakeService := services.NewIntakeService(intakeRepo)
+
+	// Вложения хранятся по sha256 содержимого с учетом ссылок из тикетов и ответов
+	fileService := services.NewFileStore(objectStorage, fileObjectRepo, unitOfWork, cfg.Storage.GCGrace)
+
+	// События получают подписчики SSE и очередь вебхуков
+	webhookService := services.NewWebhookService(webhookRepo, webhook.NewSender(cfg.Webhook.Timeout), services.WebhookPolicy{
+		MaxAttempts: cfg.Webhook.MaxAttempts,
+		BaseBackoff: cfg.Webhook.BackoffBase,
+		MaxBackoff:  cfg.Webhook.BackoffMax,
+	})
+	eventPublisher := services.NewEventFanout(eventBroker, webhookService)
+
+	surveySigner := auth.NewSurveyTokenSigner(cfg.Survey.Secret, cfg.Survey.TokenTTL)
+	surveyService := services.NewSurveyService(surveyRepo, responseRepo, emailService, surveySigner, cfg.Survey.BaseURL)
+
+	// Тип вложений определяется по содержимому и проверяется по списку допустимых
+	fileInspector, err := services.NewFileInspector(services.UploadPolicy{
+		AllowedTypes:   cfg.Upload.AllowedTypes,
+		DefaultMaxSize: cfg.Upload.DefaultMaxSize,
+		MaxSizes:       cfg.Upload.MaxSizes,
+	})
+	if err != nil {
+		logger.Error("Invalid upload policy", "error", err)
+		os.Exit(1)
+	}
+
+	ticketService := services.NewTicketService(ticketRepo, historyRepo, responseRepo, clamavService, fileService, surveyService, eventPublisher, auditService, unitOfWork, fileInspector, cfg.Reopen.Window)
 	if ticketService == nil {
 		logger.Error("Failed to initialize ticket service")
 		os.Exit(1)
 	}
 
-	responseService := services.NewResponseService(responseRepo, ticketRepo, fileService, emailService)
+	responseService := services.NewResponseService(responseRepo, ticketRepo, historyRepo, fileService, emailService, eventPublisher, auditService, unitOfWork, fileInspector)
 	if responseService == nil {
 		logger.Error("Failed to initialize response service")
 		os.Exit(1)
 	}
 
+	// Возобновляемые загрузки: части копятся в хранилище, смещение хранится в Redis
+	uploadStore, err := storage.NewUploadStore(cfg)
+	if err != nil {
+		logger.Error("Failed to initialize upload storage", "error", err)
+		os.Exit(1)
+	}
+	uploadService := services.NewUploadService(cache.NewUploadRepository(redisClient), uploadStore, cfg.Upload.ResumableTTL)
+
+	// Превью вложений строятся в фоне и хранятся рядом с оригиналом
+	previewService := services.NewPreviewService(ticketRepo, responseRepo, fileObjectRepo, objectStorage, preview.NewRenderer(cfg.Upload.PreviewMaxDimension))
+
+	// Переписка по тикету для архива формируется в PDF встроенными шрифтами
+	transcriptRenderer, err := transcript.NewRenderer()
+	if err != nil {
+		logger.Error("Failed to initialize transcript renderer", "error", err)
+		os.Exit(1)
+	}
+	transcriptService := services.NewTranscriptService(ticketRepo, historyRepo, responseRepo, objectStorage, transcriptRenderer, auditService)
+
+	spamService := services.NewSpamService(ticketRepo, services.SpamPolicy{
+		EmailQuota:        cfg.Spam.EmailQuota,
+		PhoneQuota:        cfg.Spam.PhoneQuota,
+		QuotaWindow:       cfg.Spam.QuotaWindow,
+		MaxLinks:          cfg.Spam.MaxLinks,
+		Keywords:          cfg.Spam.Keywords,
+		DisposableDomains: cfg.Spam.DisposableDomains,
+		Threshold:         cfg.Spam.Threshold,
+	})
+
+	staleTicketService := services.NewStaleTicketService(
+		ticketRepo,
+		historyRepo,
+		emailService,
+		surveyService,
+		eventPublisher,
+		auditService,
+		unitOfWork,
+		services.StalePolicy{ReminderAfter: cfg.Stale.ReminderAfter, CloseAfter: cfg.Stale.CloseAfter},
+		stalePolicies(cfg),
+	)
+
+	privacyService := services.NewPrivacyService(
+		ticketRepo,
+		historyRepo,
+		responseRepo,
+		privacyRepo,
+		fileService,
+		auditService,
+		services.RetentionPolicy{AnonymizeAfter: cfg.Retention.AnonymizeAfter},
+		retentionPolicies(cfg),
+	)
+
+	// Фоновые задачи выполняет только реплика, удерживающая блокировку в Redis
+	jobScheduler := scheduler.NewScheduler(cache.NewLock(redisClient, "ticket-service:scheduler:leader", 30*time.Second), 10*time.Second)
+	jobScheduler.AddJob("stale_tickets", cfg.Stale.CheckInterval, staleTicketService.ProcessStaleTickets)
+	jobScheduler.AddJob("pii_retention", cfg.Retention.CheckInterval, privacyService.ApplyRetention)
+	jobScheduler.AddJob("webhook_deliveries", cfg.Webhook.DispatchInterval, webhookService.ProcessDeliveries)
+	jobScheduler.AddJob("file_gc", cfg.Storage.GCInterval, fileService.CollectGarbage)
+	jobScheduler.AddJob("expired_uploads", cfg.Upload.ResumableCleanupInterval, uploadService.ExpireUploads)
+	jobScheduler.AddJob("attachment_previews", cfg.Upload.PreviewInterval, previewService.GeneratePreviews)
+	jobScheduler.Start(backgroundCtx)
+
 	// Инициализация обработчиков
-	ticketHandler := handlers.NewTicketHandler(ticketService)
-	responseHandler := handlers.NewResponseHandler(responseService)
+	ticketHandler := handlers.NewTicketHandler(ticketService, spamService, uploadService, intakeService)
+	responseHandler := handlers.NewResponseHandler(responseService, uploadService)
+	uploadHandler := handlers.NewUploadHandler(uploadService)
+	previewHandler := handlers.NewPreviewHandler(previewService)
+	transcriptHandler := handlers.NewTranscriptHandler(transcriptService)
+	intakeHandler := handlers.NewIntakeHandler(intakeService)
+	surveyHandler := handlers.NewSurveyHandler(surveyService)
+	eventHandler := handlers.NewEventHandler(eventBroker)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	privacyHandler := handlers.NewPrivacyHandler(privacyService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	var fileHandler *handlers.FileHandler
+	if fileURLVerifier != nil {
+		fileHandler = handlers.NewFileHandler(fileService, fileURLVerifier)
+	}
 
 	// Проверка инициализации обработчиков
-	if ticketHandler == nil || responseHandler == nil {
+	if ticketHandler == nil || responseHandler == nil || uploadHandler == nil || previewHandler == nil || transcriptHandler == nil || intakeHandler == nil || surveyHandler == nil || eventHandler == nil || auditHandler == nil || privacyHandler == nil || webhookHandler == nil {
 		logger.Error("Failed to initialize handlers")
 		os.Exit(1)
 	}
 
+	// Капча для гостевых тикетов включается заданием CAPTCHA_SECRET_KEY
+	var captchaVerifier services.ICaptchaVerifier
+	if cfg.Captcha.SecretKey != "" {
+		captchaVerifier, err = captcha.NewVerifier(cfg.Captcha.Provider, cfg.Captcha.SecretKey, cfg.Captcha.VerifyURL, cfg.Captcha.Timeout)
+		if err != nil {
+			logger.Error("Failed to initialize captcha verifier", "error", err)
+			os.Exit(1)
+		}
+	} else {
+		logger.Info("Captcha is disabled: CAPTCHA_SECRET_KEY is not set")
+	}
+	captchaConfig := middleware.CaptchaConfig{MinScore: cfg.Captcha.MinScore, Action: cfg.Captcha.Action}
+	idempotencyConfig := middleware.IdempotencyConfig{TTL: cfg.Idempotency.TTL, LockTimeout: cfg.Idempotency.LockTimeout, MaxFileSize: fileInspector.LargestMaxSize()}
+
 	// Инициализация роутера
-	r := router.SetupRouter(ticketHandler, responseHandler, redisClient)
+	r := router.SetupRouter(ticketHandler, responseHandler, surveyHandler, eventHandler, auditHandler, privacyHandler, webhookHandler, fileHandler, uploadHandler, previewHandler, transcriptHandler, intakeHandler, redisClient, captchaVerifier, captchaConfig, idempotencyConfig, rateLimiterConfig(cfg), cfg.Auth.JWTSecret)
 	if r == nil {
 		logger.Error("Failed to setup router")
 		os.Exit(1)
@@ -145,6 +309,26 @@ func main() {
 		}
 	}()
 
+	// gRPC API для внутренних сервисов работает рядом с HTTP
+	if len(cfg.GRPC.ServiceTokens) == 0 {
+		logger.Warn("GRPC_SERVICE_TOKENS is empty: gRPC calls except health checks will be rejected")
+	}
+	grpcServer := grpcapi.NewServer(ticketService, intakeService, grpcapi.Config{
+		ServiceTokens: cfg.GRPC.ServiceTokens,
+		Reflection:    cfg.GRPC.Reflection,
+	})
+	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.GRPC.Port))
+	if err != nil {
+		logger.Error("Failed to listen for gRPC", "error", err)
+		os.Exit(1)
+	}
+	go func() {
+		logger.Info("Starting gRPC server", "port", cfg.GRPC.Port)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			logger.Error("Failed to start gRPC server", "error", err)
+		}
+	}()
+
 	// Ожидание сигнала для graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -152,12 +336,52 @@ func main() {
 
 	// Graceful shutdown
 	logger.Info("Shutting down server...")
+	stopBackground()
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.Error("Server forced to shutdown", "error", err)
 	}
+	grpcServer.Shutdown(ctx)
 
 	logger.Info("Server exiting")
 }
+
+// stalePolicies переводит настройки категорий из конфигурации в политики сервиса
+func stalePolicies(cfg *config.Config) map[models.TicketCategory]services.StalePolicy {
+	policies := make(map[models.TicketCategory]services.StalePolicy, len(cfg.Stale.Categories))
+	for category, policy := range cfg.Stale.Categories {
+		policies[models.TicketCategory(category)] = services.StalePolicy{
+			ReminderAfter: policy.ReminderAfter,
+			CloseAfter:    policy.CloseAfter,
+		}
+	}
+	return policies
+}
+
+// rateLimiterConfig переводит ограничения частоты запросов из конфигурации в настройки middleware
+func rateLimiterConfig(cfg *config.Config) middleware.RateLimiterConfig {
+	routes := make(map[string]map[string]middleware.RateLimitPolicy, len(cfg.RateLimit.Routes))
+	for route, principals := range cfg.RateLimit.Routes {
+		routes[route] = make(map[string]middleware.RateLimitPolicy, len(principals))
+		for principal, policy := range principals {
+			routes[route][principal] = middleware.RateLimitPolicy(policy)
+		}
+	}
+	return middleware.RateLimiterConfig{
+		Guest:  middleware.RateLimitPolicy(cfg.RateLimit.Guest),
+		User:   middleware.RateLimitPolicy(cfg.RateLimit.User),
+		Admin:  middleware.RateLimitPolicy(cfg.RateLimit.Admin),
+		Routes: routes,
+	}
+}
+
+// retentionPolicies переводит сроки хранения по категориям из конфигурации в политики сервиса
+func retentionPolicies(cfg *config.Config) map[models.TicketCategory]services.RetentionPolicy {
+	policies := make(map[models.TicketCategory]services.RetentionPolicy, len(cfg.Retention.Categories))
+	for category, after := range cfg.Retention.Categories {
+		policies[models.TicketCategory(category)] = services.RetentionPolicy{AnonymizeAfter: after}
+	}
+	return policies
+}
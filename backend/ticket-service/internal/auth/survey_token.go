@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const surveyTokenPurpose = "csat"
+
+var ErrInvalidSurveyToken = errors.New("invalid survey token")
+
+// SurveyTokenSigner выпускает и проверяет подписанные ссылки на опрос, не требующие входа
+type SurveyTokenSigner struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewSurveyTokenSigner(secret string, ttl time.Duration) *SurveyTokenSigner {
+	return &SurveyTokenSigner{
+		secret: []byte(secret),
+		ttl:    ttl,
+	}
+}
+
+// Sign возвращает токен, привязанный к тикету и к отправке опроса с nonce (claim jti)
+func (s *SurveyTokenSigner) Sign(ticketID int64, nonce string) (string, error) {
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		ID:        nonce,
+		Subject:   strconv.FormatInt(ticketID, 10),
+		Audience:  jwt.ClaimStrings{surveyTokenPurpose},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(s.ttl)),
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign survey token: %w", err)
+	}
+	return token, nil
+}
+
+// Parse проверяет подпись и срок действия токена и возвращает ID тикета и nonce отправки опроса.
+// Совпадение nonce с текущей отправкой проверяет вызывающий
+func (s *SurveyTokenSigner) Parse(tokenString string) (int64, string, error) {
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(surveyTokenPurpose),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return 0, "", fmt.Errorf("%w: %v", ErrInvalidSurveyToken, err)
+	}
+
+	ticketID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		return 0, "", ErrInvalidSurveyToken
+	}
+	return ticketID, claims.ID, nil
+}
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSurveyTokenSignerRoundTrip(t *testing.T) {
+	signer := NewSurveyTokenSigner("survey-secret", time.Hour)
+
+	token, err := signer.Sign(42, "nonce")
+	assert.NoError(t, err)
+
+	ticketID, nonce, err := signer.Parse(token)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), ticketID)
+	assert.Equal(t, "nonce", nonce)
+}
+
+func TestSurveyTokenSignerParsesLegacyToken(t *testing.T) {
+	signer := NewSurveyTokenSigner("survey-secret", time.Hour)
+
+	// Токены, выпущенные до привязки к отправке, не содержат nonce
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   "42",
+		Audience:  jwt.ClaimStrings{surveyTokenPurpose},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString([]byte("survey-secret"))
+	assert.NoError(t, err)
+
+	ticketID, nonce, err := signer.Parse(token)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), ticketID)
+	assert.Empty(t, nonce)
+}
+
+func TestSurveyTokenSignerRejectsInvalidTokens(t *testing.T) {
+	signer := NewSurveyTokenSigner("survey-secret", time.Hour)
+	now := time.Now()
+
+	sign := func(claims jwt.RegisteredClaims, secret string) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		assert.NoError(t, err)
+		return token
+	}
+	valid, err := signer.Sign(42, "nonce")
+	assert.NoError(t, err)
+	other, err := signer.Sign(43, "nonce")
+	assert.NoError(t, err)
+	parts := strings.Split(valid, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{
+			name: "wrong audience",
+			token: sign(jwt.RegisteredClaims{
+				Subject:   "42",
+				Audience:  jwt.ClaimStrings{"session"},
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			}, "survey-secret"),
+		},
+		{
+			name: "expired",
+			token: sign(jwt.RegisteredClaims{
+				Subject:   "42",
+				Audience:  jwt.ClaimStrings{surveyTokenPurpose},
+				ExpiresAt: jwt.NewNumericDate(now.Add(-time.Minute)),
+			}, "survey-secret"),
+		},
+		{
+			name: "without expiry",
+			token: sign(jwt.RegisteredClaims{
+				Subject:  "42",
+				Audience: jwt.ClaimStrings{surveyTokenPurpose},
+			}, "survey-secret"),
+		},
+		{
+			name: "signed with another secret",
+			token: sign(jwt.RegisteredClaims{
+				Subject:   "42",
+				Audience:  jwt.ClaimStrings{surveyTokenPurpose},
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			}, "jwt-secret"),
+		},
+		{
+			// Подпись от токена тикета 42, полезная нагрузка - от токена тикета 43
+			name:  "tampered payload",
+			token: parts[0] + "." + strings.Split(other, ".")[1] + "." + parts[2],
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := signer.Parse(tt.token)
+			assert.ErrorIs(t, err, ErrInvalidSurveyToken)
+		})
+	}
+}
@@ -1,20 +1,38 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
+	"golang.org/x/crypto/hkdf"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	S3       S3Config
-	ClamAV   ClamAVConfig
-	Captcha  CaptchaConfig
-	Auth     AuthConfig
+	Server      ServerConfig
+	GRPC        GRPCConfig
+	Database    DatabaseConfig
+	Redis       RedisConfig
+	S3          S3Config
+	Storage     StorageConfig
+	Upload      UploadConfig
+	ClamAV      ClamAVConfig
+	Captcha     CaptchaConfig
+	Auth        AuthConfig
+	Stale       StaleTicketsConfig
+	Reopen      ReopenConfig
+	Survey      SurveyConfig
+	Spam        SpamConfig
+	Retention   RetentionConfig
+	Idempotency IdempotencyConfig
+	RateLimit   RateLimitConfig
+	Webhook     WebhookConfig
 }
 
 type ServerConfig struct {
@@ -22,6 +40,14 @@ type ServerConfig struct {
 	ShutdownTimeout time.Duration
 }
 
+// GRPCConfig gRPC API для внутренних сервисов
+type GRPCConfig struct {
+	Port string
+	// ServiceTokens имя сервиса -> токен для метаданных authorization
+	ServiceTokens map[string]string
+	Reflection    bool
+}
+
 type DatabaseConfig struct {
 	Host     string
 	Port     string
@@ -29,8 +55,17 @@ type DatabaseConfig struct {
 	Password string
 	DBName   string
 	SSLMode  string
+	// MigrationsMode поведение при старте: off - не проверять схему, check - не запускаться
+	// с непримененными миграциями, auto - применить их
+	MigrationsMode string
 }
 
+const (
+	MigrationsModeOff   = "off"
+	MigrationsModeCheck = "check"
+	MigrationsModeAuto  = "auto"
+)
+
 type RedisConfig struct {
 	Host     string
 	Port     string
@@ -47,36 +82,278 @@ type S3Config struct {
 	UseSSL          bool
 }
 
+// StorageConfig выбор хранилища вложений. Файлы локального хранилища, хранилища в памяти и
+// зашифрованные файлы отдает сам сервис по подписанным ссылкам с адресом PublicURL
+type StorageConfig struct {
+	// Backend: s3, local или memory
+	Backend  string
+	LocalDir string
+	// PublicURL адрес маршрута /api/v1/files, доступный клиентам
+	PublicURL string
+	// SigningSecret подписывает ссылки на скачивание; если не задан, выводится из JWT_SECRET через HKDF
+	// и не совпадает с ключом подписи токенов
+	SigningSecret string
+	URLTTL        time.Duration
+	// GCInterval период сборки мусора: удаления объектов, на которые не ссылается ни один тикет или ответ
+	GCInterval time.Duration
+	// GCGrace объект без ссылок удаляется не раньше этого срока после освобождения
+	GCGrace time.Duration
+	// EncryptionKeys мастер-ключи AES-256 по идентификаторам; если пусто, вложения хранятся без шифрования
+	EncryptionKeys map[string][]byte
+	// EncryptionKeyID мастер-ключ, которым шифруются ключи данных новых объектов; остальные нужны для чтения
+	EncryptionKeyID string
+}
+
+const (
+	StorageBackendS3     = "s3"
+	StorageBackendLocal  = "local"
+	StorageBackendMemory = "memory"
+)
+
+// UploadConfig допустимые типы вложений и ограничения размера по типам
+type UploadConfig struct {
+	// AllowedTypes пустой список разрешает все распознаваемые типы
+	AllowedTypes   []string
+	DefaultMaxSize int64
+	MaxSizes       map[string]int64
+	// ResumableTTL возобновляемая загрузка истекает, если за это время не пришло ни одной части
+	ResumableTTL time.Duration
+	// ResumableCleanupInterval период удаления данных истекших загрузок из хранилища
+	ResumableCleanupInterval time.Duration
+	// PreviewInterval период построения превью для новых вложений
+	PreviewInterval time.Duration
+	// PreviewMaxDimension наибольшая сторона превью в пикселях
+	PreviewMaxDimension int
+}
+
 type ClamAVConfig struct {
 	Address string
 	Timeout time.Duration
 }
 
+// CaptchaConfig настройки проверки капчи для гостевых тикетов; пустой SecretKey отключает проверку
 type CaptchaConfig struct {
+	// Provider: recaptcha, hcaptcha или turnstile
+	Provider  string
 	SecretKey string
 	MinScore  float64
+	// VerifyURL переопределяет адрес проверки провайдера, например для локальной заглушки
+	VerifyURL string
+	// Action ожидаемое действие reCAPTCHA v3; пустое значение не проверяется
+	Action  string
+	Timeout time.Duration
+}
+
+// IdempotencyConfig хранение результатов создания тикетов по заголовку Idempotency-Key
+type IdempotencyConfig struct {
+	TTL         time.Duration
+	LockTimeout time.Duration
+}
+
+// RateLimitConfig ограничения частоты запросов для гостей (по IP), пользователей и администраторов
+type RateLimitConfig struct {
+	Guest RateLimitPolicy
+	User  RateLimitPolicy
+	Admin RateLimitPolicy
+	// Routes ограничения отдельных маршрутов по категориям инициаторов: "POST /api/v1/tickets" -> "guest" -> политика
+	Routes map[string]map[string]RateLimitPolicy
+}
+
+// RateLimitPolicy корзина токенов: Burst запросов подряд, затем PerMinute запросов в минуту; 0 отключает ограничение
+type RateLimitPolicy struct {
+	PerMinute int
+	Burst     int
+}
+
+// WebhookConfig доставка событий тикетов внешним системам
+type WebhookConfig struct {
+	DispatchInterval time.Duration
+	Timeout          time.Duration
+	// MaxAttempts после стольких неудачных попыток доставка помечается failed
+	MaxAttempts int
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	// DeliveryRetention завершенные доставки старше этого срока удаляет команда purge
+	DeliveryRetention time.Duration
 }
 
 type AuthConfig struct {
 	JWTSecret string
 }
 
+// SurveyConfig настройки опросов удовлетворенности после закрытия тикета
+type SurveyConfig struct {
+	// Secret подписывает ссылки на опрос; если не задан, выводится из JWT_SECRET через HKDF
+	// чтобы ссылка на опрос не проходила проверку как токен сессии
+	Secret   string
+	BaseURL  string
+	TokenTTL time.Duration
+}
+
+// RetentionConfig сроки хранения персональных данных в закрытых тикетах
+type RetentionConfig struct {
+	CheckInterval time.Duration
+	// AnonymizeAfter срок после закрытия, по истечении которого тикет обезличивается; 0 - хранить бессрочно
+	AnonymizeAfter time.Duration
+	// Categories переопределяет срок для отдельных категорий тикетов
+	Categories map[string]time.Duration
+}
+
+// SpamConfig настройки антиспама для гостевых тикетов
+type SpamConfig struct {
+	// Квоты на количество тикетов с одного email или телефона за окно QuotaWindow; 0 отключает квоту
+	EmailQuota  int
+	PhoneQuota  int
+	QuotaWindow time.Duration
+	// MaxLinks количество ссылок в тексте, после которого срабатывает сигнал
+	MaxLinks int
+	Keywords []string
+	// DisposableDomains дополняет встроенный список одноразовых почтовых доменов
+	DisposableDomains []string
+	// Threshold сумма оценок сигналов, с которой тикет задерживается как спам
+	Threshold float64
+}
+
+// StaleTicketsConfig настройки напоминаний и автозакрытия тикетов, ожидающих заявителя
+type StaleTicketsConfig struct {
+	CheckInterval time.Duration
+	ReminderAfter time.Duration
+	CloseAfter    time.Duration
+	// Categories переопределяет сроки для отдельных категорий тикетов
+	Categories map[string]StalePolicy
+}
+
+// ReopenConfig срок, в течение которого заявитель может открыть закрытый тикет повторно;
+// позже вместо этого создается новый тикет со ссылкой на закрытый
+type ReopenConfig struct {
+	Window time.Duration
+}
+
+type StalePolicy struct {
+	ReminderAfter time.Duration
+	CloseAfter    time.Duration
+}
+
 func LoadConfig() (*Config, error) {
 	v := viper.New()
 	v.AutomaticEnv()
 
+	v.SetDefault("DB_MIGRATIONS_MODE", MigrationsModeCheck)
+	v.SetDefault("GRPC_PORT", "9090")
+	v.SetDefault("STORAGE_BACKEND", StorageBackendS3)
+	v.SetDefault("STORAGE_LOCAL_DIR", "./data/files")
+	v.SetDefault("STORAGE_PUBLIC_URL", "http://localhost:8085/api/v1/files")
+	v.SetDefault("STORAGE_URL_TTL", 24*time.Hour)
+	v.SetDefault("STORAGE_GC_INTERVAL", time.Hour)
+	v.SetDefault("STORAGE_GC_GRACE", 24*time.Hour)
+	v.SetDefault("UPLOAD_MAX_SIZE_MB", 100)
+	v.SetDefault("UPLOAD_RESUMABLE_TTL", 24*time.Hour)
+	v.SetDefault("UPLOAD_RESUMABLE_CLEANUP_INTERVAL", time.Hour)
+	v.SetDefault("UPLOAD_PREVIEW_INTERVAL", time.Minute)
+	v.SetDefault("UPLOAD_PREVIEW_MAX_DIMENSION", 480)
+	v.SetDefault("GRPC_REFLECTION", true)
+	v.SetDefault("STALE_CHECK_INTERVAL", time.Hour)
+	v.SetDefault("STALE_REMINDER_DAYS", 3)
+	v.SetDefault("STALE_CLOSE_DAYS", 14)
+	v.SetDefault("REOPEN_WINDOW_DAYS", 14)
+	v.SetDefault("SURVEY_BASE_URL", "http://localhost:8085/api/v1/surveys")
+	v.SetDefault("SURVEY_TOKEN_TTL", 30*24*time.Hour)
+	v.SetDefault("CAPTCHA_PROVIDER", "recaptcha")
+	v.SetDefault("CAPTCHA_MIN_SCORE", 0.5)
+	v.SetDefault("CAPTCHA_TIMEOUT", 5*time.Second)
+	v.SetDefault("IDEMPOTENCY_TTL", 24*time.Hour)
+	v.SetDefault("IDEMPOTENCY_LOCK_TIMEOUT", 30*time.Second)
+	v.SetDefault("RATE_LIMIT_GUEST", "60:10")
+	v.SetDefault("RATE_LIMIT_USER", "120:20")
+	v.SetDefault("RATE_LIMIT_ADMIN", "600:100")
+	v.SetDefault("RATE_LIMIT_ROUTES", "POST /api/v1/tickets=guest:10:5,PATCH /api/v1/uploads/:id=user:600:100")
+	v.SetDefault("WEBHOOK_DISPATCH_INTERVAL", 15*time.Second)
+	v.SetDefault("WEBHOOK_TIMEOUT", 10*time.Second)
+	v.SetDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+	v.SetDefault("WEBHOOK_BACKOFF_BASE", 30*time.Second)
+	v.SetDefault("WEBHOOK_BACKOFF_MAX", 6*time.Hour)
+	v.SetDefault("WEBHOOK_DELIVERY_RETENTION", 30*24*time.Hour)
+	v.SetDefault("RETENTION_CHECK_INTERVAL", 24*time.Hour)
+	v.SetDefault("RETENTION_ANONYMIZE_MONTHS", 36)
+	v.SetDefault("SPAM_EMAIL_QUOTA", 5)
+	v.SetDefault("SPAM_PHONE_QUOTA", 5)
+	v.SetDefault("SPAM_QUOTA_WINDOW", 24*time.Hour)
+	v.SetDefault("SPAM_MAX_LINKS", 2)
+	v.SetDefault("SPAM_SCORE_THRESHOLD", 1.0)
+
+	stalePolicies, err := parseStalePolicies(v.GetString("STALE_CATEGORY_POLICIES"))
+	if err != nil {
+		return nil, err
+	}
+
+	retentionPolicies, err := parseRetentionPolicies(v.GetString("RETENTION_CATEGORY_MONTHS"))
+	if err != nil {
+		return nil, err
+	}
+
+	migrationsMode := v.GetString("DB_MIGRATIONS_MODE")
+	switch migrationsMode {
+	case MigrationsModeOff, MigrationsModeCheck, MigrationsModeAuto:
+	default:
+		return nil, fmt.Errorf("invalid DB_MIGRATIONS_MODE %q: expected off, check or auto", migrationsMode)
+	}
+
+	storageBackend := v.GetString("STORAGE_BACKEND")
+	switch storageBackend {
+	case StorageBackendS3, StorageBackendLocal, StorageBackendMemory:
+	default:
+		return nil, fmt.Errorf("invalid STORAGE_BACKEND %q: expected s3, local or memory", storageBackend)
+	}
+
+	encryptionKeys, err := parseEncryptionKeys(v.GetString("STORAGE_ENCRYPTION_KEYS"))
+	if err != nil {
+		return nil, err
+	}
+	encryptionKeyID := v.GetString("STORAGE_ENCRYPTION_KEY_ID")
+	if encryptionKeyID == "" && len(encryptionKeys) == 1 {
+		for id := range encryptionKeys {
+			encryptionKeyID = id
+		}
+	}
+	if len(encryptionKeys) > 0 {
+		if _, ok := encryptionKeys[encryptionKeyID]; !ok {
+			return nil, fmt.Errorf("STORAGE_ENCRYPTION_KEY_ID %q does not match any key in STORAGE_ENCRYPTION_KEYS", encryptionKeyID)
+		}
+	}
+
+	uploadMaxSizes, err := parseUploadMaxSizes(v.GetString("UPLOAD_TYPE_MAX_SIZE_MB"))
+	if err != nil {
+		return nil, err
+	}
+
+	rateLimit, err := parseRateLimitConfig(v)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceTokens, err := parseServiceTokens(v.GetString("GRPC_SERVICE_TOKENS"))
+	if err != nil {
+		return nil, err
+	}
+
 	config := &Config{
 		Server: ServerConfig{
 			Port:            v.GetString("SERVER_PORT"),
 			ShutdownTimeout: v.GetDuration("SERVER_SHUTDOWN_TIMEOUT"),
 		},
+		GRPC: GRPCConfig{
+			Port:          v.GetString("GRPC_PORT"),
+			ServiceTokens: serviceTokens,
+			Reflection:    v.GetBool("GRPC_REFLECTION"),
+		},
 		Database: DatabaseConfig{
-			Host:     v.GetString("DB_HOST"),
-			Port:     v.GetString("DB_PORT"),
-			User:     v.GetString("DB_USER"),
-			Password: v.GetString("DB_PASSWORD"),
-			DBName:   v.GetString("DB_NAME"),
-			SSLMode:  v.GetString("DB_SSLMODE"),
+			Host:           v.GetString("DB_HOST"),
+			Port:           v.GetString("DB_PORT"),
+			User:           v.GetString("DB_USER"),
+			Password:       v.GetString("DB_PASSWORD"),
+			DBName:         v.GetString("DB_NAME"),
+			SSLMode:        v.GetString("DB_SSLMODE"),
+			MigrationsMode: migrationsMode,
 		},
 		Redis: RedisConfig{
 			Host:     v.GetString("REDIS_HOST"),
@@ -92,17 +369,89 @@ func LoadConfig() (*Config, error) {
 			Region:          v.GetString("S3_REGION"),
 			UseSSL:          v.GetBool("S3_USE_SSL"),
 		},
+		Storage: StorageConfig{
+			Backend:         storageBackend,
+			LocalDir:        v.GetString("STORAGE_LOCAL_DIR"),
+			PublicURL:       v.GetString("STORAGE_PUBLIC_URL"),
+			SigningSecret:   v.GetString("STORAGE_SIGNING_SECRET"),
+			URLTTL:          v.GetDuration("STORAGE_URL_TTL"),
+			GCInterval:      v.GetDuration("STORAGE_GC_INTERVAL"),
+			GCGrace:         v.GetDuration("STORAGE_GC_GRACE"),
+			EncryptionKeys:  encryptionKeys,
+			EncryptionKeyID: encryptionKeyID,
+		},
+		Upload: UploadConfig{
+			AllowedTypes:             splitList(v.GetString("UPLOAD_ALLOWED_TYPES")),
+			DefaultMaxSize:           int64(v.GetInt("UPLOAD_MAX_SIZE_MB")) * megabyte,
+			MaxSizes:                 uploadMaxSizes,
+			ResumableTTL:             v.GetDuration("UPLOAD_RESUMABLE_TTL"),
+			ResumableCleanupInterval: v.GetDuration("UPLOAD_RESUMABLE_CLEANUP_INTERVAL"),
+			PreviewInterval:          v.GetDuration("UPLOAD_PREVIEW_INTERVAL"),
+			PreviewMaxDimension:      v.GetInt("UPLOAD_PREVIEW_MAX_DIMENSION"),
+		},
 		ClamAV: ClamAVConfig{
 			Address: fmt.Sprintf("%s:%s", v.GetString("CLAMAV_HOST"), v.GetString("CLAMAV_PORT")),
 			Timeout: v.GetDuration("CLAMAV_TIMEOUT"),
 		},
 		Captcha: CaptchaConfig{
+			Provider:  v.GetString("CAPTCHA_PROVIDER"),
 			SecretKey: v.GetString("CAPTCHA_SECRET_KEY"),
 			MinScore:  v.GetFloat64("CAPTCHA_MIN_SCORE"),
+			VerifyURL: v.GetString("CAPTCHA_VERIFY_URL"),
+			Action:    v.GetString("CAPTCHA_ACTION"),
+			Timeout:   v.GetDuration("CAPTCHA_TIMEOUT"),
 		},
 		Auth: AuthConfig{
 			JWTSecret: v.GetString("JWT_SECRET"),
 		},
+		Stale: StaleTicketsConfig{
+			CheckInterval: v.GetDuration("STALE_CHECK_INTERVAL"),
+			ReminderAfter: days(v.GetInt("STALE_REMINDER_DAYS")),
+			CloseAfter:    days(v.GetInt("STALE_CLOSE_DAYS")),
+			Categories:    stalePolicies,
+		},
+		Reopen: ReopenConfig{
+			Window: days(v.GetInt("REOPEN_WINDOW_DAYS")),
+		},
+		Survey: SurveyConfig{
+			Secret:   v.GetString("SURVEY_SECRET"),
+			BaseURL:  v.GetString("SURVEY_BASE_URL"),
+			TokenTTL: v.GetDuration("SURVEY_TOKEN_TTL"),
+		},
+		Idempotency: IdempotencyConfig{
+			TTL:         v.GetDuration("IDEMPOTENCY_TTL"),
+			LockTimeout: v.GetDuration("IDEMPOTENCY_LOCK_TIMEOUT"),
+		},
+		RateLimit: rateLimit,
+		Webhook: WebhookConfig{
+			DispatchInterval:  v.GetDuration("WEBHOOK_DISPATCH_INTERVAL"),
+			Timeout:           v.GetDuration("WEBHOOK_TIMEOUT"),
+			MaxAttempts:       v.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+			BackoffBase:       v.GetDuration("WEBHOOK_BACKOFF_BASE"),
+			BackoffMax:        v.GetDuration("WEBHOOK_BACKOFF_MAX"),
+			DeliveryRetention: v.GetDuration("WEBHOOK_DELIVERY_RETENTION"),
+		},
+		Retention: RetentionConfig{
+			CheckInterval:  v.GetDuration("RETENTION_CHECK_INTERVAL"),
+			AnonymizeAfter: months(v.GetInt("RETENTION_ANONYMIZE_MONTHS")),
+			Categories:     retentionPolicies,
+		},
+		Spam: SpamConfig{
+			EmailQuota:        v.GetInt("SPAM_EMAIL_QUOTA"),
+			PhoneQuota:        v.GetInt("SPAM_PHONE_QUOTA"),
+			QuotaWindow:       v.GetDuration("SPAM_QUOTA_WINDOW"),
+			MaxLinks:          v.GetInt("SPAM_MAX_LINKS"),
+			Keywords:          splitList(v.GetString("SPAM_KEYWORDS")),
+			DisposableDomains: splitList(v.GetString("SPAM_DISPOSABLE_DOMAINS")),
+			Threshold:         v.GetFloat64("SPAM_SCORE_THRESHOLD"),
+		},
+	}
+
+	if config.Survey.Secret == "" {
+		config.Survey.Secret = deriveSecret(config.Auth.JWTSecret, "ticket-service survey links")
+	}
+	if config.Storage.SigningSecret == "" && config.Auth.JWTSecret != "" {
+		config.Storage.SigningSecret = deriveSecret(config.Auth.JWTSecret, "ticket-service download links")
 	}
 
 	return config, nil
@@ -130,3 +479,208 @@ func (c *Config) GetClamAVAddr() string {
 func (c *Config) GetS3Config() S3Config {
 	return c.S3
 }
+
+// splitList разбирает список через запятую, пропуская пустые элементы
+// deriveSecret выводит из master отдельный ключ для назначения label (HKDF-SHA256)
+func deriveSecret(master, label string) string {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, []byte(master), nil, []byte(label)), key); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(key)
+}
+
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
+
+// months считает месяц за 30 дней: для сроков хранения точность до дня не важна
+func months(n int) time.Duration {
+	return days(n * 30)
+}
+
+// parseStalePolicies разбирает строку вида "recognition:5:21,legalisation:3:10",
+// где числа - дни до напоминания и до автозакрытия
+func parseStalePolicies(raw string) (map[string]StalePolicy, error) {
+	policies := make(map[string]StalePolicy)
+	if strings.TrimSpace(raw) == "" {
+		return policies, nil
+	}
+
+	for _, item := range strings.Split(raw, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid STALE_CATEGORY_POLICIES entry %q: expected category:reminder_days:close_days", item)
+		}
+
+		reminderDays, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid reminder days in STALE_CATEGORY_POLICIES entry %q: %w", item, err)
+		}
+		closeDays, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid close days in STALE_CATEGORY_POLICIES entry %q: %w", item, err)
+		}
+		if closeDays <= reminderDays {
+			return nil, fmt.Errorf("invalid STALE_CATEGORY_POLICIES entry %q: close days must be greater than reminder days", item)
+		}
+
+		policies[parts[0]] = StalePolicy{
+			ReminderAfter: days(reminderDays),
+			CloseAfter:    days(closeDays),
+		}
+	}
+
+	return policies, nil
+}
+
+// parseRetentionPolicies разбирает строку вида "recognition:60,general:12", где число - месяцы хранения
+func parseRetentionPolicies(raw string) (map[string]time.Duration, error) {
+	policies := make(map[string]time.Duration)
+
+	for _, item := range splitList(raw) {
+		parts := strings.Split(item, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid RETENTION_CATEGORY_MONTHS entry %q: expected category:months", item)
+		}
+
+		n, err := strconv.Atoi(parts[1])
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid months in RETENTION_CATEGORY_MONTHS entry %q", item)
+		}
+
+		policies[parts[0]] = months(n)
+	}
+
+	return policies, nil
+}
+
+const megabyte = 1024 * 1024
+
+// parseUploadMaxSizes разбирает UPLOAD_TYPE_MAX_SIZE_MB вида "jpeg:10,png:10,pdf:50"
+func parseUploadMaxSizes(raw string) (map[string]int64, error) {
+	sizes := make(map[string]int64)
+
+	for _, item := range splitList(raw) {
+		name, value, ok := strings.Cut(item, ":")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid UPLOAD_TYPE_MAX_SIZE_MB entry %q: expected type:megabytes", item)
+		}
+
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid size in UPLOAD_TYPE_MAX_SIZE_MB entry %q", item)
+		}
+
+		sizes[name] = int64(n) * megabyte
+	}
+
+	return sizes, nil
+}
+
+// encryptionKeySize размер мастер-ключа AES-256
+const encryptionKeySize = 32
+
+// parseEncryptionKeys разбирает STORAGE_ENCRYPTION_KEYS вида "2024:<base64>,2025:<base64>"
+func parseEncryptionKeys(raw string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+
+	for _, item := range splitList(raw) {
+		id, value, ok := strings.Cut(item, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("invalid STORAGE_ENCRYPTION_KEYS entry for %q: expected id:base64-key", id)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || len(key) != encryptionKeySize {
+			return nil, fmt.Errorf("invalid STORAGE_ENCRYPTION_KEYS key %q: expected %d bytes in base64", id, encryptionKeySize)
+		}
+		keys[id] = key
+	}
+
+	return keys, nil
+}
+
+// parseRateLimitConfig читает ограничения по категориям инициаторов вида "60:10" и RATE_LIMIT_ROUTES
+func parseRateLimitConfig(v *viper.Viper) (RateLimitConfig, error) {
+	config := RateLimitConfig{Routes: make(map[string]map[string]RateLimitPolicy)}
+	for name, policy := range map[string]*RateLimitPolicy{
+		"RATE_LIMIT_GUEST": &config.Guest,
+		"RATE_LIMIT_USER":  &config.User,
+		"RATE_LIMIT_ADMIN": &config.Admin,
+	} {
+		parsed, err := parseRateLimitPolicy(v.GetString(name))
+		if err != nil {
+			return config, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		*policy = parsed
+	}
+
+	// Элементы вида "POST /api/v1/tickets=guest:10:5": маршрут, категория, запросов в минуту и запас
+	for _, item := range splitList(v.GetString("RATE_LIMIT_ROUTES")) {
+		route, value, ok := strings.Cut(item, "=")
+		method, path, hasPath := strings.Cut(strings.TrimSpace(route), " ")
+		principal, rawPolicy, hasPolicy := strings.Cut(value, ":")
+		if !ok || !hasPath || !hasPolicy || method == "" || !strings.HasPrefix(strings.TrimSpace(path), "/") {
+			return config, fmt.Errorf("invalid RATE_LIMIT_ROUTES entry %q: expected METHOD /path=principal:per_minute:burst", item)
+		}
+		switch principal {
+		case "guest", "user", "admin":
+		default:
+			return config, fmt.Errorf("invalid principal in RATE_LIMIT_ROUTES entry %q: expected guest, user or admin", item)
+		}
+		policy, err := parseRateLimitPolicy(rawPolicy)
+		if err != nil {
+			return config, fmt.Errorf("invalid RATE_LIMIT_ROUTES entry %q: %w", item, err)
+		}
+
+		key := strings.ToUpper(method) + " " + strings.TrimSpace(path)
+		if config.Routes[key] == nil {
+			config.Routes[key] = make(map[string]RateLimitPolicy)
+		}
+		config.Routes[key][principal] = policy
+	}
+
+	return config, nil
+}
+
+func parseRateLimitPolicy(raw string) (RateLimitPolicy, error) {
+	rawPerMinute, rawBurst, ok := strings.Cut(strings.TrimSpace(raw), ":")
+	if !ok {
+		return RateLimitPolicy{}, fmt.Errorf("expected per_minute:burst, got %q", raw)
+	}
+	perMinute, err := strconv.Atoi(rawPerMinute)
+	if err != nil || perMinute < 0 {
+		return RateLimitPolicy{}, fmt.Errorf("invalid requests per minute %q", rawPerMinute)
+	}
+	burst, err := strconv.Atoi(rawBurst)
+	if err != nil || burst < 1 {
+		return RateLimitPolicy{}, fmt.Errorf("invalid burst %q", rawBurst)
+	}
+	return RateLimitPolicy{PerMinute: perMinute, Burst: burst}, nil
+}
+
+// parseServiceTokens разбирает GRPC_SERVICE_TOKENS вида "service:token,other:token"
+func parseServiceTokens(raw string) (map[string]string, error) {
+	tokens := make(map[string]string)
+
+	for _, item := range splitList(raw) {
+		name, token, ok := strings.Cut(item, ":")
+		if !ok || name == "" || token == "" {
+			return nil, fmt.Errorf("invalid GRPC_SERVICE_TOKENS entry for %q: expected service:token", name)
+		}
+		tokens[name] = token
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,139 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/infrastructure/metrics"
+	"ticket-service/internal/logger"
+)
+
+const (
+	authorizationKey = "authorization"
+	actorIDKey       = "x-actor-id"
+	actorTypeKey     = "x-actor-type"
+
+	// healthServicePrefix проверки состояния доступны без токена, чтобы их могли выполнять оркестратор и балансировщик
+	healthServicePrefix = "/grpc.health.v1.Health/"
+)
+
+type callerContextKey struct{}
+
+// callerFromContext возвращает имя сервиса, выполнившего вызов
+func callerFromContext(ctx context.Context) string {
+	caller, _ := ctx.Value(callerContextKey{}).(string)
+	return caller
+}
+
+// authenticator проверяет токен вызывающего сервиса и переносит инициатора из метаданных в контекст
+type authenticator struct {
+	// tokens имя сервиса -> токен
+	tokens map[string]string
+}
+
+func (a *authenticator) authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	if strings.HasPrefix(fullMethod, healthServicePrefix) {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	token, ok := strings.CutPrefix(firstValue(md, authorizationKey), "Bearer ")
+	if !ok || token == "" {
+		return nil, status.Error(codes.Unauthenticated, "service token is required")
+	}
+
+	caller := a.lookup(token)
+	if caller == "" {
+		return nil, status.Error(codes.Unauthenticated, "invalid service token")
+	}
+
+	actor := models.Actor{Type: models.ActorTypeSystem}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		actor.IP = p.Addr.String()
+	}
+
+	if value := firstValue(md, actorIDKey); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil || id == uuid.Nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid x-actor-id")
+		}
+		actor.ID = &id
+
+		switch actorType := models.ActorType(firstValue(md, actorTypeKey)); actorType {
+		case "", models.ActorTypeUser:
+			actor.Type = models.ActorTypeUser
+		case models.ActorTypeAdmin:
+			actor.Type = models.ActorTypeAdmin
+		default:
+			return nil, status.Error(codes.InvalidArgument, "x-actor-type must be user or admin")
+		}
+	}
+
+	ctx = models.ContextWithActor(ctx, actor)
+	return context.WithValue(ctx, callerContextKey{}, caller), nil
+}
+
+// lookup сравнивает токен со всеми известными за постоянное время и возвращает имя сервиса
+func (a *authenticator) lookup(token string) string {
+	caller := ""
+	for name, expected := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1 {
+			caller = name
+		}
+	}
+	return caller
+}
+
+func (a *authenticator) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+
+	authCtx, err := a.authenticate(ctx, info.FullMethod)
+	var resp interface{}
+	if err == nil {
+		resp, err = handler(authCtx, req)
+	}
+
+	code := status.Code(err)
+	metrics.GRPCRequestsTotal.WithLabelValues(info.FullMethod, code.String()).Inc()
+	metrics.GRPCRequestDuration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+	if code == codes.Internal || code == codes.Unknown {
+		logger.Error("gRPC call failed", "method", info.FullMethod, "caller", callerFromContext(authCtx), "error", err)
+	}
+
+	return resp, err
+}
+
+func (a *authenticator) streamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+}
+
+// authenticatedStream подменяет контекст потока на контекст с инициатором
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,134 @@
+package grpcapi
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	ticketv1 "ticket-service/api/ticket/v1"
+	"ticket-service/internal/domain/models"
+)
+
+var statusToProto = map[models.TicketStatus]ticketv1.TicketStatus{
+	models.TicketStatusNew:        ticketv1.TicketStatus_TICKET_STATUS_NEW,
+	models.TicketStatusInProgress: ticketv1.TicketStatus_TICKET_STATUS_IN_PROGRESS,
+	models.TicketStatusWaiting:    ticketv1.TicketStatus_TICKET_STATUS_WAITING,
+	models.TicketStatusClosed:     ticketv1.TicketStatus_TICKET_STATUS_CLOSED,
+	models.TicketStatusSpam:       ticketv1.TicketStatus_TICKET_STATUS_SPAM,
+}
+
+var categoryToProto = map[models.TicketCategory]ticketv1.TicketCategory{
+	models.TicketCategoryGeneral:       ticketv1.TicketCategory_TICKET_CATEGORY_GENERAL,
+	models.TicketCategoryRecognition:   ticketv1.TicketCategory_TICKET_CATEGORY_RECOGNITION,
+	models.TicketCategoryLegalisation:  ticketv1.TicketCategory_TICKET_CATEGORY_LEGALISATION,
+	models.TicketCategoryAccreditation: ticketv1.TicketCategory_TICKET_CATEGORY_ACCREDITATION,
+	models.TicketCategoryBologna:       ticketv1.TicketCategory_TICKET_CATEGORY_BOLOGNA,
+}
+
+// statusFromProto возвращает пустой статус для UNSPECIFIED и неизвестных значений
+func statusFromProto(status ticketv1.TicketStatus) models.TicketStatus {
+	for model, proto := range statusToProto {
+		if proto == status {
+			return model
+		}
+	}
+	return ""
+}
+
+// categoryFromProto возвращает пустую категорию для UNSPECIFIED и неизвестных значений
+func categoryFromProto(category ticketv1.TicketCategory) models.TicketCategory {
+	for model, proto := range categoryToProto {
+		if proto == category {
+			return model
+		}
+	}
+	return ""
+}
+
+func ticketToProto(ticket *models.Ticket) *ticketv1.Ticket {
+	return &ticketv1.Ticket{
+		Id:               ticket.ID,
+		UserId:           userIDToProto(ticket.UserID),
+		Category:         categoryToProto[ticket.Category],
+		Subject:          ticket.Subject,
+		Question:         ticket.Question,
+		FullName:         ticket.FullName,
+		Email:            ticket.Email,
+		Phone:            ticket.Phone,
+		TelegramId:       ticket.TelegramID,
+		FileUrl:          ticket.FileURL,
+		FileName:         ticket.FileName,
+		FileChecked:      ticket.FileChecked,
+		Status:           statusToProto[ticket.Status],
+		NotifyEmail:      ticket.NotifyEmail,
+		NotifyTg:         ticket.NotifyTG,
+		AssignedTo:       optionalUUIDToProto(ticket.AssignedTo),
+		WaitingSince:     timestampOrNil(ticket.WaitingSince),
+		ClosedAt:         timestampOrNil(ticket.ClosedAt),
+		CreatedAt:        timestamppb.New(ticket.CreatedAt),
+		UpdatedAt:        timestamppb.New(ticket.UpdatedAt),
+		ReopenCount:      int32(ticket.ReopenCount),
+		PreviousTicketId: ticket.PreviousTicketID,
+		Fields:           fieldsToProto(ticket.Fields),
+	}
+}
+
+// fieldsToProto переводит дополнительные поля в Struct; значения уже проверены по схеме
+// и содержат только строки, числа и логические значения
+func fieldsToProto(fields map[string]any) *structpb.Struct {
+	if len(fields) == 0 {
+		return nil
+	}
+	result, err := structpb.NewStruct(fields)
+	if err != nil {
+		return nil
+	}
+	return result
+}
+
+func ticketsToProto(tickets []*models.Ticket, total int64) *ticketv1.ListTicketsResponse {
+	resp := &ticketv1.ListTicketsResponse{
+		Tickets: make([]*ticketv1.Ticket, 0, len(tickets)),
+		Total:   total,
+	}
+	for _, ticket := range tickets {
+		resp.Tickets = append(resp.Tickets, ticketToProto(ticket))
+	}
+	return resp
+}
+
+func responseToProto(response *models.Response) *ticketv1.Response {
+	return &ticketv1.Response{
+		Id:        response.ID,
+		TicketId:  response.TicketID,
+		AdminId:   response.AdminID.String(),
+		Message:   response.Message,
+		FileUrl:   response.FileURL,
+		CreatedAt: timestamppb.New(response.CreatedAt),
+	}
+}
+
+// userIDToProto у гостевых тикетов пользователя нет, поле остается пустым
+func userIDToProto(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
+
+func optionalUUIDToProto(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+	value := id.String()
+	return &value
+}
+
+func timestampOrNil(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,267 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	ticketv1 "ticket-service/api/ticket/v1"
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/services"
+)
+
+const (
+	defaultPageSize = 10
+	maxPageSize     = 100
+)
+
+// Config параметры gRPC сервера
+type Config struct {
+	// ServiceTokens имя сервиса -> токен, который он передает в authorization
+	ServiceTokens map[string]string
+	// Reflection включает gRPC reflection для grpcurl и подобных клиентов
+	Reflection bool
+}
+
+// Server gRPC сервер с API тикетов, проверкой состояния и reflection
+type Server struct {
+	*grpc.Server
+	health *health.Server
+}
+
+// NewServer регистрирует TicketService, health и reflection
+func NewServer(ticketService *services.TicketService, intakeService *services.IntakeService, config Config) *Server {
+	auth := &authenticator{tokens: config.ServiceTokens}
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(auth.unaryInterceptor),
+		grpc.ChainStreamInterceptor(auth.streamInterceptor),
+	)
+
+	ticketv1.RegisterTicketServiceServer(server, &ticketServer{ticketService: ticketService, intakeService: intakeService})
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	healthServer.SetServingStatus(ticketv1.TicketService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	if config.Reflection {
+		reflection.Register(server)
+	}
+
+	return &Server{Server: server, health: healthServer}
+}
+
+// Shutdown переводит проверки состояния в NOT_SERVING и дожидается завершения текущих вызовов
+func (s *Server) Shutdown(ctx context.Context) {
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.Stop()
+	}
+}
+
+type ticketServer struct {
+	ticketv1.UnimplementedTicketServiceServer
+	ticketService *services.TicketService
+	intakeService *services.IntakeService
+}
+
+// CreateTicket создает тикет от имени вызывающего сервиса. Вызовы доверенные,
+// поэтому, как и тикеты авторизованных пользователей, не проходят капчу и антиспам
+func (s *ticketServer) CreateTicket(ctx context.Context, req *ticketv1.CreateTicketRequest) (*ticketv1.Ticket, error) {
+	if req.GetSubject() == "" || req.GetQuestion() == "" || req.GetFullName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "subject, question and full_name are required")
+	}
+
+	category := models.TicketCategoryGeneral
+	if req.GetCategory() != ticketv1.TicketCategory_TICKET_CATEGORY_UNSPECIFIED {
+		category = categoryFromProto(req.GetCategory())
+		if category == "" {
+			return nil, status.Error(codes.InvalidArgument, "invalid category")
+		}
+	}
+
+	var fields map[string]any
+	if s.intakeService != nil {
+		var err error
+		fields, err = s.intakeService.ValidateFields(ctx, category, req.GetFields().AsMap())
+		if err != nil {
+			return nil, toStatusError(err)
+		}
+	}
+
+	ticket := &models.Ticket{
+		Category:    category,
+		Subject:     req.GetSubject(),
+		Question:    req.GetQuestion(),
+		Email:       req.GetEmail(),
+		FullName:    req.GetFullName(),
+		Phone:       req.Phone,
+		TelegramID:  req.TelegramId,
+		NotifyEmail: req.GetNotifyEmail(),
+		NotifyTG:    req.GetNotifyTg(),
+		Status:      models.TicketStatusNew,
+		Fields:      fields,
+	}
+
+	actor := models.ActorFromContext(ctx)
+	if actor.ID != nil {
+		ticket.UserID = *actor.ID
+		// Для авторизованных пользователей всегда включаем уведомления, как в REST API
+		ticket.NotifyEmail = true
+	} else if _, err := mail.ParseAddress(req.GetEmail()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "valid email is required for guests")
+	}
+
+	if err := s.ticketService.CreateTicket(ctx, ticket, nil); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return ticketToProto(ticket), nil
+}
+
+func (s *ticketServer) GetTicket(ctx context.Context, req *ticketv1.GetTicketRequest) (*ticketv1.Ticket, error) {
+	ticket, err := s.ticketService.GetTicket(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	if ticket == nil {
+		return nil, status.Error(codes.NotFound, services.ErrTicketNotFound.Error())
+	}
+
+	return ticketToProto(ticket), nil
+}
+
+func (s *ticketServer) ListTickets(ctx context.Context, req *ticketv1.ListTicketsRequest) (*ticketv1.ListTicketsResponse, error) {
+	page, pageSize := pagination(req.GetPage(), req.GetPageSize())
+	filter := models.GetTicketsRequest{
+		Page:     page,
+		PageSize: pageSize,
+		Status:   statusFromProto(req.GetStatus()),
+		Fields:   req.GetFields(),
+	}
+
+	var tickets []*models.Ticket
+	var total int64
+	var err error
+	if req.UserId != nil {
+		userID, parseErr := uuid.Parse(req.GetUserId())
+		if parseErr != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+		}
+		tickets, total, err = s.ticketService.GetUserTickets(ctx, userID, filter)
+	} else {
+		tickets, total, err = s.ticketService.GetAllTickets(ctx, filter)
+	}
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return ticketsToProto(tickets, total), nil
+}
+
+func (s *ticketServer) SearchTickets(ctx context.Context, req *ticketv1.SearchTicketsRequest) (*ticketv1.ListTicketsResponse, error) {
+	query := strings.TrimSpace(req.GetQuery())
+	if query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+
+	page, pageSize := pagination(req.GetPage(), req.GetPageSize())
+	tickets, total, err := s.ticketService.SearchTickets(ctx, query, models.GetTicketsRequest{Page: page, PageSize: pageSize, Fields: req.GetFields()})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return ticketsToProto(tickets, total), nil
+}
+
+func (s *ticketServer) AddResponse(ctx context.Context, req *ticketv1.AddResponseRequest) (*ticketv1.Response, error) {
+	adminID, err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.GetMessage()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "message is required")
+	}
+
+	response := &models.Response{
+		TicketID: req.GetTicketId(),
+		AdminID:  adminID,
+		Message:  req.GetMessage(),
+	}
+	if _, err := s.ticketService.CreateResponse(ctx, response); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return responseToProto(response), nil
+}
+
+func (s *ticketServer) UpdateStatus(ctx context.Context, req *ticketv1.UpdateStatusRequest) (*ticketv1.Ticket, error) {
+	adminID, err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ticketStatus := statusFromProto(req.GetStatus())
+	if ticketStatus == "" {
+		return nil, status.Error(codes.InvalidArgument, "status is required")
+	}
+
+	if err := s.ticketService.UpdateTicketStatus(ctx, req.GetId(), ticketStatus, adminID, req.Comment); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return s.GetTicket(ctx, &ticketv1.GetTicketRequest{Id: req.GetId()})
+}
+
+// requireAdmin возвращает ID администратора, от имени которого выполняется вызов
+func requireAdmin(ctx context.Context) (uuid.UUID, error) {
+	actor := models.ActorFromContext(ctx)
+	if actor.ID == nil || actor.Type != models.ActorTypeAdmin {
+		return uuid.Nil, status.Error(codes.PermissionDenied, "admin actor is required")
+	}
+	return *actor.ID, nil
+}
+
+func pagination(page, pageSize int32) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return int(page), int(pageSize)
+}
+
+// toStatusError переводит ошибки сервиса в коды gRPC
+func toStatusError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrTicketNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, services.ErrTicketClosed), errors.Is(err, services.ErrStatusTransition):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, services.ErrInvalidIntakeFields):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/services"
+	"ticket-service/internal/logger"
+)
+
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// ListAuditEntries возвращает журнал изменений тикетов (только для админов)
+// @Summary Журнал аудита
+// @Description Возвращает изменения тикетов с инициатором, IP и разницей полей до/после
+// @Tags audit
+// @Produce json
+// @Param ticket_id query int false "ID тикета"
+// @Param actor_id query string false "UUID инициатора"
+// @Param actor_type query string false "Тип инициатора: guest, user, admin, system"
+// @Param action query string false "Тип изменения, например ticket.status_changed"
+// @Param from query string false "Начало периода (RFC3339)"
+// @Param to query string false "Конец периода, не включая (RFC3339)"
+// @Param page query int false "Номер страницы"
+// @Param page_size query int false "Размер страницы"
+// @Success 200 {object} []models.AuditEntry
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /audit [get]
+func (h *AuditHandler) ListAuditEntries(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	entries, total, err := h.auditService.List(c.Request.Context(), filter)
+	if err != nil {
+		logger.Error("Failed to get audit entries", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"total":   total,
+	})
+}
+
+func parseAuditFilter(c *gin.Context) (models.AuditFilter, error) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	filter := models.AuditFilter{
+		ActorType: models.ActorType(c.Query("actor_type")),
+		Action:    models.AuditAction(c.Query("action")),
+		Page:      page,
+		PageSize:  pageSize,
+	}
+
+	if value := c.Query("ticket_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return filter, errors.New("invalid ticket_id")
+		}
+		filter.TicketID = &id
+	}
+
+	if value := c.Query("actor_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return filter, errors.New("invalid actor_id")
+		}
+		filter.ActorID = &id
+	}
+
+	if value := c.Query("from"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, errors.New("invalid from, expected RFC3339")
+		}
+		filter.From = &t
+	}
+
+	if value := c.Query("to"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, errors.New("invalid to, expected RFC3339")
+		}
+		filter.To = &t
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/services"
+	"ticket-service/internal/logger"
+)
+
+// heartbeatInterval не дает прокси закрыть простаивающее соединение
+const heartbeatInterval = 25 * time.Second
+
+type EventHandler struct {
+	subscriber services.IEventSubscriber
+}
+
+func NewEventHandler(subscriber services.IEventSubscriber) *EventHandler {
+	return &EventHandler{
+		subscriber: subscriber,
+	}
+}
+
+// StreamEvents отдает события по тикетам через Server-Sent Events
+// @Summary Поток событий по тикетам
+// @Description Администраторы получают события по всем тикетам, пользователи - только по своим. События: ticket.created, ticket.status_changed, ticket.response_added, ticket.assigned
+// @Tags events
+// @Produce text/event-stream
+// @Success 200 {object} models.TicketEvent
+// @Failure 401 {object} ErrorResponse
+// @Router /events/stream [get]
+func (h *EventHandler) StreamEvents(c *gin.Context) {
+	userID := currentUserID(c)
+	isAdmin := c.GetBool("isAdmin")
+	if userID == uuid.Nil && !isAdmin {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	events, unsubscribe := h.subscriber.Subscribe(func(event *models.TicketEvent) bool {
+		return isAdmin || (event.OwnerID != nil && *event.OwnerID == userID)
+	})
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Отключаем буферизацию в nginx
+	c.Header("X-Accel-Buffering", "no")
+
+	logger.Info("Event stream opened", "userID", userID, "isAdmin", isAdmin)
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			logger.Info("Event stream closed", "userID", userID)
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.Render(-1, sse.Event{
+				Id:    event.ID,
+				Event: string(event.Type),
+				Data:  event,
+			})
+			return true
+		case <-heartbeat.C:
+			// Комментарий SSE игнорируется клиентом, но держит соединение активным
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return false
+			}
+			return true
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ticket-service/internal/domain/services"
+	"ticket-service/internal/logger"
+)
+
+// FileHandler отдает вложения из локального хранилища и хранилища в памяти по подписанным ссылкам
+type FileHandler struct {
+	fileService services.IFileService
+	verifier    services.ISignedURLVerifier
+}
+
+func NewFileHandler(fileService services.IFileService, verifier services.ISignedURLVerifier) *FileHandler {
+	return &FileHandler{
+		fileService: fileService,
+		verifier:    verifier,
+	}
+}
+
+// Download отдает файл по подписанной ссылке
+// @Summary Скачать вложение
+// @Description Отдает вложение по ссылке, выданной хранилищем; вход не требуется, доступ ограничен подписью и сроком действия
+// @Tags files
+// @Produce octet-stream
+// @Param key path string true "Ключ файла"
+// @Param expires query int true "Срок действия ссылки, unix time"
+// @Param signature query string true "Подпись ссылки"
+// @Success 200 {file} binary
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /files/{key} [get]
+func (h *FileHandler) Download(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "invalid download link"})
+		return
+	}
+	if err := h.verifier.Verify(key, expires, c.Query("signature")); err != nil {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	file, err := h.fileService.DownloadFile(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, services.ErrFileNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "file not found"})
+			return
+		}
+		logger.Error("Failed to download file", "error", err, "key", key)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to download file"})
+		return
+	}
+	defer file.Close()
+
+	// Файл всегда отдается как вложение, чтобы браузер не исполнял загруженный пользователем HTML
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(key)))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Type", "application/octet-stream")
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, file); err != nil {
+		logger.Error("Failed to stream file", "error", err, "key", key)
+	}
+}
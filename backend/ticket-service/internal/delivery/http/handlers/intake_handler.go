@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/services"
+	"ticket-service/internal/logger"
+)
+
+// IntakeHandler отдает формам схемы дополнительных полей категорий и позволяет администраторам их менять
+type IntakeHandler struct {
+	intakeService *services.IntakeService
+}
+
+func NewIntakeHandler(intakeService *services.IntakeService) *IntakeHandler {
+	return &IntakeHandler{
+		intakeService: intakeService,
+	}
+}
+
+// ListCategories возвращает категории тикетов со схемами дополнительных полей
+// @Summary Категории тикетов
+// @Description Список категорий со схемами дополнительных полей заявки; подписи полей даны на казахском, русском и английском
+// @Tags categories
+// @Produce json
+// @Success 200 {object} []models.CategoryIntake
+// @Failure 500 {object} ErrorResponse
+// @Router /categories [get]
+func (h *IntakeHandler) ListCategories(c *gin.Context) {
+	intakes, err := h.intakeService.ListSchemas(c.Request.Context())
+	if err != nil {
+		logger.Error("Failed to list intake schemas", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to list categories"})
+		return
+	}
+
+	c.JSON(http.StatusOK, intakes)
+}
+
+// GetCategoryFields возвращает схему дополнительных полей категории
+// @Summary Дополнительные поля категории
+// @Tags categories
+// @Produce json
+// @Param category path string true "Категория"
+// @Success 200 {object} models.CategoryIntake
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /categories/{category}/fields [get]
+func (h *IntakeHandler) GetCategoryFields(c *gin.Context) {
+	category, ok := categoryParam(c)
+	if !ok {
+		return
+	}
+
+	intake, err := h.intakeService.GetSchema(c.Request.Context(), category)
+	if err != nil {
+		logger.Error("Failed to get intake schema", "error", err, "category", category)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to get category fields"})
+		return
+	}
+
+	c.JSON(http.StatusOK, intake)
+}
+
+// UpdateCategoryFields заменяет схему дополнительных полей категории (только для администраторов)
+// @Summary Изменить дополнительные поля категории
+// @Description Принимает JSON Schema объекта: для полей поддерживаются type (string, integer, number, boolean), enum, minLength, maxLength, minimum, maximum и подписи x-labels и x-enum-labels (kz, ru, en; ru обязательна). Уже созданные тикеты не меняются
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param category path string true "Категория"
+// @Param request body models.IntakeSchema true "Схема дополнительных полей"
+// @Success 200 {object} models.CategoryIntake
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /categories/{category}/fields [put]
+func (h *IntakeHandler) UpdateCategoryFields(c *gin.Context) {
+	category, ok := categoryParam(c)
+	if !ok {
+		return
+	}
+
+	var schema models.IntakeSchema
+	if err := c.ShouldBindJSON(&schema); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	intake, err := h.intakeService.UpdateSchema(c.Request.Context(), category, &schema, currentUserID(c))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidIntakeSchema) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		logger.Error("Failed to update intake schema", "error", err, "category", category)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to update category fields"})
+		return
+	}
+
+	c.JSON(http.StatusOK, intake)
+}
+
+func categoryParam(c *gin.Context) (models.TicketCategory, bool) {
+	category := models.TicketCategory(c.Param("category"))
+	if !category.IsValid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid category"})
+		return "", false
+	}
+	return category, true
+}
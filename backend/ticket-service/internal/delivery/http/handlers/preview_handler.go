@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"ticket-service/internal/domain/services"
+	"ticket-service/internal/logger"
+)
+
+// PreviewHandler отдает администраторам превью вложений тикетов и ответов
+type PreviewHandler struct {
+	previewService *services.PreviewService
+}
+
+func NewPreviewHandler(previewService *services.PreviewService) *PreviewHandler {
+	return &PreviewHandler{
+		previewService: previewService,
+	}
+}
+
+// GetTicketPreview отдает превью вложения тикета
+// @Summary Превью вложения тикета
+// @Description Отдает PNG-превью изображения или первой страницы PDF, приложенного к тикету (только для администраторов). Превью строится в фоне после загрузки; пока оно не готово или для файлов других типов возвращается 404
+// @Tags tickets
+// @Produce png
+// @Param id path int true "ID тикета"
+// @Success 200 {file} binary
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tickets/{id}/attachment/preview [get]
+func (h *PreviewHandler) GetTicketPreview(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid ticket ID"})
+		return
+	}
+
+	preview, err := h.previewService.OpenTicketPreview(c.Request.Context(), id)
+	h.respondPreview(c, preview, err)
+}
+
+// GetResponsePreview отдает превью вложения ответа
+// @Summary Превью вложения ответа
+// @Description Отдает PNG-превью изображения или первой страницы PDF, приложенного к ответу (только для администраторов)
+// @Tags responses
+// @Produce png
+// @Param id path int true "ID ответа"
+// @Success 200 {file} binary
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /responses/{id}/attachment/preview [get]
+func (h *PreviewHandler) GetResponsePreview(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid response ID"})
+		return
+	}
+
+	preview, err := h.previewService.OpenResponsePreview(c.Request.Context(), id)
+	h.respondPreview(c, preview, err)
+}
+
+func (h *PreviewHandler) respondPreview(c *gin.Context, preview io.ReadCloser, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPreviewNotFound), errors.Is(err, services.ErrTicketNotFound),
+			errors.Is(err, services.ErrResponseNotFound), errors.Is(err, services.ErrFileNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		default:
+			logger.Error("Failed to open attachment preview", "error", err, "path", c.Request.URL.Path)
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to open attachment preview"})
+		}
+		return
+	}
+	defer preview.Close()
+
+	// Превью строится сервисом, а не пользователем, поэтому его можно показывать прямо в браузере
+	c.Header("Content-Type", "image/png")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Cache-Control", "private, max-age=3600")
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, preview); err != nil {
+		logger.Error("Failed to stream attachment preview", "error", err, "path", c.Request.URL.Path)
+	}
+}
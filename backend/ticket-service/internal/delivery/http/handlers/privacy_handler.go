@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/services"
+	"ticket-service/internal/logger"
+)
+
+type PrivacyHandler struct {
+	privacyService *services.PrivacyService
+}
+
+func NewPrivacyHandler(privacyService *services.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyService: privacyService,
+	}
+}
+
+// ExportPersonalData выгружает все тикеты заявителя по email (только для админов)
+// @Summary Выгрузить персональные данные
+// @Description Возвращает все тикеты заявителя с историей и ответами; выгрузка фиксируется в журнале аудита
+// @Tags privacy
+// @Produce json
+// @Param email query string true "Email заявителя"
+// @Success 200 {object} models.PersonalDataExport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /privacy/export [get]
+func (h *PrivacyHandler) ExportPersonalData(c *gin.Context) {
+	email := strings.TrimSpace(c.Query("email"))
+	if !isValidEmail(email) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "valid email is required"})
+		return
+	}
+
+	export, err := h.privacyService.ExportByEmail(c.Request.Context(), email)
+	if err != nil {
+		logger.Error("Failed to export personal data", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+// ErasePersonalData обезличивает все тикеты заявителя по email (только для админов)
+// @Summary Удалить персональные данные
+// @Description Обезличивает все тикеты заявителя и удаляет вложения; статистические поля сохраняются, действие фиксируется в журнале аудита
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Param request body models.EraseRequest true "Email заявителя"
+// @Success 200 {object} BulkResultResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /privacy/erase [post]
+func (h *PrivacyHandler) ErasePersonalData(c *gin.Context) {
+	var req models.EraseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	erased, err := h.privacyService.EraseByEmail(c.Request.Context(), req.Email)
+	if err != nil {
+		logger.Error("Failed to erase personal data", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, BulkResultResponse{Processed: erased})
+}
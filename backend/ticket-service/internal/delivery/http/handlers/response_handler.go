@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"io"
 	"net/http"
 	"strconv"
@@ -14,11 +15,13 @@ import (
 
 type ResponseHandler struct {
 	responseService *services.ResponseService
+	uploadService   *services.UploadService
 }
 
-func NewResponseHandler(responseService *services.ResponseService) *ResponseHandler {
+func NewResponseHandler(responseService *services.ResponseService, uploadService *services.UploadService) *ResponseHandler {
 	return &ResponseHandler{
 		responseService: responseService,
+		uploadService:   uploadService,
 	}
 }
 
@@ -31,10 +34,15 @@ func NewResponseHandler(responseService *services.ResponseService) *ResponseHand
 // @Param id path int true "ID тикета"
 // @Param message formData string true "Сообщение"
 // @Param file formData file false "Прикрепленный файл"
+// @Param upload_id formData string false "ID завершенной загрузки из /uploads вместо file"
 // @Success 201 {object} models.Response
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 413 {object} FileRejectedResponse
+// @Failure 415 {object} FileRejectedResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /responses/ticket/{id} [post]
 func (h *ResponseHandler) CreateResponse(c *gin.Context) {
@@ -50,9 +58,31 @@ func (h *ResponseHandler) CreateResponse(c *gin.Context) {
 		return
 	}
 
+	adminID := currentUserID(c)
+
 	file, _ := c.FormFile("file")
-	var fileReader io.Reader
-	if file != nil {
+	uploadID := c.PostForm("upload_id")
+	if file != nil && uploadID != "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "file and upload_id are mutually exclusive"})
+		return
+	}
+
+	var upload *models.FileUpload
+	var resumable *models.Upload
+	if uploadID != "" {
+		var reader io.ReadCloser
+		var ok bool
+		resumable, reader, ok = openUpload(c, h.uploadService, uploadID, adminID)
+		if !ok {
+			return
+		}
+		defer reader.Close()
+		upload = &models.FileUpload{
+			Reader:      reader,
+			Name:        resumable.Filename(),
+			ContentType: resumable.FileType(),
+		}
+	} else if file != nil {
 		openedFile, err := file.Open()
 		if err != nil {
 			logger.Error("Failed to open file", "error", err)
@@ -60,21 +90,37 @@ func (h *ResponseHandler) CreateResponse(c *gin.Context) {
 			return
 		}
 		defer openedFile.Close()
-		fileReader = openedFile
+		upload = &models.FileUpload{
+			Reader:      openedFile,
+			Name:        file.Filename,
+			ContentType: file.Header.Get("Content-Type"),
+		}
 	}
 
-	adminID := c.GetInt64("userID")
 	response := &models.Response{
 		TicketID: ticketID,
 		AdminID:  adminID,
 		Message:  message,
 	}
 
-	if err := h.responseService.CreateResponse(c.Request.Context(), response, fileReader); err != nil {
-		logger.Error("Failed to create response", "error", err, "ticketID", ticketID)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	if err := h.responseService.CreateResponse(c.Request.Context(), response, upload); err != nil {
+		if respondFileRejected(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, services.ErrTicketNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrTicketClosed):
+			c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+		default:
+			logger.Error("Failed to create response", "error", err, "ticketID", ticketID)
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
 		return
 	}
+	if resumable != nil {
+		h.uploadService.ConsumeUpload(c.Request.Context(), resumable)
+	}
 
 	c.JSON(http.StatusCreated, response)
 }
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/services"
+	"ticket-service/internal/logger"
+)
+
+type SurveyHandler struct {
+	surveyService *services.SurveyService
+}
+
+func NewSurveyHandler(surveyService *services.SurveyService) *SurveyHandler {
+	return &SurveyHandler{
+		surveyService: surveyService,
+	}
+}
+
+// RateFromLink показывает страницу подтверждения оценки, выбранной в письме
+// @Summary Страница оценки по ссылке
+// @Description Открывается по подписанной ссылке из письма, вход не требуется. Оценка не сохраняется: GET-ссылки открывают почтовые сканеры и предзагрузка, поэтому страница отправляет выбранную оценку формой через POST
+// @Tags surveys
+// @Produce html
+// @Param token path string true "Подписанный токен опроса"
+// @Param rating query int false "Оценка от 1 до 5, выбранная в письме"
+// @Success 200 {string} string "HTML-страница"
+// @Failure 401 {string} string "HTML-страница"
+// @Failure 404 {string} string "HTML-страница"
+// @Failure 409 {string} string "HTML-страница"
+// @Failure 500 {string} string "HTML-страница"
+// @Router /surveys/{token} [get]
+func (h *SurveyHandler) RateFromLink(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+
+	if _, err := h.surveyService.GetSurvey(c.Request.Context(), c.Param("token")); err != nil {
+		status, message := surveyErrorStatus(err)
+		renderSurveyPage(c, status, surveyPage{Message: message})
+		return
+	}
+
+	rating, _ := strconv.Atoi(c.Query("rating"))
+	renderSurveyPage(c, http.StatusOK, surveyPage{Form: true, Rating: rating, Ratings: surveyRatings()})
+}
+
+// SubmitRating сохраняет оценку с необязательным комментарием
+// @Summary Отправить оценку с комментарием
+// @Description Сохраняет оценку 1-5 и комментарий по подписанной ссылке из письма. Принимает JSON или форму со страницы подтверждения; на форму отвечает HTML-страницей
+// @Tags surveys
+// @Accept json
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token path string true "Подписанный токен опроса"
+// @Param request body models.SubmitSurveyRequest true "Оценка"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /surveys/{token} [post]
+func (h *SurveyHandler) SubmitRating(c *gin.Context) {
+	fromPage := c.ContentType() == binding.MIMEPOSTForm
+
+	var req models.SubmitSurveyRequest
+	if err := c.ShouldBind(&req); err != nil {
+		if fromPage {
+			renderSurveyPage(c, http.StatusBadRequest, surveyPage{Message: "Выберите оценку от 1 до 5."})
+			return
+		}
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request format"})
+		return
+	}
+	if req.Comment != nil && strings.TrimSpace(*req.Comment) == "" {
+		req.Comment = nil
+	}
+
+	err := h.surveyService.SubmitRating(c.Request.Context(), c.Param("token"), req.Rating, req.Comment)
+
+	if fromPage {
+		if err != nil {
+			status, message := surveyErrorStatus(err)
+			renderSurveyPage(c, status, surveyPage{Message: message})
+			return
+		}
+		renderSurveyPage(c, http.StatusOK, surveyPage{Message: "Спасибо за оценку!"})
+		return
+	}
+
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, MessageResponse{Message: "thank you for your feedback"})
+	case errors.Is(err, services.ErrInvalidRating):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrInvalidSurveyToken):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrSurveyNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrSurveyAlreadyRated):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	default:
+		logger.Error("Failed to submit survey rating", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
+
+// surveyPage данные страницы опроса: форма с оценкой или итоговое сообщение
+type surveyPage struct {
+	Form    bool
+	Rating  int
+	Ratings []int
+	Message string
+}
+
+var surveyPageTemplate = template.Must(template.New("survey").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Оценка поддержки</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
+<div style="max-width: 600px; margin: 0 auto; padding: 20px;">
+<h2 style="color: #2c3e50;">Оценка поддержки</h2>
+{{if .Form}}
+<form method="post">
+<p>Оцените качество поддержки от 1 до 5:</p>
+<p>{{range .Ratings}}<label style="margin-right: 12px;"><input type="radio" name="rating" value="{{.}}" required{{if eq . $.Rating}} checked{{end}}> {{.}}</label>{{end}}</p>
+<p><label>Комментарий (необязательно):<br><textarea name="comment" rows="4" maxlength="2000" style="width: 100%;"></textarea></label></p>
+<button type="submit" style="padding: 8px 14px; background: #2c3e50; color: #fff; border: 0; border-radius: 4px;">Отправить оценку</button>
+</form>
+{{else}}
+<p>{{.Message}}</p>
+{{end}}
+</div>
+</body>
+</html>
+`))
+
+func renderSurveyPage(c *gin.Context, status int, page surveyPage) {
+	var body bytes.Buffer
+	if err := surveyPageTemplate.Execute(&body, page); err != nil {
+		logger.Error("Failed to render survey page", "error", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Data(status, "text/html; charset=utf-8", body.Bytes())
+}
+
+func surveyRatings() []int {
+	ratings := make([]int, 0, models.MaxSurveyRating-models.MinSurveyRating+1)
+	for rating := models.MinSurveyRating; rating <= models.MaxSurveyRating; rating++ {
+		ratings = append(ratings, rating)
+	}
+	return ratings
+}
+
+// surveyErrorStatus код ответа и текст для страницы опроса
+func surveyErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidRating):
+		return http.StatusBadRequest, "Выберите оценку от 1 до 5."
+	case errors.Is(err, services.ErrInvalidSurveyToken):
+		return http.StatusUnauthorized, "Ссылка на опрос недействительна или устарела."
+	case errors.Is(err, services.ErrSurveyNotFound):
+		return http.StatusNotFound, "Опрос не найден."
+	case errors.Is(err, services.ErrSurveyAlreadyRated):
+		return http.StatusConflict, "Оценка по этому опросу уже получена. Спасибо!"
+	default:
+		logger.Error("Failed to process survey", "error", err)
+		return http.StatusInternalServerError, "Не удалось обработать оценку, попробуйте позже."
+	}
+}
+
+// GetCSATSummary возвращает сводную статистику оценок
+// @Summary Сводная статистика CSAT
+// @Description Возвращает число отправленных опросов, оценок, среднюю оценку и распределение (только для администраторов)
+// @Tags analytics
+// @Produce json
+// @Param from_date query string false "Начало периода (YYYY-MM-DD)"
+// @Param to_date query string false "Конец периода включительно (YYYY-MM-DD)"
+// @Success 200 {object} models.CSATSummary
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /analytics/csat [get]
+func (h *SurveyHandler) GetCSATSummary(c *gin.Context) {
+	filter, err := parseCSATFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	summary, err := h.surveyService.GetSummary(c.Request.Context(), filter)
+	if err != nil {
+		logger.Error("Failed to get CSAT summary", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetAdminCSAT возвращает статистику оценок по администраторам
+// @Summary Статистика CSAT по администраторам
+// @Description Возвращает количество оценок и среднюю оценку для каждого администратора (только для администраторов)
+// @Tags analytics
+// @Produce json
+// @Param from_date query string false "Начало периода (YYYY-MM-DD)"
+// @Param to_date query string false "Конец периода включительно (YYYY-MM-DD)"
+// @Success 200 {object} []models.AdminCSATStats
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /analytics/csat/admins [get]
+func (h *SurveyHandler) GetAdminCSAT(c *gin.Context) {
+	filter, err := parseCSATFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	stats, err := h.surveyService.GetAdminStats(c.Request.Context(), filter)
+	if err != nil {
+		logger.Error("Failed to get admin CSAT stats", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// parseCSATFilter разбирает период в формате YYYY-MM-DD; to_date включается целиком
+func parseCSATFilter(c *gin.Context) (models.CSATFilter, error) {
+	var filter models.CSATFilter
+
+	if from := c.Query("from_date"); from != "" {
+		t, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return filter, errors.New("invalid from_date, expected YYYY-MM-DD")
+		}
+		filter.From = &t
+	}
+
+	if to := c.Query("to_date"); to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return filter, errors.New("invalid to_date, expected YYYY-MM-DD")
+		}
+		t = t.AddDate(0, 0, 1)
+		filter.To = &t
+	}
+
+	return filter, nil
+}
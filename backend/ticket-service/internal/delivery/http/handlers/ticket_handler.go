@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 
 	"ticket-service/internal/domain/models"
 	"ticket-service/internal/domain/services"
@@ -14,24 +17,38 @@ import (
 
 type TicketHandler struct {
 	ticketService *services.TicketService
+	spamService   *services.SpamService
+	uploadService *services.UploadService
+	intakeService *services.IntakeService
 }
 
-func NewTicketHandler(ticketService *services.TicketService) *TicketHandler {
+func NewTicketHandler(ticketService *services.TicketService, spamService *services.SpamService, uploadService *services.UploadService, intakeService *services.IntakeService) *TicketHandler {
 	return &TicketHandler{
 		ticketService: ticketService,
+		spamService:   spamService,
+		uploadService: uploadService,
+		intakeService: intakeService,
 	}
 }
 
 // CreateTicket создает новый тикет
 // @Summary Создать новый тикет
-// @Description Создает новый тикет с возможностью прикрепления файла
+// @Description Создает новый тикет; авторизованный пользователь может прикрепить файл, загруженный через /uploads, указав upload_id. Дополнительные поля категории передаются в fields и проверяются по схеме из /categories/{category}/fields
 // @Tags tickets
 // @Accept json
 // @Produce json
 // @Param request body models.CreateTicketRequest true "Данные тикета"
+// @Param X-Captcha-Token header string false "Токен капчи, обязателен для неавторизованных пользователей"
 // @Success 201 {object} models.Ticket
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 413 {object} FileRejectedResponse
+// @Failure 415 {object} FileRejectedResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
 // @Router /tickets [post]
 func (h *TicketHandler) CreateTicket(c *gin.Context) {
 	var req models.CreateTicketRequest
@@ -52,6 +69,10 @@ func (h *TicketHandler) CreateTicket(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid email format"})
 			return
 		}
+		if req.UploadID != "" {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "attaching uploads requires authentication"})
+			return
+		}
 	}
 
 	if req.Subject == "" || req.Question == "" || req.FullName == "" {
@@ -59,8 +80,33 @@ func (h *TicketHandler) CreateTicket(c *gin.Context) {
 		return
 	}
 
+	category := req.Category
+	if category == "" {
+		category = models.TicketCategoryGeneral
+	}
+	if !category.IsValid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid category"})
+		return
+	}
+
+	var fields map[string]any
+	if h.intakeService != nil {
+		var err error
+		fields, err = h.intakeService.ValidateFields(c.Request.Context(), category, req.Fields)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidIntakeFields) {
+				c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+				return
+			}
+			logger.Error("Failed to validate intake fields", "error", err, "category", category)
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to validate fields"})
+			return
+		}
+	}
+
 	// Создаём тикет
 	ticket := &models.Ticket{
+		Category:    category,
 		Subject:     req.Subject,
 		Question:    req.Question,
 		Email:       req.Email,
@@ -69,24 +115,81 @@ func (h *TicketHandler) CreateTicket(c *gin.Context) {
 		NotifyEmail: req.NotifyEmail,
 		NotifyTG:    req.NotifyTG,
 		Status:      models.TicketStatusNew,
+		Fields:      fields,
 	}
 
 	if exists {
-		ticket.UserID = userID.(int64)
+		ticket.UserID = userID.(uuid.UUID)
 		// Для авторизованных пользователей всегда включаем уведомления
 		ticket.NotifyEmail = true
+	} else if h.spamService != nil {
+		// Гостевые тикеты проходят квоты и антиспам; подозрительные сохраняются, но задерживаются
+		if err := h.spamService.Evaluate(c.Request.Context(), ticket, req.Website); err != nil {
+			if errors.Is(err, services.ErrSubmissionQuotaExceeded) {
+				c.JSON(http.StatusTooManyRequests, ErrorResponse{Error: err.Error()})
+				return
+			}
+			logger.Error("Failed to evaluate ticket for spam", "error", err)
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	// Завершенная загрузка проходит ту же проверку и антивирус, что и файл, переданный целиком
+	var fileReader io.Reader
+	var upload *models.Upload
+	if req.UploadID != "" {
+		var file io.ReadCloser
+		var ok bool
+		upload, file, ok = openUpload(c, h.uploadService, req.UploadID, ticket.UserID)
+		if !ok {
+			return
+		}
+		defer file.Close()
+
+		fileName, fileType := upload.Filename(), upload.FileType()
+		ticket.FileName = &fileName
+		ticket.FileType = &fileType
+		fileReader = file
 	}
 
 	// Сохраняем тикет
-	if err := h.ticketService.CreateTicket(c.Request.Context(), ticket, nil); err != nil {
+	if err := h.ticketService.CreateTicket(c.Request.Context(), ticket, fileReader); err != nil {
+		if respondFileRejected(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrFileContainsMalware) {
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error()})
+			return
+		}
 		logger.Error("Failed to create ticket", "error", err)
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
+	if upload != nil {
+		h.uploadService.ConsumeUpload(c.Request.Context(), upload)
+	}
+
+	if !exists {
+		c.JSON(http.StatusCreated, publicTicketView(ticket))
+		return
+	}
 
 	c.JSON(http.StatusCreated, ticket)
 }
 
+// publicTicketView скрывает решение антиспама: задержанный тикет выглядит как новый, оценка и
+// сигналы не отдаются, чтобы по ответу нельзя было подобрать обход
+func publicTicketView(ticket *models.Ticket) models.Ticket {
+	view := *ticket
+	if view.Status == models.TicketStatusSpam {
+		view.Status = models.TicketStatusNew
+	}
+	view.SpamScore = 0
+	view.SpamSignals = nil
+	return view
+}
+
 // isValidEmail проверяет корректность формата email
 func isValidEmail(email string) bool {
 	// Простая проверка формата email
@@ -101,7 +204,7 @@ func isValidEmail(email string) bool {
 
 // GetTicket получает тикет по ID
 // @Summary Получить тикет
-// @Description Получает информацию о тикете по его ID
+// @Description Получает информацию о тикете по его ID; тикет с владельцем доступен только владельцу и администраторам, решение антиспама видят только администраторы
 // @Tags tickets
 // @Produce json
 // @Param id path int true "ID тикета"
@@ -123,11 +226,19 @@ func (h *TicketHandler) GetTicket(c *gin.Context) {
 		return
 	}
 
-	if ticket == nil {
+	// После привязки к учетной записи гостевой доступ по номеру закрывается: тикет с владельцем
+	// видят только сам владелец и администраторы
+	isAdmin := c.GetBool("isAdmin")
+	if ticket == nil || (!isAdmin && ticket.UserID != uuid.Nil && ticket.UserID != currentUserID(c)) {
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: "ticket not found"})
 		return
 	}
 
+	if !isAdmin {
+		c.JSON(http.StatusOK, publicTicketView(ticket))
+		return
+	}
+
 	c.JSON(http.StatusOK, ticket)
 }
 
@@ -143,8 +254,8 @@ func (h *TicketHandler) GetTicket(c *gin.Context) {
 // @Failure 500 {object} ErrorResponse
 // @Router /tickets/user [get]
 func (h *TicketHandler) GetUserTickets(c *gin.Context) {
-	userID := c.GetInt64("userID")
-	if userID == 0 {
+	userID := currentUserID(c)
+	if userID == uuid.Nil {
 		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "unauthorized"})
 		return
 	}
@@ -170,6 +281,82 @@ func (h *TicketHandler) GetUserTickets(c *gin.Context) {
 	})
 }
 
+// GetClaimableTickets возвращает гостевые тикеты, которые пользователь может привязать
+// @Summary Гостевые тикеты пользователя
+// @Description Возвращает тикеты, оставленные без входа с подтвержденным адресом почты пользователя, - их можно привязать к учетной записи
+// @Tags tickets
+// @Produce json
+// @Success 200 {object} []models.Ticket
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tickets/user/claimable [get]
+func (h *TicketHandler) GetClaimableTickets(c *gin.Context) {
+	email, ok := verifiedEmail(c)
+	if !ok {
+		return
+	}
+
+	tickets, err := h.ticketService.GetClaimableTickets(c.Request.Context(), email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tickets": tickets,
+		"total":   len(tickets),
+	})
+}
+
+// ClaimTickets привязывает гостевые тикеты к учетной записи пользователя
+// @Summary Привязать гостевые тикеты
+// @Description Привязывает к пользователю гостевые тикеты с его подтвержденным адресом почты: перечисленные в ticket_ids или все сразу при all=true. Привязка записывается в историю тикета, после нее тикеты видны в /tickets/user
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param request body models.ClaimTicketsRequest true "Привязываемые тикеты"
+// @Success 200 {object} []models.Ticket
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tickets/user/claim [post]
+func (h *TicketHandler) ClaimTickets(c *gin.Context) {
+	var req models.ClaimTicketsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if req.All == (len(req.TicketIDs) > 0) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "either ticket_ids or all must be specified"})
+		return
+	}
+
+	email, ok := verifiedEmail(c)
+	if !ok {
+		return
+	}
+
+	userID := currentUserID(c)
+	tickets, err := h.ticketService.ClaimTickets(c.Request.Context(), userID, email, req.TicketIDs)
+	if err != nil {
+		if errors.Is(err, services.ErrTicketNotClaimable) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			return
+		}
+		logger.Error("Failed to claim tickets", "error", err, "userID", userID)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tickets": tickets,
+		"total":   len(tickets),
+	})
+}
+
 // GetAllTickets получает все тикеты (только для админов)
 // @Summary Получить все тикеты
 // @Description Получает список всех тикетов (только для администраторов)
@@ -177,6 +364,8 @@ func (h *TicketHandler) GetUserTickets(c *gin.Context) {
 // @Produce json
 // @Param page query int false "Номер страницы"
 // @Param page_size query int false "Размер страницы"
+// @Param status query string false "Статус; без фильтра задержанные антиспамом тикеты (spam) не возвращаются"
+// @Param fields[name] query string false "Значение дополнительного поля name; можно указать несколько полей"
 // @Success 200 {object} []models.Ticket
 // @Failure 401 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
@@ -194,6 +383,8 @@ func (h *TicketHandler) GetAllTickets(c *gin.Context) {
 	req := models.GetTicketsRequest{
 		Page:     page,
 		PageSize: pageSize,
+		Status:   models.TicketStatus(c.Query("status")),
+		Fields:   c.QueryMap("fields"),
 	}
 
 	tickets, total, err := h.ticketService.GetAllTickets(c.Request.Context(), req)
@@ -211,7 +402,7 @@ func (h *TicketHandler) GetAllTickets(c *gin.Context) {
 
 // UpdateTicketStatus обновляет статус тикета (только для админов)
 // @Summary Обновить статус тикета
-// @Description Обновляет статус тикета (только для администраторов)
+// @Description Обновляет статус тикета (только для администраторов). Недопустимая смена статуса, например закрытого тикета в waiting, отклоняется с 409
 // @Tags tickets
 // @Accept json
 // @Produce json
@@ -221,6 +412,8 @@ func (h *TicketHandler) GetAllTickets(c *gin.Context) {
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /tickets/{id}/status [put]
 func (h *TicketHandler) UpdateTicketStatus(c *gin.Context) {
@@ -241,16 +434,65 @@ func (h *TicketHandler) UpdateTicketStatus(c *gin.Context) {
 		return
 	}
 
-	adminID := c.GetInt64("userID")
+	adminID := currentUserID(c)
 	if err := h.ticketService.UpdateTicketStatus(c.Request.Context(), id, req.Status, adminID, req.Comment); err != nil {
-		logger.Error("Failed to update ticket status", "error", err, "ticketID", id)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		switch {
+		case errors.Is(err, services.ErrTicketNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrStatusTransition):
+			c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+		default:
+			logger.Error("Failed to update ticket status", "error", err, "ticketID", id)
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "status updated successfully"})
 }
 
+// AssignTicket назначает тикет администратору (только для админов)
+// @Summary Назначить тикет
+// @Description Назначает ответственного администратора (только для администраторов)
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param id path int true "ID тикета"
+// @Param request body models.AssignTicketRequest true "Администратор"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tickets/{id}/assign [put]
+func (h *TicketHandler) AssignTicket(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid ticket ID"})
+		return
+	}
+
+	var req models.AssignTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	actorID := currentUserID(c)
+	if err := h.ticketService.AssignTicket(c.Request.Context(), id, req.AdminID, actorID); err != nil {
+		if errors.Is(err, services.ErrTicketNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			return
+		}
+		logger.Error("Failed to assign ticket", "error", err, "ticketID", id)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "ticket assigned successfully"})
+}
+
 // SearchTickets ищет тикеты (только для админов)
 // @Summary Поиск тикетов
 // @Description Поиск тикетов по запросу (только для администраторов)
@@ -259,6 +501,7 @@ func (h *TicketHandler) UpdateTicketStatus(c *gin.Context) {
 // @Param query query string true "Поисковый запрос"
 // @Param page query int false "Номер страницы"
 // @Param page_size query int false "Размер страницы"
+// @Param fields[name] query string false "Значение дополнительного поля name; можно указать несколько полей"
 // @Success 200 {object} []models.Ticket
 // @Failure 401 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
@@ -282,6 +525,7 @@ func (h *TicketHandler) SearchTickets(c *gin.Context) {
 	req := models.GetTicketsRequest{
 		Page:     page,
 		PageSize: pageSize,
+		Fields:   c.QueryMap("fields"),
 	}
 
 	tickets, total, err := h.ticketService.SearchTickets(c.Request.Context(), query, req)
@@ -297,6 +541,91 @@ func (h *TicketHandler) SearchTickets(c *gin.Context) {
 	})
 }
 
+// ReopenTicket открывает закрытый тикет повторно по просьбе заявителя
+// @Summary Открыть тикет повторно
+// @Description Возвращает закрытый тикет пользователя в работу, сообщение записывается в историю тикета. Если срок повторного открытия (REOPEN_WINDOW_DAYS) истек, создается новый тикет со ссылкой на закрытый и возвращается 201
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param id path int true "ID тикета"
+// @Param request body models.ReopenTicketRequest true "Сообщение заявителя"
+// @Success 200 {object} models.Ticket
+// @Success 201 {object} models.Ticket
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tickets/user/{id}/reopen [post]
+func (h *TicketHandler) ReopenTicket(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid ticket ID"})
+		return
+	}
+
+	var req models.ReopenTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	message := strings.TrimSpace(req.Message)
+	if message == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "message is required"})
+		return
+	}
+
+	ticket, reopened, err := h.ticketService.ReopenTicket(c.Request.Context(), id, currentUserID(c), message)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTicketNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrTicketNotClosed), errors.Is(err, services.ErrStatusTransition):
+			c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+		default:
+			logger.Error("Failed to reopen ticket", "error", err, "ticketID", id)
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	if !reopened {
+		c.JSON(http.StatusCreated, ticket)
+		return
+	}
+	c.JSON(http.StatusOK, ticket)
+}
+
+// GetReopenStats возвращает статистику повторных открытий
+// @Summary Статистика повторных открытий
+// @Description Для тикетов, созданных за период, возвращает число закрывавшихся, открытых повторно, общее число повторных открытий, новых тикетов вместо открытия и долю открытых повторно (только для администраторов)
+// @Tags analytics
+// @Produce json
+// @Param from_date query string false "Начало периода (YYYY-MM-DD)"
+// @Param to_date query string false "Конец периода включительно (YYYY-MM-DD)"
+// @Success 200 {object} models.ReopenStats
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /analytics/reopens [get]
+func (h *TicketHandler) GetReopenStats(c *gin.Context) {
+	// Период задается так же, как для статистики CSAT
+	filter, err := parseCSATFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	stats, err := h.ticketService.GetReopenStats(c.Request.Context(), filter.From, filter.To)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
 // GetTicketHistory получает историю тикета
 // @Summary Получить историю тикета
 // @Description Получает историю изменений тикета
@@ -324,11 +653,233 @@ func (h *TicketHandler) GetTicketHistory(c *gin.Context) {
 	c.JSON(http.StatusOK, history)
 }
 
+// ReleaseSpam возвращает задержанные антиспамом тикеты в работу
+// @Summary Освободить тикеты из спама
+// @Description Переводит задержанные антиспамом тикеты в статус new; тикеты в других статусах пропускаются
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param request body models.BulkTicketsRequest true "ID тикетов"
+// @Success 200 {object} BulkResultResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tickets/spam/release [post]
+func (h *TicketHandler) ReleaseSpam(c *gin.Context) {
+	var req models.BulkTicketsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	released, err := h.ticketService.ReleaseSpam(c.Request.Context(), req.TicketIDs, currentUserID(c))
+	if err != nil {
+		logger.Error("Failed to release spam tickets", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, BulkResultResponse{Processed: released})
+}
+
+// PurgeSpam удаляет задержанные антиспамом тикеты
+// @Summary Удалить спам
+// @Description Окончательно удаляет задержанные антиспамом тикеты; тикеты в других статусах не затрагиваются
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param request body models.BulkTicketsRequest true "ID тикетов"
+// @Success 200 {object} BulkResultResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tickets/spam/purge [post]
+func (h *TicketHandler) PurgeSpam(c *gin.Context) {
+	var req models.BulkTicketsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	purged, err := h.ticketService.PurgeSpam(c.Request.Context(), req.TicketIDs)
+	if err != nil {
+		logger.Error("Failed to purge spam tickets", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, BulkResultResponse{Processed: purged})
+}
+
+// UpdateResponse редактирует ответ администратора
+// @Summary Изменить ответ на тикет
+// @Description Изменяет текст ответа; доступно автору ответа или старшему администратору. Прежний текст сохраняется в ревизиях
+// @Tags responses
+// @Accept json
+// @Produce json
+// @Param id path int true "ID ответа"
+// @Param request body models.UpdateResponseRequest true "Новый текст"
+// @Success 200 {object} models.Response
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /responses/{id} [put]
+func (h *TicketHandler) UpdateResponse(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid response ID"})
+		return
+	}
+
+	var req models.UpdateResponseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response, err := h.ticketService.UpdateResponse(c.Request.Context(), id, req.Message, currentUserID(c), c.GetBool("isSeniorAdmin"))
+	if err != nil {
+		h.respondResponseError(c, err, id)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteResponse удаляет ответ администратора
+// @Summary Удалить ответ на тикет
+// @Description Мягко удаляет ответ; доступно автору ответа или старшему администратору. Удаление фиксируется в журнале аудита
+// @Tags responses
+// @Produce json
+// @Param id path int true "ID ответа"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /responses/{id} [delete]
+func (h *TicketHandler) DeleteResponse(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid response ID"})
+		return
+	}
+
+	if err := h.ticketService.DeleteResponse(c.Request.Context(), id, currentUserID(c), c.GetBool("isSeniorAdmin")); err != nil {
+		h.respondResponseError(c, err, id)
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "response deleted successfully"})
+}
+
+// GetResponseRevisions возвращает прежние версии текста ответа
+// @Summary Ревизии ответа
+// @Description Возвращает прежние версии текста ответа от старых к новым (только для администраторов)
+// @Tags responses
+// @Produce json
+// @Param id path int true "ID ответа"
+// @Success 200 {object} []models.ResponseRevision
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /responses/{id}/revisions [get]
+func (h *TicketHandler) GetResponseRevisions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid response ID"})
+		return
+	}
+
+	revisions, err := h.ticketService.GetResponseRevisions(c.Request.Context(), id)
+	if err != nil {
+		h.respondResponseError(c, err, id)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// respondResponseError сопоставляет ошибки изменения ответа с HTTP статусами
+func (h *TicketHandler) respondResponseError(c *gin.Context, err error, id int64) {
+	switch {
+	case errors.Is(err, services.ErrResponseNotFound), errors.Is(err, services.ErrTicketNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrResponseForbidden):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrTicketClosed):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	default:
+		logger.Error("Failed to process response", "error", err, "responseID", id)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
+
+// currentUserID возвращает ID пользователя, сохраненный AuthMiddleware или OptionalAuth; у гостей uuid.Nil
+func currentUserID(c *gin.Context) uuid.UUID {
+	value, _ := c.Get("userID")
+	userID, _ := value.(uuid.UUID)
+	return userID
+}
+
+// verifiedEmail возвращает подтвержденный адрес почты из токена; без него отвечает 403
+func verifiedEmail(c *gin.Context) (string, bool) {
+	email := c.GetString("userEmail")
+	if email == "" {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "confirmed email is required"})
+		return "", false
+	}
+	return email, true
+}
+
 // ErrorResponse представляет структуру ответа с ошибкой
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
+// FileRejectedResponse отказ в приеме вложения с машиночитаемой причиной
+type FileRejectedResponse struct {
+	Error     string               `json:"error"`
+	Rejection models.FileRejection `json:"rejection"`
+}
+
+// respondFileRejected отвечает клиенту описанием отказа, если err - отказ в приеме вложения
+func respondFileRejected(c *gin.Context, err error) bool {
+	var rejected *services.FileRejectedError
+	if !errors.As(err, &rejected) {
+		return false
+	}
+
+	status := http.StatusUnsupportedMediaType
+	switch rejected.Rejection.Code {
+	case models.FileRejectionTooLarge:
+		status = http.StatusRequestEntityTooLarge
+	case models.FileRejectionEmpty:
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, FileRejectedResponse{Error: err.Error(), Rejection: rejected.Rejection})
+	return true
+}
+
+// MessageResponse представляет структуру ответа с сообщением
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// BulkResultResponse количество тикетов, обработанных массовой операцией
+type BulkResultResponse struct {
+	Processed int `json:"processed"`
+}
+
 // UpdateStatusRequest представляет структуру запроса на обновление статуса
 type UpdateStatusRequest struct {
 	Status  models.TicketStatus `json:"status" binding:"required"`
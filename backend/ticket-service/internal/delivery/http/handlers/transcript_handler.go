@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"ticket-service/internal/domain/services"
+	"ticket-service/internal/logger"
+)
+
+// TranscriptHandler отдает администраторам PDF с перепиской по тикету
+type TranscriptHandler struct {
+	transcriptService *services.TranscriptService
+}
+
+func NewTranscriptHandler(transcriptService *services.TranscriptService) *TranscriptHandler {
+	return &TranscriptHandler{
+		transcriptService: transcriptService,
+	}
+}
+
+// GetTranscript формирует PDF с перепиской по тикету
+// @Summary Переписка по тикету в PDF
+// @Description Формирует PDF для архива: метаданные тикета, история статусов, заявка и ответы, список вложений с SHA-256 (только для администраторов). В колонтитуле каждой страницы и в заголовке X-Transcript-SHA256 - контрольная сумма содержимого, которая записывается в журнал аудита действием ticket.transcript_generated
+// @Tags tickets
+// @Produce application/pdf
+// @Param id path int true "ID тикета"
+// @Success 200 {file} binary
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tickets/{id}/transcript [get]
+func (h *TranscriptHandler) GetTranscript(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid ticket ID"})
+		return
+	}
+
+	transcript, pdf, err := h.transcriptService.GenerateTranscript(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, services.ErrTicketNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			return
+		}
+		logger.Error("Failed to generate ticket transcript", "error", err, "ticketID", id)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to generate transcript"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="ticket-%d-transcript.pdf"`, id))
+	c.Header("X-Transcript-SHA256", transcript.Digest)
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/services"
+	"ticket-service/internal/logger"
+)
+
+const (
+	tusVersion        = "1.0.0"
+	tusExtensions     = "creation,expiration,termination"
+	offsetContentType = "application/offset+octet-stream"
+)
+
+// UploadHandler реализует протокол tus 1.0 (core, creation, expiration, termination) для загрузки
+// больших вложений частями с продолжением после обрыва соединения
+type UploadHandler struct {
+	uploadService *services.UploadService
+}
+
+func NewUploadHandler(uploadService *services.UploadService) *UploadHandler {
+	return &UploadHandler{
+		uploadService: uploadService,
+	}
+}
+
+// Options сообщает клиенту tus поддерживаемые версию, расширения и максимальный размер
+// @Summary Возможности сервера загрузок
+// @Tags uploads
+// @Success 204
+// @Router /uploads [options]
+func (h *UploadHandler) Options(c *gin.Context) {
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Tus-Version", tusVersion)
+	c.Header("Tus-Extension", tusExtensions)
+	c.Header("Tus-Max-Size", strconv.Itoa(services.MaxFileSize))
+	c.Status(http.StatusNoContent)
+}
+
+// CreateUpload начинает загрузку
+// @Summary Начать загрузку
+// @Description Создает загрузку размером Upload-Length; в Upload-Metadata обязателен filename. Адрес загрузки возвращается в Location
+// @Tags uploads
+// @Param Tus-Resumable header string true "Версия протокола, 1.0.0"
+// @Param Upload-Length header int true "Размер файла в байтах"
+// @Param Upload-Metadata header string true "Метаданные tus: filename и filetype в base64"
+// @Success 201
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 412 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /uploads [post]
+func (h *UploadHandler) CreateUpload(c *gin.Context) {
+	if !checkTusResumable(c) {
+		return
+	}
+
+	length, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid Upload-Length"})
+		return
+	}
+	metadata, err := parseUploadMetadata(c.GetHeader("Upload-Metadata"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	upload, err := h.uploadService.CreateUpload(c.Request.Context(), currentUserID(c), length, metadata)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrFileTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrUploadInvalidLength), errors.Is(err, services.ErrUploadFilenameNeeded):
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		default:
+			logger.Error("Failed to create upload", "error", err)
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to create upload"})
+		}
+		return
+	}
+
+	c.Header("Location", strings.TrimSuffix(c.Request.URL.Path, "/")+"/"+upload.ID)
+	setUploadExpires(c, upload)
+	c.Status(http.StatusCreated)
+}
+
+// GetUploadOffset возвращает смещение, с которого нужно продолжить загрузку
+// @Summary Состояние загрузки
+// @Tags uploads
+// @Param id path string true "ID загрузки"
+// @Param Tus-Resumable header string true "Версия протокола, 1.0.0"
+// @Success 200
+// @Failure 404
+// @Router /uploads/{id} [head]
+func (h *UploadHandler) GetUploadOffset(c *gin.Context) {
+	if !checkTusResumable(c) {
+		return
+	}
+
+	upload, err := h.uploadService.GetUpload(c.Request.Context(), c.Param("id"), currentUserID(c))
+	if err != nil {
+		if !errors.Is(err, services.ErrUploadNotFound) {
+			logger.Error("Failed to get upload", "error", err, "uploadID", c.Param("id"))
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	c.Header("Upload-Metadata", formatUploadMetadata(upload.Metadata))
+	setUploadExpires(c, upload)
+	c.Status(http.StatusOK)
+}
+
+// AppendChunk дописывает часть файла
+// @Summary Загрузить часть файла
+// @Description Тело запроса дописывается с позиции Upload-Offset, которая должна совпадать с уже полученным размером
+// @Tags uploads
+// @Accept application/offset+octet-stream
+// @Param id path string true "ID загрузки"
+// @Param Tus-Resumable header string true "Версия протокола, 1.0.0"
+// @Param Upload-Offset header int true "Смещение части"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 423 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /uploads/{id} [patch]
+func (h *UploadHandler) AppendChunk(c *gin.Context) {
+	if !checkTusResumable(c) {
+		return
+	}
+	if c.ContentType() != offsetContentType {
+		c.JSON(http.StatusUnsupportedMediaType, ErrorResponse{Error: "Content-Type must be " + offsetContentType})
+		return
+	}
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid Upload-Offset"})
+		return
+	}
+
+	upload, err := h.uploadService.AppendChunk(c.Request.Context(), c.Param("id"), currentUserID(c), offset, c.Request.Body)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUploadNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrUploadOffsetMismatch):
+			c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrUploadLocked):
+			c.JSON(http.StatusLocked, ErrorResponse{Error: err.Error()})
+		default:
+			// Полученная до обрыва часть сохранена, клиент узнает новое смещение через HEAD
+			logger.Warn("Upload chunk interrupted", "error", err, "uploadID", c.Param("id"))
+			if upload != nil {
+				c.Header("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+			}
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to store upload chunk"})
+		}
+		return
+	}
+
+	c.Header("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	setUploadExpires(c, upload)
+	c.Status(http.StatusNoContent)
+}
+
+// TerminateUpload прерывает загрузку
+// @Summary Прервать загрузку
+// @Tags uploads
+// @Param id path string true "ID загрузки"
+// @Param Tus-Resumable header string true "Версия протокола, 1.0.0"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Failure 423 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /uploads/{id} [delete]
+func (h *UploadHandler) TerminateUpload(c *gin.Context) {
+	if !checkTusResumable(c) {
+		return
+	}
+
+	if err := h.uploadService.TerminateUpload(c.Request.Context(), c.Param("id"), currentUserID(c)); err != nil {
+		switch {
+		case errors.Is(err, services.ErrUploadNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrUploadLocked):
+			c.JSON(http.StatusLocked, ErrorResponse{Error: err.Error()})
+		default:
+			logger.Error("Failed to terminate upload", "error", err, "uploadID", c.Param("id"))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to terminate upload"})
+		}
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// checkTusResumable добавляет Tus-Resumable в ответ и отклоняет запросы другой версии протокола
+func checkTusResumable(c *gin.Context) bool {
+	c.Header("Tus-Resumable", tusVersion)
+	if c.GetHeader("Tus-Resumable") != tusVersion {
+		c.Header("Tus-Version", tusVersion)
+		c.AbortWithStatusJSON(http.StatusPreconditionFailed, ErrorResponse{Error: "unsupported tus version"})
+		return false
+	}
+	return true
+}
+
+func setUploadExpires(c *gin.Context, upload *models.Upload) {
+	c.Header("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+}
+
+// parseUploadMetadata разбирает Upload-Metadata: пары "ключ значение-в-base64" через запятую
+func parseUploadMetadata(raw string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(pair, " ")
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Upload-Metadata value for %q", key)
+		}
+		metadata[key] = string(decoded)
+	}
+	return metadata, nil
+}
+
+func formatUploadMetadata(metadata map[string]string) string {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+" "+base64.StdEncoding.EncodeToString([]byte(metadata[key])))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// openUpload открывает завершенную загрузку пользователя для прикрепления; при ошибке сам отвечает клиенту
+func openUpload(c *gin.Context, uploadService *services.UploadService, id string, ownerID uuid.UUID) (*models.Upload, io.ReadCloser, bool) {
+	if uploadService == nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "resumable uploads are not available"})
+		return nil, nil, false
+	}
+
+	upload, file, err := uploadService.OpenCompleted(c.Request.Context(), id, ownerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUploadNotFound):
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "upload not found or expired"})
+		case errors.Is(err, services.ErrUploadIncomplete):
+			c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+		default:
+			logger.Error("Failed to open upload", "error", err, "uploadID", id)
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to open upload"})
+		}
+		return nil, nil, false
+	}
+	return upload, file, true
+}
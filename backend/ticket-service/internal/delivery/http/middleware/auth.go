@@ -3,12 +3,12 @@ package middleware
 import (
 	"errors"
 	"net/http"
-	"os"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 
+	"ticket-service/internal/domain/models"
 	"ticket-service/internal/logger"
 )
 
@@ -16,10 +16,15 @@ const (
 	authCookieName = "access_token"
 	userIDKey      = "userID"
 	isAdminKey     = "isAdmin"
+	isSeniorKey    = "isSeniorAdmin"
+	userEmailKey   = "userEmail"
+
+	roleAdmin     = "admin"
+	roleRootAdmin = "root_admin"
 )
 
-// AuthMiddleware проверяет JWT токен из куки
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware проверяет JWT токен из куки; secret - JWT_SECRET, которым токены подписывает private-service
+func AuthMiddleware(secret string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token, err := c.Cookie(authCookieName)
 		if err != nil {
@@ -28,7 +33,7 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		claims, err := validateToken(token)
+		claims, err := validateToken(token, secret)
 		if err != nil {
 			logger.Error("Invalid token", "error", err)
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
@@ -36,86 +41,117 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// Сохраняем информацию о пользователе в контексте
-		c.Set(userIDKey, claims.UserID)
-		c.Set(isAdminKey, claims.IsAdmin)
+		setIdentity(c, claims)
 
 		c.Next()
 	}
 }
 
-// AdminOnly проверяет, что пользователь является администратором
-func AdminOnly() gin.HandlerFunc {
+// OptionalAuth распознает пользователя, если кука с токеном есть, но пропускает и гостей.
+// Недействительный токен не блокирует запрос: он обрабатывается как гостевой
+func OptionalAuth(secret string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		isAdmin, exists := c.Get(isAdminKey)
-		if !exists || !isAdmin.(bool) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
-			c.Abort()
-			return
+		if token, err := c.Cookie(authCookieName); err == nil && token != "" {
+			if claims, err := validateToken(token, secret); err == nil {
+				setIdentity(c, claims)
+			} else {
+				logger.Info("Ignoring invalid token on public route", "error", err)
+			}
 		}
 
 		c.Next()
 	}
 }
 
-// Claims представляет структуру JWT токена
-type Claims struct {
-	UserID    int64     `json:"user_id"`
-	IsAdmin   bool      `json:"is_admin"`
-	ExpiresAt time.Time `json:"exp"`
-	IssuedAt  time.Time `json:"iat"`
-	NotBefore time.Time `json:"nbf"`
-	Issuer    string    `json:"iss"`
-	Subject   string    `json:"sub"`
-	Audience  []string  `json:"aud"`
+// setIdentity сохраняет информацию о пользователе в контексте
+func setIdentity(c *gin.Context, claims *Claims) {
+	c.Set(userIDKey, claims.UserID)
+	// root_admin - старший администратор
+	isAdmin := claims.isAdmin()
+	c.Set(isAdminKey, isAdmin)
+	c.Set(isSeniorKey, claims.Role == roleRootAdmin)
+	// Адрес используется для сопоставления с гостевыми данными, поэтому берется только подтвержденный
+	if claims.EmailVerified && claims.Email != "" {
+		c.Set(userEmailKey, claims.Email)
+	}
+
+	actorType := models.ActorTypeUser
+	if isAdmin {
+		actorType = models.ActorTypeAdmin
+	}
+	userID := claims.UserID
+	setActor(c, models.Actor{ID: &userID, Type: actorType, IP: c.ClientIP()})
 }
 
-// GetExpirationTime возвращает время истечения токена
-func (c *Claims) GetExpirationTime() (*jwt.NumericDate, error) {
-	return jwt.NewNumericDate(c.ExpiresAt), nil
+// Actor помечает запрос как анонимный для журнала аудита; AuthMiddleware уточняет инициатора
+func Actor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		setActor(c, models.Actor{Type: models.ActorTypeGuest, IP: c.ClientIP()})
+		c.Next()
+	}
 }
 
-// GetNotBefore возвращает время начала действия токена
-func (c *Claims) GetNotBefore() (*jwt.NumericDate, error) {
-	return jwt.NewNumericDate(c.NotBefore), nil
+// setActor передает инициатора в context.Context запроса, который получают сервисы
+func setActor(c *gin.Context, actor models.Actor) {
+	c.Request = c.Request.WithContext(models.ContextWithActor(c.Request.Context(), actor))
 }
 
-// GetIssuedAt возвращает время создания токена
-func (c *Claims) GetIssuedAt() (*jwt.NumericDate, error) {
-	return jwt.NewNumericDate(c.IssuedAt), nil
+// AdminOnly проверяет, что пользователь является администратором
+func AdminOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		isAdmin, exists := c.Get(isAdminKey)
+		if !exists || !isAdmin.(bool) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
 }
 
-// GetIssuer возвращает издателя токена
-func (c *Claims) GetIssuer() (string, error) {
-	return c.Issuer, nil
+// RootAdminOnly пропускает только старших администраторов (роль root_admin)
+func RootAdminOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !c.GetBool(isSeniorKey) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
 }
 
-// GetSubject возвращает субъект токена
-func (c *Claims) GetSubject() (string, error) {
-	return c.Subject, nil
+// Claims токен, который выдает private-service: UUID пользователя, роль user, admin или root_admin
+// и адрес почты с признаком подтверждения
+type Claims struct {
+	UserID        uuid.UUID `json:"user_id"`
+	Role          string    `json:"role"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"email_verified"`
+	jwt.RegisteredClaims
 }
 
-// GetAudience возвращает аудиторию токена
-func (c *Claims) GetAudience() (jwt.ClaimStrings, error) {
-	return jwt.ClaimStrings(c.Audience), nil
+// isAdmin администраторами считаются роли admin и root_admin
+func (c *Claims) isAdmin() bool {
+	return c.Role == roleAdmin || c.Role == roleRootAdmin
 }
 
 // validateToken проверяет JWT токен и возвращает claims
-func validateToken(tokenString string) (*Claims, error) {
-	// Получаем секретный ключ из переменных окружения
-	secretKey := []byte(os.Getenv("JWT_SECRET_KEY"))
+func validateToken(tokenString string, secret string) (*Claims, error) {
+	secretKey := []byte(secret)
 	if len(secretKey) == 0 {
 		return nil, errors.New("JWT secret key is not set")
 	}
 
-	// Парсим токен
+	// Парсим токен: принимается только HS256 и только с указанным сроком действия
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// Проверяем метод подписи
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
 		return secretKey, nil
-	})
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
 
 	if err != nil {
 		return nil, err
@@ -132,9 +168,10 @@ func validateToken(tokenString string) (*Claims, error) {
 		return nil, errors.New("invalid token claims")
 	}
 
-	// Проверяем срок действия токена
-	if claims.ExpiresAt.Before(time.Now()) {
-		return nil, errors.New("token has expired")
+	// Срок действия проверяет jwt.ParseWithClaims, токен без exp отклоняется там же;
+	// токен без пользователя не принимается
+	if claims.UserID == uuid.Nil {
+		return nil, errors.New("token has no user ID")
 	}
 
 	return claims, nil
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const testJWTSecret = "test-secret"
+
+func signToken(t *testing.T, method jwt.SigningMethod, claims *Claims, secret string) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, claims).SignedString([]byte(secret))
+	assert.NoError(t, err)
+	return token
+}
+
+func TestValidateToken(t *testing.T) {
+	userID := uuid.New()
+	expires := jwt.NewNumericDate(time.Now().Add(time.Hour))
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, &Claims{UserID: userID, RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: expires}}).
+		SignedString(jwt.UnsafeAllowNoneSignatureType)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{
+			name:  "valid token",
+			token: signToken(t, jwt.SigningMethodHS256, &Claims{UserID: userID, Role: roleAdmin, RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: expires}}, testJWTSecret),
+		},
+		{
+			name:    "without expiry",
+			token:   signToken(t, jwt.SigningMethodHS256, &Claims{UserID: userID, Role: roleAdmin}, testJWTSecret),
+			wantErr: true,
+		},
+		{
+			name:    "expired",
+			token:   signToken(t, jwt.SigningMethodHS256, &Claims{UserID: userID, RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))}}, testJWTSecret),
+			wantErr: true,
+		},
+		{
+			name:    "other HMAC method",
+			token:   signToken(t, jwt.SigningMethodHS512, &Claims{UserID: userID, RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: expires}}, testJWTSecret),
+			wantErr: true,
+		},
+		{
+			name:    "unsigned",
+			token:   unsigned,
+			wantErr: true,
+		},
+		{
+			name:    "signed with another secret",
+			token:   signToken(t, jwt.SigningMethodHS256, &Claims{UserID: userID, RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: expires}}, "other-secret"),
+			wantErr: true,
+		},
+		{
+			name:    "without user ID",
+			token:   signToken(t, jwt.SigningMethodHS256, &Claims{RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: expires}}, testJWTSecret),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := validateToken(tt.token, testJWTSecret)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, userID, claims.UserID)
+		})
+	}
+}
+
+func TestValidateTokenRequiresSecret(t *testing.T) {
+	token := signToken(t, jwt.SigningMethodHS256, &Claims{UserID: uuid.New(), RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))}}, "")
+
+	_, err := validateToken(token, "")
+	assert.Error(t, err)
+}
+
+func TestAuthMiddlewareRejectsTokenWithoutExpiry(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/", AuthMiddleware(testJWTSecret), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	token := signToken(t, jwt.SigningMethodHS256, &Claims{UserID: uuid.New(), Role: roleAdmin}, testJWTSecret)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: authCookieName, Value: token})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
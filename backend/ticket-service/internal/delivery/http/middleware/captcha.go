@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"ticket-service/internal/domain/services"
+	"ticket-service/internal/infrastructure/metrics"
+	"ticket-service/internal/logger"
+)
+
+// CaptchaHeader заголовок, в котором клиент передает токен капчи
+const CaptchaHeader = "X-Captcha-Token"
+
+// captchaRejectedKey отмечает в контексте запрос, отклоненный капчей, чтобы Idempotency не сохранял ответ
+const captchaRejectedKey = "captchaRejected"
+
+// CaptchaConfig параметры проверки капчи
+type CaptchaConfig struct {
+	// MinScore минимальная оценка для провайдеров, возвращающих score
+	MinScore float64
+	// Action ожидаемое действие reCAPTCHA v3; пустое значение не проверяется
+	Action string
+}
+
+// Captcha требует пройденную капчу от неавторизованных клиентов.
+// Авторизованные пользователи (userID в контексте, см. OptionalAuth) проходят без проверки.
+// Если verifier не задан, проверка отключена
+func Captcha(verifier services.ICaptchaVerifier, config CaptchaConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if verifier == nil {
+			c.Next()
+			return
+		}
+
+		if _, authenticated := c.Get(userIDKey); authenticated {
+			metrics.CaptchaVerificationsTotal.WithLabelValues("bypassed").Inc()
+			c.Next()
+			return
+		}
+
+		token := c.GetHeader(CaptchaHeader)
+		if token == "" {
+			metrics.CaptchaVerificationsTotal.WithLabelValues("missing").Inc()
+			rejectCaptcha(c, http.StatusBadRequest, "captcha token is required")
+			return
+		}
+
+		result, err := verifier.Verify(c.Request.Context(), token, c.ClientIP())
+		if err != nil {
+			// Без ответа провайдера не пропускаем гостей, иначе сбой открывает форму для ботов
+			logger.Error("Captcha verification failed", "error", err)
+			metrics.CaptchaVerificationsTotal.WithLabelValues("error").Inc()
+			rejectCaptcha(c, http.StatusServiceUnavailable, "captcha verification is unavailable")
+			return
+		}
+
+		if !result.Success {
+			logger.Info("Captcha rejected", "errorCodes", result.ErrorCodes, "ip", c.ClientIP())
+			metrics.CaptchaVerificationsTotal.WithLabelValues("failed").Inc()
+			rejectCaptcha(c, http.StatusForbidden, "captcha verification failed")
+			return
+		}
+
+		if config.Action != "" && result.Action != "" && result.Action != config.Action {
+			logger.Info("Captcha action mismatch", "action", result.Action, "expected", config.Action)
+			metrics.CaptchaVerificationsTotal.WithLabelValues("failed").Inc()
+			rejectCaptcha(c, http.StatusForbidden, "captcha verification failed")
+			return
+		}
+
+		if result.Score != nil && *result.Score < config.MinScore {
+			logger.Info("Captcha score below threshold", "score", *result.Score, "minScore", config.MinScore)
+			metrics.CaptchaVerificationsTotal.WithLabelValues("low_score").Inc()
+			rejectCaptcha(c, http.StatusForbidden, "captcha verification failed")
+			return
+		}
+
+		metrics.CaptchaVerificationsTotal.WithLabelValues("passed").Inc()
+		c.Next()
+	}
+}
+
+func rejectCaptcha(c *gin.Context, status int, message string) {
+	c.Set(captchaRejectedKey, true)
+	c.JSON(status, gin.H{"error": message})
+	c.Abort()
+}
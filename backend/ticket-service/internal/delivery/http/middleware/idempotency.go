@@ -0,0 +1,223 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"ticket-service/internal/infrastructure/metrics"
+	"ticket-service/internal/logger"
+)
+
+// IdempotencyKeyHeader заголовок, которым клиент помечает повторы одного и того же запроса
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyReplayedHeader выставляется в ответе, восстановленном из сохраненного результата
+const IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength ограничивает длину ключа, чтобы клиент не раздувал ключи Redis
+const maxIdempotencyKeyLength = 255
+
+// idempotencyFormOverhead запас сверх размера вложения на остальные поля формы
+const idempotencyFormOverhead = 1 << 20
+
+// IdempotencyConfig параметры хранения результатов идемпотентных запросов
+type IdempotencyConfig struct {
+	// TTL сколько хранится результат запроса
+	TTL time.Duration
+	// LockTimeout сколько ключ считается занятым выполняющимся запросом;
+	// по истечении повтор снова выполнит запрос, если первый так и не завершился
+	LockTimeout time.Duration
+	// MaxFileSize наибольший размер вложения; тело запроса читается в память целиком для отпечатка,
+	// поэтому больше MaxFileSize с запасом на поля формы не принимается. 0 - без ограничения
+	MaxFileSize int64
+}
+
+// idempotencyRecord запись о запросе в Redis
+type idempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// responseRecorder копирует тело ответа, чтобы сохранить его для повторов
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency сохраняет результат запроса с заголовком Idempotency-Key.
+// Повтор с тем же ключом и тем же телом получает исходный ответ без повторного выполнения,
+// повтор с другим телом или во время выполнения первого запроса - 409.
+// Ключи авторизованных пользователей изолированы по userID, поэтому middleware ставится после OptionalAuth.
+// Ответы 5xx и отказы капчи не сохраняются: клиент может повторить запрос с тем же ключом.
+// Запросы без заголовка и запросы при недоступном Redis выполняются как обычно
+func Idempotency(redisClient *redis.Client, config IdempotencyConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || redisClient == nil {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "idempotency key is too long"})
+			c.Abort()
+			return
+		}
+
+		if config.MaxFileSize > 0 {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.MaxFileSize+idempotencyFormOverhead)
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body is too large"})
+				c.Abort()
+				return
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		redisKey := idempotencyRedisKey(c, key)
+		fingerprint := requestFingerprint(c, body)
+
+		pending, _ := json.Marshal(idempotencyRecord{Fingerprint: fingerprint})
+		acquired, err := redisClient.SetNX(ctx, redisKey, pending, config.LockTimeout).Result()
+		if err != nil {
+			logger.Error("Failed to acquire idempotency key", "error", err)
+			c.Next()
+			return
+		}
+
+		if !acquired {
+			replayIdempotent(c, redisClient, redisKey, fingerprint)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		// Отказ капчи не сохраняется: повтор с тем же ключом и новым токеном должен пройти проверку заново
+		status := c.Writer.Status()
+		if status >= http.StatusInternalServerError || c.GetBool(captchaRejectedKey) {
+			if err := redisClient.Del(ctx, redisKey).Err(); err != nil {
+				logger.Error("Failed to release idempotency key", "error", err)
+			}
+			return
+		}
+
+		record, _ := json.Marshal(idempotencyRecord{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			Status:      status,
+			ContentType: c.Writer.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		})
+		if err := redisClient.Set(ctx, redisKey, record, config.TTL).Err(); err != nil {
+			logger.Error("Failed to store idempotent response", "error", err)
+			return
+		}
+		metrics.IdempotentRequestsTotal.WithLabelValues("stored").Inc()
+	}
+}
+
+// replayIdempotent отвечает на повтор запроса по сохраненной записи
+func replayIdempotent(c *gin.Context, redisClient *redis.Client, redisKey, fingerprint string) {
+	data, err := redisClient.Get(c.Request.Context(), redisKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// Первый запрос завершился ошибкой и освободил ключ между SETNX и GET
+		c.JSON(http.StatusConflict, gin.H{"error": "request with this idempotency key is being processed, retry later"})
+		c.Abort()
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to get idempotent response", "error", err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "idempotency storage is unavailable"})
+		c.Abort()
+		return
+	}
+
+	var record idempotencyRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		logger.Error("Failed to decode idempotent response", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to decode stored response"})
+		c.Abort()
+		return
+	}
+
+	if record.Fingerprint != fingerprint {
+		metrics.IdempotentRequestsTotal.WithLabelValues("conflict").Inc()
+		c.JSON(http.StatusConflict, gin.H{"error": "idempotency key was already used with a different request"})
+		c.Abort()
+		return
+	}
+
+	if !record.Completed {
+		metrics.IdempotentRequestsTotal.WithLabelValues("in_progress").Inc()
+		c.JSON(http.StatusConflict, gin.H{"error": "request with this idempotency key is being processed, retry later"})
+		c.Abort()
+		return
+	}
+
+	metrics.IdempotentRequestsTotal.WithLabelValues("replayed").Inc()
+	c.Header(IdempotencyReplayedHeader, "true")
+	c.Data(record.Status, record.ContentType, record.Body)
+	c.Abort()
+}
+
+// idempotencyRedisKey изолирует ключи по пользователю: гости делят общее пространство ключей
+func idempotencyRedisKey(c *gin.Context, key string) string {
+	owner := "guest"
+	if userID, ok := c.Get(userIDKey); ok {
+		owner = fmt.Sprintf("user:%v", userID)
+	}
+	return fmt.Sprintf("idempotency:%s:%s:%s", c.FullPath(), owner, key)
+}
+
+// requestFingerprint хэширует метод, маршрут и тело запроса.
+// JSON приводится к каноническому виду, чтобы порядок полей и пробелы не влияли на отпечаток
+func requestFingerprint(c *gin.Context, body []byte) string {
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if canonical, err := json.Marshal(payload); err == nil {
+			body = canonical
+		}
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(c.FullPath()))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
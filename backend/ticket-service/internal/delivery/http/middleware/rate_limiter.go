@@ -1,69 +1,113 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
-	"time"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
-	"golang.org/x/time/rate"
+
+	"ticket-service/internal/infrastructure/metrics"
+	"ticket-service/internal/logger"
+)
+
+// Категории инициаторов запросов, для которых задаются отдельные ограничения
+const (
+	PrincipalGuest = "guest"
+	PrincipalUser  = "user"
+	PrincipalAdmin = "admin"
 )
 
-// RateLimiterConfig конфигурация для rate limiter
+// RateLimitPolicy корзина токенов: Burst запросов подряд, затем PerMinute запросов в минуту.
+// Нулевой PerMinute отключает ограничение
+type RateLimitPolicy struct {
+	PerMinute int
+	Burst     int
+}
+
+// RateLimiterConfig ограничения для гостей (по IP), пользователей и администраторов (по ID).
+// Routes переопределяет ограничения для маршрутов вида "POST /api/v1/tickets"; у такого маршрута
+// своя корзина, остальные маршруты расходуют общую
 type RateLimiterConfig struct {
-	RequestsPerMinute int
-	BurstSize         int
+	Guest  RateLimitPolicy
+	User   RateLimitPolicy
+	Admin  RateLimitPolicy
+	Routes map[string]map[string]RateLimitPolicy
 }
 
-// RateLimiter middleware для ограничения количества запросов
-func RateLimiter(redisClient *redis.Client, config RateLimiterConfig) gin.HandlerFunc {
-	// Создаем rate limiter для каждого IP
-	limiters := make(map[string]*rate.Limiter)
+// Заголовки ограничения частоты по черновику IETF RateLimit header fields
+const (
+	rateLimitLimitHeader     = "RateLimit-Limit"
+	rateLimitRemainingHeader = "RateLimit-Remaining"
+	rateLimitResetHeader     = "RateLimit-Reset"
+	retryAfterHeader         = "Retry-After"
+)
 
-	return func(c *gin.Context) {
-		ip := c.ClientIP()
+// tokenBucketScript атомарно пополняет корзину по прошедшему времени и списывает токен.
+// Время берется из Redis, чтобы реплики с расходящимися часами делили одну корзину корректно.
+// Возвращает: разрешен ли запрос, остаток токенов, мс до появления токена, мс до полной корзины
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) * 1000 + math.floor(tonumber(clock[2]) / 1000)
 
-		// Получаем или создаем limiter для IP
-		limiter, exists := limiters[ip]
-		if !exists {
-			limiter = rate.NewLimiter(rate.Limit(config.RequestsPerMinute)/60, config.BurstSize)
-			limiters[ip] = limiter
-		}
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
 
-		// Проверяем, не превышен ли лимит
-		if !limiter.Allow() {
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error": "Too many requests. Please try again later.",
-			})
-			c.Abort()
-			return
-		}
+local allowed = 0
+local retry_after = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry_after = math.ceil((1 - tokens) / rate)
+end
 
-		c.Next()
-	}
-}
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate))
+return {allowed, math.floor(tokens), retry_after, math.ceil((burst - tokens) / rate)}
+`)
 
-// RedisRateLimiter middleware для ограничения количества запросов с использованием Redis
-func RedisRateLimiter(redisClient *redis.Client, config RateLimiterConfig) gin.HandlerFunc {
+// RedisRateLimiter ограничивает частоту запросов корзиной токенов в Redis, общей для всех реплик.
+// Middleware стоит до авторизации маршрутов, поэтому инициатор определяется по куке с токеном;
+// без действительного токена запрос считается гостевым. При недоступном Redis запросы пропускаются
+func RedisRateLimiter(redisClient *redis.Client, config RateLimiterConfig, jwtSecret string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		key := "rate_limit:" + ip
+		route := c.Request.Method + " " + c.FullPath()
+		principal, subject := rateLimitPrincipal(c, jwtSecret)
 
-		// Увеличиваем счетчик запросов
-		count, err := redisClient.Incr(c.Request.Context(), key).Result()
-		if err != nil {
-			// Пропускаем запрос в случае ошибки Redis
+		policy, scope := config.policy(route, principal)
+		if policy.PerMinute <= 0 || redisClient == nil {
 			c.Next()
 			return
 		}
+		burst := max(policy.Burst, 1)
 
-		// Устанавливаем TTL для ключа (1 минута)
-		if count == 1 {
-			redisClient.Expire(c.Request.Context(), key, time.Minute)
+		key := fmt.Sprintf("rate_limit:%s:%s:%s", scope, principal, subject)
+		ratePerMs := float64(policy.PerMinute) / 60000
+		result, err := tokenBucketScript.Run(c.Request.Context(), redisClient, []string{key}, ratePerMs, burst).Int64Slice()
+		if err != nil || len(result) != 4 {
+			logger.Error("Rate limiter is unavailable", "error", err, "key", key)
+			c.Next()
+			return
 		}
+		allowed, remaining, retryAfterMs, resetMs := result[0] == 1, result[1], result[2], result[3]
+
+		c.Header(rateLimitLimitHeader, strconv.Itoa(burst))
+		c.Header(rateLimitRemainingHeader, strconv.FormatInt(remaining, 10))
+		c.Header(rateLimitResetHeader, strconv.FormatInt(ceilSeconds(resetMs), 10))
 
-		// Проверяем, не превышен ли лимит
-		if count > int64(config.RequestsPerMinute) {
+		if !allowed {
+			metrics.RateLimitRejectionsTotal.WithLabelValues(rateLimitRouteLabel(c), principal).Inc()
+			c.Header(retryAfterHeader, strconv.FormatInt(ceilSeconds(retryAfterMs), 10))
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error": "Too many requests. Please try again later.",
 			})
@@ -74,3 +118,46 @@ func RedisRateLimiter(redisClient *redis.Client, config RateLimiterConfig) gin.H
 		c.Next()
 	}
 }
+
+// policy возвращает ограничение для маршрута и инициатора и корзину, из которой списываются токены
+func (config RateLimiterConfig) policy(route, principal string) (RateLimitPolicy, string) {
+	if policy, ok := config.Routes[route][principal]; ok {
+		return policy, route
+	}
+
+	switch principal {
+	case PrincipalAdmin:
+		return config.Admin, "default"
+	case PrincipalUser:
+		return config.User, "default"
+	default:
+		return config.Guest, "default"
+	}
+}
+
+// rateLimitPrincipal определяет категорию инициатора и идентификатор его корзины
+func rateLimitPrincipal(c *gin.Context, jwtSecret string) (string, string) {
+	if token, err := c.Cookie(authCookieName); err == nil && token != "" {
+		if claims, err := validateToken(token, jwtSecret); err == nil {
+			principal := PrincipalUser
+			if claims.isAdmin() {
+				principal = PrincipalAdmin
+			}
+			return principal, claims.UserID.String()
+		}
+	}
+	return PrincipalGuest, c.ClientIP()
+}
+
+// rateLimitRouteLabel шаблон маршрута для метрик; пути без маршрута сводятся к одному значению,
+// чтобы перебор адресов не раздувал число рядов
+func rateLimitRouteLabel(c *gin.Context) string {
+	if c.FullPath() == "" {
+		return "unmatched"
+	}
+	return c.Request.Method + " " + c.FullPath()
+}
+
+func ceilSeconds(ms int64) int64 {
+	return (ms + 999) / 1000
+}
@@ -9,13 +9,29 @@ import (
 
 	"ticket-service/internal/delivery/http/handlers"
 	"ticket-service/internal/delivery/http/middleware"
+	"ticket-service/internal/domain/services"
 )
 
 // SetupRouter настраивает маршруты приложения
 func SetupRouter(
 	ticketHandler *handlers.TicketHandler,
 	responseHandler *handlers.ResponseHandler,
+	surveyHandler *handlers.SurveyHandler,
+	eventHandler *handlers.EventHandler,
+	auditHandler *handlers.AuditHandler,
+	privacyHandler *handlers.PrivacyHandler,
+	webhookHandler *handlers.WebhookHandler,
+	fileHandler *handlers.FileHandler,
+	uploadHandler *handlers.UploadHandler,
+	previewHandler *handlers.PreviewHandler,
+	transcriptHandler *handlers.TranscriptHandler,
+	intakeHandler *handlers.IntakeHandler,
 	redisClient *redis.Client,
+	captchaVerifier services.ICaptchaVerifier,
+	captchaConfig middleware.CaptchaConfig,
+	idempotencyConfig middleware.IdempotencyConfig,
+	rateLimiterConfig middleware.RateLimiterConfig,
+	jwtSecret string,
 ) *gin.Engine {
 	// Используем gin.New() вместо gin.Default() чтобы убрать стандартные логи
 	router := gin.New()
@@ -26,12 +42,11 @@ func SetupRouter(
 	// Добавляем Prometheus middleware
 	router.Use(middleware.PrometheusMiddleware())
 
-	// Добавляем rate limiter
-	rateLimiterConfig := middleware.RateLimiterConfig{
-		RequestsPerMinute: 60, // 1 запрос в секунду
-		BurstSize:         10, // Разрешаем до 10 запросов одновременно
-	}
-	router.Use(middleware.RedisRateLimiter(redisClient, rateLimiterConfig))
+	// Инициатор запроса для журнала аудита
+	router.Use(middleware.Actor())
+
+	// Ограничение частоты запросов, общее для всех реплик
+	router.Use(middleware.RedisRateLimiter(redisClient, rateLimiterConfig, jwtSecret))
 
 	// Эндпоинт для метрик Prometheus
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
@@ -48,34 +63,129 @@ func SetupRouter(
 		// Маршруты для тикетов
 		tickets := public.Group("/tickets")
 		{
-			// Публичные маршруты
-			tickets.POST("", ticketHandler.CreateTicket)
-			tickets.GET("/:id", ticketHandler.GetTicket)
+			// Публичные маршруты; гости проходят капчу, авторизованные пользователи - нет
+			// Idempotency стоит до капчи: повтор с тем же ключом получает исходный ответ без повторной проверки одноразового токена
+			tickets.POST("", middleware.OptionalAuth(jwtSecret), middleware.Idempotency(redisClient, idempotencyConfig), middleware.Captcha(captchaVerifier, captchaConfig), ticketHandler.CreateTicket)
+			// Администратор, распознанный по куке, видит решение антиспама
+			tickets.GET("/:id", middleware.OptionalAuth(jwtSecret), ticketHandler.GetTicket)
 
 			// Защищенные маршруты
 			auth := tickets.Group("")
-			auth.Use(middleware.AuthMiddleware())
+			auth.Use(middleware.AuthMiddleware(jwtSecret))
 			{
 				auth.GET("/user", ticketHandler.GetUserTickets)
+				auth.GET("/user/claimable", ticketHandler.GetClaimableTickets)
+				auth.POST("/user/claim", ticketHandler.ClaimTickets)
 				auth.GET("/user/:id/history", ticketHandler.GetTicketHistory)
+				auth.POST("/user/:id/reopen", ticketHandler.ReopenTicket)
 			}
 
 			// Маршруты только для админов
 			admin := tickets.Group("")
-			admin.Use(middleware.AuthMiddleware(), middleware.AdminOnly())
+			admin.Use(middleware.AuthMiddleware(jwtSecret), middleware.AdminOnly())
 			{
 				admin.GET("", ticketHandler.GetAllTickets)
 				admin.PUT("/:id/status", ticketHandler.UpdateTicketStatus)
+				admin.PUT("/:id/assign", ticketHandler.AssignTicket)
 				admin.GET("/search", ticketHandler.SearchTickets)
+				admin.POST("/spam/release", ticketHandler.ReleaseSpam)
+				admin.POST("/spam/purge", ticketHandler.PurgeSpam)
+				admin.GET("/:id/attachment/preview", previewHandler.GetTicketPreview)
+				admin.GET("/:id/transcript", transcriptHandler.GetTranscript)
 			}
 		}
 
+		// Категории и их дополнительные поля читают формы без авторизации, меняют администраторы
+		categories := public.Group("/categories")
+		{
+			categories.GET("", intakeHandler.ListCategories)
+			categories.GET("/:category/fields", intakeHandler.GetCategoryFields)
+			categories.PUT("/:category/fields", middleware.AuthMiddleware(jwtSecret), middleware.AdminOnly(), intakeHandler.UpdateCategoryFields)
+		}
+
 		// Маршруты для ответов
 		responses := public.Group("/responses")
-		responses.Use(middleware.AuthMiddleware(), middleware.AdminOnly())
+		responses.Use(middleware.AuthMiddleware(jwtSecret), middleware.AdminOnly())
 		{
 			responses.POST("/ticket/:id", responseHandler.CreateResponse)
 			responses.GET("/ticket/:id", responseHandler.GetTicketResponses)
+			responses.PUT("/:id", ticketHandler.UpdateResponse)
+			responses.DELETE("/:id", ticketHandler.DeleteResponse)
+			responses.GET("/:id/revisions", ticketHandler.GetResponseRevisions)
+			responses.GET("/:id/attachment/preview", previewHandler.GetResponsePreview)
+		}
+
+		// Опросы удовлетворенности доступны по подписанной ссылке без авторизации
+		surveys := public.Group("/surveys")
+		{
+			surveys.GET("/:token", surveyHandler.RateFromLink)
+			surveys.POST("/:token", surveyHandler.SubmitRating)
+		}
+
+		// Вложения из локального хранилища отдаются по подписанной ссылке без авторизации;
+		// при хранении в S3 ссылки ведут напрямую в бакет и маршрут не нужен
+		if fileHandler != nil {
+			public.GET("/files/*key", fileHandler.Download)
+		}
+
+		// Загрузка больших вложений частями по протоколу tus; готовый файл прикрепляется по upload_id
+		uploads := public.Group("/uploads")
+		{
+			uploads.OPTIONS("", uploadHandler.Options)
+
+			auth := uploads.Group("")
+			auth.Use(middleware.AuthMiddleware(jwtSecret))
+			{
+				auth.POST("", uploadHandler.CreateUpload)
+				auth.HEAD("/:id", uploadHandler.GetUploadOffset)
+				auth.PATCH("/:id", uploadHandler.AppendChunk)
+				auth.DELETE("/:id", uploadHandler.TerminateUpload)
+			}
+		}
+
+		// Поток событий в реальном времени
+		events := public.Group("/events")
+		events.Use(middleware.AuthMiddleware(jwtSecret))
+		{
+			events.GET("/stream", eventHandler.StreamEvents)
+		}
+
+		// Аналитика только для админов
+		analytics := public.Group("/analytics")
+		analytics.Use(middleware.AuthMiddleware(jwtSecret), middleware.AdminOnly())
+		{
+			analytics.GET("/csat", surveyHandler.GetCSATSummary)
+			analytics.GET("/csat/admins", surveyHandler.GetAdminCSAT)
+			analytics.GET("/reopens", ticketHandler.GetReopenStats)
+		}
+
+		// Журнал аудита только для админов
+		audit := public.Group("/audit")
+		audit.Use(middleware.AuthMiddleware(jwtSecret), middleware.AdminOnly())
+		{
+			audit.GET("", auditHandler.ListAuditEntries)
+		}
+
+		// Запросы заявителей на выгрузку и удаление персональных данных
+		privacy := public.Group("/privacy")
+		privacy.Use(middleware.AuthMiddleware(jwtSecret), middleware.AdminOnly())
+		{
+			privacy.GET("/export", privacyHandler.ExportPersonalData)
+			privacy.POST("/erase", privacyHandler.ErasePersonalData)
+		}
+
+		// Подписки внешних систем на события тикетов; управляют только старшие администраторы
+		webhooks := public.Group("/webhooks")
+		webhooks.Use(middleware.AuthMiddleware(jwtSecret), middleware.RootAdminOnly())
+		{
+			webhooks.GET("", webhookHandler.ListWebhooks)
+			webhooks.POST("", webhookHandler.CreateWebhook)
+			webhooks.GET("/:id", webhookHandler.GetWebhook)
+			webhooks.PUT("/:id", webhookHandler.UpdateWebhook)
+			webhooks.DELETE("/:id", webhookHandler.DeleteWebhook)
+			webhooks.GET("/:id/deliveries", webhookHandler.ListDeliveries)
+			webhooks.GET("/deliveries/:id", webhookHandler.GetDelivery)
+			webhooks.POST("/deliveries/:id/replay", webhookHandler.ReplayDelivery)
 		}
 	}
 
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ActorType кто выполнил действие над тикетом
+type ActorType string
+
+const (
+	ActorTypeGuest  ActorType = "guest"
+	ActorTypeUser   ActorType = "user"
+	ActorTypeAdmin  ActorType = "admin"
+	ActorTypeSystem ActorType = "system"
+)
+
+// Actor инициатор изменения, передается через контекст запроса
+type Actor struct {
+	ID   *uuid.UUID
+	Type ActorType
+	IP   string
+}
+
+type actorContextKey struct{}
+
+// ContextWithActor сохраняет инициатора в контексте
+func ContextWithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext возвращает инициатора; без него действие считается системным (планировщик, миграции)
+func ActorFromContext(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorContextKey{}).(Actor); ok {
+		return actor
+	}
+	return Actor{Type: ActorTypeSystem}
+}
+
+// AuditAction тип изменения в журнале аудита
+type AuditAction string
+
+const (
+	AuditTicketCreated             AuditAction = "ticket.created"
+	AuditTicketStatusChanged       AuditAction = "ticket.status_changed"
+	AuditTicketAssigned            AuditAction = "ticket.assigned"
+	AuditTicketClaimed             AuditAction = "ticket.claimed"
+	AuditTicketReopened            AuditAction = "ticket.reopened"
+	AuditTicketAttachmentUpdated   AuditAction = "ticket.attachment_updated"
+	AuditTicketAttachmentChecked   AuditAction = "ticket.attachment_checked"
+	AuditTicketReminderSent        AuditAction = "ticket.reminder_sent"
+	AuditTicketPurged              AuditAction = "ticket.purged"
+	AuditTicketAnonymized          AuditAction = "ticket.anonymized"
+	AuditTicketExported            AuditAction = "ticket.exported"
+	AuditTicketTranscriptGenerated AuditAction = "ticket.transcript_generated"
+	AuditResponseCreated           AuditAction = "response.created"
+	AuditResponseUpdated           AuditAction = "response.updated"
+	AuditResponseAttachmentUpdated AuditAction = "response.attachment_updated"
+	AuditResponseDeleted           AuditAction = "response.deleted"
+)
+
+// AuditEntry запись журнала аудита; Before и After содержат только изменившиеся поля
+type AuditEntry struct {
+	ID        int64           `json:"id"`
+	TicketID  int64           `json:"ticket_id"`
+	Action    AuditAction     `json:"action"`
+	ActorID   *uuid.UUID      `json:"actor_id,omitempty"`
+	ActorType ActorType       `json:"actor_type"`
+	IP        *string         `json:"ip,omitempty"`
+	Before    json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After     json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// AuditFilter условия выборки журнала аудита
+type AuditFilter struct {
+	TicketID  *int64
+	ActorID   *uuid.UUID
+	ActorType ActorType
+	Action    AuditAction
+	From      *time.Time
+	To        *time.Time
+	Page      int
+	PageSize  int
+}
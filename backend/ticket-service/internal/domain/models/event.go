@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TicketEventType тип события по тикету
+type TicketEventType string
+
+const (
+	TicketEventCreated       TicketEventType = "ticket.created"
+	TicketEventStatusChanged TicketEventType = "ticket.status_changed"
+	TicketEventResponseAdded TicketEventType = "ticket.response_added"
+	TicketEventAssigned      TicketEventType = "ticket.assigned"
+	// TicketEventClosed выделяется из смены статуса на closed для подписчиков вебхуков
+	TicketEventClosed TicketEventType = "ticket.closed"
+)
+
+// TicketEvent событие, рассылаемое подписчикам во всех репликах
+type TicketEvent struct {
+	ID         string                 `json:"id"`
+	Type       TicketEventType        `json:"type"`
+	TicketID   int64                  `json:"ticket_id"`
+	OwnerID    *uuid.UUID             `json:"owner_id,omitempty"`
+	Status     TicketStatus           `json:"status,omitempty"`
+	ActorID    *uuid.UUID             `json:"actor_id,omitempty"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	OccurredAt time.Time              `json:"occurred_at"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"io"
+	"time"
+)
+
+// FileUpload вложение из запроса: содержимое и заявленные клиентом имя и MIME-тип
+type FileUpload struct {
+	Reader      io.Reader
+	Name        string
+	ContentType string
+}
+
+// FileRejectionCode причина отказа в приеме вложения
+type FileRejectionCode string
+
+const (
+	FileRejectionEmpty            FileRejectionCode = "empty_file"
+	FileRejectionTooLarge         FileRejectionCode = "too_large"
+	FileRejectionUnsupportedType  FileRejectionCode = "unsupported_type"
+	FileRejectionTypeMismatch     FileRejectionCode = "type_mismatch"
+	FileRejectionMacroEnabled     FileRejectionCode = "macro_enabled"
+	FileRejectionEncryptedArchive FileRejectionCode = "encrypted_archive"
+)
+
+// FileRejection описание отказа, которое возвращается клиенту вместе с ошибкой
+type FileRejection struct {
+	Code   FileRejectionCode `json:"code"`
+	Reason string            `json:"reason"`
+	// DetectedType тип, определенный по содержимому файла
+	DetectedType string `json:"detected_type,omitempty"`
+	DeclaredName string `json:"declared_name,omitempty"`
+	DeclaredType string `json:"declared_type,omitempty"`
+	Size         int64  `json:"size,omitempty"`
+	MaxSize      int64  `json:"max_size,omitempty"`
+	// AllowedTypes допустимые типы для отказа unsupported_type
+	AllowedTypes []string `json:"allowed_types,omitempty"`
+}
+
+// FileObject объект вложения в хранилище, адресуемый по sha256 содержимого
+type FileObject struct {
+	Key    string
+	SHA256 string
+	Size   int64
+	// RefCount число тикетов и ответов, ссылающихся на объект
+	RefCount   int
+	CreatedAt  time.Time
+	ReleasedAt *time.Time
+}
+
+// FilePreviewStatus состояние превью объекта вложения
+type FilePreviewStatus string
+
+const (
+	FilePreviewPending FilePreviewStatus = "pending"
+	FilePreviewReady   FilePreviewStatus = "ready"
+	// FilePreviewNone для типа файла превью не строится
+	FilePreviewNone   FilePreviewStatus = "none"
+	FilePreviewFailed FilePreviewStatus = "failed"
+)
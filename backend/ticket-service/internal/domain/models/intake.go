@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы дополнительных полей заявки
+const (
+	IntakeFieldString  = "string"
+	IntakeFieldInteger = "integer"
+	IntakeFieldNumber  = "number"
+	IntakeFieldBoolean = "boolean"
+)
+
+// IntakeLabels подпись поля или варианта ответа на казахском, русском и английском
+type IntakeLabels struct {
+	KZ string `json:"kz,omitempty"`
+	RU string `json:"ru"`
+	EN string `json:"en,omitempty"`
+}
+
+// IntakeField описание дополнительного поля в подмножестве JSON Schema: type, enum, minLength,
+// maxLength, minimum, maximum. Подписи для форм передаются расширениями x-labels и x-enum-labels
+type IntakeField struct {
+	Type       string                  `json:"type"`
+	Enum       []string                `json:"enum,omitempty"`
+	MinLength  *int                    `json:"minLength,omitempty"`
+	MaxLength  *int                    `json:"maxLength,omitempty"`
+	Minimum    *float64                `json:"minimum,omitempty"`
+	Maximum    *float64                `json:"maximum,omitempty"`
+	Labels     IntakeLabels            `json:"x-labels"`
+	EnumLabels map[string]IntakeLabels `json:"x-enum-labels,omitempty"`
+}
+
+// IntakeSchema JSON Schema дополнительных полей заявки одной категории. Схема всегда описывает
+// объект без других свойств, поэтому type и additionalProperties задаются сервисом
+type IntakeSchema struct {
+	Type                 string                  `json:"type"`
+	Properties           map[string]*IntakeField `json:"properties"`
+	Required             []string                `json:"required,omitempty"`
+	AdditionalProperties bool                    `json:"additionalProperties"`
+}
+
+// CategoryIntake дополнительные поля заявки категории, заданные администратором
+type CategoryIntake struct {
+	Category  TicketCategory `json:"category"`
+	Schema    *IntakeSchema  `json:"schema"`
+	UpdatedBy *uuid.UUID     `json:"updated_by,omitempty"`
+	UpdatedAt *time.Time     `json:"updated_at,omitempty"`
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type TicketStatus string
@@ -9,27 +11,99 @@ type TicketStatus string
 const (
 	TicketStatusNew        TicketStatus = "new"
 	TicketStatusInProgress TicketStatus = "in_progress"
+	TicketStatusWaiting    TicketStatus = "waiting"
 	TicketStatusClosed     TicketStatus = "closed"
+	// TicketStatusSpam гостевой тикет задержан антиспамом до решения администратора
+	TicketStatusSpam TicketStatus = "spam"
+)
+
+// ticketTransitions допустимые смены статуса. Задержанный антиспамом тикет только освобождается,
+// закрытый возвращается в работу повторным открытием
+var ticketTransitions = map[TicketStatus][]TicketStatus{
+	TicketStatusNew:        {TicketStatusInProgress, TicketStatusWaiting, TicketStatusClosed},
+	TicketStatusInProgress: {TicketStatusNew, TicketStatusWaiting, TicketStatusClosed},
+	TicketStatusWaiting:    {TicketStatusNew, TicketStatusInProgress, TicketStatusClosed},
+	TicketStatusClosed:     {TicketStatusNew, TicketStatusInProgress},
+	TicketStatusSpam:       {TicketStatusNew},
+}
+
+// CanTransitionTo проверяет, что тикет можно перевести из статуса s в next
+func (s TicketStatus) CanTransitionTo(next TicketStatus) bool {
+	for _, allowed := range ticketTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// TicketCategory тематика обращения
+type TicketCategory string
+
+const (
+	TicketCategoryGeneral       TicketCategory = "general"
+	TicketCategoryRecognition   TicketCategory = "recognition"
+	TicketCategoryLegalisation  TicketCategory = "legalisation"
+	TicketCategoryAccreditation TicketCategory = "accreditation"
+	TicketCategoryBologna       TicketCategory = "bologna"
 )
 
+// TicketCategories возвращает все поддерживаемые категории
+func TicketCategories() []TicketCategory {
+	return []TicketCategory{
+		TicketCategoryGeneral, TicketCategoryRecognition, TicketCategoryLegalisation,
+		TicketCategoryAccreditation, TicketCategoryBologna,
+	}
+}
+
+// IsValid проверяет, что категория входит в список поддерживаемых
+func (c TicketCategory) IsValid() bool {
+	switch c {
+	case TicketCategoryGeneral, TicketCategoryRecognition, TicketCategoryLegalisation,
+		TicketCategoryAccreditation, TicketCategoryBologna:
+		return true
+	}
+	return false
+}
+
 type Ticket struct {
-	ID          int64        `json:"id"`
-	UserID      int64        `json:"user_id"`
-	Subject     string       `json:"subject"`
-	Question    string       `json:"question"`
-	FullName    string       `json:"full_name"`
-	Email       string       `json:"email"`
-	Phone       *string      `json:"phone,omitempty"`
-	TelegramID  *string      `json:"telegram_id,omitempty"`
-	FileURL     *string      `json:"file_url,omitempty"`
-	FileName    *string      `json:"file_name,omitempty"`
-	FileType    *string      `json:"file_type,omitempty"`
-	FileChecked bool         `json:"file_checked"`
-	Status      TicketStatus `json:"status"`
-	NotifyEmail bool         `json:"notify_email"`
-	NotifyTG    bool         `json:"notify_tg"`
-	CreatedAt   time.Time    `json:"created_at"`
-	UpdatedAt   time.Time    `json:"updated_at"`
+	ID               int64          `json:"id"`
+	UserID           uuid.UUID      `json:"user_id"` // у гостевых тикетов uuid.Nil
+	Category         TicketCategory `json:"category"`
+	Subject          string         `json:"subject"`
+	Question         string         `json:"question"`
+	FullName         string         `json:"full_name"`
+	Email            string         `json:"email"`
+	Phone            *string        `json:"phone,omitempty"`
+	TelegramID       *string        `json:"telegram_id,omitempty"`
+	FileURL          *string        `json:"file_url,omitempty"`
+	FileName         *string        `json:"file_name,omitempty"`
+	FileType         *string        `json:"file_type,omitempty"`
+	FileChecked      bool           `json:"file_checked"`
+	Status           TicketStatus   `json:"status"`
+	NotifyEmail      bool           `json:"notify_email"`
+	NotifyTG         bool           `json:"notify_tg"`
+	AssignedTo       *uuid.UUID     `json:"assigned_to,omitempty"`
+	SpamScore        float64        `json:"spam_score,omitempty"`
+	SpamSignals      []SpamSignal   `json:"spam_signals,omitempty"`
+	WaitingSince     *time.Time     `json:"waiting_since,omitempty"`
+	ReminderSentAt   *time.Time     `json:"reminder_sent_at,omitempty"`
+	ClosedAt         *time.Time     `json:"closed_at,omitempty"`
+	AnonymizedAt     *time.Time     `json:"anonymized_at,omitempty"`
+	ReopenCount      int            `json:"reopen_count"`                 // сколько раз заявитель открывал тикет повторно
+	PreviousTicketID *int64         `json:"previous_ticket_id,omitempty"` // тикет, вместо повторного открытия которого создан этот
+	Fields           map[string]any `json:"fields,omitempty"`             // дополнительные поля заявки по схеме категории
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+}
+
+// OwnerID владелец тикета для событий и вебхуков; у гостевых тикетов владельца нет
+func (t *Ticket) OwnerID() *uuid.UUID {
+	if t.UserID == uuid.Nil {
+		return nil
+	}
+	owner := t.UserID
+	return &owner
 }
 
 type TicketHistory struct {
@@ -37,17 +111,29 @@ type TicketHistory struct {
 	TicketID  int64        `json:"ticket_id"`
 	Status    TicketStatus `json:"status"`
 	Comment   *string      `json:"comment,omitempty"`
-	AdminID   *int64       `json:"admin_id,omitempty"`
+	AdminID   *uuid.UUID   `json:"admin_id,omitempty"`
 	CreatedAt time.Time    `json:"created_at"`
 }
 
 type Response struct {
-	ID        int64     `json:"id"`
-	TicketID  int64     `json:"ticket_id"`
-	AdminID   int64     `json:"admin_id"`
-	Message   string    `json:"message"`
-	FileURL   *string   `json:"file_url,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        int64      `json:"id"`
+	TicketID  int64      `json:"ticket_id"`
+	AdminID   uuid.UUID  `json:"admin_id"`
+	Message   string     `json:"message"`
+	FileURL   *string    `json:"file_url,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	DeletedBy *uuid.UUID `json:"deleted_by,omitempty"`
+}
+
+// ResponseRevision предыдущая версия текста ответа до редактирования
+type ResponseRevision struct {
+	ID         int64     `json:"id"`
+	ResponseID int64     `json:"response_id"`
+	Message    string    `json:"message"`
+	EditedBy   uuid.UUID `json:"edited_by"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
 type GetTicketsRequest struct {
@@ -56,9 +142,12 @@ type GetTicketsRequest struct {
 	Status   TicketStatus `json:"status" form:"status"`
 	FromDate string      `json:"from_date" form:"from_date"`
 	ToDate   string      `json:"to_date" form:"to_date"`
+	// Fields отбирает тикеты, у которых дополнительные поля равны заданным значениям
+	Fields map[string]string `json:"fields,omitempty" form:"-"`
 }
 
 type CreateTicketRequest struct {
+	Category   TicketCategory `json:"category,omitempty"`
 	Subject    string  `json:"subject" binding:"required"`
 	Question   string  `json:"question" binding:"required"`
 	FullName   string  `json:"full_name" binding:"required"`
@@ -67,6 +156,12 @@ type CreateTicketRequest struct {
 	TelegramID *string `json:"telegram_id,omitempty"`
 	NotifyEmail bool   `json:"notify_email"`
 	NotifyTG    bool   `json:"notify_tg"`
+	// Website ловушка для ботов: поле скрыто в форме и должно оставаться пустым
+	Website string `json:"website,omitempty"`
+	// UploadID завершенная возобновляемая загрузка (tus), которая прикрепляется к тикету
+	UploadID string `json:"upload_id,omitempty"`
+	// Fields значения дополнительных полей, заданных для категории
+	Fields map[string]any `json:"fields,omitempty"`
 }
 
 type UpdateTicketStatusRequest struct {
@@ -74,6 +169,48 @@ type UpdateTicketStatusRequest struct {
 	Comment *string      `json:"comment,omitempty"`
 }
 
+type AssignTicketRequest struct {
+	AdminID uuid.UUID `json:"admin_id" binding:"required"`
+}
+
 type CreateResponseRequest struct {
 	Message string `json:"message" binding:"required"`
-} 
\ No newline at end of file
+}
+
+type UpdateResponseRequest struct {
+	Message string `json:"message" binding:"required"`
+}
+
+// SpamSignal признак спама, сработавший при проверке гостевого тикета
+type SpamSignal struct {
+	Name   string  `json:"name"`
+	Score  float64 `json:"score"`
+	Detail string  `json:"detail,omitempty"`
+}
+
+// BulkTicketsRequest запрос на массовую обработку тикетов
+type BulkTicketsRequest struct {
+	TicketIDs []int64 `json:"ticket_ids" binding:"required,min=1,max=100"`
+}
+
+// ReopenTicketRequest запрос заявителя на повторное открытие закрытого тикета
+type ReopenTicketRequest struct {
+	Message string `json:"message" binding:"required,max=5000"`
+}
+
+// ReopenStats статистика повторных открытий тикетов, созданных за период
+type ReopenStats struct {
+	// Resolved тикеты, которые закрывались хотя бы раз; Reopened - из них открытые повторно
+	Resolved int64 `json:"resolved"`
+	Reopened int64 `json:"reopened"`
+	// Reopens общее число повторных открытий, FollowUps - новых тикетов вместо открытия после истечения срока
+	Reopens    int64   `json:"reopens"`
+	FollowUps  int64   `json:"follow_ups"`
+	ReopenRate float64 `json:"reopen_rate"`
+}
+
+// ClaimTicketsRequest запрос на привязку гостевых тикетов: перечисленных в TicketIDs или всех сразу
+type ClaimTicketsRequest struct {
+	TicketIDs []int64 `json:"ticket_ids" binding:"max=100"`
+	All       bool    `json:"all"`
+}
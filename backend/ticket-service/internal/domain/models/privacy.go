@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// PersonalDataExport все данные заявителя по тикетам для ответа на запрос субъекта данных
+type PersonalDataExport struct {
+	Email       string         `json:"email"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	Tickets     []TicketExport `json:"tickets"`
+}
+
+// TicketExport тикет вместе с историей и ответами
+type TicketExport struct {
+	Ticket    *Ticket          `json:"ticket"`
+	History   []*TicketHistory `json:"history"`
+	Responses []*Response      `json:"responses"`
+}
+
+// EraseRequest запрос на удаление персональных данных заявителя
+type EraseRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
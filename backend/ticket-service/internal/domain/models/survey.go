@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MinSurveyRating = 1
+	MaxSurveyRating = 5
+)
+
+// Survey опрос удовлетворенности, отправляемый заявителю после закрытия тикета
+type Survey struct {
+	ID       int64      `json:"id"`
+	TicketID int64      `json:"ticket_id"`
+	AdminID  *uuid.UUID `json:"admin_id,omitempty"`
+	Rating   *int       `json:"rating,omitempty"`
+	Comment  *string    `json:"comment,omitempty"`
+	SentAt   time.Time  `json:"sent_at"`
+	RatedAt  *time.Time `json:"rated_at,omitempty"`
+	// Nonce отправки опроса, к которой привязана ссылка из письма; пустой у опросов, отправленных до его появления
+	Nonce string `json:"-"`
+}
+
+// SubmitSurveyRequest принимается в JSON и из формы на странице подтверждения оценки
+type SubmitSurveyRequest struct {
+	Rating  int     `json:"rating" form:"rating" binding:"required,min=1,max=5"`
+	Comment *string `json:"comment,omitempty" form:"comment" binding:"omitempty,max=2000"`
+}
+
+// CSATFilter ограничивает выборку оценок по дате выставления; число отправленных опросов
+// считается по дате отправки
+type CSATFilter struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// CSATSummary сводная статистика оценок
+type CSATSummary struct {
+	Sent         int64         `json:"sent"`
+	Rated        int64         `json:"rated"`
+	Average      float64       `json:"average"`
+	Distribution map[int]int64 `json:"distribution"`
+}
+
+// AdminCSATStats статистика оценок по администратору
+type AdminCSATStats struct {
+	AdminID uuid.UUID `json:"admin_id"`
+	Rated   int64     `json:"rated"`
+	Average float64   `json:"average"`
+}
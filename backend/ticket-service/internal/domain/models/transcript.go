@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Transcript переписка по тикету для передачи в архив: метаданные, история статусов,
+// сообщения и вложения. Digest - sha256 содержимого, по которому выданный документ
+// сверяется с журналом аудита
+type Transcript struct {
+	Ticket      *Ticket                `json:"ticket"`
+	History     []*TicketHistory       `json:"history"`
+	Responses   []*Response            `json:"responses"`
+	Attachments []TranscriptAttachment `json:"attachments"`
+	GeneratedAt time.Time              `json:"generated_at"`
+	GeneratedBy *uuid.UUID             `json:"generated_by,omitempty"`
+	Digest      string                 `json:"-"`
+}
+
+// TranscriptAttachment вложение тикета или ответа с хешем содержимого
+type TranscriptAttachment struct {
+	// ResponseID ответ, к которому приложен файл; nil - вложение самого тикета
+	ResponseID *int64 `json:"response_id,omitempty"`
+	Name       string `json:"name"`
+	// SHA256 пустой, если файла нет в хранилище
+	SHA256 string `json:"sha256"`
+}
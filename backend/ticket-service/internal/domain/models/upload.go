@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Upload возобновляемая загрузка файла по протоколу tus. Состояние хранится в Redis до завершения
+// загрузки и прикрепления файла к тикету или ответу либо до истечения ExpiresAt
+type Upload struct {
+	ID      string    `json:"id"`
+	OwnerID uuid.UUID `json:"owner_id"`
+	// Length заявленный размер файла, Offset - сколько байт уже получено
+	Length   int64             `json:"length"`
+	Offset   int64             `json:"offset"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// StorageID идентификатор загрузки в хранилище (multipart upload в S3)
+	StorageID string       `json:"storage_id,omitempty"`
+	Parts     []UploadPart `json:"parts,omitempty"`
+	// PendingSize байты в конце загрузки, которых пока не хватает на часть multipart upload
+	PendingSize int64     `json:"pending_size,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// UploadPart загруженная часть multipart upload
+type UploadPart struct {
+	Number int    `json:"number"`
+	ETag   string `json:"etag"`
+	Size   int64  `json:"size"`
+}
+
+// Completed сообщает, получен ли файл целиком
+func (u *Upload) Completed() bool {
+	return u.Offset == u.Length
+}
+
+// Filename имя файла из метаданных загрузки
+func (u *Upload) Filename() string {
+	return u.Metadata["filename"]
+}
+
+// FileType MIME-тип из метаданных загрузки; если клиент его не передал - application/octet-stream
+func (u *Upload) FileType() string {
+	if fileType := u.Metadata["filetype"]; fileType != "" {
+		return fileType
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookPayloadVersion версия формата тела вебхука; повышается при несовместимых изменениях
+const WebhookPayloadVersion = "1"
+
+// WebhookEventTypes события, на которые можно подписаться
+func WebhookEventTypes() []TicketEventType {
+	return []TicketEventType{
+		TicketEventCreated,
+		TicketEventStatusChanged,
+		TicketEventResponseAdded,
+		TicketEventAssigned,
+		TicketEventClosed,
+	}
+}
+
+// IsWebhookEventType проверяет, что на событие можно подписаться
+func (t TicketEventType) IsWebhookEventType() bool {
+	for _, eventType := range WebhookEventTypes() {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookSubscription подписка внешней системы на события тикетов
+type WebhookSubscription struct {
+	ID          int64             `json:"id"`
+	URL         string            `json:"url"`
+	Secret      string            `json:"-"`
+	EventTypes  []TicketEventType `json:"event_types"`
+	Description *string           `json:"description,omitempty"`
+	Active      bool              `json:"active"`
+	CreatedBy   *uuid.UUID        `json:"created_by,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// Accepts проверяет, подписана ли подписка на событие
+func (s *WebhookSubscription) Accepts(eventType TicketEventType) bool {
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateWebhookRequest запрос на создание подписки; пустой secret генерируется сервисом
+type CreateWebhookRequest struct {
+	URL         string            `json:"url" binding:"required"`
+	Secret      string            `json:"secret"`
+	EventTypes  []TicketEventType `json:"event_types" binding:"required,min=1"`
+	Description *string           `json:"description"`
+}
+
+// UpdateWebhookRequest изменяет только переданные поля подписки
+type UpdateWebhookRequest struct {
+	URL         *string           `json:"url"`
+	Secret      *string           `json:"secret"`
+	EventTypes  []TicketEventType `json:"event_types"`
+	Description *string           `json:"description"`
+	Active      *bool             `json:"active"`
+}
+
+// CreatedWebhookResponse подписка с секретом; секрет возвращается только при создании
+type CreatedWebhookResponse struct {
+	WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// WebhookPayload тело запроса, отправляемого подписчику
+type WebhookPayload struct {
+	Version    string             `json:"version"`
+	ID         string             `json:"id"`
+	Type       TicketEventType    `json:"type"`
+	OccurredAt time.Time          `json:"occurred_at"`
+	Data       WebhookPayloadData `json:"data"`
+}
+
+// WebhookPayloadData сведения о тикете без персональных данных заявителя
+type WebhookPayloadData struct {
+	TicketID int64                  `json:"ticket_id"`
+	Status   TicketStatus           `json:"status,omitempty"`
+	OwnerID  *uuid.UUID             `json:"owner_id,omitempty"`
+	ActorID  *uuid.UUID             `json:"actor_id,omitempty"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+}
+
+// WebhookDeliveryStatus состояние доставки
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery доставка одного события одной подписке
+type WebhookDelivery struct {
+	ID             int64                     `json:"id"`
+	SubscriptionID int64                     `json:"subscription_id"`
+	EventID        string                    `json:"event_id"`
+	EventType      TicketEventType           `json:"event_type"`
+	Payload        json.RawMessage           `json:"payload" swaggertype:"object"`
+	Status         WebhookDeliveryStatus     `json:"status"`
+	Attempts       int                       `json:"attempts"`
+	NextAttemptAt  time.Time                 `json:"next_attempt_at"`
+	LastStatusCode *int                      `json:"last_status_code,omitempty"`
+	LastError      *string                   `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time                `json:"delivered_at,omitempty"`
+	ReplayOf       *int64                    `json:"replay_of,omitempty"`
+	CreatedAt      time.Time                 `json:"created_at"`
+	AttemptLog     []*WebhookDeliveryAttempt `json:"attempt_log,omitempty"`
+}
+
+// WebhookDeliveryAttempt результат одной попытки доставки
+type WebhookDeliveryAttempt struct {
+	ID          int64     `json:"id"`
+	DeliveryID  int64     `json:"delivery_id"`
+	StatusCode  *int      `json:"status_code,omitempty"`
+	Error       *string   `json:"error,omitempty"`
+	DurationMs  int64     `json:"duration_ms"`
+	AttemptedAt time.Time `json:"attempted_at"`
+}
+
+// WebhookDeliveryFilter параметры выборки журнала доставок
+type WebhookDeliveryFilter struct {
+	SubscriptionID int64
+	Status         WebhookDeliveryStatus
+	Page           int
+	PageSize       int
+}
@@ -2,6 +2,9 @@ package repositories
 
 import (
 	"context"
+	"time"
+
+	"github.com/google/uuid"
 
 	"ticket-service/internal/domain/models"
 )
@@ -10,12 +13,30 @@ import (
 type TicketRepository interface {
 	Create(ctx context.Context, ticket *models.Ticket) (int64, error)
 	GetByID(ctx context.Context, id int64) (*models.Ticket, error)
-	GetByUserID(ctx context.Context, userID int64, req models.GetTicketsRequest) ([]*models.Ticket, int64, error)
+	// GetByIDForUpdate читает тикет с блокировкой строки до конца транзакции
+	GetByIDForUpdate(ctx context.Context, id int64) (*models.Ticket, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, req models.GetTicketsRequest) ([]*models.Ticket, int64, error)
 	GetAll(ctx context.Context, req models.GetTicketsRequest) ([]*models.Ticket, int64, error)
-	UpdateStatus(ctx context.Context, id int64, status models.TicketStatus, adminID int64, comment *string) error
+	UpdateStatus(ctx context.Context, id int64, status models.TicketStatus, adminID uuid.UUID, comment *string) error
 	UpdateFileURL(ctx context.Context, id int64, fileURL string) error
 	UpdateFileChecked(ctx context.Context, id int64, checked bool) error
 	Search(ctx context.Context, query string, req models.GetTicketsRequest) ([]*models.Ticket, int64, error)
+	Assign(ctx context.Context, id int64, adminID uuid.UUID) error
+	GetGuestByEmail(ctx context.Context, email string) ([]*models.Ticket, error)
+	Claim(ctx context.Context, id int64, userID uuid.UUID, email string) (bool, error)
+	Reopen(ctx context.Context, id int64, status models.TicketStatus) (bool, error)
+	GetReopenStats(ctx context.Context, from, to *time.Time) (*models.ReopenStats, error)
+	GetWaiting(ctx context.Context) ([]*models.Ticket, error)
+	MarkReminderSent(ctx context.Context, id int64, sentAt time.Time) error
+	// CloseWaiting закрывает тикет, только если он еще в статусе waiting
+	CloseWaiting(ctx context.Context, id int64) (bool, error)
+	CountByEmailSince(ctx context.Context, email string, since time.Time) (int64, error)
+	CountByPhoneSince(ctx context.Context, phone string, since time.Time) (int64, error)
+	DeleteSpam(ctx context.Context, id int64) (bool, error)
+	GetForAnonymization(ctx context.Context, category models.TicketCategory, closedBefore time.Time, limit int) ([]*models.Ticket, error)
+	GetByEmail(ctx context.Context, email string) ([]*models.Ticket, error)
+	GetAfterID(ctx context.Context, afterID int64, limit int) ([]*models.Ticket, error)
+	GetWithAttachmentsAfterID(ctx context.Context, afterID int64, limit int) ([]*models.Ticket, error)
 }
 
 // TicketHistoryRepository определяет методы для работы с историей тикетов
@@ -31,9 +52,55 @@ type ResponseRepository interface {
 	Create(ctx context.Context, response *models.Response) (int64, error)
 	GetByTicketID(ctx context.Context, ticketID int64) ([]*models.Response, error)
 	GetByTicketIDWithPagination(ctx context.Context, ticketID int64, page, pageSize int) ([]*models.Response, int, error)
-	UpdateMessage(ctx context.Context, id int64, message string) error
+	GetByID(ctx context.Context, id int64) (*models.Response, error)
+	UpdateMessage(ctx context.Context, id int64, message string, editedBy uuid.UUID) error
 	UpdateFileURL(ctx context.Context, id int64, fileURL string) error
-	Delete(ctx context.Context, id int64) error
+	Delete(ctx context.Context, id int64, deletedBy uuid.UUID) (bool, error)
+	GetRevisions(ctx context.Context, responseID int64) ([]*models.ResponseRevision, error)
+}
+
+// TxRepositories репозитории, выполняющие запросы в одной транзакции
+type TxRepositories struct {
+	Tickets     TicketRepository
+	History     TicketHistoryRepository
+	Responses   ResponseRepository
+	FileObjects FileObjectRepository
+}
+
+// FileObjectRepository учитывает ссылки на объекты вложений, адресуемые по содержимому
+type FileObjectRepository interface {
+	// Acquire добавляет ссылку на объект; created - объект учтен впервые или заново после удаления сборщиком
+	Acquire(ctx context.Context, object *models.FileObject) (created bool, err error)
+	// Register учитывает объект без ссылок, если он еще не учтен, чтобы его удалил сборщик мусора,
+	// когда ссылку на него так и не возьмут
+	Register(ctx context.Context, object *models.FileObject) error
+	// Release снимает ссылку; found - false, если объект не учитывается (загружен до адресации по содержимому)
+	Release(ctx context.Context, key string) (found bool, err error)
+	// Reconcile пересчитывает ссылки по вложениям тикетов и ответов
+	Reconcile(ctx context.Context) error
+	// GetUnreferenced возвращает ключи объектов без ссылок, освобожденных раньше before
+	GetUnreferenced(ctx context.Context, before time.Time, limit int) ([]string, error)
+	// DeleteUnreferenced удаляет запись, если на объект по-прежнему нет ссылок; в транзакции строка
+	// остается заблокированной до коммита, и параллельная загрузка того же содержимого ждет его
+	DeleteUnreferenced(ctx context.Context, key string, before time.Time) (bool, error)
+	// GetPendingPreviews возвращает ключи объектов со ссылками, для которых еще не строилось превью
+	GetPendingPreviews(ctx context.Context, limit int) ([]string, error)
+	SetPreviewStatus(ctx context.Context, key string, status models.FilePreviewStatus) error
+}
+
+// UploadRepository хранит состояние возобновляемых загрузок до ExpiresAt
+type UploadRepository interface {
+	Save(ctx context.Context, upload *models.Upload) error
+	// Get возвращает nil, если загрузки нет или она истекла
+	Get(ctx context.Context, id string) (*models.Upload, error)
+	Delete(ctx context.Context, id string) error
+	// Lock не дает параллельно дописывать одну загрузку; ok - false, если блокировка занята
+	Lock(ctx context.Context, id string) (unlock func(), ok bool, err error)
+}
+
+// UnitOfWork выполняет операцию атомарно: изменения фиксируются, только если fn вернула nil, иначе откатываются
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(repos TxRepositories) error) error
 }
 
 type HistoryRepository interface {
@@ -43,3 +110,57 @@ type HistoryRepository interface {
 	GetByTicketIDWithPagination(ctx context.Context, ticketID int64, page, pageSize int) ([]*models.TicketHistory, int, error)
 }
 
+
+// SurveyRepository определяет методы для работы с опросами удовлетворенности
+type SurveyRepository interface {
+	Upsert(ctx context.Context, survey *models.Survey) (int64, error)
+	GetByTicketID(ctx context.Context, ticketID int64) (*models.Survey, error)
+	// SaveRating сохраняет оценку, только если опрос с nonce еще не оценен
+	SaveRating(ctx context.Context, ticketID int64, nonce string, rating int, comment *string) (bool, error)
+	GetSummary(ctx context.Context, filter models.CSATFilter) (*models.CSATSummary, error)
+	GetAdminStats(ctx context.Context, filter models.CSATFilter) ([]*models.AdminCSATStats, error)
+}
+
+// IntakeRepository хранит схемы дополнительных полей заявки по категориям
+type IntakeRepository interface {
+	// Get возвращает nil, если для категории поля не заданы
+	Get(ctx context.Context, category models.TicketCategory) (*models.CategoryIntake, error)
+	List(ctx context.Context) ([]*models.CategoryIntake, error)
+	Upsert(ctx context.Context, intake *models.CategoryIntake) error
+}
+
+// AuditRepository определяет методы для работы с журналом аудита
+type AuditRepository interface {
+	Create(ctx context.Context, entry *models.AuditEntry) (int64, error)
+	List(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEntry, int64, error)
+}
+
+// PrivacyRepository удаляет персональные данные заявителя из всех таблиц тикета
+type PrivacyRepository interface {
+	// GetAttachmentURLs возвращает вложения тикета и всех его ответов, включая удаленные
+	GetAttachmentURLs(ctx context.Context, ticketID int64) ([]string, error)
+	Anonymize(ctx context.Context, ticketID int64, anonymizedAt time.Time) error
+}
+
+// WebhookRepository определяет методы для работы с подписками на вебхуки и журналом доставок
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) (int64, error)
+	// GetSubscription возвращает nil, nil, если подписки нет
+	GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error)
+	GetActiveSubscriptions(ctx context.Context, eventType models.TicketEventType) ([]*models.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id int64) (bool, error)
+
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) (int64, error)
+	// GetDelivery возвращает nil, nil, если доставки нет
+	GetDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, int64, error)
+	// GetDueDeliveries возвращает ожидающие доставки, время попытки которых наступило
+	GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error)
+	// RecordAttempt сохраняет попытку и новое состояние доставки в одной транзакции
+	RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery, attempt *models.WebhookDeliveryAttempt) error
+	GetAttempts(ctx context.Context, deliveryID int64) ([]*models.WebhookDeliveryAttempt, error)
+	// DeleteFinishedBefore удаляет завершенные доставки, созданные раньше before, и возвращает их число
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"ticket-service/internal/logger"
+)
+
+// attachment содержимое вложения с произвольным доступом: проверка типа, антивирус, подсчет sha256
+// и загрузка в хранилище читают его по очереди через reader, не копируя файл в память
+type attachment struct {
+	file io.ReaderAt
+	size int64
+	// tmp временный файл, в который переписан поток без произвольного доступа
+	tmp *os.File
+}
+
+// openAttachment открывает file для повторного чтения. Файлы multipart-формы, завершенные загрузки
+// и bytes.Reader читаются на месте, остальные потоки переписываются во временный файл.
+// Содержимое больше MaxFileSize отклоняется с ErrFileTooLarge
+func openAttachment(file io.Reader) (*attachment, error) {
+	if seekable, ok := file.(interface {
+		io.ReaderAt
+		io.Seeker
+	}); ok {
+		size, err := seekable.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get file size: %w", err)
+		}
+		if size > MaxFileSize {
+			return nil, ErrFileTooLarge
+		}
+		return &attachment{file: seekable, size: size}, nil
+	}
+
+	tmp, err := os.CreateTemp("", "attachment-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	content := &attachment{file: tmp, tmp: tmp}
+	content.size, err = io.Copy(tmp, io.LimitReader(file, MaxFileSize+1))
+	if err != nil {
+		content.Close()
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if content.size > MaxFileSize {
+		content.Close()
+		return nil, ErrFileTooLarge
+	}
+	return content, nil
+}
+
+// reader возвращает независимый поток по содержимому с начала
+func (a *attachment) reader() *io.SectionReader {
+	return io.NewSectionReader(a.file, 0, a.size)
+}
+
+// Close удаляет временный файл; исходный файл закрывает тот, кто его открыл
+func (a *attachment) Close() error {
+	if a.tmp == nil {
+		return nil
+	}
+	a.tmp.Close()
+	if err := os.Remove(a.tmp.Name()); err != nil {
+		logger.Error("Failed to remove temporary attachment file", "error", err, "path", a.tmp.Name())
+		return err
+	}
+	return nil
+}
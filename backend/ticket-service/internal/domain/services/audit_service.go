@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+	"ticket-service/internal/logger"
+)
+
+type AuditService struct {
+	auditRepo repositories.AuditRepository
+}
+
+func NewAuditService(auditRepo repositories.AuditRepository) *AuditService {
+	return &AuditService{auditRepo: auditRepo}
+}
+
+// Record сохраняет изменение тикета с инициатором из контекста и разницей состояний.
+// before и after - состояния объекта до и после изменения (nil для создания и удаления).
+// Ошибки только логируются: журнал не должен отменять уже выполненную операцию
+func (s *AuditService) Record(ctx context.Context, ticketID int64, action models.AuditAction, before, after interface{}) {
+	actor := models.ActorFromContext(ctx)
+
+	beforeJSON, afterJSON, err := diffJSON(before, after)
+	if err != nil {
+		logger.Error("Failed to build audit diff", "error", err, "ticketID", ticketID, "action", action)
+		return
+	}
+
+	entry := &models.AuditEntry{
+		TicketID:  ticketID,
+		Action:    action,
+		ActorID:   actor.ID,
+		ActorType: actor.Type,
+		Before:    beforeJSON,
+		After:     afterJSON,
+	}
+	if actor.IP != "" {
+		entry.IP = &actor.IP
+	}
+
+	if _, err := s.auditRepo.Create(ctx, entry); err != nil {
+		logger.Error("Failed to create audit entry", "error", err, "ticketID", ticketID, "action", action)
+	}
+}
+
+// List возвращает записи журнала аудита по фильтру
+func (s *AuditService) List(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEntry, int64, error) {
+	entries, total, err := s.auditRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get audit entries: %w", err)
+	}
+	return entries, total, nil
+}
+
+// recordAudit nil-safe обертка, чтобы сервисы работали и без журнала
+func recordAudit(ctx context.Context, recorder IAuditRecorder, ticketID int64, action models.AuditAction, before, after interface{}) {
+	if recorder == nil {
+		return
+	}
+	recorder.Record(ctx, ticketID, action, before, after)
+}
+
+// diffJSON сериализует состояния и оставляет в них только различающиеся поля.
+// Если одно из состояний отсутствует, другое сохраняется целиком
+func diffJSON(before, after interface{}) (json.RawMessage, json.RawMessage, error) {
+	beforeMap, err := toJSONMap(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterMap, err := toJSONMap(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if beforeMap != nil && afterMap != nil {
+		// Поля с omitempty могут отсутствовать в одном из состояний - считаем их null
+		for key := range afterMap {
+			if _, ok := beforeMap[key]; !ok {
+				beforeMap[key] = nil
+			}
+		}
+		for key, value := range beforeMap {
+			other, ok := afterMap[key]
+			if !ok {
+				afterMap[key] = nil
+			}
+			if reflect.DeepEqual(value, other) {
+				delete(beforeMap, key)
+				delete(afterMap, key)
+			}
+		}
+	}
+
+	beforeJSON, err := marshalJSONMap(beforeMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterJSON, err := marshalJSONMap(afterMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	return beforeJSON, afterJSON, nil
+}
+
+func toJSONMap(value interface{}) (map[string]interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit state: %w", err)
+	}
+
+	result := make(map[string]interface{})
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("audit state must be an object: %w", err)
+	}
+	return result, nil
+}
+
+func marshalJSONMap(value map[string]interface{}) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	return json.Marshal(value)
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"ticket-service/internal/domain/models"
+)
+
+// MockAuditRepository мок для AuditRepository
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Create(ctx context.Context, entry *models.AuditEntry) (int64, error) {
+	args := m.Called(ctx, entry)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAuditRepository) List(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEntry, int64, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]*models.AuditEntry), args.Get(1).(int64), args.Error(2)
+}
+
+func TestDiffJSON(t *testing.T) {
+	phone := "+77001234567"
+
+	tests := []struct {
+		name           string
+		before         interface{}
+		after          interface{}
+		expectedBefore string
+		expectedAfter  string
+	}{
+		{
+			name:           "Создание - сохраняется только новое состояние",
+			before:         nil,
+			after:          map[string]interface{}{"status": "new"},
+			expectedBefore: "",
+			expectedAfter:  `{"status":"new"}`,
+		},
+		{
+			name:           "Удаление - сохраняется только прежнее состояние",
+			before:         map[string]interface{}{"message": "text"},
+			after:          nil,
+			expectedBefore: `{"message":"text"}`,
+			expectedAfter:  "",
+		},
+		{
+			name:           "Изменение - остаются только отличающиеся поля",
+			before:         &models.Ticket{ID: 1, Status: models.TicketStatusNew, Subject: "Вопрос"},
+			after:          &models.Ticket{ID: 1, Status: models.TicketStatusWaiting, Subject: "Вопрос"},
+			expectedBefore: `{"status":"new"}`,
+			expectedAfter:  `{"status":"waiting"}`,
+		},
+		{
+			name:           "Поле с omitempty появилось - прежнее значение null",
+			before:         &models.Ticket{ID: 1},
+			after:          &models.Ticket{ID: 1, Phone: &phone},
+			expectedBefore: `{"phone":null}`,
+			expectedAfter:  `{"phone":"+77001234567"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, after, err := diffJSON(tt.before, tt.after)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedBefore, string(before))
+			assert.Equal(t, tt.expectedAfter, string(after))
+		})
+	}
+}
+
+func TestAuditServiceRecord(t *testing.T) {
+	adminID := uuid.New()
+	ctx := models.ContextWithActor(context.Background(), models.Actor{
+		ID:   &adminID,
+		Type: models.ActorTypeAdmin,
+		IP:   "10.0.0.1",
+	})
+
+	mockAuditRepo := new(MockAuditRepository)
+	mockAuditRepo.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditEntry) bool {
+		return entry.TicketID == 1 &&
+			entry.Action == models.AuditTicketAssigned &&
+			entry.ActorType == models.ActorTypeAdmin &&
+			*entry.ActorID == adminID &&
+			*entry.IP == "10.0.0.1" &&
+			string(entry.Before) == `{"assigned_to":null}` &&
+			string(entry.After) == `{"assigned_to":3}`
+	})).Return(int64(1), nil)
+
+	service := NewAuditService(mockAuditRepo)
+	service.Record(ctx, 1, models.AuditTicketAssigned,
+		map[string]interface{}{"assigned_to": nil},
+		map[string]interface{}{"assigned_to": 3})
+
+	mockAuditRepo.AssertExpectations(t)
+}
+
+func TestActorFromContextDefaultsToSystem(t *testing.T) {
+	actor := models.ActorFromContext(context.Background())
+	assert.Equal(t, models.ActorTypeSystem, actor.Type)
+	assert.Nil(t, actor.ID)
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/logger"
+)
+
+// publishEvent дополняет событие служебными полями и публикует его.
+// Ошибки публикации только логируются: уведомления не должны ломать основную операцию
+func publishEvent(ctx context.Context, publisher IEventPublisher, event *models.TicketEvent) {
+	if publisher == nil {
+		return
+	}
+
+	event.ID = uuid.NewString()
+	event.OccurredAt = time.Now()
+
+	if err := publisher.Publish(ctx, event); err != nil {
+		logger.Error("Failed to publish ticket event", "error", err, "type", event.Type, "ticketID", event.TicketID)
+	}
+}
+
+// eventFanout передает событие нескольким получателям
+type eventFanout []IEventPublisher
+
+// NewEventFanout объединяет получателей событий; ошибка одного не мешает доставке остальным
+func NewEventFanout(publishers ...IEventPublisher) IEventPublisher {
+	return eventFanout(publishers)
+}
+
+func (f eventFanout) Publish(ctx context.Context, event *models.TicketEvent) error {
+	var errs []error
+	for _, publisher := range f {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,340 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/infrastructure/metrics"
+)
+
+// fileType тип вложения: расширения и MIME-типы, с которыми он может быть заявлен клиентом
+type fileType struct {
+	mime       string
+	extensions []string
+	aliases    []string
+}
+
+// fileTypes типы, которые умеет распознавать FileInspector
+var fileTypes = map[string]fileType{
+	"pdf":  {mime: "application/pdf", extensions: []string{".pdf"}},
+	"jpeg": {mime: "image/jpeg", extensions: []string{".jpg", ".jpeg"}, aliases: []string{"image/jpg", "image/pjpeg"}},
+	"png":  {mime: "image/png", extensions: []string{".png"}},
+	"gif":  {mime: "image/gif", extensions: []string{".gif"}},
+	"webp": {mime: "image/webp", extensions: []string{".webp"}},
+	"docx": {mime: "application/vnd.openxmlformats-officedocument.wordprocessingml.document", extensions: []string{".docx"}},
+	"xlsx": {mime: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", extensions: []string{".xlsx"}},
+	"pptx": {mime: "application/vnd.openxmlformats-officedocument.presentationml.presentation", extensions: []string{".pptx"}},
+	"zip":  {mime: "application/zip", extensions: []string{".zip"}, aliases: []string{"application/x-zip-compressed"}},
+	"txt":  {mime: "text/plain", extensions: []string{".txt", ".csv", ".log"}, aliases: []string{"text/csv"}},
+}
+
+// FileTypes возвращает имена распознаваемых типов вложений
+func FileTypes() []string {
+	names := make([]string, 0, len(fileTypes))
+	for name := range fileTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UploadPolicy допустимые типы вложений и ограничения размера
+type UploadPolicy struct {
+	// AllowedTypes имена типов из FileTypes; пустой список разрешает все
+	AllowedTypes []string
+	// DefaultMaxSize ограничение для типов без собственного; 0 - MaxFileSize
+	DefaultMaxSize int64
+	MaxSizes       map[string]int64
+}
+
+// FileRejectedError отказ в приеме вложения; описание возвращается клиенту в структурированном виде
+type FileRejectedError struct {
+	Rejection models.FileRejection
+}
+
+func (e *FileRejectedError) Error() string {
+	return fmt.Sprintf("file rejected: %s", e.Rejection.Reason)
+}
+
+// InspectedFile тип вложения, определенный по содержимому
+type InspectedFile struct {
+	Type string
+	MIME string
+	Size int64
+}
+
+// FileInspector определяет тип вложения по сигнатуре содержимого, а не по заявленному клиентом,
+// и проверяет его по UploadPolicy
+type FileInspector struct {
+	allowed map[string]bool
+	policy  UploadPolicy
+}
+
+func NewFileInspector(policy UploadPolicy) (*FileInspector, error) {
+	allowed := make(map[string]bool)
+	names := policy.AllowedTypes
+	if len(names) == 0 {
+		names = FileTypes()
+	}
+	for _, name := range names {
+		if _, ok := fileTypes[name]; !ok {
+			return nil, fmt.Errorf("unknown file type %q", name)
+		}
+		allowed[name] = true
+	}
+	for name := range policy.MaxSizes {
+		if _, ok := fileTypes[name]; !ok {
+			return nil, fmt.Errorf("unknown file type %q in size limits", name)
+		}
+	}
+
+	return &FileInspector{allowed: allowed, policy: policy}, nil
+}
+
+// Inspect проверяет содержимое вложения с заявленными именем и MIME-типом; пустые значения не проверяются.
+// Отказ возвращается как *FileRejectedError
+func (i *FileInspector) Inspect(data []byte, declaredName, declaredType string) (*InspectedFile, error) {
+	return i.InspectFile(bytes.NewReader(data), int64(len(data)), declaredName, declaredType)
+}
+
+// InspectFile проверяет вложение размером size как Inspect, но читает из file только сигнатуру,
+// каталог архива и, для текста, содержимое потоком, не загружая файл в память
+func (i *FileInspector) InspectFile(file io.ReaderAt, size int64, declaredName, declaredType string) (*InspectedFile, error) {
+	rejection := models.FileRejection{
+		DeclaredName: declaredName,
+		DeclaredType: declaredType,
+		Size:         size,
+	}
+
+	if size == 0 {
+		return nil, i.reject(rejection, models.FileRejectionEmpty, "file is empty")
+	}
+	if size > MaxFileSize {
+		rejection.MaxSize = MaxFileSize
+		return nil, i.reject(rejection, models.FileRejectionTooLarge, "file exceeds the maximum allowed size")
+	}
+
+	header := make([]byte, min(size, fileHeaderSize))
+	if _, err := file.ReadAt(header, 0); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	name, code, reason := detectFileType(header, file, size)
+	if code != "" {
+		rejection.DetectedType = name
+		return nil, i.reject(rejection, code, reason)
+	}
+	rejection.DetectedType = name
+
+	if !i.allowed[name] {
+		rejection.AllowedTypes = i.allowedTypes()
+		if name == "" {
+			return nil, i.reject(rejection, models.FileRejectionUnsupportedType, "file type is not recognized")
+		}
+		return nil, i.reject(rejection, models.FileRejectionUnsupportedType, fmt.Sprintf("file type %s is not allowed", name))
+	}
+
+	detected := fileTypes[name]
+	if declaredName != "" {
+		ext := strings.ToLower(filepath.Ext(declaredName))
+		if !slices.Contains(detected.extensions, ext) {
+			return nil, i.reject(rejection, models.FileRejectionTypeMismatch,
+				fmt.Sprintf("file extension %q does not match its content (%s)", ext, name))
+		}
+	}
+	if declaredType != "" {
+		mediaType, _, err := mime.ParseMediaType(declaredType)
+		if err != nil || (mediaType != "application/octet-stream" && mediaType != detected.mime && !slices.Contains(detected.aliases, mediaType)) {
+			return nil, i.reject(rejection, models.FileRejectionTypeMismatch,
+				fmt.Sprintf("declared type %q does not match file content (%s)", declaredType, detected.mime))
+		}
+	}
+
+	if limit := i.maxSize(name); size > limit {
+		rejection.MaxSize = limit
+		return nil, i.reject(rejection, models.FileRejectionTooLarge, fmt.Sprintf("%s files must not exceed %d bytes", name, limit))
+	}
+
+	return &InspectedFile{Type: name, MIME: detected.mime, Size: size}, nil
+}
+
+// LargestMaxSize наибольший допустимый размер вложения среди всех типов
+func (i *FileInspector) LargestMaxSize() int64 {
+	largest := i.maxSize("")
+	for _, limit := range i.policy.MaxSizes {
+		largest = max(largest, limit)
+	}
+	return min(largest, MaxFileSize)
+}
+
+func (i *FileInspector) maxSize(name string) int64 {
+	if limit, ok := i.policy.MaxSizes[name]; ok && limit > 0 {
+		return limit
+	}
+	if i.policy.DefaultMaxSize > 0 {
+		return i.policy.DefaultMaxSize
+	}
+	return MaxFileSize
+}
+
+func (i *FileInspector) allowedTypes() []string {
+	names := make([]string, 0, len(i.allowed))
+	for name := range i.allowed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (i *FileInspector) reject(rejection models.FileRejection, code models.FileRejectionCode, reason string) error {
+	rejection.Code = code
+	rejection.Reason = reason
+	metrics.UploadsRejectedTotal.WithLabelValues(string(code)).Inc()
+	return &FileRejectedError{Rejection: rejection}
+}
+
+// fileHeaderSize сколько байт с начала файла достаточно для проверки сигнатур
+const fileHeaderSize = 512
+
+// plainTextChunkSize размер блока, которым текст проверяется на корректность UTF-8
+const plainTextChunkSize = 32 * 1024
+
+var (
+	signaturePDF  = []byte("%PDF-")
+	signatureJPEG = []byte{0xFF, 0xD8, 0xFF}
+	signaturePNG  = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}
+	signatureZIP  = []byte{'P', 'K', 0x03, 0x04}
+	// signatureOLE контейнер старых документов Office и зашифрованных документов OOXML
+	signatureOLE = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+	signatureRAR = []byte("Rar!\x1A\x07")
+	signature7z  = []byte{'7', 'z', 0xBC, 0xAF, 0x27, 0x1C}
+)
+
+// detectFileType определяет тип по сигнатуре в header - начале файла file размером size. Для содержимого,
+// которое нельзя принимать независимо от политики (макросы, шифрование), возвращается код отказа
+func detectFileType(header []byte, file io.ReaderAt, size int64) (string, models.FileRejectionCode, string) {
+	switch {
+	case bytes.HasPrefix(header, signaturePDF):
+		return "pdf", "", ""
+	case bytes.HasPrefix(header, signatureJPEG):
+		return "jpeg", "", ""
+	case bytes.HasPrefix(header, signaturePNG):
+		return "png", "", ""
+	case bytes.HasPrefix(header, []byte("GIF87a")), bytes.HasPrefix(header, []byte("GIF89a")):
+		return "gif", "", ""
+	case len(header) >= 12 && bytes.Equal(header[:4], []byte("RIFF")) && bytes.Equal(header[8:12], []byte("WEBP")):
+		return "webp", "", ""
+	case bytes.HasPrefix(header, signatureZIP):
+		return detectZipType(file, size)
+	case bytes.HasPrefix(header, signatureOLE):
+		return "", models.FileRejectionUnsupportedType, "legacy or password-protected Office documents are not accepted"
+	case bytes.HasPrefix(header, signatureRAR), bytes.HasPrefix(header, signature7z):
+		return "", models.FileRejectionUnsupportedType, "only ZIP archives are accepted"
+	case isPlainText(io.NewSectionReader(file, 0, size)):
+		return "txt", "", ""
+	}
+	return "", "", ""
+}
+
+// detectZipType отличает документы OOXML от обычных архивов и отклоняет зашифрованные архивы
+// и документы с макросами, в том числе переименованные в .docx
+func detectZipType(file io.ReaderAt, size int64) (string, models.FileRejectionCode, string) {
+	archive, err := zip.NewReader(file, size)
+	if err != nil {
+		return "", models.FileRejectionUnsupportedType, "archive is corrupted"
+	}
+
+	var contentTypes *zip.File
+	parts := make(map[string]bool, len(archive.File))
+	for _, file := range archive.File {
+		if file.Flags&0x1 != 0 {
+			return "zip", models.FileRejectionEncryptedArchive, "encrypted archives are not accepted"
+		}
+		if strings.EqualFold(filepath.Base(file.Name), "vbaProject.bin") {
+			return "", models.FileRejectionMacroEnabled, "documents with macros are not accepted"
+		}
+		if file.Name == "[Content_Types].xml" {
+			contentTypes = file
+		}
+		parts[strings.SplitN(file.Name, "/", 2)[0]] = true
+	}
+
+	if contentTypes == nil {
+		return "zip", "", ""
+	}
+
+	manifest, err := readZipFile(contentTypes)
+	if err != nil {
+		return "", models.FileRejectionUnsupportedType, "document is corrupted"
+	}
+	if bytes.Contains(bytes.ToLower(manifest), []byte("macroenabled")) {
+		return "", models.FileRejectionMacroEnabled, "documents with macros are not accepted"
+	}
+
+	switch {
+	case parts["word"]:
+		return "docx", "", ""
+	case parts["xl"]:
+		return "xlsx", "", ""
+	case parts["ppt"]:
+		return "pptx", "", ""
+	}
+	return "zip", "", ""
+}
+
+// readZipFile читает файл архива с ограничением, чтобы архив-бомба не занял всю память
+func readZipFile(file *zip.File) ([]byte, error) {
+	const maxManifestSize = 1 << 20
+
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return io.ReadAll(io.LimitReader(rc, maxManifestSize))
+}
+
+// isPlainText считает текстом корректный UTF-8 без нулевых байтов. Файл читается блоками,
+// неполный символ в конце блока переносится в следующий
+func isPlainText(file io.Reader) bool {
+	buf := make([]byte, plainTextChunkSize+utf8.UTFMax)
+	pending := 0
+	for {
+		n, err := file.Read(buf[pending:plainTextChunkSize])
+		chunk := buf[:pending+n]
+
+		valid := len(chunk)
+		if err == nil {
+			for start := len(chunk) - 1; start >= 0 && start >= len(chunk)-utf8.UTFMax; start-- {
+				if utf8.RuneStart(chunk[start]) {
+					if !utf8.FullRune(chunk[start:]) {
+						valid = start
+					}
+					break
+				}
+			}
+		}
+		if !utf8.Valid(chunk[:valid]) || bytes.IndexByte(chunk[:valid], 0) >= 0 {
+			return false
+		}
+		pending = copy(buf, chunk[valid:])
+
+		switch {
+		case errors.Is(err, io.EOF):
+			return true
+		case err != nil:
+			return false
+		}
+	}
+}
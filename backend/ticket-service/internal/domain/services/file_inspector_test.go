@@ -0,0 +1,146 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ticket-service/internal/domain/models"
+)
+
+// buildZip собирает архив из указанных файлов; encrypted помечает записи как зашифрованные
+func buildZip(t *testing.T, files map[string]string, encrypted bool) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		header := &zip.FileHeader{Name: name, Method: zip.Store}
+		if encrypted {
+			header.Flags |= 0x1
+		}
+		f, err := w.CreateHeader(header)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestFileInspectorInspect(t *testing.T) {
+	pdf := []byte("%PDF-1.7\n%test document")
+	png := append([]byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}, make([]byte, 64)...)
+	docx := buildZip(t, map[string]string{
+		"[Content_Types].xml": `<Types><Override ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/></Types>`,
+		"word/document.xml":   "<document/>",
+	}, false)
+	docm := buildZip(t, map[string]string{
+		"[Content_Types].xml": `<Types><Override ContentType="application/vnd.ms-word.document.macroEnabled.main+xml"/></Types>`,
+		"word/document.xml":   "<document/>",
+	}, false)
+	vbaDocx := buildZip(t, map[string]string{
+		"[Content_Types].xml": "<Types/>",
+		"word/document.xml":   "<document/>",
+		"word/vbaProject.bin": "macro",
+	}, false)
+	encrypted := buildZip(t, map[string]string{"secret.txt": "data"}, true)
+
+	inspector, err := NewFileInspector(UploadPolicy{
+		AllowedTypes: []string{"pdf", "png", "docx", "zip", "txt"},
+		MaxSizes:     map[string]int64{"png": 32},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		data         []byte
+		declaredName string
+		declaredType string
+		wantType     string
+		wantCode     models.FileRejectionCode
+	}{
+		{name: "pdf accepted", data: pdf, declaredName: "report.pdf", declaredType: "application/pdf", wantType: "pdf"},
+		{name: "octet-stream declared type accepted", data: pdf, declaredName: "report.PDF", declaredType: "application/octet-stream", wantType: "pdf"},
+		{name: "docx detected by parts", data: docx, declaredName: "letter.docx", wantType: "docx"},
+		{name: "text accepted", data: []byte("hello"), declaredName: "notes.txt", wantType: "txt"},
+		{name: "empty file", data: nil, declaredName: "empty.txt", wantCode: models.FileRejectionEmpty},
+		{name: "extension mismatch", data: pdf, declaredName: "photo.png", wantCode: models.FileRejectionTypeMismatch},
+		{name: "declared type mismatch", data: pdf, declaredName: "report.pdf", declaredType: "image/png", wantCode: models.FileRejectionTypeMismatch},
+		{name: "macro-enabled manifest", data: docm, declaredName: "letter.docx", wantCode: models.FileRejectionMacroEnabled},
+		{name: "vba project in docx", data: vbaDocx, declaredName: "letter.docx", wantCode: models.FileRejectionMacroEnabled},
+		{name: "encrypted archive", data: encrypted, declaredName: "secret.zip", wantCode: models.FileRejectionEncryptedArchive},
+		{name: "type not in allowlist", data: []byte("GIF89a....."), declaredName: "anim.gif", wantCode: models.FileRejectionUnsupportedType},
+		{name: "unrecognized binary", data: []byte{0x00, 0x01, 0x02, 0xFF}, declaredName: "blob.bin", wantCode: models.FileRejectionUnsupportedType},
+		{name: "per-type size limit", data: png, declaredName: "image.png", wantCode: models.FileRejectionTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inspected, err := inspector.Inspect(tt.data, tt.declaredName, tt.declaredType)
+
+			if tt.wantCode == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantType, inspected.Type)
+				assert.Equal(t, int64(len(tt.data)), inspected.Size)
+				return
+			}
+
+			var rejected *FileRejectedError
+			require.True(t, errors.As(err, &rejected), "expected FileRejectedError, got %v", err)
+			assert.Equal(t, tt.wantCode, rejected.Rejection.Code)
+			assert.NotEmpty(t, rejected.Rejection.Reason)
+		})
+	}
+}
+
+func TestFileInspectorRejectionDetails(t *testing.T) {
+	inspector, err := NewFileInspector(UploadPolicy{AllowedTypes: []string{"pdf"}, DefaultMaxSize: 8})
+	require.NoError(t, err)
+
+	_, err = inspector.Inspect([]byte("%PDF-1.7 too long"), "a.pdf", "")
+	var rejected *FileRejectedError
+	require.True(t, errors.As(err, &rejected))
+	assert.Equal(t, models.FileRejectionTooLarge, rejected.Rejection.Code)
+	assert.Equal(t, int64(8), rejected.Rejection.MaxSize)
+	assert.Equal(t, "pdf", rejected.Rejection.DetectedType)
+
+	_, err = inspector.Inspect([]byte("plain text"), "a.txt", "")
+	require.True(t, errors.As(err, &rejected))
+	assert.Equal(t, models.FileRejectionUnsupportedType, rejected.Rejection.Code)
+	assert.Equal(t, []string{"pdf"}, rejected.Rejection.AllowedTypes)
+}
+
+func TestNewFileInspectorUnknownType(t *testing.T) {
+	_, err := NewFileInspector(UploadPolicy{AllowedTypes: []string{"exe"}})
+	assert.Error(t, err)
+
+	_, err = NewFileInspector(UploadPolicy{MaxSizes: map[string]int64{"exe": 1}})
+	assert.Error(t, err)
+}
+
+func TestIsPlainTextAcrossChunks(t *testing.T) {
+	// Двухбайтовый символ начинается последним байтом первого блока
+	split := append(bytes.Repeat([]byte("a"), plainTextChunkSize-1), []byte("ж конец")...)
+
+	tests := []struct {
+		name string
+		data []byte
+		want bool
+	}{
+		{name: "symbol split between chunks", data: split, want: true},
+		{name: "several chunks of cyrillic", data: bytes.Repeat([]byte("текст "), plainTextChunkSize), want: true},
+		{name: "truncated symbol at the end", data: split[:plainTextChunkSize], want: false},
+		{name: "zero byte in a later chunk", data: append(bytes.Repeat([]byte("a"), plainTextChunkSize+10), 0), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isPlainText(bytes.NewReader(tt.data)))
+		})
+	}
+}
@@ -63,4 +63,8 @@ func (s *MinioFileService) CheckFileExists(ctx context.Context, filepath string)
 		return false, err
 	}
 	return true, nil
-} 
\ No newline at end of file
+} 
+// ObjectKey возвращает ключ объекта; MinioFileService выдает ключи вместо URL
+func (s *MinioFileService) ObjectKey(fileURL string) (string, error) {
+	return fileURL, nil
+}
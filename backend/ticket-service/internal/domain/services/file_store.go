@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+	"ticket-service/internal/infrastructure/metrics"
+	"ticket-service/internal/logger"
+)
+
+// fileGCBatchSize число объектов без ссылок, удаляемых сборщиком за один проход
+const fileGCBatchSize = 100
+
+// contentFolder префикс ключей объектов, адресуемых по содержимому
+const contentFolder = "objects"
+
+// ContentKey возвращает ключ объекта с sha256 содержимого sum: objects/<первые два символа>/<sum>
+func ContentKey(sum string) string {
+	return contentFolder + "/" + sum[:2] + "/" + sum
+}
+
+// FileStore хранит вложения по sha256 содержимого поверх хранилища storage: одинаковые файлы
+// хранятся один раз, а ссылки на них учитываются в FileObjectRepository. Объект удаляется не
+// при удалении вложения, а сборщиком мусора, когда на него не остается ссылок
+type FileStore struct {
+	storage    IFileService
+	objectRepo repositories.FileObjectRepository
+	uow        repositories.UnitOfWork
+	// grace объект без ссылок удаляется не раньше этого срока, чтобы не удалить файл,
+	// загруженный для тикета, который еще не сохранен
+	grace time.Duration
+}
+
+func NewFileStore(storage IFileService, objectRepo repositories.FileObjectRepository, uow repositories.UnitOfWork, grace time.Duration) *FileStore {
+	return &FileStore{
+		storage:    storage,
+		objectRepo: objectRepo,
+		uow:        uow,
+		grace:      grace,
+	}
+}
+
+// UploadFile сохраняет файл под ключом ContentKey; folder и id остаются для журнала.
+// Если объект с тем же содержимым уже есть, файл не загружается повторно
+func (s *FileStore) UploadFile(ctx context.Context, file io.Reader, folder string, id string) (string, error) {
+	content, object, err := openContent(file)
+	if err != nil {
+		return "", err
+	}
+	defer content.Close()
+	return s.store(ctx, s.objectRepo, object, content, folder, id)
+}
+
+// UploadFileTx загружает файл как UploadFile, но ссылку берет через objects - репозиторий транзакции,
+// в которой сохраняется вложение, так что при откате ссылка снимается вместе с ним. Объект заранее
+// учитывается без ссылок: файл, загруженный в откаченной транзакции, удалит сборщик мусора.
+// Без objects ссылка берется вне транзакции
+func (s *FileStore) UploadFileTx(ctx context.Context, objects repositories.FileObjectRepository, file io.Reader, folder string, id string) (string, error) {
+	content, object, err := openContent(file)
+	if err != nil {
+		return "", err
+	}
+	defer content.Close()
+	if objects == nil {
+		return s.store(ctx, s.objectRepo, object, content, folder, id)
+	}
+	if err := s.objectRepo.Register(ctx, object); err != nil {
+		return "", err
+	}
+	return s.store(ctx, objects, object, content, folder, id)
+}
+
+// openContent открывает файл для повторного чтения и описывает объект с ключом по sha256,
+// посчитанному одним проходом по содержимому
+func openContent(file io.Reader) (*attachment, *models.FileObject, error) {
+	content, err := openAttachment(file)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content.reader()); err != nil {
+		content.Close()
+		return nil, nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+	return content, &models.FileObject{Key: ContentKey(sum), SHA256: sum, Size: content.size}, nil
+}
+
+// store берет ссылку на объект через objectRepo и загружает content, если объекта еще нет в хранилище
+func (s *FileStore) store(ctx context.Context, objectRepo repositories.FileObjectRepository, object *models.FileObject, content *attachment, folder string, id string) (string, error) {
+	created, err := objectRepo.Acquire(ctx, object)
+	if err != nil {
+		return "", err
+	}
+
+	// Объект мог пропасть из хранилища при учтенной записи, поэтому при повторной загрузке проверяется его наличие
+	exists := false
+	if !created {
+		if exists, err = s.storage.CheckFileExists(ctx, object.Key); err != nil {
+			releaseFileObject(ctx, objectRepo, object.Key)
+			return "", err
+		}
+	}
+
+	if exists {
+		metrics.FileObjectsDeduplicatedTotal.Inc()
+		logger.Info("File deduplicated", "key", object.Key, "folder", folder, "id", id, "refs", object.RefCount)
+		return s.storage.GetFileURL(ctx, object.Key)
+	}
+
+	fileURL, err := s.storage.UploadFile(ctx, content.reader(), contentFolder+"/"+object.SHA256[:2], object.SHA256)
+	if err != nil {
+		releaseFileObject(ctx, objectRepo, object.Key)
+		return "", err
+	}
+	logger.Info("File stored", "key", object.Key, "folder", folder, "id", id, "size", object.Size)
+	return fileURL, nil
+}
+
+func (s *FileStore) DownloadFile(ctx context.Context, fileURL string) (io.ReadCloser, error) {
+	return s.storage.DownloadFile(ctx, fileURL)
+}
+
+// DeleteFile снимает ссылку на объект; сам объект удалит сборщик мусора. Файлы, загруженные
+// до адресации по содержимому, не учитываются и удаляются сразу
+func (s *FileStore) DeleteFile(ctx context.Context, fileURL string) error {
+	key, err := s.storage.ObjectKey(fileURL)
+	if err != nil {
+		return err
+	}
+
+	found, err := s.objectRepo.Release(ctx, key)
+	if err != nil {
+		return err
+	}
+	if found {
+		return nil
+	}
+	return s.storage.DeleteFile(ctx, fileURL)
+}
+
+func (s *FileStore) GetFileURL(ctx context.Context, fileURL string) (string, error) {
+	return s.storage.GetFileURL(ctx, fileURL)
+}
+
+func (s *FileStore) CheckFileExists(ctx context.Context, fileURL string) (bool, error) {
+	return s.storage.CheckFileExists(ctx, fileURL)
+}
+
+func (s *FileStore) ObjectKey(fileURL string) (string, error) {
+	return s.storage.ObjectKey(fileURL)
+}
+
+// CollectGarbage пересчитывает ссылки по вложениям тикетов и ответов и удаляет объекты,
+// на которые никто не ссылается дольше grace
+func (s *FileStore) CollectGarbage(ctx context.Context) error {
+	if err := s.objectRepo.Reconcile(ctx); err != nil {
+		return err
+	}
+
+	before := time.Now().Add(-s.grace)
+	collected := 0
+	for {
+		keys, err := s.objectRepo.GetUnreferenced(ctx, before, fileGCBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			deleted, err := s.collect(ctx, key, before)
+			if err != nil {
+				logger.Error("Failed to collect file object", "error", err, "key", key)
+				return err
+			}
+			if deleted {
+				collected++
+				metrics.FileObjectsCollectedTotal.Inc()
+			}
+		}
+
+		if len(keys) < fileGCBatchSize {
+			break
+		}
+	}
+
+	if collected > 0 {
+		logger.Info("Unreferenced file objects collected", "count", collected)
+	}
+	return nil
+}
+
+// collect удаляет запись и объект в одной транзакции: пока объект удаляется из хранилища, строка
+// заблокирована, и загрузка того же содержимого дождется коммита и загрузит объект заново
+func (s *FileStore) collect(ctx context.Context, key string, before time.Time) (bool, error) {
+	deleteObject := func(objectRepo repositories.FileObjectRepository) (bool, error) {
+		deleted, err := objectRepo.DeleteUnreferenced(ctx, key, before)
+		if err != nil || !deleted {
+			return false, err
+		}
+		if err := s.storage.DeleteFile(ctx, key); err != nil && !errors.Is(err, ErrFileNotFound) {
+			return false, err
+		}
+		if err := s.storage.DeleteFile(ctx, PreviewKey(key)); err != nil && !errors.Is(err, ErrFileNotFound) {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if s.uow == nil {
+		return deleteObject(s.objectRepo)
+	}
+
+	var deleted bool
+	err := s.uow.Do(ctx, func(repos repositories.TxRepositories) error {
+		var err error
+		deleted, err = deleteObject(repos.FileObjects)
+		return err
+	})
+	return deleted, err
+}
+
+// releaseFileObject снимает ссылку, взятую для загрузки, которая не удалась
+func releaseFileObject(ctx context.Context, objectRepo repositories.FileObjectRepository, key string) {
+	if _, err := objectRepo.Release(ctx, key); err != nil {
+		logger.Error("Failed to release file object", "error", err, "key", key)
+	}
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+)
+
+type MockFileObjectRepository struct {
+	mock.Mock
+}
+
+func (m *MockFileObjectRepository) Acquire(ctx context.Context, object *models.FileObject) (bool, error) {
+	args := m.Called(ctx, object)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockFileObjectRepository) Register(ctx context.Context, object *models.FileObject) error {
+	args := m.Called(ctx, object)
+	return args.Error(0)
+}
+
+func (m *MockFileObjectRepository) Release(ctx context.Context, key string) (bool, error) {
+	args := m.Called(ctx, key)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockFileObjectRepository) Reconcile(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockFileObjectRepository) GetUnreferenced(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	args := m.Called(ctx, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockFileObjectRepository) DeleteUnreferenced(ctx context.Context, key string, before time.Time) (bool, error) {
+	args := m.Called(ctx, key, before)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockFileObjectRepository) GetPendingPreviews(ctx context.Context, limit int) ([]string, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockFileObjectRepository) SetPreviewStatus(ctx context.Context, key string, status models.FilePreviewStatus) error {
+	args := m.Called(ctx, key, status)
+	return args.Error(0)
+}
+
+func contentSum(content string) string {
+	digest := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(digest[:])
+}
+
+func TestFileStoreUploadFile(t *testing.T) {
+	sum := contentSum("content")
+	key := ContentKey(sum)
+
+	tests := []struct {
+		name      string
+		mockSetup func(*MockFileObjectRepository, *MockFileService)
+		wantURL   string
+		wantErr   bool
+	}{
+		{
+			name: "new content is uploaded under content key",
+			mockSetup: func(repo *MockFileObjectRepository, storage *MockFileService) {
+				repo.On("Acquire", mock.Anything, mock.MatchedBy(func(o *models.FileObject) bool {
+					return o.Key == key && o.SHA256 == sum && o.Size == int64(len("content"))
+				})).Return(true, nil)
+				storage.On("UploadFile", mock.Anything, mock.Anything, "objects/"+sum[:2], sum).Return("url/"+key, nil)
+			},
+			wantURL: "url/" + key,
+		},
+		{
+			name: "same content is not uploaded twice",
+			mockSetup: func(repo *MockFileObjectRepository, storage *MockFileService) {
+				repo.On("Acquire", mock.Anything, mock.Anything).Return(false, nil)
+				storage.On("CheckFileExists", mock.Anything, key).Return(true, nil)
+				storage.On("GetFileURL", mock.Anything, key).Return("url/"+key, nil)
+			},
+			wantURL: "url/" + key,
+		},
+		{
+			name: "missing object is uploaded again",
+			mockSetup: func(repo *MockFileObjectRepository, storage *MockFileService) {
+				repo.On("Acquire", mock.Anything, mock.Anything).Return(false, nil)
+				storage.On("CheckFileExists", mock.Anything, key).Return(false, nil)
+				storage.On("UploadFile", mock.Anything, mock.Anything, "objects/"+sum[:2], sum).Return("url/"+key, nil)
+			},
+			wantURL: "url/" + key,
+		},
+		{
+			name: "reference is released when upload fails",
+			mockSetup: func(repo *MockFileObjectRepository, storage *MockFileService) {
+				repo.On("Acquire", mock.Anything, mock.Anything).Return(true, nil)
+				repo.On("Release", mock.Anything, key).Return(true, nil)
+				storage.On("UploadFile", mock.Anything, mock.Anything, "objects/"+sum[:2], sum).Return("", errors.New("storage error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockFileObjectRepository)
+			storage := new(MockFileService)
+			tt.mockSetup(repo, storage)
+
+			store := NewFileStore(storage, repo, nil, time.Hour)
+			fileURL, err := store.UploadFile(context.Background(), strings.NewReader("content"), "tickets", "1")
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantURL, fileURL)
+			}
+			repo.AssertExpectations(t)
+			storage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFileStoreUploadFileTx(t *testing.T) {
+	sum := contentSum("content")
+	key := ContentKey(sum)
+
+	repo := new(MockFileObjectRepository)
+	txRepo := new(MockFileObjectRepository)
+	storage := new(MockFileService)
+
+	// Объект учитывается без ссылок вне транзакции, а ссылка берется в ней
+	repo.On("Register", mock.Anything, mock.MatchedBy(func(o *models.FileObject) bool {
+		return o.Key == key && o.SHA256 == sum
+	})).Return(nil)
+	txRepo.On("Acquire", mock.Anything, mock.Anything).Return(false, nil)
+	storage.On("CheckFileExists", mock.Anything, key).Return(false, nil)
+	storage.On("UploadFile", mock.Anything, mock.Anything, "objects/"+sum[:2], sum).Return("url/"+key, nil)
+
+	store := NewFileStore(storage, repo, nil, time.Hour)
+	fileURL, err := store.UploadFileTx(context.Background(), txRepo, strings.NewReader("content"), "responses", "1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "url/"+key, fileURL)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "Acquire", mock.Anything, mock.Anything)
+	txRepo.AssertExpectations(t)
+	storage.AssertExpectations(t)
+}
+
+func TestFileStoreDeleteFile(t *testing.T) {
+	t.Run("tracked object only loses a reference", func(t *testing.T) {
+		repo := new(MockFileObjectRepository)
+		storage := new(MockFileService)
+		storage.On("ObjectKey", "url/objects/ab/abc").Return("objects/ab/abc", nil)
+		repo.On("Release", mock.Anything, "objects/ab/abc").Return(true, nil)
+
+		err := NewFileStore(storage, repo, nil, time.Hour).DeleteFile(context.Background(), "url/objects/ab/abc")
+
+		assert.NoError(t, err)
+		storage.AssertNotCalled(t, "DeleteFile", mock.Anything, mock.Anything)
+	})
+
+	t.Run("untracked legacy file is deleted", func(t *testing.T) {
+		repo := new(MockFileObjectRepository)
+		storage := new(MockFileService)
+		storage.On("ObjectKey", "tickets/1/20240101120000").Return("tickets/1/20240101120000", nil)
+		repo.On("Release", mock.Anything, "tickets/1/20240101120000").Return(false, nil)
+		storage.On("DeleteFile", mock.Anything, "tickets/1/20240101120000").Return(nil)
+
+		err := NewFileStore(storage, repo, nil, time.Hour).DeleteFile(context.Background(), "tickets/1/20240101120000")
+
+		assert.NoError(t, err)
+		storage.AssertExpectations(t)
+	})
+}
+
+func TestFileStoreCollectGarbage(t *testing.T) {
+	repo := new(MockFileObjectRepository)
+	txRepo := new(MockFileObjectRepository)
+	storage := new(MockFileService)
+
+	repo.On("Reconcile", mock.Anything).Return(nil)
+	repo.On("GetUnreferenced", mock.Anything, mock.Anything, fileGCBatchSize).Return([]string{"objects/aa/a", "objects/bb/b", "objects/cc/c"}, nil)
+	// Пока сборщик работал, на объект b появилась ссылка
+	txRepo.On("DeleteUnreferenced", mock.Anything, "objects/aa/a", mock.Anything).Return(true, nil)
+	txRepo.On("DeleteUnreferenced", mock.Anything, "objects/bb/b", mock.Anything).Return(false, nil)
+	txRepo.On("DeleteUnreferenced", mock.Anything, "objects/cc/c", mock.Anything).Return(true, nil)
+	storage.On("DeleteFile", mock.Anything, "objects/aa/a").Return(nil)
+	storage.On("DeleteFile", mock.Anything, PreviewKey("objects/aa/a")).Return(nil)
+	storage.On("DeleteFile", mock.Anything, "objects/cc/c").Return(ErrFileNotFound)
+	// Превью строится не для всех вложений
+	storage.On("DeleteFile", mock.Anything, PreviewKey("objects/cc/c")).Return(ErrFileNotFound)
+
+	uow := &fakeUnitOfWork{repos: repositories.TxRepositories{FileObjects: txRepo}}
+	err := NewFileStore(storage, repo, uow, time.Hour).CollectGarbage(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, uow.commits)
+	storage.AssertNotCalled(t, "DeleteFile", mock.Anything, "objects/bb/b")
+	txRepo.AssertExpectations(t)
+	storage.AssertExpectations(t)
+
+	before := repo.Calls[1].Arguments.Get(1).(time.Time)
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), before, time.Minute)
+}
+
+func TestFileStoreCollectGarbageRollsBackOnStorageError(t *testing.T) {
+	repo := new(MockFileObjectRepository)
+	storage := new(MockFileService)
+
+	repo.On("Reconcile", mock.Anything).Return(nil)
+	repo.On("GetUnreferenced", mock.Anything, mock.Anything, fileGCBatchSize).Return([]string{"objects/aa/a"}, nil)
+	repo.On("DeleteUnreferenced", mock.Anything, "objects/aa/a", mock.Anything).Return(true, nil)
+	storage.On("DeleteFile", mock.Anything, "objects/aa/a").Return(errors.New("storage unavailable"))
+
+	uow := &fakeUnitOfWork{repos: repositories.TxRepositories{FileObjects: repo}}
+	err := NewFileStore(storage, repo, uow, time.Hour).CollectGarbage(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, uow.rollbacks)
+}
+
+func TestOpenAttachment(t *testing.T) {
+	// Поток без произвольного доступа переписывается во временный файл, который удаляется при закрытии
+	content, err := openAttachment(io.MultiReader(strings.NewReader("con"), strings.NewReader("tent")))
+	require.NoError(t, err)
+	require.NotNil(t, content.tmp)
+	path := content.tmp.Name()
+
+	data, err := io.ReadAll(content.reader())
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(data))
+	assert.Equal(t, int64(7), content.size)
+
+	require.NoError(t, content.Close())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	// Файл с произвольным доступом читается на месте
+	content, err = openAttachment(strings.NewReader("content"))
+	require.NoError(t, err)
+	assert.Nil(t, content.tmp)
+	assert.Equal(t, int64(7), content.size)
+}
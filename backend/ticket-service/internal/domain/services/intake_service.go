@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+	"ticket-service/internal/logger"
+)
+
+var (
+	ErrInvalidIntakeSchema = errors.New("invalid intake schema")
+	ErrInvalidIntakeFields = errors.New("invalid intake fields")
+)
+
+// intakeFieldName имя дополнительного поля: оно же ключ в JSONB и параметр фильтра fields[имя]
+var intakeFieldName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+const (
+	// maxIntakeFields ограничивает число дополнительных полей одной категории
+	maxIntakeFields = 30
+	// defaultIntakeMaxLength длина строкового поля, если maxLength в схеме не задан
+	defaultIntakeMaxLength = 1000
+)
+
+// IntakeService управляет дополнительными полями заявки, которые администраторы задают для
+// категорий, и проверяет значения этих полей при создании тикета
+type IntakeService struct {
+	intakeRepo repositories.IntakeRepository
+}
+
+func NewIntakeService(intakeRepo repositories.IntakeRepository) *IntakeService {
+	return &IntakeService{
+		intakeRepo: intakeRepo,
+	}
+}
+
+// ListSchemas возвращает схемы всех категорий; у категорий без дополнительных полей схема пустая
+func (s *IntakeService) ListSchemas(ctx context.Context) ([]*models.CategoryIntake, error) {
+	stored, err := s.intakeRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byCategory := make(map[models.TicketCategory]*models.CategoryIntake, len(stored))
+	for _, intake := range stored {
+		byCategory[intake.Category] = intake
+	}
+
+	intakes := make([]*models.CategoryIntake, 0, len(models.TicketCategories()))
+	for _, category := range models.TicketCategories() {
+		intake, ok := byCategory[category]
+		if !ok {
+			intake = &models.CategoryIntake{Category: category, Schema: emptyIntakeSchema()}
+		}
+		intakes = append(intakes, intake)
+	}
+	return intakes, nil
+}
+
+// GetSchema возвращает схему дополнительных полей категории
+func (s *IntakeService) GetSchema(ctx context.Context, category models.TicketCategory) (*models.CategoryIntake, error) {
+	intake, err := s.intakeRepo.Get(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+	if intake == nil {
+		return &models.CategoryIntake{Category: category, Schema: emptyIntakeSchema()}, nil
+	}
+	return intake, nil
+}
+
+// UpdateSchema заменяет схему дополнительных полей категории. Значения, сохраненные в тикетах
+// по прежней схеме, не меняются
+func (s *IntakeService) UpdateSchema(ctx context.Context, category models.TicketCategory, schema *models.IntakeSchema, adminID uuid.UUID) (*models.CategoryIntake, error) {
+	if err := validateIntakeSchema(schema); err != nil {
+		return nil, err
+	}
+	schema.Type = "object"
+	schema.AdditionalProperties = false
+	if schema.Properties == nil {
+		schema.Properties = map[string]*models.IntakeField{}
+	}
+
+	intake := &models.CategoryIntake{Category: category, Schema: schema, UpdatedBy: &adminID}
+	if err := s.intakeRepo.Upsert(ctx, intake); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Intake schema updated", "category", category, "fields", len(schema.Properties), "adminID", adminID)
+	return intake, nil
+}
+
+// ValidateFields проверяет значения дополнительных полей по схеме категории и возвращает их
+// в нормализованном виде: строки без пробелов по краям, пустые значения отброшены
+func (s *IntakeService) ValidateFields(ctx context.Context, category models.TicketCategory, values map[string]any) (map[string]any, error) {
+	intake, err := s.intakeRepo.Get(ctx, category)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get intake schema: %w", err)
+	}
+	schema := emptyIntakeSchema()
+	if intake != nil {
+		schema = intake.Schema
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make(map[string]any, len(values))
+	for _, name := range names {
+		field, ok := schema.Properties[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown field %q for category %s", ErrInvalidIntakeFields, name, category)
+		}
+		value, err := normalizeIntakeValue(field, values[name])
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %q %v", ErrInvalidIntakeFields, name, err)
+		}
+		if value != nil {
+			fields[name] = value
+		}
+	}
+
+	for _, name := range schema.Required {
+		if _, ok := fields[name]; !ok {
+			return nil, fmt.Errorf("%w: field %q is required", ErrInvalidIntakeFields, name)
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// normalizeIntakeValue проверяет значение по описанию поля; nil означает, что поле не заполнено.
+// Числа из JSON приходят как float64
+func normalizeIntakeValue(field *models.IntakeField, value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch field.Type {
+	case models.IntakeFieldString:
+		str, ok := value.(string)
+		if !ok {
+			return nil, errors.New("must be a string")
+		}
+		str = strings.TrimSpace(str)
+		if str == "" {
+			return nil, nil
+		}
+		if len(field.Enum) > 0 && !slices.Contains(field.Enum, str) {
+			return nil, fmt.Errorf("must be one of %s", strings.Join(field.Enum, ", "))
+		}
+		maxLength := defaultIntakeMaxLength
+		if field.MaxLength != nil {
+			maxLength = *field.MaxLength
+		}
+		length := utf8.RuneCountInString(str)
+		if length > maxLength {
+			return nil, fmt.Errorf("must be at most %d characters", maxLength)
+		}
+		if field.MinLength != nil && length < *field.MinLength {
+			return nil, fmt.Errorf("must be at least %d characters", *field.MinLength)
+		}
+		return str, nil
+
+	case models.IntakeFieldInteger, models.IntakeFieldNumber:
+		number, ok := value.(float64)
+		if !ok || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, errors.New("must be a number")
+		}
+		if field.Type == models.IntakeFieldInteger && number != math.Trunc(number) {
+			return nil, errors.New("must be an integer")
+		}
+		if field.Minimum != nil && number < *field.Minimum {
+			return nil, fmt.Errorf("must be at least %v", *field.Minimum)
+		}
+		if field.Maximum != nil && number > *field.Maximum {
+			return nil, fmt.Errorf("must be at most %v", *field.Maximum)
+		}
+		return number, nil
+
+	case models.IntakeFieldBoolean:
+		boolean, ok := value.(bool)
+		if !ok {
+			return nil, errors.New("must be a boolean")
+		}
+		return boolean, nil
+	}
+	return nil, fmt.Errorf("has unsupported type %q", field.Type)
+}
+
+// validateIntakeSchema проверяет, что схема использует только поддерживаемое подмножество JSON Schema
+func validateIntakeSchema(schema *models.IntakeSchema) error {
+	if schema == nil {
+		return fmt.Errorf("%w: schema is required", ErrInvalidIntakeSchema)
+	}
+	if schema.Type != "" && schema.Type != "object" {
+		return fmt.Errorf("%w: type must be object", ErrInvalidIntakeSchema)
+	}
+	if len(schema.Properties) > maxIntakeFields {
+		return fmt.Errorf("%w: at most %d fields are allowed", ErrInvalidIntakeSchema, maxIntakeFields)
+	}
+
+	for name, field := range schema.Properties {
+		if !intakeFieldName.MatchString(name) {
+			return fmt.Errorf("%w: field name %q must match %s", ErrInvalidIntakeSchema, name, intakeFieldName)
+		}
+		if field == nil {
+			return fmt.Errorf("%w: field %q is empty", ErrInvalidIntakeSchema, name)
+		}
+		if err := validateIntakeField(field); err != nil {
+			return fmt.Errorf("%w: field %q %v", ErrInvalidIntakeSchema, name, err)
+		}
+	}
+
+	seen := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		if _, ok := schema.Properties[name]; !ok {
+			return fmt.Errorf("%w: required field %q is not defined", ErrInvalidIntakeSchema, name)
+		}
+		if seen[name] {
+			return fmt.Errorf("%w: required field %q is listed twice", ErrInvalidIntakeSchema, name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+func validateIntakeField(field *models.IntakeField) error {
+	switch field.Type {
+	case models.IntakeFieldString, models.IntakeFieldInteger, models.IntakeFieldNumber, models.IntakeFieldBoolean:
+	default:
+		return fmt.Errorf("has unsupported type %q", field.Type)
+	}
+	if strings.TrimSpace(field.Labels.RU) == "" {
+		return errors.New("must have x-labels.ru")
+	}
+
+	isString := field.Type == models.IntakeFieldString
+	isNumber := field.Type == models.IntakeFieldInteger || field.Type == models.IntakeFieldNumber
+	if !isString && (len(field.Enum) > 0 || field.MinLength != nil || field.MaxLength != nil) {
+		return errors.New("may use enum, minLength and maxLength only with type string")
+	}
+	if !isNumber && (field.Minimum != nil || field.Maximum != nil) {
+		return errors.New("may use minimum and maximum only with numeric types")
+	}
+
+	if field.MinLength != nil && *field.MinLength < 0 || field.MaxLength != nil && *field.MaxLength < 1 {
+		return errors.New("has invalid length limits")
+	}
+	if field.MinLength != nil && field.MaxLength != nil && *field.MinLength > *field.MaxLength {
+		return errors.New("has minLength greater than maxLength")
+	}
+	if field.Minimum != nil && field.Maximum != nil && *field.Minimum > *field.Maximum {
+		return errors.New("has minimum greater than maximum")
+	}
+
+	options := make(map[string]bool, len(field.Enum))
+	for _, option := range field.Enum {
+		if strings.TrimSpace(option) == "" || option != strings.TrimSpace(option) {
+			return errors.New("has an empty or padded enum value")
+		}
+		if options[option] {
+			return fmt.Errorf("has duplicate enum value %q", option)
+		}
+		options[option] = true
+	}
+	for option := range field.EnumLabels {
+		if !options[option] {
+			return fmt.Errorf("has x-enum-labels for unknown value %q", option)
+		}
+	}
+	return nil
+}
+
+func emptyIntakeSchema() *models.IntakeSchema {
+	return &models.IntakeSchema{Type: "object", Properties: map[string]*models.IntakeField{}}
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"ticket-service/internal/domain/models"
+)
+
+type MockIntakeRepository struct {
+	mock.Mock
+}
+
+func (m *MockIntakeRepository) Get(ctx context.Context, category models.TicketCategory) (*models.CategoryIntake, error) {
+	args := m.Called(ctx, category)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CategoryIntake), args.Error(1)
+}
+
+func (m *MockIntakeRepository) List(ctx context.Context) ([]*models.CategoryIntake, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*models.CategoryIntake), args.Error(1)
+}
+
+func (m *MockIntakeRepository) Upsert(ctx context.Context, intake *models.CategoryIntake) error {
+	args := m.Called(ctx, intake)
+	return args.Error(0)
+}
+
+const recognitionIntakeSchema = `{
+	"properties": {
+		"country": {"type": "string", "enum": ["kz", "ru", "other"], "x-labels": {"kz": "Ел", "ru": "Страна", "en": "Country"}},
+		"graduation_year": {"type": "integer", "minimum": 1950, "maximum": 2100, "x-labels": {"ru": "Год окончания"}},
+		"gpa": {"type": "number", "minimum": 0, "maximum": 5, "x-labels": {"ru": "Средний балл"}},
+		"apostille": {"type": "boolean", "x-labels": {"ru": "Апостиль"}},
+		"university": {"type": "string", "minLength": 2, "maxLength": 10, "x-labels": {"ru": "Вуз"}}
+	},
+	"required": ["country", "graduation_year"]
+}`
+
+func recognitionIntake(t *testing.T) *models.CategoryIntake {
+	var schema models.IntakeSchema
+	if err := json.Unmarshal([]byte(recognitionIntakeSchema), &schema); err != nil {
+		t.Fatal(err)
+	}
+	return &models.CategoryIntake{Category: models.TicketCategoryRecognition, Schema: &schema}
+}
+
+func TestIntakeServiceValidateFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  string
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name:   "valid values are normalized",
+			values: `{"country": " kz ", "graduation_year": 2020, "gpa": 4.5, "apostille": true, "university": ""}`,
+			want:   map[string]any{"country": "kz", "graduation_year": float64(2020), "gpa": 4.5, "apostille": true},
+		},
+		{name: "missing required field", values: `{"country": "kz"}`, wantErr: true},
+		{name: "blank required string", values: `{"country": "  ", "graduation_year": 2020}`, wantErr: true},
+		{name: "unknown field", values: `{"country": "kz", "graduation_year": 2020, "city": "Астана"}`, wantErr: true},
+		{name: "value outside enum", values: `{"country": "us", "graduation_year": 2020}`, wantErr: true},
+		{name: "fractional integer", values: `{"country": "kz", "graduation_year": 2020.5}`, wantErr: true},
+		{name: "number below minimum", values: `{"country": "kz", "graduation_year": 1900}`, wantErr: true},
+		{name: "string instead of number", values: `{"country": "kz", "graduation_year": "2020"}`, wantErr: true},
+		{name: "string instead of boolean", values: `{"country": "kz", "graduation_year": 2020, "apostille": "yes"}`, wantErr: true},
+		{name: "string too long", values: `{"country": "kz", "graduation_year": 2020, "university": "Назарбаев Университет"}`, wantErr: true},
+		{name: "string too short", values: `{"country": "kz", "graduation_year": 2020, "university": "Н"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intakeRepo := new(MockIntakeRepository)
+			intakeRepo.On("Get", mock.Anything, models.TicketCategoryRecognition).Return(recognitionIntake(t), nil)
+
+			var values map[string]any
+			assert.NoError(t, json.Unmarshal([]byte(tt.values), &values))
+
+			fields, err := NewIntakeService(intakeRepo).ValidateFields(context.Background(), models.TicketCategoryRecognition, values)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidIntakeFields)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, fields)
+		})
+	}
+}
+
+func TestIntakeServiceValidateFieldsWithoutSchema(t *testing.T) {
+	intakeRepo := new(MockIntakeRepository)
+	intakeRepo.On("Get", mock.Anything, models.TicketCategoryGeneral).Return(nil, nil)
+	service := NewIntakeService(intakeRepo)
+
+	fields, err := service.ValidateFields(context.Background(), models.TicketCategoryGeneral, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, fields)
+
+	_, err = service.ValidateFields(context.Background(), models.TicketCategoryGeneral, map[string]any{"country": "kz"})
+	assert.ErrorIs(t, err, ErrInvalidIntakeFields)
+}
+
+func TestIntakeServiceUpdateSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr bool
+	}{
+		{name: "valid schema", schema: recognitionIntakeSchema},
+		{name: "empty schema removes fields", schema: `{"properties": {}}`},
+		{name: "non-object schema", schema: `{"type": "array", "properties": {}}`, wantErr: true},
+		{name: "invalid field name", schema: `{"properties": {"Country": {"type": "string", "x-labels": {"ru": "Страна"}}}}`, wantErr: true},
+		{name: "unsupported type", schema: `{"properties": {"files": {"type": "array", "x-labels": {"ru": "Файлы"}}}}`, wantErr: true},
+		{name: "missing russian label", schema: `{"properties": {"country": {"type": "string", "x-labels": {"en": "Country"}}}}`, wantErr: true},
+		{name: "enum on number", schema: `{"properties": {"year": {"type": "integer", "enum": ["2020"], "x-labels": {"ru": "Год"}}}}`, wantErr: true},
+		{name: "minimum above maximum", schema: `{"properties": {"year": {"type": "integer", "minimum": 10, "maximum": 1, "x-labels": {"ru": "Год"}}}}`, wantErr: true},
+		{name: "labels for unknown option", schema: `{"properties": {"country": {"type": "string", "enum": ["kz"], "x-enum-labels": {"ru": {"ru": "Россия"}}, "x-labels": {"ru": "Страна"}}}}`, wantErr: true},
+		{name: "undefined required field", schema: `{"properties": {}, "required": ["country"]}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema models.IntakeSchema
+			assert.NoError(t, json.Unmarshal([]byte(tt.schema), &schema))
+
+			intakeRepo := new(MockIntakeRepository)
+			intakeRepo.On("Upsert", mock.Anything, mock.AnythingOfType("*models.CategoryIntake")).Return(nil)
+			adminID := uuid.New()
+
+			intake, err := NewIntakeService(intakeRepo).UpdateSchema(context.Background(), models.TicketCategoryRecognition, &schema, adminID)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidIntakeSchema)
+				intakeRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "object", intake.Schema.Type)
+			assert.False(t, intake.Schema.AdditionalProperties)
+			assert.Equal(t, adminID, *intake.UpdatedBy)
+			intakeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIntakeServiceListSchemas(t *testing.T) {
+	intakeRepo := new(MockIntakeRepository)
+	intakeRepo.On("List", mock.Anything).Return([]*models.CategoryIntake{recognitionIntake(t)}, nil)
+
+	intakes, err := NewIntakeService(intakeRepo).ListSchemas(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, intakes, len(models.TicketCategories()))
+	for _, intake := range intakes {
+		if intake.Category == models.TicketCategoryRecognition {
+			assert.Len(t, intake.Schema.Properties, 5)
+		} else {
+			assert.Empty(t, intake.Schema.Properties)
+		}
+	}
+}
@@ -2,12 +2,33 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
 )
 
+// MaxFileSize максимальный размер файла, принимаемого хранилищем
+const MaxFileSize = 100 * 1024 * 1024 // 100 MB
+
+// MaxStoredFileSize предел размера объекта в хранилище: зашифрованный файл MaxFileSize больше
+// исходного на заголовок и теги аутентификации
+const MaxStoredFileSize = MaxFileSize + MaxFileSize/1024
+
+// ErrFileNotFound возвращается реализациями IFileService, если файла нет в хранилище
+var ErrFileNotFound = errors.New("file not found")
+
+// ErrFileTooLarge возвращается реализациями IFileService для файлов больше MaxFileSize
+var ErrFileTooLarge = fmt.Errorf("file size exceeds maximum allowed size of %d MB", MaxFileSize/1024/1024)
+
 // IFileService определяет интерфейс для работы с файлами
 type IFileService interface {
-	// UploadFile загружает файл в хранилище
+	// UploadFile загружает файл в хранилище под ключом folder/id, перезаписывая существующий
 	UploadFile(ctx context.Context, file io.Reader, folder string, id string) (string, error)
 	
 	// DownloadFile скачивает файл из хранилища
@@ -21,11 +42,79 @@ type IFileService interface {
 	
 	// CheckFileExists проверяет существование файла
 	CheckFileExists(ctx context.Context, filepath string) (bool, error)
+
+	// ObjectKey возвращает ключ объекта по URL, выданному UploadFile или GetFileURL
+	ObjectKey(fileURL string) (string, error)
+}
+
+// ITxFileService хранилище вложений, в котором ссылку на объект можно взять в транзакции,
+// сохраняющей вложение
+type ITxFileService interface {
+	IFileService
+
+	// UploadFileTx загружает файл как UploadFile, но ссылку на объект берет через objects
+	UploadFileTx(ctx context.Context, objects repositories.FileObjectRepository, file io.Reader, folder string, id string) (string, error)
+}
+
+// IObjectLister перечисляет ключи всех объектов хранилища вложений, например для их повторного шифрования
+type IObjectLister interface {
+	ListObjects(ctx context.Context) ([]string, error)
+}
+
+// ErrPreviewUnsupported возвращается IPreviewRenderer, если для файла нельзя построить превью
+var ErrPreviewUnsupported = errors.New("preview is not supported for this file")
+
+// IPreviewRenderer строит PNG-превью вложения
+type IPreviewRenderer interface {
+	// Render строит превью файла data типа fileType (имя из FileTypes)
+	Render(fileType string, data []byte) ([]byte, error)
+}
+
+// ITranscriptRenderer формирует PDF с перепиской по тикету
+type ITranscriptRenderer interface {
+	Render(transcript *models.Transcript) ([]byte, error)
+}
+
+// IUploadStore хранит содержимое возобновляемых загрузок до их завершения
+type IUploadStore interface {
+	// Begin готовит хранилище к новой загрузке и при необходимости заполняет upload.StorageID
+	Begin(ctx context.Context, upload *models.Upload) error
+	// Append дописывает data в конец загрузки и обновляет upload.Offset. Полученное до ошибки
+	// чтения data сохраняется, чтобы клиент продолжил загрузку с нового смещения
+	Append(ctx context.Context, upload *models.Upload, data io.Reader) error
+	// Finish собирает файл после получения последнего байта
+	Finish(ctx context.Context, upload *models.Upload) error
+	// Open возвращает содержимое завершенной загрузки
+	Open(ctx context.Context, upload *models.Upload) (io.ReadCloser, error)
+	// Remove удаляет данные загрузки; для отсутствующих данных ошибки нет
+	Remove(ctx context.Context, upload *models.Upload) error
+	// List возвращает загрузки, начатые раньше before, с заполненными ID и StorageID
+	List(ctx context.Context, before time.Time) ([]*models.Upload, error)
+}
+
+// ISignedURLVerifier проверяет ссылки на скачивание, которые выдает хранилище, отдающее файлы через сам сервис
+type ISignedURLVerifier interface {
+	Verify(key string, expires int64, signature string) error
 }
 
 // IEmailService определяет интерфейс для отправки email
 type IEmailService interface {
 	SendTicketResponseNotification(to, ticketSubject, responseMessage string) error
+	SendStaleTicketReminder(to, ticketSubject string, closeAt time.Time) error
+	SendSatisfactionSurvey(to, ticketSubject, surveyURL string) error
+}
+
+// ISurveyTokenSigner подписывает ссылки на опрос удовлетворенности
+type ISurveyTokenSigner interface {
+	// Sign подписывает ссылку на отправку опроса с nonce по тикету ticketID
+	Sign(ticketID int64, nonce string) (string, error)
+	// Parse возвращает ID тикета и nonce отправки из токена
+	Parse(token string) (int64, string, error)
+}
+
+// ISurveySender отправляет опрос после закрытия тикета
+type ISurveySender interface {
+	SendSurvey(ctx context.Context, ticket *models.Ticket, adminID uuid.UUID) error
 }
 
 // IAntivirusService определяет интерфейс для проверки файлов
@@ -33,4 +122,45 @@ type IAntivirusService interface {
 	ScanFile(ctx context.Context, file io.Reader) (bool, error)
 	ScanFileFromPath(ctx context.Context, filePath string) (bool, error)
 	IsAvailable(ctx context.Context) bool
-} 
\ No newline at end of file
+} 
+// IEventPublisher публикует события по тикетам для подписчиков во всех репликах
+type IEventPublisher interface {
+	Publish(ctx context.Context, event *models.TicketEvent) error
+}
+
+// IEventSubscriber выдает поток событий, прошедших фильтр; вызов отписки закрывает канал
+type IEventSubscriber interface {
+	Subscribe(filter func(event *models.TicketEvent) bool) (<-chan *models.TicketEvent, func())
+}
+
+// IAuditRecorder записывает изменения тикетов в журнал аудита; инициатор берется из контекста
+type IAuditRecorder interface {
+	Record(ctx context.Context, ticketID int64, action models.AuditAction, before, after interface{})
+}
+
+// CaptchaResult ответ провайдера капчи; Score есть только у провайдеров с оценкой (reCAPTCHA v3, hCaptcha Enterprise)
+type CaptchaResult struct {
+	Success    bool
+	Score      *float64
+	Action     string
+	ErrorCodes []string
+}
+
+// ICaptchaVerifier проверяет токен капчи, полученный клиентом
+type ICaptchaVerifier interface {
+	Verify(ctx context.Context, token, remoteIP string) (*CaptchaResult, error)
+}
+
+// WebhookMessage подписанный запрос к подписчику вебхука
+type WebhookMessage struct {
+	URL        string
+	Secret     string
+	DeliveryID int64
+	EventType  models.TicketEventType
+	Payload    []byte
+}
+
+// IWebhookSender отправляет вебхук и возвращает HTTP-статус ответа; ошибка означает, что ответа не было
+type IWebhookSender interface {
+	Send(ctx context.Context, message WebhookMessage) (int, error)
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+	"ticket-service/internal/logger"
+)
+
+// maintenanceBatchSize число тикетов, читаемых за один запрос при обходе всей таблицы
+const maintenanceBatchSize = 200
+
+// RescanResult итог повторной проверки вложений
+type RescanResult struct {
+	Scanned  int `json:"scanned"`
+	Infected int `json:"infected"`
+	Failed   int `json:"failed"`
+}
+
+// MaintenanceService обслуживающие операции над всеми тикетами, запускаемые из командной строки
+type MaintenanceService struct {
+	ticketRepo       repositories.TicketRepository
+	historyRepo      repositories.TicketHistoryRepository
+	responseRepo     repositories.ResponseRepository
+	antivirusService IAntivirusService
+	fileService      IFileService
+	auditRecorder    IAuditRecorder
+}
+
+func NewMaintenanceService(
+	ticketRepo repositories.TicketRepository,
+	historyRepo repositories.TicketHistoryRepository,
+	responseRepo repositories.ResponseRepository,
+	antivirusService IAntivirusService,
+	fileService IFileService,
+	auditRecorder IAuditRecorder,
+) *MaintenanceService {
+	return &MaintenanceService{
+		ticketRepo:       ticketRepo,
+		historyRepo:      historyRepo,
+		responseRepo:     responseRepo,
+		antivirusService: antivirusService,
+		fileService:      fileService,
+		auditRecorder:    auditRecorder,
+	}
+}
+
+// RescanAttachments повторно проверяет антивирусом вложения всех тикетов, например после обновления баз.
+// Зараженные вложения помечаются непроверенными; ошибки по отдельным файлам не прерывают обход
+func (s *MaintenanceService) RescanAttachments(ctx context.Context) (*RescanResult, error) {
+	if s.antivirusService == nil {
+		return nil, ErrAntivirusNotAvailable
+	}
+
+	result := &RescanResult{}
+	var afterID int64
+	for {
+		tickets, err := s.ticketRepo.GetWithAttachmentsAfterID(ctx, afterID, maintenanceBatchSize)
+		if err != nil {
+			return result, fmt.Errorf("failed to get tickets with attachments: %w", err)
+		}
+		if len(tickets) == 0 {
+			break
+		}
+
+		for _, ticket := range tickets {
+			afterID = ticket.ID
+			clean, err := s.scanAttachment(ctx, ticket)
+			if err != nil {
+				logger.Error("Failed to rescan attachment", "error", err, "ticketID", ticket.ID)
+				result.Failed++
+				continue
+			}
+			result.Scanned++
+			if !clean {
+				logger.Warn("Attachment contains malware", "ticketID", ticket.ID)
+				result.Infected++
+			}
+
+			if ticket.FileChecked == clean {
+				continue
+			}
+			if err := s.ticketRepo.UpdateFileChecked(ctx, ticket.ID, clean); err != nil {
+				logger.Error("Failed to update file checked status", "error", err, "ticketID", ticket.ID)
+				result.Failed++
+				continue
+			}
+			recordAudit(ctx, s.auditRecorder, ticket.ID, models.AuditTicketAttachmentChecked,
+				map[string]interface{}{"file_checked": ticket.FileChecked},
+				map[string]interface{}{"file_checked": clean})
+		}
+	}
+
+	logger.Info("Attachments rescanned", "scanned", result.Scanned, "infected", result.Infected, "failed", result.Failed)
+	return result, nil
+}
+
+func (s *MaintenanceService) scanAttachment(ctx context.Context, ticket *models.Ticket) (bool, error) {
+	file, err := s.fileService.DownloadFile(ctx, *ticket.FileURL)
+	if err != nil {
+		return false, fmt.Errorf("failed to download file: %w", err)
+	}
+	defer file.Close()
+
+	clean, err := s.antivirusService.ScanFile(ctx, file)
+	if err != nil {
+		return false, fmt.Errorf("failed to scan file: %w", err)
+	}
+	return clean, nil
+}
+
+// ExportTickets пишет в w тикеты с историей и ответами, по одному JSON-объекту в строке.
+// Пустой status выгружает все тикеты, включая задержанные антиспамом. Возвращает число выгруженных тикетов
+func (s *MaintenanceService) ExportTickets(ctx context.Context, w io.Writer, status models.TicketStatus) (int, error) {
+	encoder := json.NewEncoder(w)
+	exported := 0
+
+	var afterID int64
+	for {
+		tickets, err := s.ticketRepo.GetAfterID(ctx, afterID, maintenanceBatchSize)
+		if err != nil {
+			return exported, fmt.Errorf("failed to get tickets: %w", err)
+		}
+		if len(tickets) == 0 {
+			break
+		}
+
+		for _, ticket := range tickets {
+			afterID = ticket.ID
+			if status != "" && ticket.Status != status {
+				continue
+			}
+
+			history, err := s.historyRepo.GetByTicketID(ctx, ticket.ID)
+			if err != nil {
+				return exported, fmt.Errorf("failed to get ticket history: %w", err)
+			}
+			responses, err := s.responseRepo.GetByTicketID(ctx, ticket.ID)
+			if err != nil {
+				return exported, fmt.Errorf("failed to get ticket responses: %w", err)
+			}
+
+			if err := encoder.Encode(models.TicketExport{Ticket: ticket, History: history, Responses: responses}); err != nil {
+				return exported, fmt.Errorf("failed to write ticket: %w", err)
+			}
+			recordAudit(ctx, s.auditRecorder, ticket.ID, models.AuditTicketExported, nil, nil)
+			exported++
+		}
+	}
+
+	logger.Info("Tickets exported", "count", exported)
+	return exported, nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"ticket-service/internal/domain/models"
+)
+
+func TestRescanAttachments(t *testing.T) {
+	mockTicketRepo := new(MockTicketRepository)
+	mockAntivirusService := new(MockAntivirusService)
+	mockFileService := new(MockFileService)
+	mockAuditRepo := new(MockAuditRepository)
+
+	cleanURL, infectedURL, missingURL := "tickets/1/a.pdf", "tickets/2/b.pdf", "tickets/3/c.pdf"
+	tickets := []*models.Ticket{
+		{ID: 1, FileURL: &cleanURL, FileChecked: true},
+		{ID: 2, FileURL: &infectedURL, FileChecked: true},
+		{ID: 3, FileURL: &missingURL, FileChecked: true},
+	}
+	mockTicketRepo.On("GetWithAttachmentsAfterID", mock.Anything, int64(0), maintenanceBatchSize).Return(tickets, nil)
+	mockTicketRepo.On("GetWithAttachmentsAfterID", mock.Anything, int64(3), maintenanceBatchSize).Return([]*models.Ticket{}, nil)
+
+	mockFileService.On("DownloadFile", mock.Anything, cleanURL).Return(io.NopCloser(strings.NewReader("clean")), nil)
+	mockFileService.On("DownloadFile", mock.Anything, infectedURL).Return(io.NopCloser(strings.NewReader("infected")), nil)
+	mockFileService.On("DownloadFile", mock.Anything, missingURL).Return(nil, ErrFileNotFound)
+	mockAntivirusService.On("ScanFile", mock.Anything, mock.MatchedBy(func(r io.Reader) bool {
+		data, _ := io.ReadAll(r)
+		return string(data) == "clean"
+	})).Return(true, nil)
+	mockAntivirusService.On("ScanFile", mock.Anything, mock.Anything).Return(false, nil)
+
+	// Состояние меняется только у зараженного вложения
+	mockTicketRepo.On("UpdateFileChecked", mock.Anything, int64(2), false).Return(nil)
+	mockAuditRepo.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditEntry) bool {
+		return entry.TicketID == 2 && entry.Action == models.AuditTicketAttachmentChecked
+	})).Return(int64(1), nil)
+
+	service := NewMaintenanceService(mockTicketRepo, nil, nil, mockAntivirusService, mockFileService, NewAuditService(mockAuditRepo))
+
+	result, err := service.RescanAttachments(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, &RescanResult{Scanned: 2, Infected: 1, Failed: 1}, result)
+	mockTicketRepo.AssertExpectations(t)
+	mockAuditRepo.AssertExpectations(t)
+	mockTicketRepo.AssertNotCalled(t, "UpdateFileChecked", mock.Anything, int64(1), mock.Anything)
+}
+
+func TestRescanAttachmentsRequiresAntivirus(t *testing.T) {
+	service := NewMaintenanceService(new(MockTicketRepository), nil, nil, nil, nil, nil)
+
+	_, err := service.RescanAttachments(context.Background())
+
+	assert.True(t, errors.Is(err, ErrAntivirusNotAvailable))
+}
+
+func TestExportTickets(t *testing.T) {
+	mockTicketRepo := new(MockTicketRepository)
+	mockHistoryRepo := new(MockTicketHistoryRepository)
+	mockResponseRepo := new(MockResponseRepository)
+
+	mockTicketRepo.On("GetAfterID", mock.Anything, int64(0), maintenanceBatchSize).Return([]*models.Ticket{
+		{ID: 1, Subject: "Открыт", Status: models.TicketStatusNew},
+		{ID: 2, Subject: "Закрыт", Status: models.TicketStatusClosed},
+	}, nil)
+	mockTicketRepo.On("GetAfterID", mock.Anything, int64(2), maintenanceBatchSize).Return([]*models.Ticket{}, nil)
+	mockHistoryRepo.On("GetByTicketID", mock.Anything, int64(2)).Return([]*models.TicketHistory{{ID: 5, TicketID: 2}}, nil)
+	mockResponseRepo.On("GetByTicketID", mock.Anything, int64(2)).Return([]*models.Response{{ID: 7, TicketID: 2}}, nil)
+
+	service := NewMaintenanceService(mockTicketRepo, mockHistoryRepo, mockResponseRepo, nil, nil, nil)
+
+	var buf bytes.Buffer
+	exported, err := service.ExportTickets(context.Background(), &buf, models.TicketStatusClosed)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, exported)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 1)
+
+	var export models.TicketExport
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &export))
+	assert.Equal(t, int64(2), export.Ticket.ID)
+	assert.Len(t, export.History, 1)
+	assert.Len(t, export.Responses, 1)
+	mockHistoryRepo.AssertNotCalled(t, "GetByTicketID", mock.Anything, int64(1))
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+	"ticket-service/internal/infrastructure/metrics"
+	"ticket-service/internal/logger"
+)
+
+// previewBatchSize число вложений, для которых превью строится за один проход
+const previewBatchSize = 50
+
+// previewSuffix превью хранится в том же каталоге, что и оригинал
+const previewSuffix = ".preview.png"
+
+var ErrPreviewNotFound = errors.New("attachment preview not found")
+
+// PreviewKey возвращает ключ PNG-превью объекта вложения
+func PreviewKey(objectKey string) string {
+	return objectKey + previewSuffix
+}
+
+// PreviewService строит превью вложений в фоне и отдает их администраторам, чтобы не скачивать
+// каждый файл целиком. Превью строятся только для объектов, адресуемых по содержимому
+type PreviewService struct {
+	ticketRepo   repositories.TicketRepository
+	responseRepo repositories.ResponseRepository
+	objectRepo   repositories.FileObjectRepository
+	// storage хранилище объектов, в котором ключи используются без учета ссылок
+	storage  IFileService
+	renderer IPreviewRenderer
+}
+
+func NewPreviewService(
+	ticketRepo repositories.TicketRepository,
+	responseRepo repositories.ResponseRepository,
+	objectRepo repositories.FileObjectRepository,
+	storage IFileService,
+	renderer IPreviewRenderer,
+) *PreviewService {
+	return &PreviewService{
+		ticketRepo:   ticketRepo,
+		responseRepo: responseRepo,
+		objectRepo:   objectRepo,
+		storage:      storage,
+		renderer:     renderer,
+	}
+}
+
+// GeneratePreviews строит превью для вложений, загруженных с прошлого запуска. Ошибки хранилища
+// прерывают проход, и вложение обрабатывается при следующем запуске; файлы, которые не удалось
+// разобрать, помечаются failed и больше не обрабатываются
+func (s *PreviewService) GeneratePreviews(ctx context.Context) error {
+	generated := 0
+	for {
+		keys, err := s.objectRepo.GetPendingPreviews(ctx, previewBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			status, err := s.generate(ctx, key)
+			if err != nil {
+				return fmt.Errorf("failed to generate preview for %s: %w", key, err)
+			}
+			if err := s.objectRepo.SetPreviewStatus(ctx, key, status); err != nil {
+				return err
+			}
+			metrics.AttachmentPreviewsTotal.WithLabelValues(string(status)).Inc()
+			if status == models.FilePreviewReady {
+				generated++
+			}
+		}
+
+		if len(keys) < previewBatchSize {
+			break
+		}
+	}
+
+	if generated > 0 {
+		logger.Info("Attachment previews generated", "count", generated)
+	}
+	return nil
+}
+
+func (s *PreviewService) generate(ctx context.Context, key string) (models.FilePreviewStatus, error) {
+	file, err := s.storage.DownloadFile(ctx, key)
+	if err != nil {
+		if errors.Is(err, ErrFileNotFound) {
+			return models.FilePreviewFailed, nil
+		}
+		return "", err
+	}
+	data, err := io.ReadAll(io.LimitReader(file, MaxFileSize+1))
+	file.Close()
+	if err != nil {
+		return "", err
+	}
+
+	fileType, _, _ := detectFileType(data, bytes.NewReader(data), int64(len(data)))
+	preview, err := s.renderer.Render(fileType, data)
+	if err != nil {
+		if errors.Is(err, ErrPreviewUnsupported) {
+			return models.FilePreviewNone, nil
+		}
+		logger.Warn("Failed to render attachment preview", "error", err, "key", key, "type", fileType)
+		return models.FilePreviewFailed, nil
+	}
+
+	previewKey := PreviewKey(key)
+	if _, err := s.storage.UploadFile(ctx, bytes.NewReader(preview), path.Dir(previewKey), path.Base(previewKey)); err != nil {
+		return "", err
+	}
+	return models.FilePreviewReady, nil
+}
+
+// OpenTicketPreview открывает превью вложения тикета
+func (s *PreviewService) OpenTicketPreview(ctx context.Context, ticketID int64) (io.ReadCloser, error) {
+	ticket, err := s.ticketRepo.GetByID(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket == nil {
+		return nil, ErrTicketNotFound
+	}
+	if ticket.FileURL == nil {
+		return nil, ErrPreviewNotFound
+	}
+	return s.open(ctx, *ticket.FileURL)
+}
+
+// OpenResponsePreview открывает превью вложения ответа
+func (s *PreviewService) OpenResponsePreview(ctx context.Context, responseID int64) (io.ReadCloser, error) {
+	response, err := s.responseRepo.GetByID(ctx, responseID)
+	if err != nil {
+		return nil, err
+	}
+	if response == nil || response.DeletedAt != nil {
+		return nil, ErrResponseNotFound
+	}
+	if response.FileURL == nil {
+		return nil, ErrPreviewNotFound
+	}
+	return s.open(ctx, *response.FileURL)
+}
+
+func (s *PreviewService) open(ctx context.Context, fileURL string) (io.ReadCloser, error) {
+	key, err := s.storage.ObjectKey(fileURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Хранилище S3 сообщает об отсутствии объекта только при чтении, поэтому наличие проверяется заранее
+	previewKey := PreviewKey(key)
+	exists, err := s.storage.CheckFileExists(ctx, previewKey)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrPreviewNotFound
+	}
+	return s.storage.DownloadFile(ctx, previewKey)
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"ticket-service/internal/domain/models"
+)
+
+type MockPreviewRenderer struct {
+	mock.Mock
+}
+
+func (m *MockPreviewRenderer) Render(fileType string, data []byte) ([]byte, error) {
+	args := m.Called(fileType, data)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+const pngHeader = "\x89PNG\r\n\x1a\n"
+
+func TestPreviewServiceGeneratePreviews(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		mockSetup  func(*MockFileService, *MockPreviewRenderer)
+		wantStatus models.FilePreviewStatus
+	}{
+		{
+			name:    "image preview is stored next to the original",
+			content: pngHeader + "image",
+			mockSetup: func(storage *MockFileService, renderer *MockPreviewRenderer) {
+				renderer.On("Render", "png", []byte(pngHeader+"image")).Return([]byte("preview"), nil)
+				storage.On("UploadFile", mock.Anything, mock.Anything, "objects/aa", "a"+previewSuffix).Return("url", nil)
+			},
+			wantStatus: models.FilePreviewReady,
+		},
+		{
+			name:    "unsupported type is marked as having no preview",
+			content: "plain text",
+			mockSetup: func(storage *MockFileService, renderer *MockPreviewRenderer) {
+				renderer.On("Render", "txt", mock.Anything).Return(nil, ErrPreviewUnsupported)
+			},
+			wantStatus: models.FilePreviewNone,
+		},
+		{
+			name:    "broken file is not retried",
+			content: "%PDF-1.7 broken",
+			mockSetup: func(storage *MockFileService, renderer *MockPreviewRenderer) {
+				renderer.On("Render", "pdf", mock.Anything).Return(nil, errors.New("failed to read PDF"))
+			},
+			wantStatus: models.FilePreviewFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objectRepo := new(MockFileObjectRepository)
+			storage := new(MockFileService)
+			renderer := new(MockPreviewRenderer)
+
+			objectRepo.On("GetPendingPreviews", mock.Anything, previewBatchSize).Return([]string{"objects/aa/a"}, nil)
+			objectRepo.On("SetPreviewStatus", mock.Anything, "objects/aa/a", tt.wantStatus).Return(nil)
+			storage.On("DownloadFile", mock.Anything, "objects/aa/a").Return(io.NopCloser(strings.NewReader(tt.content)), nil)
+			tt.mockSetup(storage, renderer)
+
+			service := NewPreviewService(nil, nil, objectRepo, storage, renderer)
+			err := service.GeneratePreviews(context.Background())
+
+			assert.NoError(t, err)
+			objectRepo.AssertExpectations(t)
+			storage.AssertExpectations(t)
+			renderer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPreviewServiceGeneratePreviewsStopsOnStorageError(t *testing.T) {
+	objectRepo := new(MockFileObjectRepository)
+	storage := new(MockFileService)
+
+	objectRepo.On("GetPendingPreviews", mock.Anything, previewBatchSize).Return([]string{"objects/aa/a", "objects/bb/b"}, nil)
+	storage.On("DownloadFile", mock.Anything, "objects/aa/a").Return(nil, errors.New("storage unavailable"))
+
+	service := NewPreviewService(nil, nil, objectRepo, storage, new(MockPreviewRenderer))
+	err := service.GeneratePreviews(context.Background())
+
+	// Вложение остается в очереди и обрабатывается при следующем запуске
+	assert.Error(t, err)
+	objectRepo.AssertNotCalled(t, "SetPreviewStatus", mock.Anything, mock.Anything, mock.Anything)
+	storage.AssertNotCalled(t, "DownloadFile", mock.Anything, "objects/bb/b")
+}
+
+func TestPreviewServiceOpenTicketPreview(t *testing.T) {
+	fileURL := "url/objects/aa/a"
+
+	tests := []struct {
+		name      string
+		mockSetup func(*MockTicketRepository, *MockFileService)
+		wantErr   error
+	}{
+		{
+			name: "ready preview is opened",
+			mockSetup: func(ticketRepo *MockTicketRepository, storage *MockFileService) {
+				ticketRepo.On("GetByID", mock.Anything, int64(1)).Return(&models.Ticket{ID: 1, FileURL: &fileURL}, nil)
+				storage.On("ObjectKey", fileURL).Return("objects/aa/a", nil)
+				storage.On("CheckFileExists", mock.Anything, PreviewKey("objects/aa/a")).Return(true, nil)
+				storage.On("DownloadFile", mock.Anything, PreviewKey("objects/aa/a")).Return(io.NopCloser(strings.NewReader("preview")), nil)
+			},
+		},
+		{
+			name: "preview is not built yet",
+			mockSetup: func(ticketRepo *MockTicketRepository, storage *MockFileService) {
+				ticketRepo.On("GetByID", mock.Anything, int64(1)).Return(&models.Ticket{ID: 1, FileURL: &fileURL}, nil)
+				storage.On("ObjectKey", fileURL).Return("objects/aa/a", nil)
+				storage.On("CheckFileExists", mock.Anything, PreviewKey("objects/aa/a")).Return(false, nil)
+			},
+			wantErr: ErrPreviewNotFound,
+		},
+		{
+			name: "ticket without attachment",
+			mockSetup: func(ticketRepo *MockTicketRepository, storage *MockFileService) {
+				ticketRepo.On("GetByID", mock.Anything, int64(1)).Return(&models.Ticket{ID: 1}, nil)
+			},
+			wantErr: ErrPreviewNotFound,
+		},
+		{
+			name: "ticket not found",
+			mockSetup: func(ticketRepo *MockTicketRepository, storage *MockFileService) {
+				ticketRepo.On("GetByID", mock.Anything, int64(1)).Return(nil, nil)
+			},
+			wantErr: ErrTicketNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticketRepo := new(MockTicketRepository)
+			storage := new(MockFileService)
+			tt.mockSetup(ticketRepo, storage)
+
+			service := NewPreviewService(ticketRepo, nil, nil, storage, nil)
+			preview, err := service.OpenTicketPreview(context.Background(), 1)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, preview)
+			} else {
+				assert.NoError(t, err)
+				content, _ := io.ReadAll(preview)
+				assert.Equal(t, "preview", string(content))
+			}
+			storage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPreviewServiceOpenResponsePreviewHidesDeletedResponse(t *testing.T) {
+	fileURL := "url/objects/aa/a"
+	responseRepo := new(MockResponseRepository)
+	responseRepo.On("GetByID", mock.Anything, int64(1)).Return(&models.Response{ID: 1, FileURL: &fileURL, DeletedAt: new(time.Time)}, nil)
+
+	service := NewPreviewService(nil, responseRepo, nil, new(MockFileService), nil)
+	_, err := service.OpenResponsePreview(context.Background(), 1)
+
+	assert.ErrorIs(t, err, ErrResponseNotFound)
+}
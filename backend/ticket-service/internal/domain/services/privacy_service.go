@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+	"ticket-service/internal/infrastructure/metrics"
+	"ticket-service/internal/logger"
+)
+
+// anonymizationBatchSize ограничивает число тикетов одной категории за запуск задачи
+const anonymizationBatchSize = 100
+
+// RetentionPolicy срок хранения персональных данных после закрытия тикета; 0 - хранить бессрочно
+type RetentionPolicy struct {
+	AnonymizeAfter time.Duration
+}
+
+// PrivacyService обезличивает тикеты по сроку хранения и выполняет запросы заявителей на выгрузку и удаление данных
+type PrivacyService struct {
+	ticketRepo    repositories.TicketRepository
+	historyRepo   repositories.TicketHistoryRepository
+	responseRepo  repositories.ResponseRepository
+	privacyRepo   repositories.PrivacyRepository
+	fileService   IFileService
+	auditRecorder IAuditRecorder
+	defaultPolicy RetentionPolicy
+	policies      map[models.TicketCategory]RetentionPolicy
+}
+
+func NewPrivacyService(
+	ticketRepo repositories.TicketRepository,
+	historyRepo repositories.TicketHistoryRepository,
+	responseRepo repositories.ResponseRepository,
+	privacyRepo repositories.PrivacyRepository,
+	fileService IFileService,
+	auditRecorder IAuditRecorder,
+	defaultPolicy RetentionPolicy,
+	policies map[models.TicketCategory]RetentionPolicy,
+) *PrivacyService {
+	return &PrivacyService{
+		ticketRepo:    ticketRepo,
+		historyRepo:   historyRepo,
+		responseRepo:  responseRepo,
+		privacyRepo:   privacyRepo,
+		fileService:   fileService,
+		auditRecorder: auditRecorder,
+		defaultPolicy: defaultPolicy,
+		policies:      policies,
+	}
+}
+
+// PolicyFor возвращает срок хранения для категории, по умолчанию - общий
+func (s *PrivacyService) PolicyFor(category models.TicketCategory) RetentionPolicy {
+	if policy, ok := s.policies[category]; ok {
+		return policy
+	}
+	return s.defaultPolicy
+}
+
+// ApplyRetention обезличивает тикеты, закрытые дольше срока хранения своей категории
+func (s *PrivacyService) ApplyRetention(ctx context.Context) error {
+	now := time.Now()
+
+	for _, category := range models.TicketCategories() {
+		policy := s.PolicyFor(category)
+		if policy.AnonymizeAfter <= 0 {
+			continue
+		}
+
+		tickets, err := s.ticketRepo.GetForAnonymization(ctx, category, now.Add(-policy.AnonymizeAfter), anonymizationBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to get tickets for anonymization: %w", err)
+		}
+
+		for _, ticket := range tickets {
+			if err := s.anonymize(ctx, ticket); err != nil {
+				logger.Error("Failed to anonymize ticket", "error", err, "ticketID", ticket.ID)
+				continue
+			}
+			metrics.TicketsAnonymizedTotal.WithLabelValues("retention").Inc()
+		}
+	}
+
+	return nil
+}
+
+// ExportByEmail собирает все тикеты заявителя с историей и ответами; выгрузка каждого тикета фиксируется в аудите
+func (s *PrivacyService) ExportByEmail(ctx context.Context, email string) (*models.PersonalDataExport, error) {
+	tickets, err := s.ticketRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tickets: %w", err)
+	}
+
+	export := &models.PersonalDataExport{
+		Email:       email,
+		GeneratedAt: time.Now(),
+		Tickets:     make([]models.TicketExport, 0, len(tickets)),
+	}
+
+	for _, ticket := range tickets {
+		history, err := s.historyRepo.GetByTicketID(ctx, ticket.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ticket history: %w", err)
+		}
+
+		responses, err := s.responseRepo.GetByTicketID(ctx, ticket.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ticket responses: %w", err)
+		}
+
+		export.Tickets = append(export.Tickets, models.TicketExport{
+			Ticket:    ticket,
+			History:   history,
+			Responses: responses,
+		})
+
+		recordAudit(ctx, s.auditRecorder, ticket.ID, models.AuditTicketExported, nil, nil)
+	}
+
+	logger.Info("Personal data exported", "tickets", len(export.Tickets))
+	return export, nil
+}
+
+// EraseByEmail обезличивает все тикеты заявителя независимо от статуса и срока хранения.
+// Возвращает количество обезличенных тикетов
+func (s *PrivacyService) EraseByEmail(ctx context.Context, email string) (int, error) {
+	tickets, err := s.ticketRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get tickets: %w", err)
+	}
+
+	erased := 0
+	for _, ticket := range tickets {
+		if err := s.anonymize(ctx, ticket); err != nil {
+			return erased, err
+		}
+		metrics.TicketsAnonymizedTotal.WithLabelValues("erasure_request").Inc()
+		erased++
+	}
+
+	logger.Info("Personal data erased on request", "tickets", erased)
+	return erased, nil
+}
+
+// anonymize удаляет вложения из хранилища, затем персональные данные из базы.
+// Вложения удаляются первыми: если это не удалось, тикет останется необработанным и задача повторит попытку
+func (s *PrivacyService) anonymize(ctx context.Context, ticket *models.Ticket) error {
+	urls, err := s.privacyRepo.GetAttachmentURLs(ctx, ticket.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get attachments: %w", err)
+	}
+
+	for _, url := range urls {
+		if err := s.fileService.DeleteFile(ctx, url); err != nil && !errors.Is(err, ErrFileNotFound) {
+			return fmt.Errorf("failed to delete attachment: %w", err)
+		}
+	}
+
+	anonymizedAt := time.Now()
+	if err := s.privacyRepo.Anonymize(ctx, ticket.ID, anonymizedAt); err != nil {
+		return fmt.Errorf("failed to anonymize ticket: %w", err)
+	}
+
+	recordAudit(ctx, s.auditRecorder, ticket.ID, models.AuditTicketAnonymized,
+		map[string]interface{}{"anonymized_at": nil, "attachments": len(urls)},
+		map[string]interface{}{"anonymized_at": anonymizedAt, "attachments": 0})
+
+	logger.Info("Ticket anonymized", "ticketID", ticket.ID, "attachments", len(urls))
+	return nil
+}
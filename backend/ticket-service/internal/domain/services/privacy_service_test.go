@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"ticket-service/internal/domain/models"
+)
+
+type MockPrivacyRepository struct {
+	mock.Mock
+}
+
+func (m *MockPrivacyRepository) GetAttachmentURLs(ctx context.Context, ticketID int64) ([]string, error) {
+	args := m.Called(ctx, ticketID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockPrivacyRepository) Anonymize(ctx context.Context, ticketID int64, anonymizedAt time.Time) error {
+	args := m.Called(ctx, ticketID, anonymizedAt)
+	return args.Error(0)
+}
+
+func TestApplyRetention(t *testing.T) {
+	day := 24 * time.Hour
+	defaultPolicy := RetentionPolicy{AnonymizeAfter: 365 * day}
+	policies := map[models.TicketCategory]RetentionPolicy{
+		models.TicketCategoryLegalisation: {AnonymizeAfter: 30 * day},
+		models.TicketCategoryBologna:      {AnonymizeAfter: 0},
+	}
+
+	mockTicketRepo := new(MockTicketRepository)
+	mockPrivacyRepo := new(MockPrivacyRepository)
+	mockFileService := new(MockFileService)
+
+	for _, category := range models.TicketCategories() {
+		switch category {
+		case models.TicketCategoryBologna:
+			// Бессрочное хранение - категорию не запрашиваем
+		case models.TicketCategoryLegalisation:
+			mockTicketRepo.On("GetForAnonymization", mock.Anything, category, mock.MatchedBy(func(before time.Time) bool {
+				return time.Since(before) >= 30*day && time.Since(before) < 31*day
+			}), anonymizationBatchSize).Return([]*models.Ticket{{ID: 1, Category: category}, {ID: 2, Category: category}}, nil)
+		default:
+			mockTicketRepo.On("GetForAnonymization", mock.Anything, category, mock.Anything, anonymizationBatchSize).Return([]*models.Ticket{}, nil)
+		}
+	}
+
+	// Вложение уже удалено из хранилища - тикет все равно обезличивается
+	mockPrivacyRepo.On("GetAttachmentURLs", mock.Anything, int64(1)).Return([]string{"http://storage/tickets/1.pdf"}, nil)
+	mockFileService.On("DeleteFile", mock.Anything, "http://storage/tickets/1.pdf").Return(ErrFileNotFound)
+	mockPrivacyRepo.On("Anonymize", mock.Anything, int64(1), mock.Anything).Return(nil)
+
+	// Ошибка хранилища - тикет остается для следующего запуска
+	mockPrivacyRepo.On("GetAttachmentURLs", mock.Anything, int64(2)).Return([]string{"http://storage/tickets/2.pdf"}, nil)
+	mockFileService.On("DeleteFile", mock.Anything, "http://storage/tickets/2.pdf").Return(errors.New("storage unavailable"))
+
+	service := NewPrivacyService(mockTicketRepo, nil, nil, mockPrivacyRepo, mockFileService, nil, defaultPolicy, policies)
+
+	err := service.ApplyRetention(context.Background())
+
+	assert.NoError(t, err)
+	mockTicketRepo.AssertExpectations(t)
+	mockPrivacyRepo.AssertExpectations(t)
+	mockFileService.AssertExpectations(t)
+	mockPrivacyRepo.AssertNotCalled(t, "Anonymize", mock.Anything, int64(2), mock.Anything)
+	mockTicketRepo.AssertNotCalled(t, "GetForAnonymization", mock.Anything, models.TicketCategoryBologna, mock.Anything, mock.Anything)
+}
+
+func TestEraseByEmail(t *testing.T) {
+	mockTicketRepo := new(MockTicketRepository)
+	mockPrivacyRepo := new(MockPrivacyRepository)
+	mockFileService := new(MockFileService)
+
+	mockTicketRepo.On("GetByEmail", mock.Anything, "test@example.com").Return([]*models.Ticket{{ID: 5}, {ID: 6}}, nil)
+	mockPrivacyRepo.On("GetAttachmentURLs", mock.Anything, mock.Anything).Return([]string{}, nil)
+	mockPrivacyRepo.On("Anonymize", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	service := NewPrivacyService(mockTicketRepo, nil, nil, mockPrivacyRepo, mockFileService, nil, RetentionPolicy{}, nil)
+
+	erased, err := service.EraseByEmail(context.Background(), "test@example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, erased)
+	mockPrivacyRepo.AssertNumberOfCalls(t, "Anonymize", 2)
+}
@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"fmt"
-	"io"
+	"time"
+
+	"github.com/google/uuid"
 
 	"ticket-service/internal/domain/models"
 	"ticket-service/internal/domain/repositories"
@@ -11,49 +13,129 @@ import (
 )
 
 type ResponseService struct {
-	responseRepo repositories.ResponseRepository
-	ticketRepo   repositories.TicketRepository
-	fileService  IFileService
-	emailService IEmailService
+	responseRepo   repositories.ResponseRepository
+	ticketRepo     repositories.TicketRepository
+	historyRepo    repositories.TicketHistoryRepository
+	fileService    ITxFileService
+	emailService   IEmailService
+	eventPublisher IEventPublisher
+	auditRecorder  IAuditRecorder
+	uow            repositories.UnitOfWork
+	fileInspector  *FileInspector
 }
 
 func NewResponseService(
 	responseRepo repositories.ResponseRepository,
 	ticketRepo repositories.TicketRepository,
-	fileService IFileService,
+	historyRepo repositories.TicketHistoryRepository,
+	fileService ITxFileService,
 	emailService IEmailService,
+	eventPublisher IEventPublisher,
+	auditRecorder IAuditRecorder,
+	uow repositories.UnitOfWork,
+	fileInspector *FileInspector,
 ) *ResponseService {
 	return &ResponseService{
-		responseRepo: responseRepo,
-		ticketRepo:   ticketRepo,
-		fileService:  fileService,
-		emailService: emailService,
+		responseRepo:   responseRepo,
+		ticketRepo:     ticketRepo,
+		historyRepo:    historyRepo,
+		fileService:    fileService,
+		emailService:   emailService,
+		eventPublisher: eventPublisher,
+		auditRecorder:  auditRecorder,
+		uow:            uow,
+		fileInspector:  fileInspector,
 	}
 }
 
+// inTx выполняет fn атомарно через unit of work, как TicketService.inTx
+func (s *ResponseService) inTx(ctx context.Context, fn func(repos repositories.TxRepositories) error) error {
+	if s.uow == nil {
+		return fn(repositories.TxRepositories{
+			Tickets:   s.ticketRepo,
+			History:   s.historyRepo,
+			Responses: s.responseRepo,
+		})
+	}
+	return s.uow.Do(ctx, fn)
+}
+
 // CreateResponse создает новый ответ на тикет
-func (s *ResponseService) CreateResponse(ctx context.Context, response *models.Response, file io.Reader) error {
+func (s *ResponseService) CreateResponse(ctx context.Context, response *models.Response, file *models.FileUpload) error {
 	// Получаем информацию о тикете
 	ticket, err := s.ticketRepo.GetByID(ctx, response.TicketID)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to get ticket: %w", err)
+	}
+	if ticket == nil {
+		return ErrTicketNotFound
+	}
+	if ticket.Status == models.TicketStatusClosed {
+		return ErrTicketClosed
 	}
 
-	// Если есть файл, загружаем его в S3
+	// Если есть файл, проверяем его тип до начала транзакции; файл читается из источника без копии в памяти
+	var content *attachment
 	if file != nil {
-		fileURL, err := s.fileService.UploadFile(ctx, file, "responses", fmt.Sprintf("%d", response.TicketID))
+		content, err = openAttachment(file.Reader)
 		if err != nil {
 			return err
 		}
-		response.FileURL = &fileURL
+		defer content.Close()
+		if s.fileInspector != nil {
+			if _, err := s.fileInspector.InspectFile(content.reader(), content.size, file.Name, file.ContentType); err != nil {
+				logger.Warn("File rejected", "error", err, "filename", file.Name)
+				return err
+			}
+		}
 	}
 
-	// Создаем ответ
-	id, err := s.responseRepo.Create(ctx, response)
+	// Ссылка на вложение, ответ и запись в истории сохраняются вместе
+	response.CreatedAt = time.Now()
+	comment := "Добавлен ответ"
+	err = s.inTx(ctx, func(repos repositories.TxRepositories) error {
+		if file != nil {
+			fileURL, err := s.fileService.UploadFileTx(ctx, repos.FileObjects, content.reader(), "responses", fmt.Sprintf("%d", response.TicketID))
+			if err != nil {
+				return err
+			}
+			response.FileURL = &fileURL
+		}
+
+		id, err := repos.Responses.Create(ctx, response)
+		if err != nil {
+			return fmt.Errorf("failed to create response: %w", err)
+		}
+		response.ID = id
+
+		history := &models.TicketHistory{
+			TicketID: response.TicketID,
+			Status:   ticket.Status,
+			Comment:  &comment,
+			AdminID:  &response.AdminID,
+		}
+		if _, err := repos.History.Create(ctx, history); err != nil {
+			return fmt.Errorf("failed to create history record: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
+		response.ID = 0
+		response.FileURL = nil
 		return err
 	}
-	response.ID = id
+	id := response.ID
+
+	recordAudit(ctx, s.auditRecorder, ticket.ID, models.AuditResponseCreated, nil, response)
+
+	publishEvent(ctx, s.eventPublisher, &models.TicketEvent{
+		Type:     models.TicketEventResponseAdded,
+		TicketID: ticket.ID,
+		OwnerID:  ticket.OwnerID(),
+		Status:   ticket.Status,
+		ActorID:  &response.AdminID,
+		Data:     map[string]interface{}{"response_id": id},
+	})
 
 	// Если пользователь подписан на уведомления по email, отправляем уведомление
 	if ticket.NotifyEmail {
@@ -111,10 +193,10 @@ func (s *ResponseService) GetByTicketIDWithPagination(ctx context.Context, ticke
 	return responses, total, nil
 }
 
-func (s *ResponseService) UpdateMessage(ctx context.Context, id int64, message string) error {
+func (s *ResponseService) UpdateMessage(ctx context.Context, id int64, message string, editedBy uuid.UUID) error {
 	logger.Info("Updating response message", "responseID", id)
 
-	if err := s.responseRepo.UpdateMessage(ctx, id, message); err != nil {
+	if err := s.responseRepo.UpdateMessage(ctx, id, message, editedBy); err != nil {
 		logger.Error("Failed to update response message", "error", err)
 		return fmt.Errorf("failed to update response message: %w", err)
 	}
@@ -133,13 +215,17 @@ func (s *ResponseService) UpdateFileURL(ctx context.Context, id int64, fileURL s
 	return nil
 }
 
-func (s *ResponseService) Delete(ctx context.Context, id int64) error {
+func (s *ResponseService) Delete(ctx context.Context, id int64, deletedBy uuid.UUID) error {
 	logger.Info("Deleting response", "responseID", id)
 
-	if err := s.responseRepo.Delete(ctx, id); err != nil {
+	deleted, err := s.responseRepo.Delete(ctx, id, deletedBy)
+	if err != nil {
 		logger.Error("Failed to delete response", "error", err)
 		return fmt.Errorf("failed to delete response: %w", err)
 	}
+	if !deleted {
+		return ErrResponseNotFound
+	}
 
 	return nil
 } 
\ No newline at end of file
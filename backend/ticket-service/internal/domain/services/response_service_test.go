@@ -0,0 +1,120 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+)
+
+func TestResponseServiceCreateResponse(t *testing.T) {
+	dbErr := errors.New("db error")
+
+	tests := []struct {
+		name         string
+		upload       *models.FileUpload
+		mockSetup    func(*MockTicketRepository, *MockTicketHistoryRepository, *MockResponseRepository, *MockFileService)
+		wantErr      error
+		wantCommits  int
+		wantRollback int
+	}{
+		{
+			name: "response and history are committed together",
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository, rr *MockResponseRepository, _ *MockFileService) {
+				tr.On("GetByID", mock.Anything, int64(1)).Return(&models.Ticket{ID: 1, UserID: testOwnerID, Status: models.TicketStatusInProgress}, nil)
+				rr.On("Create", mock.Anything, mock.Anything).Return(int64(7), nil)
+				hr.On("Create", mock.Anything, mock.MatchedBy(func(h *models.TicketHistory) bool {
+					return h.TicketID == 1 && h.AdminID != nil && *h.AdminID == testAdminID
+				})).Return(int64(1), nil)
+			},
+			wantCommits: 1,
+		},
+		{
+			name: "missing ticket",
+			mockSetup: func(tr *MockTicketRepository, _ *MockTicketHistoryRepository, _ *MockResponseRepository, _ *MockFileService) {
+				tr.On("GetByID", mock.Anything, int64(1)).Return(nil, nil)
+			},
+			wantErr: ErrTicketNotFound,
+		},
+		{
+			name:   "closed ticket is rejected before upload",
+			upload: &models.FileUpload{Reader: bytes.NewReader([]byte("content")), Name: "file.txt", ContentType: "text/plain"},
+			mockSetup: func(tr *MockTicketRepository, _ *MockTicketHistoryRepository, _ *MockResponseRepository, _ *MockFileService) {
+				tr.On("GetByID", mock.Anything, int64(1)).Return(&models.Ticket{ID: 1, UserID: testOwnerID, Status: models.TicketStatusClosed}, nil)
+			},
+			wantErr: ErrTicketClosed,
+		},
+		{
+			name:   "attachment is stored with the response",
+			upload: &models.FileUpload{Reader: bytes.NewReader([]byte("content")), Name: "file.txt", ContentType: "text/plain"},
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository, rr *MockResponseRepository, fs *MockFileService) {
+				tr.On("GetByID", mock.Anything, int64(1)).Return(&models.Ticket{ID: 1, UserID: testOwnerID, Status: models.TicketStatusInProgress}, nil)
+				fs.On("UploadFileTx", mock.Anything, mock.AnythingOfType("*services.MockFileObjectRepository"), mock.Anything, "responses", "1").Return("objects/ed/ed7002", nil)
+				rr.On("Create", mock.Anything, mock.MatchedBy(func(r *models.Response) bool {
+					return r.FileURL != nil && *r.FileURL == "objects/ed/ed7002"
+				})).Return(int64(7), nil)
+				hr.On("Create", mock.Anything, mock.Anything).Return(int64(1), nil)
+			},
+			wantCommits: 1,
+		},
+		{
+			name:   "history failure rolls back the attachment reference",
+			upload: &models.FileUpload{Reader: bytes.NewReader([]byte("content")), Name: "file.txt", ContentType: "text/plain"},
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository, rr *MockResponseRepository, fs *MockFileService) {
+				tr.On("GetByID", mock.Anything, int64(1)).Return(&models.Ticket{ID: 1, UserID: testOwnerID, Status: models.TicketStatusInProgress}, nil)
+				fs.On("UploadFileTx", mock.Anything, mock.AnythingOfType("*services.MockFileObjectRepository"), mock.Anything, "responses", "1").Return("objects/ed/ed7002", nil)
+				rr.On("Create", mock.Anything, mock.Anything).Return(int64(7), nil)
+				hr.On("Create", mock.Anything, mock.Anything).Return(int64(0), dbErr)
+			},
+			wantErr:      dbErr,
+			wantRollback: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTicketRepo := new(MockTicketRepository)
+			mockHistoryRepo := new(MockTicketHistoryRepository)
+			mockResponseRepo := new(MockResponseRepository)
+			mockFileService := new(MockFileService)
+			mockPublisher := new(MockEventPublisher)
+			mockPublisher.On("Publish", mock.Anything, mock.Anything).Return(nil)
+
+			tt.mockSetup(mockTicketRepo, mockHistoryRepo, mockResponseRepo, mockFileService)
+
+			uow := &fakeUnitOfWork{repos: repositories.TxRepositories{
+				Tickets:     mockTicketRepo,
+				History:     mockHistoryRepo,
+				Responses:   mockResponseRepo,
+				FileObjects: new(MockFileObjectRepository),
+			}}
+			service := NewResponseService(mockResponseRepo, mockTicketRepo, mockHistoryRepo, mockFileService, new(MockEmailService), mockPublisher, nil, uow, nil)
+
+			response := &models.Response{TicketID: 1, AdminID: testAdminID, Message: "Ответ"}
+			err := service.CreateResponse(context.Background(), response, tt.upload)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, int64(0), response.ID)
+				assert.Nil(t, response.FileURL)
+				mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(7), response.ID)
+				mockPublisher.AssertNumberOfCalls(t, "Publish", 1)
+			}
+			assert.Equal(t, tt.wantCommits, uow.commits)
+			assert.Equal(t, tt.wantRollback, uow.rollbacks)
+			mockTicketRepo.AssertExpectations(t)
+			mockHistoryRepo.AssertExpectations(t)
+			mockResponseRepo.AssertExpectations(t)
+			mockFileService.AssertExpectations(t)
+		})
+	}
+}
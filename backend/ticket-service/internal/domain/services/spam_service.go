@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+	"ticket-service/internal/infrastructure/metrics"
+	"ticket-service/internal/logger"
+)
+
+var ErrSubmissionQuotaExceeded = errors.New("too many tickets submitted from this contact, try again later")
+
+// Веса сигналов: ловушка для ботов сама по себе достигает порога по умолчанию
+const (
+	honeypotScore   = 1.0
+	disposableScore = 0.6
+	linksScore      = 0.5
+	keywordScore    = 0.3
+	maxKeywordScore = 0.9
+)
+
+// Названия сигналов в SpamSignal.Name и метриках
+const (
+	SpamSignalHoneypot   = "honeypot"
+	SpamSignalDisposable = "disposable_email"
+	SpamSignalLinks      = "links"
+	SpamSignalKeywords   = "keywords"
+)
+
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)\S+`)
+
+// defaultDisposableDomains распространенные одноразовые почтовые сервисы
+var defaultDisposableDomains = []string{
+	"mailinator.com", "guerrillamail.com", "10minutemail.com", "temp-mail.org",
+	"tempmail.com", "yopmail.com", "trashmail.com", "getnada.com",
+	"sharklasers.com", "dispostable.com", "maildrop.cc", "throwawaymail.com",
+}
+
+// SpamPolicy пороги антиспама
+type SpamPolicy struct {
+	EmailQuota        int
+	PhoneQuota        int
+	QuotaWindow       time.Duration
+	MaxLinks          int
+	Keywords          []string
+	DisposableDomains []string
+	Threshold         float64
+}
+
+// SpamService проверяет гостевые тикеты: квоты на контакты и эвристическая оценка
+type SpamService struct {
+	ticketRepo repositories.TicketRepository
+	policy     SpamPolicy
+	disposable map[string]struct{}
+	keywords   []string
+}
+
+func NewSpamService(ticketRepo repositories.TicketRepository, policy SpamPolicy) *SpamService {
+	disposable := make(map[string]struct{})
+	for _, domain := range append(defaultDisposableDomains, policy.DisposableDomains...) {
+		disposable[strings.ToLower(strings.TrimSpace(domain))] = struct{}{}
+	}
+
+	keywords := make([]string, 0, len(policy.Keywords))
+	for _, keyword := range policy.Keywords {
+		if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+
+	return &SpamService{
+		ticketRepo: ticketRepo,
+		policy:     policy,
+		disposable: disposable,
+		keywords:   keywords,
+	}
+}
+
+// Evaluate проверяет квоты и оценивает гостевой тикет до сохранения.
+// Сработавшие сигналы и сумма записываются в тикет; при достижении порога тикет получает статус spam.
+// honeypot - значение скрытого поля формы
+func (s *SpamService) Evaluate(ctx context.Context, ticket *models.Ticket, honeypot string) error {
+	if err := s.checkQuotas(ctx, ticket); err != nil {
+		if errors.Is(err, ErrSubmissionQuotaExceeded) {
+			metrics.SpamVerdictsTotal.WithLabelValues("quota_exceeded").Inc()
+		}
+		return err
+	}
+
+	signals := s.score(ticket, honeypot)
+
+	var total float64
+	for _, signal := range signals {
+		total += signal.Score
+		metrics.SpamSignalsTotal.WithLabelValues(signal.Name).Inc()
+	}
+	ticket.SpamScore = total
+	ticket.SpamSignals = signals
+
+	if len(signals) > 0 && total >= s.policy.Threshold {
+		ticket.Status = models.TicketStatusSpam
+		metrics.SpamVerdictsTotal.WithLabelValues("suspected").Inc()
+		logger.Info("Ticket held as suspected spam", "email", ticket.Email, "score", total)
+		return nil
+	}
+
+	metrics.SpamVerdictsTotal.WithLabelValues("clean").Inc()
+	return nil
+}
+
+func (s *SpamService) checkQuotas(ctx context.Context, ticket *models.Ticket) error {
+	since := time.Now().Add(-s.policy.QuotaWindow)
+
+	if s.policy.EmailQuota > 0 && ticket.Email != "" {
+		count, err := s.ticketRepo.CountByEmailSince(ctx, ticket.Email, since)
+		if err != nil {
+			return fmt.Errorf("failed to check email quota: %w", err)
+		}
+		if count >= int64(s.policy.EmailQuota) {
+			logger.Info("Email submission quota exceeded", "email", ticket.Email, "count", count)
+			return ErrSubmissionQuotaExceeded
+		}
+	}
+
+	if s.policy.PhoneQuota > 0 && ticket.Phone != nil && *ticket.Phone != "" {
+		count, err := s.ticketRepo.CountByPhoneSince(ctx, *ticket.Phone, since)
+		if err != nil {
+			return fmt.Errorf("failed to check phone quota: %w", err)
+		}
+		if count >= int64(s.policy.PhoneQuota) {
+			logger.Info("Phone submission quota exceeded", "phone", *ticket.Phone, "count", count)
+			return ErrSubmissionQuotaExceeded
+		}
+	}
+
+	return nil
+}
+
+func (s *SpamService) score(ticket *models.Ticket, honeypot string) []models.SpamSignal {
+	var signals []models.SpamSignal
+
+	if strings.TrimSpace(honeypot) != "" {
+		signals = append(signals, models.SpamSignal{Name: SpamSignalHoneypot, Score: honeypotScore})
+	}
+
+	if at := strings.LastIndex(ticket.Email, "@"); at >= 0 {
+		domain := strings.ToLower(ticket.Email[at+1:])
+		if _, ok := s.disposable[domain]; ok {
+			signals = append(signals, models.SpamSignal{Name: SpamSignalDisposable, Score: disposableScore, Detail: domain})
+		}
+	}
+
+	text := ticket.Subject + "\n" + ticket.Question
+	if links := len(linkPattern.FindAllString(text, -1)); s.policy.MaxLinks > 0 && links > s.policy.MaxLinks {
+		signals = append(signals, models.SpamSignal{
+			Name:   SpamSignalLinks,
+			Score:  linksScore,
+			Detail: fmt.Sprintf("%d links", links),
+		})
+	}
+
+	lower := strings.ToLower(text)
+	var matched []string
+	for _, keyword := range s.keywords {
+		if strings.Contains(lower, keyword) {
+			matched = append(matched, keyword)
+		}
+	}
+	if len(matched) > 0 {
+		score := keywordScore * float64(len(matched))
+		if score > maxKeywordScore {
+			score = maxKeywordScore
+		}
+		signals = append(signals, models.SpamSignal{
+			Name:   SpamSignalKeywords,
+			Score:  score,
+			Detail: strings.Join(matched, ","),
+		})
+	}
+
+	return signals
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"ticket-service/internal/domain/models"
+)
+
+func TestSpamServiceEvaluate(t *testing.T) {
+	policy := SpamPolicy{
+		EmailQuota:  3,
+		PhoneQuota:  3,
+		QuotaWindow: 24 * time.Hour,
+		MaxLinks:    2,
+		Keywords:    []string{"casino", "crypto"},
+		Threshold:   1.0,
+	}
+	phone := "+77001234567"
+
+	tests := []struct {
+		name            string
+		ticket          *models.Ticket
+		honeypot        string
+		mockSetup       func(*MockTicketRepository)
+		expectedError   error
+		expectedStatus  models.TicketStatus
+		expectedSignals []string
+	}{
+		{
+			name:   "Обычный тикет проходит",
+			ticket: &models.Ticket{Email: "user@example.com", Phone: &phone, Subject: "Признание диплома", Question: "Какие документы нужны?", Status: models.TicketStatusNew},
+			mockSetup: func(tr *MockTicketRepository) {
+				tr.On("CountByEmailSince", mock.Anything, "user@example.com", mock.Anything).Return(int64(0), nil)
+				tr.On("CountByPhoneSince", mock.Anything, phone, mock.Anything).Return(int64(2), nil)
+			},
+			expectedStatus: models.TicketStatusNew,
+		},
+		{
+			name:     "Заполненная ловушка задерживает тикет",
+			ticket:   &models.Ticket{Email: "user@example.com", Subject: "Вопрос", Question: "Текст", Status: models.TicketStatusNew},
+			honeypot: "http://spam.example",
+			mockSetup: func(tr *MockTicketRepository) {
+				tr.On("CountByEmailSince", mock.Anything, "user@example.com", mock.Anything).Return(int64(0), nil)
+			},
+			expectedStatus:  models.TicketStatusSpam,
+			expectedSignals: []string{SpamSignalHoneypot},
+		},
+		{
+			name:   "Одноразовая почта и ссылки суммируются до порога",
+			ticket: &models.Ticket{Email: "bot@Mailinator.com", Subject: "Offer", Question: "https://a.example https://b.example www.c.example", Status: models.TicketStatusNew},
+			mockSetup: func(tr *MockTicketRepository) {
+				tr.On("CountByEmailSince", mock.Anything, "bot@Mailinator.com", mock.Anything).Return(int64(0), nil)
+			},
+			expectedStatus:  models.TicketStatusSpam,
+			expectedSignals: []string{SpamSignalDisposable, SpamSignalLinks},
+		},
+		{
+			name:   "Стоп-слова ниже порога записываются, но не задерживают",
+			ticket: &models.Ticket{Email: "user@example.com", Subject: "Crypto", Question: "Вопрос про crypto", Status: models.TicketStatusNew},
+			mockSetup: func(tr *MockTicketRepository) {
+				tr.On("CountByEmailSince", mock.Anything, "user@example.com", mock.Anything).Return(int64(0), nil)
+			},
+			expectedStatus:  models.TicketStatusNew,
+			expectedSignals: []string{SpamSignalKeywords},
+		},
+		{
+			name:   "Превышена квота по email",
+			ticket: &models.Ticket{Email: "user@example.com", Subject: "Вопрос", Question: "Текст", Status: models.TicketStatusNew},
+			mockSetup: func(tr *MockTicketRepository) {
+				tr.On("CountByEmailSince", mock.Anything, "user@example.com", mock.Anything).Return(int64(3), nil)
+			},
+			expectedError:  ErrSubmissionQuotaExceeded,
+			expectedStatus: models.TicketStatusNew,
+		},
+		{
+			name:   "Превышена квота по телефону",
+			ticket: &models.Ticket{Email: "user@example.com", Phone: &phone, Subject: "Вопрос", Question: "Текст", Status: models.TicketStatusNew},
+			mockSetup: func(tr *MockTicketRepository) {
+				tr.On("CountByEmailSince", mock.Anything, "user@example.com", mock.Anything).Return(int64(0), nil)
+				tr.On("CountByPhoneSince", mock.Anything, phone, mock.Anything).Return(int64(3), nil)
+			},
+			expectedError:  ErrSubmissionQuotaExceeded,
+			expectedStatus: models.TicketStatusNew,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTicketRepo := new(MockTicketRepository)
+			tt.mockSetup(mockTicketRepo)
+
+			service := NewSpamService(mockTicketRepo, policy)
+			err := service.Evaluate(context.Background(), tt.ticket, tt.honeypot)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedStatus, tt.ticket.Status)
+
+			var names []string
+			for _, signal := range tt.ticket.SpamSignals {
+				names = append(names, signal.Name)
+			}
+			assert.Equal(t, tt.expectedSignals, names)
+
+			mockTicketRepo.AssertExpectations(t)
+		})
+	}
+}
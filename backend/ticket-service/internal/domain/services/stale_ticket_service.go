@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+	"ticket-service/internal/infrastructure/metrics"
+	"ticket-service/internal/logger"
+)
+
+// StalePolicy сроки бездействия заявителя до напоминания и до автозакрытия
+type StalePolicy struct {
+	ReminderAfter time.Duration
+	CloseAfter    time.Duration
+}
+
+// StaleTicketService напоминает заявителям о тикетах в статусе waiting и закрывает заброшенные
+type StaleTicketService struct {
+	ticketRepo     repositories.TicketRepository
+	historyRepo    repositories.TicketHistoryRepository
+	emailService   IEmailService
+	surveySender   ISurveySender
+	eventPublisher IEventPublisher
+	auditRecorder  IAuditRecorder
+	uow            repositories.UnitOfWork
+	defaultPolicy  StalePolicy
+	policies       map[models.TicketCategory]StalePolicy
+}
+
+func NewStaleTicketService(
+	ticketRepo repositories.TicketRepository,
+	historyRepo repositories.TicketHistoryRepository,
+	emailService IEmailService,
+	surveySender ISurveySender,
+	eventPublisher IEventPublisher,
+	auditRecorder IAuditRecorder,
+	uow repositories.UnitOfWork,
+	defaultPolicy StalePolicy,
+	policies map[models.TicketCategory]StalePolicy,
+) *StaleTicketService {
+	return &StaleTicketService{
+		ticketRepo:     ticketRepo,
+		historyRepo:    historyRepo,
+		emailService:   emailService,
+		surveySender:   surveySender,
+		eventPublisher: eventPublisher,
+		auditRecorder:  auditRecorder,
+		uow:            uow,
+		defaultPolicy:  defaultPolicy,
+		policies:       policies,
+	}
+}
+
+// inTx выполняет fn атомарно через unit of work, как TicketService.inTx
+func (s *StaleTicketService) inTx(ctx context.Context, fn func(repos repositories.TxRepositories) error) error {
+	if s.uow == nil {
+		return fn(repositories.TxRepositories{
+			Tickets: s.ticketRepo,
+			History: s.historyRepo,
+		})
+	}
+	return s.uow.Do(ctx, fn)
+}
+
+// PolicyFor возвращает сроки для категории, по умолчанию - общие
+func (s *StaleTicketService) PolicyFor(category models.TicketCategory) StalePolicy {
+	if policy, ok := s.policies[category]; ok {
+		return policy
+	}
+	return s.defaultPolicy
+}
+
+// ProcessStaleTickets отправляет напоминания и закрывает тикеты, чей срок ожидания истек
+func (s *StaleTicketService) ProcessStaleTickets(ctx context.Context) error {
+	tickets, err := s.ticketRepo.GetWaiting(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get waiting tickets: %w", err)
+	}
+
+	now := time.Now()
+	for _, ticket := range tickets {
+		if ticket.WaitingSince == nil {
+			continue
+		}
+
+		policy := s.PolicyFor(ticket.Category)
+		idle := now.Sub(*ticket.WaitingSince)
+
+		switch {
+		case idle >= policy.CloseAfter:
+			if err := s.autoClose(ctx, ticket, policy); err != nil {
+				logger.Error("Failed to auto-close stale ticket", "error", err, "ticketID", ticket.ID)
+			}
+		case idle >= policy.ReminderAfter && ticket.ReminderSentAt == nil:
+			if err := s.remind(ctx, ticket, policy, now); err != nil {
+				logger.Error("Failed to send stale ticket reminder", "error", err, "ticketID", ticket.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *StaleTicketService) remind(ctx context.Context, ticket *models.Ticket, policy StalePolicy, now time.Time) error {
+	if ticket.NotifyEmail && ticket.Email != "" {
+		closeAt := ticket.WaitingSince.Add(policy.CloseAfter)
+		if err := s.emailService.SendStaleTicketReminder(ticket.Email, ticket.Subject, closeAt); err != nil {
+			return fmt.Errorf("failed to send reminder: %w", err)
+		}
+	}
+
+	if err := s.ticketRepo.MarkReminderSent(ctx, ticket.ID, now); err != nil {
+		return fmt.Errorf("failed to mark reminder as sent: %w", err)
+	}
+
+	recordAudit(ctx, s.auditRecorder, ticket.ID, models.AuditTicketReminderSent,
+		map[string]interface{}{"reminder_sent_at": nil},
+		map[string]interface{}{"reminder_sent_at": now})
+
+	metrics.StaleTicketRemindersTotal.WithLabelValues(string(ticket.Category)).Inc()
+	logger.Info("Stale ticket reminder sent", "ticketID", ticket.ID, "category", ticket.Category)
+	return nil
+}
+
+func (s *StaleTicketService) autoClose(ctx context.Context, ticket *models.Ticket, policy StalePolicy) error {
+	comment := fmt.Sprintf("Тикет закрыт автоматически: нет ответа заявителя %d дн.", int(policy.CloseAfter.Hours()/24))
+
+	closed := false
+	err := s.inTx(ctx, func(repos repositories.TxRepositories) error {
+		ok, err := repos.Tickets.CloseWaiting(ctx, ticket.ID)
+		if err != nil {
+			return fmt.Errorf("failed to close ticket: %w", err)
+		}
+		if !ok {
+			return nil
+		}
+		closed = true
+
+		// Закрытие выполнено системой, поэтому admin_id не заполняется
+		history := &models.TicketHistory{
+			TicketID: ticket.ID,
+			Status:   models.TicketStatusClosed,
+			Comment:  &comment,
+		}
+		if _, err := repos.History.Create(ctx, history); err != nil {
+			return fmt.Errorf("failed to create history record: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !closed {
+		// Заявитель ответил или администратор сменил статус после выборки
+		logger.Info("Stale ticket is no longer waiting", "ticketID", ticket.ID)
+		return nil
+	}
+
+	recordAudit(ctx, s.auditRecorder, ticket.ID, models.AuditTicketStatusChanged,
+		map[string]interface{}{"status": ticket.Status, "waiting_since": ticket.WaitingSince},
+		map[string]interface{}{"status": models.TicketStatusClosed, "waiting_since": nil})
+
+	publishEvent(ctx, s.eventPublisher, &models.TicketEvent{
+		Type:     models.TicketEventStatusChanged,
+		TicketID: ticket.ID,
+		OwnerID:  ticket.OwnerID(),
+		Status:   models.TicketStatusClosed,
+	})
+
+	if s.surveySender != nil {
+		if err := s.surveySender.SendSurvey(ctx, ticket, uuid.Nil); err != nil {
+			logger.Error("Failed to send satisfaction survey", "error", err, "ticketID", ticket.ID)
+		}
+	}
+
+	metrics.StaleTicketsAutoClosedTotal.WithLabelValues(string(ticket.Category)).Inc()
+	logger.Info("Stale ticket closed automatically", "ticketID", ticket.ID, "category", ticket.Category)
+	return nil
+}
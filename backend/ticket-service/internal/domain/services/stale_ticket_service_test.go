@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+)
+
+type MockEmailService struct {
+	mock.Mock
+}
+
+func (m *MockEmailService) SendTicketResponseNotification(to, ticketSubject, responseMessage string) error {
+	args := m.Called(to, ticketSubject, responseMessage)
+	return args.Error(0)
+}
+
+func (m *MockEmailService) SendStaleTicketReminder(to, ticketSubject string, closeAt time.Time) error {
+	args := m.Called(to, ticketSubject, closeAt)
+	return args.Error(0)
+}
+
+func (m *MockEmailService) SendSatisfactionSurvey(to, ticketSubject, surveyURL string) error {
+	args := m.Called(to, ticketSubject, surveyURL)
+	return args.Error(0)
+}
+
+func waitingTicket(id int64, category models.TicketCategory, idle time.Duration, reminded bool) *models.Ticket {
+	since := time.Now().Add(-idle)
+	ticket := &models.Ticket{
+		ID:           id,
+		Category:     category,
+		Subject:      "Test Subject",
+		Email:        "test@example.com",
+		Status:       models.TicketStatusWaiting,
+		NotifyEmail:  true,
+		WaitingSince: &since,
+	}
+	if reminded {
+		ticket.ReminderSentAt = &since
+	}
+	return ticket
+}
+
+func TestProcessStaleTickets(t *testing.T) {
+	day := 24 * time.Hour
+	defaultPolicy := StalePolicy{ReminderAfter: 3 * day, CloseAfter: 14 * day}
+	policies := map[models.TicketCategory]StalePolicy{
+		models.TicketCategoryLegalisation: {ReminderAfter: day, CloseAfter: 2 * day},
+	}
+
+	tests := []struct {
+		name      string
+		ticket    *models.Ticket
+		mockSetup func(*MockTicketRepository, *MockTicketHistoryRepository, *MockEmailService)
+		// Закрытие и запись истории выполняются в одной транзакции
+		wantCommits  int
+		wantRollback int
+	}{
+		{
+			name:      "Срок напоминания не наступил",
+			ticket:    waitingTicket(1, models.TicketCategoryGeneral, 2*day, false),
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository, es *MockEmailService) {},
+		},
+		{
+			name:   "Отправка напоминания",
+			ticket: waitingTicket(2, models.TicketCategoryGeneral, 4*day, false),
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository, es *MockEmailService) {
+				es.On("SendStaleTicketReminder", "test@example.com", "Test Subject", mock.Anything).Return(nil)
+				tr.On("MarkReminderSent", mock.Anything, int64(2), mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:      "Напоминание уже отправлено",
+			ticket:    waitingTicket(3, models.TicketCategoryGeneral, 5*day, true),
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository, es *MockEmailService) {},
+		},
+		{
+			name:   "Автозакрытие по политике категории",
+			ticket: waitingTicket(4, models.TicketCategoryLegalisation, 3*day, true),
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository, es *MockEmailService) {
+				tr.On("CloseWaiting", mock.Anything, int64(4)).Return(true, nil)
+				hr.On("Create", mock.Anything, mock.MatchedBy(func(h *models.TicketHistory) bool {
+					return h.TicketID == 4 && h.Status == models.TicketStatusClosed && h.AdminID == nil && h.Comment != nil
+				})).Return(int64(1), nil)
+			},
+			wantCommits: 1,
+		},
+		{
+			name:   "Ошибка истории откатывает автозакрытие",
+			ticket: waitingTicket(6, models.TicketCategoryGeneral, 15*day, true),
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository, es *MockEmailService) {
+				tr.On("CloseWaiting", mock.Anything, int64(6)).Return(true, nil)
+				hr.On("Create", mock.Anything, mock.Anything).Return(int64(0), errors.New("db error"))
+			},
+			wantRollback: 1,
+		},
+		{
+			name:   "Статус сменился до автозакрытия",
+			ticket: waitingTicket(5, models.TicketCategoryGeneral, 15*day, true),
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository, es *MockEmailService) {
+				tr.On("CloseWaiting", mock.Anything, int64(5)).Return(false, nil)
+			},
+			wantCommits: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTicketRepo := new(MockTicketRepository)
+			mockHistoryRepo := new(MockTicketHistoryRepository)
+			mockEmailService := new(MockEmailService)
+
+			mockTicketRepo.On("GetWaiting", mock.Anything).Return([]*models.Ticket{tt.ticket}, nil)
+			tt.mockSetup(mockTicketRepo, mockHistoryRepo, mockEmailService)
+
+			uow := &fakeUnitOfWork{repos: repositories.TxRepositories{Tickets: mockTicketRepo, History: mockHistoryRepo}}
+			service := NewStaleTicketService(mockTicketRepo, mockHistoryRepo, mockEmailService, nil, nil, nil, uow, defaultPolicy, policies)
+
+			err := service.ProcessStaleTickets(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCommits, uow.commits)
+			assert.Equal(t, tt.wantRollback, uow.rollbacks)
+			mockTicketRepo.AssertExpectations(t)
+			mockHistoryRepo.AssertExpectations(t)
+			mockEmailService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+	"ticket-service/internal/infrastructure/metrics"
+	"ticket-service/internal/logger"
+)
+
+var (
+	ErrSurveyNotFound     = errors.New("survey not found")
+	ErrInvalidRating      = errors.New("rating must be between 1 and 5")
+	ErrInvalidSurveyToken = errors.New("invalid or expired survey link")
+	ErrSurveyAlreadyRated = errors.New("survey has already been rated")
+)
+
+type SurveyService struct {
+	surveyRepo   repositories.SurveyRepository
+	responseRepo repositories.ResponseRepository
+	emailService IEmailService
+	signer       ISurveyTokenSigner
+	baseURL      string
+}
+
+func NewSurveyService(
+	surveyRepo repositories.SurveyRepository,
+	responseRepo repositories.ResponseRepository,
+	emailService IEmailService,
+	signer ISurveyTokenSigner,
+	baseURL string,
+) *SurveyService {
+	return &SurveyService{
+		surveyRepo:   surveyRepo,
+		responseRepo: responseRepo,
+		emailService: emailService,
+		signer:       signer,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// SendSurvey регистрирует опрос по закрытому тикету и отправляет заявителю ссылку на оценку.
+// adminID - закрывший администратор; uuid.Nil означает закрытие системой, тогда оценка относится к последнему ответившему
+func (s *SurveyService) SendSurvey(ctx context.Context, ticket *models.Ticket, adminID uuid.UUID) error {
+	if !ticket.NotifyEmail || ticket.Email == "" {
+		logger.Info("Skipping survey: applicant has no email notifications", "ticketID", ticket.ID)
+		return nil
+	}
+
+	// Новый nonce делает недействительными ссылки из писем прежних отправок
+	survey := &models.Survey{
+		TicketID: ticket.ID,
+		SentAt:   time.Now(),
+		Nonce:    uuid.NewString(),
+	}
+	if adminID != uuid.Nil {
+		survey.AdminID = &adminID
+	} else {
+		responses, err := s.responseRepo.GetByTicketID(ctx, ticket.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get ticket responses: %w", err)
+		}
+		if len(responses) > 0 {
+			survey.AdminID = &responses[len(responses)-1].AdminID
+		}
+	}
+
+	token, err := s.signer.Sign(ticket.ID, survey.Nonce)
+	if err != nil {
+		return fmt.Errorf("failed to sign survey token: %w", err)
+	}
+
+	if _, err := s.surveyRepo.Upsert(ctx, survey); err != nil {
+		return fmt.Errorf("failed to save survey: %w", err)
+	}
+
+	surveyURL := fmt.Sprintf("%s/%s", s.baseURL, token)
+	if err := s.emailService.SendSatisfactionSurvey(ticket.Email, ticket.Subject, surveyURL); err != nil {
+		return fmt.Errorf("failed to send survey: %w", err)
+	}
+
+	metrics.CSATSurveysSentTotal.Inc()
+	logger.Info("Satisfaction survey sent", "ticketID", ticket.ID)
+	return nil
+}
+
+// GetSurvey возвращает опрос по подписанному токену из письма, ничего не сохраняя. Ссылка действует,
+// пока опрос не оценен и не отправлен заново
+func (s *SurveyService) GetSurvey(ctx context.Context, token string) (*models.Survey, error) {
+	ticketID, nonce, err := s.signer.Parse(token)
+	if err != nil {
+		logger.Warn("Invalid survey token", "error", err)
+		return nil, ErrInvalidSurveyToken
+	}
+
+	survey, err := s.surveyRepo.GetByTicketID(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get survey: %w", err)
+	}
+	if survey == nil {
+		return nil, ErrSurveyNotFound
+	}
+	if survey.Nonce != nonce {
+		logger.Warn("Survey token from a replaced survey", "ticketID", ticketID)
+		return nil, ErrInvalidSurveyToken
+	}
+	if survey.RatedAt != nil {
+		return nil, ErrSurveyAlreadyRated
+	}
+	return survey, nil
+}
+
+// SubmitRating сохраняет оценку по подписанному токену из письма
+func (s *SurveyService) SubmitRating(ctx context.Context, token string, rating int, comment *string) error {
+	if rating < models.MinSurveyRating || rating > models.MaxSurveyRating {
+		return ErrInvalidRating
+	}
+
+	survey, err := s.GetSurvey(ctx, token)
+	if err != nil {
+		return err
+	}
+	ticketID := survey.TicketID
+
+	saved, err := s.surveyRepo.SaveRating(ctx, ticketID, survey.Nonce, rating, comment)
+	if err != nil {
+		logger.Error("Failed to save survey rating", "error", err, "ticketID", ticketID)
+		return fmt.Errorf("failed to save rating: %w", err)
+	}
+	// Опрос оценили или отправили заново после проверки ссылки
+	if !saved {
+		return ErrSurveyAlreadyRated
+	}
+
+	metrics.CSATRatingsTotal.WithLabelValues(fmt.Sprintf("%d", rating)).Inc()
+	logger.Info("Survey rating saved", "ticketID", ticketID, "rating", rating)
+	return nil
+}
+
+func (s *SurveyService) GetSummary(ctx context.Context, filter models.CSATFilter) (*models.CSATSummary, error) {
+	summary, err := s.surveyRepo.GetSummary(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get survey summary: %w", err)
+	}
+	return summary, nil
+}
+
+func (s *SurveyService) GetAdminStats(ctx context.Context, filter models.CSATFilter) ([]*models.AdminCSATStats, error) {
+	stats, err := s.surveyRepo.GetAdminStats(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admin survey stats: %w", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"ticket-service/internal/domain/models"
+)
+
+type MockSurveyRepository struct {
+	mock.Mock
+}
+
+func (m *MockSurveyRepository) Upsert(ctx context.Context, survey *models.Survey) (int64, error) {
+	args := m.Called(ctx, survey)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockSurveyRepository) GetByTicketID(ctx context.Context, ticketID int64) (*models.Survey, error) {
+	args := m.Called(ctx, ticketID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Survey), args.Error(1)
+}
+
+func (m *MockSurveyRepository) SaveRating(ctx context.Context, ticketID int64, nonce string, rating int, comment *string) (bool, error) {
+	args := m.Called(ctx, ticketID, nonce, rating, comment)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSurveyRepository) GetSummary(ctx context.Context, filter models.CSATFilter) (*models.CSATSummary, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CSATSummary), args.Error(1)
+}
+
+func (m *MockSurveyRepository) GetAdminStats(ctx context.Context, filter models.CSATFilter) ([]*models.AdminCSATStats, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*models.AdminCSATStats), args.Error(1)
+}
+
+type MockSurveyTokenSigner struct {
+	mock.Mock
+}
+
+func (m *MockSurveyTokenSigner) Sign(ticketID int64, nonce string) (string, error) {
+	args := m.Called(ticketID, nonce)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockSurveyTokenSigner) Parse(token string) (int64, string, error) {
+	args := m.Called(token)
+	return args.Get(0).(int64), args.String(1), args.Error(2)
+}
+
+func TestSurveyServiceSendSurvey(t *testing.T) {
+	closedBy := uuid.New()
+	lastResponder := uuid.New()
+
+	tests := []struct {
+		name      string
+		ticket    *models.Ticket
+		adminID   uuid.UUID
+		mockSetup func(*MockSurveyRepository, *MockResponseRepository, *MockSurveyTokenSigner, *MockEmailService)
+	}{
+		{
+			name:      "Без email-уведомлений опрос не отправляется",
+			ticket:    &models.Ticket{ID: 1, Email: "test@example.com", NotifyEmail: false},
+			adminID:   closedBy,
+			mockSetup: func(*MockSurveyRepository, *MockResponseRepository, *MockSurveyTokenSigner, *MockEmailService) {},
+		},
+		{
+			name:    "Оценка относится к закрывшему администратору",
+			ticket:  &models.Ticket{ID: 2, Subject: "Тема", Email: "test@example.com", NotifyEmail: true},
+			adminID: closedBy,
+			mockSetup: func(sr *MockSurveyRepository, rr *MockResponseRepository, signer *MockSurveyTokenSigner, es *MockEmailService) {
+				signer.On("Sign", int64(2), mock.AnythingOfType("string")).Return("token", nil)
+				sr.On("Upsert", mock.Anything, mock.MatchedBy(func(s *models.Survey) bool {
+					return s.TicketID == 2 && s.AdminID != nil && *s.AdminID == closedBy && s.Nonce != ""
+				})).Return(int64(1), nil)
+				es.On("SendSatisfactionSurvey", "test@example.com", "Тема", "https://support.example/surveys/token").Return(nil)
+			},
+		},
+		{
+			name:    "При закрытии системой оценка относится к последнему ответившему",
+			ticket:  &models.Ticket{ID: 3, Subject: "Тема", Email: "test@example.com", NotifyEmail: true},
+			adminID: uuid.Nil,
+			mockSetup: func(sr *MockSurveyRepository, rr *MockResponseRepository, signer *MockSurveyTokenSigner, es *MockEmailService) {
+				rr.On("GetByTicketID", mock.Anything, int64(3)).Return([]*models.Response{{AdminID: uuid.New()}, {AdminID: lastResponder}}, nil)
+				signer.On("Sign", int64(3), mock.AnythingOfType("string")).Return("token", nil)
+				sr.On("Upsert", mock.Anything, mock.MatchedBy(func(s *models.Survey) bool {
+					return s.TicketID == 3 && s.AdminID != nil && *s.AdminID == lastResponder
+				})).Return(int64(1), nil)
+				es.On("SendSatisfactionSurvey", "test@example.com", "Тема", "https://support.example/surveys/token").Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			surveyRepo := new(MockSurveyRepository)
+			responseRepo := new(MockResponseRepository)
+			signer := new(MockSurveyTokenSigner)
+			emailService := new(MockEmailService)
+			tt.mockSetup(surveyRepo, responseRepo, signer, emailService)
+
+			service := NewSurveyService(surveyRepo, responseRepo, emailService, signer, "https://support.example/surveys/")
+			err := service.SendSurvey(context.Background(), tt.ticket, tt.adminID)
+
+			assert.NoError(t, err)
+			surveyRepo.AssertExpectations(t)
+			responseRepo.AssertExpectations(t)
+			signer.AssertExpectations(t)
+			emailService.AssertExpectations(t)
+
+			// Ссылка подписывается тем же nonce, что сохраняется в опросе
+			for _, call := range surveyRepo.Calls {
+				if call.Method == "Upsert" {
+					signer.AssertCalled(t, "Sign", tt.ticket.ID, call.Arguments.Get(1).(*models.Survey).Nonce)
+				}
+			}
+		})
+	}
+}
+
+func TestSurveyServiceSubmitRating(t *testing.T) {
+	comment := "Спасибо"
+
+	tests := []struct {
+		name      string
+		rating    int
+		mockSetup func(*MockSurveyRepository, *MockSurveyTokenSigner)
+		wantErr   error
+	}{
+		{
+			name:   "Оценка сохраняется",
+			rating: 5,
+			mockSetup: func(sr *MockSurveyRepository, signer *MockSurveyTokenSigner) {
+				signer.On("Parse", "token").Return(int64(7), "nonce", nil)
+				sr.On("GetByTicketID", mock.Anything, int64(7)).Return(&models.Survey{ID: 1, TicketID: 7, Nonce: "nonce"}, nil)
+				sr.On("SaveRating", mock.Anything, int64(7), "nonce", 5, &comment).Return(true, nil)
+			},
+		},
+		{
+			name:   "Ссылка из письма прежней отправки",
+			rating: 5,
+			mockSetup: func(sr *MockSurveyRepository, signer *MockSurveyTokenSigner) {
+				signer.On("Parse", "token").Return(int64(7), "old-nonce", nil)
+				sr.On("GetByTicketID", mock.Anything, int64(7)).Return(&models.Survey{ID: 1, TicketID: 7, Nonce: "nonce"}, nil)
+			},
+			wantErr: ErrInvalidSurveyToken,
+		},
+		{
+			name:   "Опрос уже оценен",
+			rating: 5,
+			mockSetup: func(sr *MockSurveyRepository, signer *MockSurveyTokenSigner) {
+				ratedAt := time.Now()
+				signer.On("Parse", "token").Return(int64(7), "nonce", nil)
+				sr.On("GetByTicketID", mock.Anything, int64(7)).Return(&models.Survey{ID: 1, TicketID: 7, Nonce: "nonce", RatedAt: &ratedAt}, nil)
+			},
+			wantErr: ErrSurveyAlreadyRated,
+		},
+		{
+			name:   "Опрос оценен параллельно",
+			rating: 5,
+			mockSetup: func(sr *MockSurveyRepository, signer *MockSurveyTokenSigner) {
+				signer.On("Parse", "token").Return(int64(7), "nonce", nil)
+				sr.On("GetByTicketID", mock.Anything, int64(7)).Return(&models.Survey{ID: 1, TicketID: 7, Nonce: "nonce"}, nil)
+				sr.On("SaveRating", mock.Anything, int64(7), "nonce", 5, &comment).Return(false, nil)
+			},
+			wantErr: ErrSurveyAlreadyRated,
+		},
+		{
+			name:      "Оценка вне диапазона",
+			rating:    6,
+			mockSetup: func(*MockSurveyRepository, *MockSurveyTokenSigner) {},
+			wantErr:   ErrInvalidRating,
+		},
+		{
+			name:   "Недействительный токен",
+			rating: 4,
+			mockSetup: func(sr *MockSurveyRepository, signer *MockSurveyTokenSigner) {
+				signer.On("Parse", "token").Return(int64(0), "", errors.New("token is expired"))
+			},
+			wantErr: ErrInvalidSurveyToken,
+		},
+		{
+			name:   "Опрос не отправлялся",
+			rating: 4,
+			mockSetup: func(sr *MockSurveyRepository, signer *MockSurveyTokenSigner) {
+				signer.On("Parse", "token").Return(int64(7), "nonce", nil)
+				sr.On("GetByTicketID", mock.Anything, int64(7)).Return(nil, nil)
+			},
+			wantErr: ErrSurveyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			surveyRepo := new(MockSurveyRepository)
+			signer := new(MockSurveyTokenSigner)
+			tt.mockSetup(surveyRepo, signer)
+
+			service := NewSurveyService(surveyRepo, nil, nil, signer, "")
+			err := service.SubmitRating(context.Background(), "token", tt.rating, &comment)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			surveyRepo.AssertExpectations(t)
+			signer.AssertExpectations(t)
+		})
+	}
+}
@@ -1,15 +1,17 @@
 package services
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"time"
 
+	"github.com/google/uuid"
+
 	"ticket-service/internal/domain/models"
 	"ticket-service/internal/domain/repositories"
+	"ticket-service/internal/infrastructure/metrics"
 	"ticket-service/internal/logger"
 )
 
@@ -18,6 +20,13 @@ var (
 	ErrAntivirusNotAvailable = errors.New("antivirus service is not available")
 	ErrFileRequired          = errors.New("file name and type are required when file is provided")
 	ErrFileContainsMalware   = errors.New("file contains malware")
+	ErrTicketNotFound        = errors.New("ticket not found")
+	ErrTicketClosed          = errors.New("ticket is closed")
+	ErrResponseNotFound      = errors.New("response not found")
+	ErrResponseForbidden     = errors.New("only the author or a senior admin can modify the response")
+	ErrTicketNotClaimable    = errors.New("ticket is not a guest ticket submitted with your email")
+	ErrStatusTransition      = errors.New("ticket status transition is not allowed")
+	ErrTicketNotClosed       = errors.New("only closed tickets can be reopened")
 )
 
 type TicketService struct {
@@ -26,6 +35,13 @@ type TicketService struct {
 	responseRepo    repositories.ResponseRepository
 	antivirusService IAntivirusService
 	fileService     IFileService
+	surveySender    ISurveySender
+	eventPublisher  IEventPublisher
+	auditRecorder   IAuditRecorder
+	uow             repositories.UnitOfWork
+	fileInspector   *FileInspector
+	// reopenWindow срок после закрытия, в течение которого заявитель может открыть тикет повторно
+	reopenWindow    time.Duration
 }
 
 func NewTicketService(
@@ -34,6 +50,12 @@ func NewTicketService(
 	responseRepo repositories.ResponseRepository,
 	antivirusService IAntivirusService,
 	fileService IFileService,
+	surveySender ISurveySender,
+	eventPublisher IEventPublisher,
+	auditRecorder IAuditRecorder,
+	uow repositories.UnitOfWork,
+	fileInspector *FileInspector,
+	reopenWindow time.Duration,
 ) *TicketService {
 	return &TicketService{
 		ticketRepo:      ticketRepo,
@@ -41,9 +63,28 @@ func NewTicketService(
 		responseRepo:    responseRepo,
 		antivirusService: antivirusService,
 		fileService:     fileService,
+		surveySender:    surveySender,
+		eventPublisher:  eventPublisher,
+		auditRecorder:   auditRecorder,
+		uow:             uow,
+		fileInspector:   fileInspector,
+		reopenWindow:    reopenWindow,
 	}
 }
 
+// inTx выполняет fn атомарно через unit of work. Без него fn работает с обычными репозиториями,
+// и изменения фиксируются по мере выполнения запросов
+func (s *TicketService) inTx(ctx context.Context, fn func(repos repositories.TxRepositories) error) error {
+	if s.uow == nil {
+		return fn(repositories.TxRepositories{
+			Tickets:   s.ticketRepo,
+			History:   s.historyRepo,
+			Responses: s.responseRepo,
+		})
+	}
+	return s.uow.Do(ctx, fn)
+}
+
 func (s *TicketService) CreateTicket(ctx context.Context, ticket *models.Ticket, fileReader io.Reader) error {
 	logger.Info("Creating new ticket", "userID", ticket.UserID, "subject", ticket.Subject)
 
@@ -57,18 +98,27 @@ func (s *TicketService) CreateTicket(ctx context.Context, ticket *models.Ticket,
 			return ErrFileRequired
 		}
 
-		// Буферизуем файл для возможности повторного чтения
-		buf := new(bytes.Buffer)
-		if _, err := io.Copy(buf, fileReader); err != nil {
-			logger.Error("Failed to buffer file", "error", err, "filename", *ticket.FileName)
-			return fmt.Errorf("failed to buffer file: %w", err)
+		// Файл читается несколько раз, но не копируется в память: завершенная загрузка читается
+		// прямо из хранилища загрузок
+		content, err := openAttachment(fileReader)
+		if err != nil {
+			logger.Error("Failed to open file", "error", err, "filename", *ticket.FileName)
+			return err
+		}
+		defer content.Close()
+
+		// Тип вложения определяется по содержимому, заявленный клиентом только сверяется с ним
+		if s.fileInspector != nil {
+			inspected, err := s.fileInspector.InspectFile(content.reader(), content.size, *ticket.FileName, *ticket.FileType)
+			if err != nil {
+				logger.Warn("File rejected", "error", err, "filename", *ticket.FileName)
+				return err
+			}
+			ticket.FileType = &inspected.MIME
 		}
-
-		// Создаем bytes.Reader для поддержки Seek
-		reader := bytes.NewReader(buf.Bytes())
 
 		// Сканируем файл
-		isClean, err := s.antivirusService.ScanFile(ctx, reader)
+		isClean, err := s.antivirusService.ScanFile(ctx, content.reader())
 		if err != nil {
 			logger.Error("Failed to scan file", "error", err, "filename", *ticket.FileName)
 			return fmt.Errorf("failed to scan file: %w", err)
@@ -79,14 +129,8 @@ func (s *TicketService) CreateTicket(ctx context.Context, ticket *models.Ticket,
 			return ErrFileContainsMalware
 		}
 
-		// Сбрасываем позицию чтения
-		if _, err := reader.Seek(0, io.SeekStart); err != nil {
-			logger.Error("Failed to reset file position", "error", err, "filename", *ticket.FileName)
-			return fmt.Errorf("failed to reset file position: %w", err)
-		}
-
 		// Загружаем файл в S3
-		fileURL, err := s.fileService.UploadFile(ctx, reader, "tickets", fmt.Sprintf("%d", ticket.UserID))
+		fileURL, err := s.fileService.UploadFile(ctx, content.reader(), "tickets", ticket.UserID.String())
 		if err != nil {
 			logger.Error("Failed to upload file", "error", err, "filename", *ticket.FileName)
 			return fmt.Errorf("failed to upload file: %w", err)
@@ -95,29 +139,54 @@ func (s *TicketService) CreateTicket(ctx context.Context, ticket *models.Ticket,
 		ticket.FileChecked = true
 	}
 
-	ticket.Status = models.TicketStatusNew
+	// Тикет, задержанный антиспамом, сохраняется в статусе spam до решения администратора
+	if ticket.Status != models.TicketStatusSpam {
+		ticket.Status = models.TicketStatusNew
+	}
 	ticket.CreatedAt = time.Now()
 	ticket.UpdatedAt = time.Now()
 
-	// Создаем тикет в базе данных
-	id, err := s.ticketRepo.Create(ctx, ticket)
-	if err != nil {
-		logger.Error("Failed to create ticket in database", "error", err, "userID", ticket.UserID)
-		return fmt.Errorf("failed to create ticket: %w", err)
+	comment := "Тикет создан"
+	if ticket.Status == models.TicketStatusSpam {
+		comment = "Тикет задержан как подозрительный на спам"
 	}
-	ticket.ID = id
 
-	// Создаем запись в истории
-	comment := "Тикет создан"
-	history := &models.TicketHistory{
-		TicketID: ticket.ID,
-		Status:   models.TicketStatusNew,
-		Comment:  &comment,
+	// Тикет и первая запись истории сохраняются в одной транзакции
+	err := s.inTx(ctx, func(repos repositories.TxRepositories) error {
+		id, err := repos.Tickets.Create(ctx, ticket)
+		if err != nil {
+			logger.Error("Failed to create ticket in database", "error", err, "userID", ticket.UserID)
+			return fmt.Errorf("failed to create ticket: %w", err)
+		}
+		ticket.ID = id
+
+		history := &models.TicketHistory{
+			TicketID: ticket.ID,
+			Status:   ticket.Status,
+			Comment:  &comment,
+		}
+		if _, err := repos.History.Create(ctx, history); err != nil {
+			logger.Error("Failed to create ticket history", "error", err, "ticketID", ticket.ID)
+			return fmt.Errorf("failed to create history record: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		ticket.ID = 0
+		s.discardUploadedFile(ctx, ticket)
+		return err
 	}
 
-	if _, err := s.historyRepo.Create(ctx, history); err != nil {
-		logger.Error("Failed to create ticket history", "error", err, "ticketID", ticket.ID)
-		// Не возвращаем ошибку, так как основная операция уже выполнена
+	recordAudit(ctx, s.auditRecorder, ticket.ID, models.AuditTicketCreated, nil, ticket)
+
+	// О задержанных тикетах не уведомляем: они появятся в потоке после освобождения
+	if ticket.Status != models.TicketStatusSpam {
+		publishEvent(ctx, s.eventPublisher, &models.TicketEvent{
+			Type:     models.TicketEventCreated,
+			TicketID: ticket.ID,
+			OwnerID:  ticket.OwnerID(),
+			Status:   ticket.Status,
+		})
 	}
 
 	logger.Info("Ticket created successfully", "ticketID", ticket.ID, "userID", ticket.UserID)
@@ -132,7 +201,7 @@ func (s *TicketService) GetTicket(ctx context.Context, id int64) (*models.Ticket
 	return ticket, nil
 }
 
-func (s *TicketService) GetUserTickets(ctx context.Context, userID int64, req models.GetTicketsRequest) ([]*models.Ticket, int64, error) {
+func (s *TicketService) GetUserTickets(ctx context.Context, userID uuid.UUID, req models.GetTicketsRequest) ([]*models.Ticket, int64, error) {
 	tickets, total, err := s.ticketRepo.GetByUserID(ctx, userID, req)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get user tickets: %w", err)
@@ -148,41 +217,431 @@ func (s *TicketService) GetAllTickets(ctx context.Context, req models.GetTickets
 	return tickets, total, nil
 }
 
-func (s *TicketService) UpdateTicketStatus(ctx context.Context, id int64, status models.TicketStatus, adminID int64, comment *string) error {
+func (s *TicketService) UpdateTicketStatus(ctx context.Context, id int64, status models.TicketStatus, adminID uuid.UUID, comment *string) error {
 	logger.Info("Updating ticket status", "ticketID", id, "status", status, "adminID", adminID)
 
-	if err := s.ticketRepo.UpdateStatus(ctx, id, status, adminID, comment); err != nil {
-		logger.Error("Failed to update ticket status", "error", err, "ticketID", id)
-		return fmt.Errorf("failed to update ticket status: %w", err)
+	var before *models.Ticket
+	err := s.inTx(ctx, func(repos repositories.TxRepositories) error {
+		// Переход проверяется по заблокированной строке: параллельная смена статуса дождется коммита
+		ticket, err := repos.Tickets.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			logger.Error("Failed to get ticket", "error", err, "ticketID", id)
+			return fmt.Errorf("failed to get ticket: %w", err)
+		}
+		if ticket == nil {
+			return ErrTicketNotFound
+		}
+		if !ticket.Status.CanTransitionTo(status) {
+			return fmt.Errorf("%w: %s -> %s", ErrStatusTransition, ticket.Status, status)
+		}
+		before = ticket
+
+		if err := repos.Tickets.UpdateStatus(ctx, id, status, adminID, comment); err != nil {
+			logger.Error("Failed to update ticket status", "error", err, "ticketID", id)
+			return fmt.Errorf("failed to update ticket status: %w", err)
+		}
+
+		history := &models.TicketHistory{
+			TicketID: id,
+			Status:   status,
+			Comment:  comment,
+			AdminID:  &adminID,
+		}
+		if _, err := repos.History.Create(ctx, history); err != nil {
+			logger.Error("Failed to create history record", "error", err, "ticketID", id)
+			return fmt.Errorf("failed to create history record: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Дальнейшие действия - уведомления, их ошибки не отменяют смену статуса
+	ticket, err := s.ticketRepo.GetByID(ctx, id)
+	if err != nil || ticket == nil {
+		logger.Error("Failed to get ticket after status update", "error", err, "ticketID", id)
+		return nil
 	}
 
-	// Создаем запись в истории
-	history := &models.TicketHistory{
+	recordAudit(ctx, s.auditRecorder, id, models.AuditTicketStatusChanged, before, ticket)
+
+	publishEvent(ctx, s.eventPublisher, &models.TicketEvent{
+		Type:     models.TicketEventStatusChanged,
 		TicketID: id,
+		OwnerID:  ticket.OwnerID(),
 		Status:   status,
-		Comment:  comment,
-		AdminID:  &adminID,
-	}
-	if _, err := s.historyRepo.Create(ctx, history); err != nil {
-		logger.Error("Failed to create history record", "error", err, "ticketID", id)
-		return fmt.Errorf("failed to create history record: %w", err)
+		ActorID:  &adminID,
+	})
+
+	if status == models.TicketStatusClosed {
+		s.sendSurvey(ctx, ticket, adminID)
 	}
 
 	logger.Info("Ticket status updated successfully", "ticketID", id, "status", status)
 	return nil
 }
 
+// ReleaseSpam возвращает задержанные антиспамом тикеты в работу; тикеты в другом статусе пропускаются.
+// Возвращает количество освобожденных тикетов
+func (s *TicketService) ReleaseSpam(ctx context.Context, ids []int64, adminID uuid.UUID) (int, error) {
+	comment := "Тикет проверен администратором и не является спамом"
+	released := 0
+
+	for _, id := range ids {
+		ticket, err := s.ticketRepo.GetByID(ctx, id)
+		if err != nil {
+			return released, fmt.Errorf("failed to get ticket: %w", err)
+		}
+		if ticket == nil || ticket.Status != models.TicketStatusSpam {
+			continue
+		}
+
+		if err := s.UpdateTicketStatus(ctx, id, models.TicketStatusNew, adminID, &comment); err != nil {
+			return released, err
+		}
+		released++
+	}
+
+	logger.Info("Spam tickets released", "requested", len(ids), "released", released, "adminID", adminID)
+	return released, nil
+}
+
+// PurgeSpam окончательно удаляет задержанные антиспамом тикеты; тикеты в другом статусе не затрагиваются.
+// Удаление остается в журнале аудита. Возвращает количество удаленных тикетов
+func (s *TicketService) PurgeSpam(ctx context.Context, ids []int64) (int, error) {
+	purged := 0
+
+	for _, id := range ids {
+		ticket, err := s.ticketRepo.GetByID(ctx, id)
+		if err != nil {
+			return purged, fmt.Errorf("failed to get ticket: %w", err)
+		}
+		if ticket == nil || ticket.Status != models.TicketStatusSpam {
+			continue
+		}
+
+		deleted, err := s.ticketRepo.DeleteSpam(ctx, id)
+		if err != nil {
+			return purged, fmt.Errorf("failed to purge ticket: %w", err)
+		}
+		if !deleted {
+			continue
+		}
+
+		recordAudit(ctx, s.auditRecorder, id, models.AuditTicketPurged, ticket, nil)
+		purged++
+	}
+
+	logger.Info("Spam tickets purged", "requested", len(ids), "purged", purged)
+	return purged, nil
+}
+
+// sendSurvey отправляет опрос удовлетворенности; ошибки не влияют на закрытие тикета
+func (s *TicketService) sendSurvey(ctx context.Context, ticket *models.Ticket, adminID uuid.UUID) {
+	if s.surveySender == nil {
+		return
+	}
+
+	if err := s.surveySender.SendSurvey(ctx, ticket, adminID); err != nil {
+		logger.Error("Failed to send satisfaction survey", "error", err, "ticketID", ticket.ID)
+	}
+}
+
+// discardUploadedFile удаляет вложение тикета, если запись о тикете не удалось сохранить
+func (s *TicketService) discardUploadedFile(ctx context.Context, ticket *models.Ticket) {
+	if ticket.FileURL == nil || s.fileService == nil {
+		return
+	}
+	if err := s.fileService.DeleteFile(ctx, *ticket.FileURL); err != nil {
+		logger.Error("Failed to delete orphaned ticket file", "error", err, "fileURL", *ticket.FileURL)
+	}
+}
+
+// AssignTicket назначает тикет администратору
+func (s *TicketService) AssignTicket(ctx context.Context, id int64, assigneeID uuid.UUID, actorID uuid.UUID) error {
+	logger.Info("Assigning ticket", "ticketID", id, "assigneeID", assigneeID, "actorID", actorID)
+
+	ticket, err := s.ticketRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.Error("Failed to get ticket", "error", err, "ticketID", id)
+		return fmt.Errorf("failed to get ticket: %w", err)
+	}
+	if ticket == nil {
+		return ErrTicketNotFound
+	}
+
+	comment := fmt.Sprintf("Тикет назначен администратору %s", assigneeID)
+	err = s.inTx(ctx, func(repos repositories.TxRepositories) error {
+		if err := repos.Tickets.Assign(ctx, id, assigneeID); err != nil {
+			logger.Error("Failed to assign ticket", "error", err, "ticketID", id)
+			return fmt.Errorf("failed to assign ticket: %w", err)
+		}
+
+		history := &models.TicketHistory{
+			TicketID: id,
+			Status:   ticket.Status,
+			Comment:  &comment,
+			AdminID:  &actorID,
+		}
+		if _, err := repos.History.Create(ctx, history); err != nil {
+			logger.Error("Failed to create history record", "error", err, "ticketID", id)
+			return fmt.Errorf("failed to create history record: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.auditRecorder, id, models.AuditTicketAssigned,
+		map[string]interface{}{"assigned_to": ticket.AssignedTo},
+		map[string]interface{}{"assigned_to": assigneeID})
+
+	publishEvent(ctx, s.eventPublisher, &models.TicketEvent{
+		Type:     models.TicketEventAssigned,
+		TicketID: id,
+		OwnerID:  ticket.OwnerID(),
+		Status:   ticket.Status,
+		ActorID:  &actorID,
+		Data:     map[string]interface{}{"assigned_to": assigneeID},
+	})
+
+	logger.Info("Ticket assigned successfully", "ticketID", id, "assigneeID", assigneeID)
+	return nil
+}
+
+// ReopenTicket открывает закрытый тикет повторно по просьбе заявителя. В пределах reopenWindow после
+// закрытия тикет возвращается в работу, а сообщение заявителя записывается в историю; позже вместо
+// этого создается новый тикет со ссылкой на закрытый. Возвращает открытый или созданный тикет и
+// признак повторного открытия
+func (s *TicketService) ReopenTicket(ctx context.Context, id int64, userID uuid.UUID, message string) (*models.Ticket, bool, error) {
+	logger.Info("Reopening ticket", "ticketID", id, "userID", userID)
+
+	before, err := s.ticketRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.Error("Failed to get ticket", "error", err, "ticketID", id)
+		return nil, false, fmt.Errorf("failed to get ticket: %w", err)
+	}
+	// Чужие и обезличенные тикеты не отличаются от несуществующих
+	if before == nil || before.UserID == uuid.Nil || before.UserID != userID || before.AnonymizedAt != nil {
+		return nil, false, ErrTicketNotFound
+	}
+	if before.Status != models.TicketStatusClosed {
+		return nil, false, ErrTicketNotClosed
+	}
+
+	if before.ClosedAt == nil || time.Since(*before.ClosedAt) > s.reopenWindow {
+		followUp, err := s.createFollowUp(ctx, before, message)
+		if err != nil {
+			return nil, false, err
+		}
+		metrics.TicketReopensTotal.WithLabelValues("follow_up").Inc()
+		return followUp, false, nil
+	}
+
+	// Назначенный тикет сразу возвращается к своему администратору, остальные - в общую очередь
+	status := models.TicketStatusNew
+	if before.AssignedTo != nil {
+		status = models.TicketStatusInProgress
+	}
+	if !before.Status.CanTransitionTo(status) {
+		return nil, false, fmt.Errorf("%w: %s -> %s", ErrStatusTransition, before.Status, status)
+	}
+
+	comment := "Заявитель открыл тикет повторно: " + message
+	err = s.inTx(ctx, func(repos repositories.TxRepositories) error {
+		reopened, err := repos.Tickets.Reopen(ctx, id, status)
+		if err != nil {
+			return err
+		}
+		// Статус изменился после чтения тикета
+		if !reopened {
+			return ErrTicketNotClosed
+		}
+
+		history := &models.TicketHistory{
+			TicketID: id,
+			Status:   status,
+			Comment:  &comment,
+		}
+		if _, err := repos.History.Create(ctx, history); err != nil {
+			logger.Error("Failed to create history record", "error", err, "ticketID", id)
+			return fmt.Errorf("failed to create history record: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	metrics.TicketReopensTotal.WithLabelValues("reopened").Inc()
+
+	ticket, err := s.ticketRepo.GetByID(ctx, id)
+	if err != nil || ticket == nil {
+		return nil, false, fmt.Errorf("failed to get ticket after reopen: %w", err)
+	}
+
+	recordAudit(ctx, s.auditRecorder, id, models.AuditTicketReopened, before, ticket)
+
+	publishEvent(ctx, s.eventPublisher, &models.TicketEvent{
+		Type:     models.TicketEventStatusChanged,
+		TicketID: id,
+		OwnerID:  ticket.OwnerID(),
+		Status:   status,
+		ActorID:  &userID,
+		Data:     map[string]interface{}{"reopen_count": ticket.ReopenCount},
+	})
+
+	logger.Info("Ticket reopened", "ticketID", id, "status", status, "reopenCount", ticket.ReopenCount)
+	return ticket, true, nil
+}
+
+// createFollowUp создает новый тикет с контактами и темой закрытого, вопросом служит сообщение заявителя
+func (s *TicketService) createFollowUp(ctx context.Context, previous *models.Ticket, message string) (*models.Ticket, error) {
+	followUp := &models.Ticket{
+		UserID:           previous.UserID,
+		Category:         previous.Category,
+		Subject:          previous.Subject,
+		Question:         message,
+		FullName:         previous.FullName,
+		Email:            previous.Email,
+		Phone:            previous.Phone,
+		TelegramID:       previous.TelegramID,
+		NotifyEmail:      previous.NotifyEmail,
+		NotifyTG:         previous.NotifyTG,
+		Fields:           previous.Fields,
+		PreviousTicketID: &previous.ID,
+	}
+	if err := s.CreateTicket(ctx, followUp, nil); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Follow-up ticket created", "ticketID", followUp.ID, "previousTicketID", previous.ID)
+	return followUp, nil
+}
+
+// GetReopenStats возвращает статистику повторных открытий тикетов, созданных за период
+func (s *TicketService) GetReopenStats(ctx context.Context, from, to *time.Time) (*models.ReopenStats, error) {
+	stats, err := s.ticketRepo.GetReopenStats(ctx, from, to)
+	if err != nil {
+		logger.Error("Failed to get reopen stats", "error", err)
+		return nil, err
+	}
+	return stats, nil
+}
+
+// GetClaimableTickets возвращает гостевые тикеты, оставленные с подтвержденным адресом пользователя
+func (s *TicketService) GetClaimableTickets(ctx context.Context, email string) ([]*models.Ticket, error) {
+	tickets, err := s.ticketRepo.GetGuestByEmail(ctx, email)
+	if err != nil {
+		logger.Error("Failed to get guest tickets", "error", err)
+		return nil, fmt.Errorf("failed to get guest tickets: %w", err)
+	}
+	return tickets, nil
+}
+
+// ClaimTickets привязывает к пользователю гостевые тикеты с его подтвержденным адресом: перечисленные
+// в ids или, если ids пуст, все. Если хотя бы один из ids привязать нельзя, ничего не изменяется;
+// тикеты, привязанные параллельным запросом, пропускаются
+func (s *TicketService) ClaimTickets(ctx context.Context, userID uuid.UUID, email string, ids []int64) ([]*models.Ticket, error) {
+	logger.Info("Claiming guest tickets", "userID", userID, "requested", len(ids))
+
+	selected, err := s.GetClaimableTickets(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > 0 {
+		byID := make(map[int64]*models.Ticket, len(selected))
+		for _, ticket := range selected {
+			byID[ticket.ID] = ticket
+		}
+		selected = selected[:0]
+		for _, id := range ids {
+			ticket, ok := byID[id]
+			if !ok {
+				return nil, fmt.Errorf("%w: %d", ErrTicketNotClaimable, id)
+			}
+			delete(byID, id)
+			selected = append(selected, ticket)
+		}
+	}
+
+	comment := "Тикет привязан к учетной записи заявителя"
+	claimed := make([]*models.Ticket, 0, len(selected))
+	for _, ticket := range selected {
+		done := false
+		err := s.inTx(ctx, func(repos repositories.TxRepositories) error {
+			ok, err := repos.Tickets.Claim(ctx, ticket.ID, userID, email)
+			if err != nil || !ok {
+				return err
+			}
+			done = true
+
+			history := &models.TicketHistory{
+				TicketID: ticket.ID,
+				Status:   ticket.Status,
+				Comment:  &comment,
+			}
+			if _, err := repos.History.Create(ctx, history); err != nil {
+				logger.Error("Failed to create history record", "error", err, "ticketID", ticket.ID)
+				return fmt.Errorf("failed to create history record: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return claimed, err
+		}
+		if !done {
+			continue
+		}
+
+		recordAudit(ctx, s.auditRecorder, ticket.ID, models.AuditTicketClaimed,
+			map[string]interface{}{"user_id": nil},
+			map[string]interface{}{"user_id": userID})
+
+		ticket.UserID = userID
+		claimed = append(claimed, ticket)
+	}
+
+	logger.Info("Guest tickets claimed", "userID", userID, "claimed", len(claimed))
+	return claimed, nil
+}
+
 func (s *TicketService) UpdateTicketFile(ctx context.Context, id int64, fileURL string) error {
+	before, err := s.ticketRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get ticket: %w", err)
+	}
+	if before == nil {
+		return ErrTicketNotFound
+	}
+
 	if err := s.ticketRepo.UpdateFileURL(ctx, id, fileURL); err != nil {
 		return fmt.Errorf("failed to update ticket file: %w", err)
 	}
+
+	recordAudit(ctx, s.auditRecorder, id, models.AuditTicketAttachmentUpdated,
+		map[string]interface{}{"file_url": before.FileURL},
+		map[string]interface{}{"file_url": fileURL})
 	return nil
 }
 
 func (s *TicketService) UpdateFileChecked(ctx context.Context, id int64, checked bool) error {
+	before, err := s.ticketRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get ticket: %w", err)
+	}
+	if before == nil {
+		return ErrTicketNotFound
+	}
+
 	if err := s.ticketRepo.UpdateFileChecked(ctx, id, checked); err != nil {
 		return fmt.Errorf("failed to update file checked status: %w", err)
 	}
+
+	recordAudit(ctx, s.auditRecorder, id, models.AuditTicketAttachmentChecked,
+		map[string]interface{}{"file_checked": before.FileChecked},
+		map[string]interface{}{"file_checked": checked})
 	return nil
 }
 
@@ -219,83 +678,152 @@ func (s *TicketService) CreateResponse(ctx context.Context, response *models.Res
 		logger.Error("Failed to get ticket", "error", err, "ticketID", response.TicketID)
 		return 0, fmt.Errorf("failed to get ticket: %w", err)
 	}
+	if ticket == nil {
+		return 0, ErrTicketNotFound
+	}
 
 	// Проверяем, что тикет не закрыт
 	if ticket.Status == models.TicketStatusClosed {
 		logger.Error("Cannot add response to closed ticket", "ticketID", response.TicketID)
-		return 0, fmt.Errorf("cannot add response to closed ticket")
+		return 0, ErrTicketClosed
 	}
 
 	response.CreatedAt = time.Now()
-	id, err := s.responseRepo.Create(ctx, response)
+	comment := "Добавлен ответ"
+	err = s.inTx(ctx, func(repos repositories.TxRepositories) error {
+		id, err := repos.Responses.Create(ctx, response)
+		if err != nil {
+			logger.Error("Failed to create response", "error", err, "ticketID", response.TicketID)
+			return fmt.Errorf("failed to create response: %w", err)
+		}
+		response.ID = id
+
+		history := &models.TicketHistory{
+			TicketID: response.TicketID,
+			Status:   ticket.Status,
+			Comment:  &comment,
+			AdminID:  &response.AdminID,
+		}
+		if _, err := repos.History.Create(ctx, history); err != nil {
+			logger.Error("Failed to create history record", "error", err, "ticketID", response.TicketID)
+			return fmt.Errorf("failed to create history record: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		logger.Error("Failed to create response", "error", err, "ticketID", response.TicketID)
-		return 0, fmt.Errorf("failed to create response: %w", err)
+		response.ID = 0
+		return 0, err
 	}
+	id := response.ID
 
-	// Создаем запись в истории
-	comment := "Добавлен ответ"
-	history := &models.TicketHistory{
+	recordAudit(ctx, s.auditRecorder, response.TicketID, models.AuditResponseCreated, nil, response)
+
+	publishEvent(ctx, s.eventPublisher, &models.TicketEvent{
+		Type:     models.TicketEventResponseAdded,
 		TicketID: response.TicketID,
+		OwnerID:  ticket.OwnerID(),
 		Status:   ticket.Status,
-		Comment:  &comment,
-	}
-	if _, err := s.historyRepo.Create(ctx, history); err != nil {
-		logger.Error("Failed to create history record", "error", err, "ticketID", response.TicketID)
-		// Не возвращаем ошибку, так как основная операция уже выполнена
-	}
+		ActorID:  &response.AdminID,
+		Data:     map[string]interface{}{"response_id": id},
+	})
 
 	logger.Info("Response created successfully", "responseID", id, "ticketID", response.TicketID)
 	return id, nil
 }
 
-func (s *TicketService) UpdateResponse(ctx context.Context, id int64, message string) error {
-	logger.Info("Updating response", "responseID", id)
-
-	// Получаем текущий ответ
-	responses, _, err := s.responseRepo.GetByTicketIDWithPagination(ctx, id, 1, 1)
+// getModifiableResponse загружает ответ и проверяет, что его можно изменить:
+// ответ не удален, тикет не закрыт, а изменяет автор или старший администратор
+func (s *TicketService) getModifiableResponse(ctx context.Context, id int64, actorID uuid.UUID, isSenior bool) (*models.Response, *models.Ticket, error) {
+	response, err := s.responseRepo.GetByID(ctx, id)
 	if err != nil {
 		logger.Error("Failed to get response", "error", err, "responseID", id)
-		return fmt.Errorf("failed to get response: %w", err)
+		return nil, nil, fmt.Errorf("failed to get response: %w", err)
 	}
-	if len(responses) == 0 {
-		logger.Error("Response not found", "responseID", id)
-		return fmt.Errorf("response not found")
+	if response == nil || response.DeletedAt != nil {
+		return nil, nil, ErrResponseNotFound
+	}
+
+	if response.AdminID != actorID && !isSenior {
+		logger.Error("Response modification denied", "responseID", id, "actorID", actorID, "authorID", response.AdminID)
+		return nil, nil, ErrResponseForbidden
 	}
 
-	response := responses[0]
-	
-	// Проверяем, что тикет не закрыт
 	ticket, err := s.ticketRepo.GetByID(ctx, response.TicketID)
 	if err != nil {
 		logger.Error("Failed to get ticket", "error", err, "ticketID", response.TicketID)
-		return fmt.Errorf("failed to get ticket: %w", err)
+		return nil, nil, fmt.Errorf("failed to get ticket: %w", err)
+	}
+	if ticket == nil {
+		return nil, nil, ErrTicketNotFound
 	}
 
 	if ticket.Status == models.TicketStatusClosed {
-		logger.Error("Cannot update response in closed ticket", "ticketID", response.TicketID)
-		return fmt.Errorf("cannot update response in closed ticket")
+		logger.Error("Cannot modify response in closed ticket", "ticketID", response.TicketID)
+		return nil, nil, ErrTicketClosed
 	}
 
-	if err := s.responseRepo.UpdateMessage(ctx, id, message); err != nil {
-		logger.Error("Failed to update response", "error", err, "responseID", id)
-		return fmt.Errorf("failed to update response: %w", err)
+	return response, ticket, nil
+}
+
+// UpdateResponse изменяет текст ответа, прежний текст сохраняется в ревизиях
+func (s *TicketService) UpdateResponse(ctx context.Context, id int64, message string, editorID uuid.UUID, isSenior bool) (*models.Response, error) {
+	logger.Info("Updating response", "responseID", id, "editorID", editorID)
+
+	response, ticket, err := s.getModifiableResponse(ctx, id, editorID, isSenior)
+	if err != nil {
+		return nil, err
 	}
 
-	// Создаем запись в истории
 	comment := "Ответ обновлен"
-	history := &models.TicketHistory{
-		TicketID: response.TicketID,
-		Status:   ticket.Status,
-		Comment:  &comment,
-	}
-	if _, err := s.historyRepo.Create(ctx, history); err != nil {
-		logger.Error("Failed to create history record", "error", err, "ticketID", response.TicketID)
-		// Не возвращаем ошибку, так как основная операция уже выполнена
+	err = s.inTx(ctx, func(repos repositories.TxRepositories) error {
+		if err := repos.Responses.UpdateMessage(ctx, id, message, editorID); err != nil {
+			logger.Error("Failed to update response", "error", err, "responseID", id)
+			return fmt.Errorf("failed to update response: %w", err)
+		}
+
+		history := &models.TicketHistory{
+			TicketID: response.TicketID,
+			Status:   ticket.Status,
+			Comment:  &comment,
+			AdminID:  &editorID,
+		}
+		if _, err := repos.History.Create(ctx, history); err != nil {
+			logger.Error("Failed to create history record", "error", err, "ticketID", response.TicketID)
+			return fmt.Errorf("failed to create history record: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	recordAudit(ctx, s.auditRecorder, response.TicketID, models.AuditResponseUpdated,
+		map[string]interface{}{"response_id": id, "message": response.Message},
+		map[string]interface{}{"response_id": id, "message": message})
+
+	updatedAt := time.Now()
+	response.Message = message
+	response.UpdatedAt = &updatedAt
+
 	logger.Info("Response updated successfully", "responseID", id)
-	return nil
+	return response, nil
+}
+
+// GetResponseRevisions возвращает прежние версии текста ответа от старых к новым
+func (s *TicketService) GetResponseRevisions(ctx context.Context, id int64) ([]*models.ResponseRevision, error) {
+	response, err := s.responseRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get response: %w", err)
+	}
+	if response == nil {
+		return nil, ErrResponseNotFound
+	}
+
+	revisions, err := s.responseRepo.GetRevisions(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get response revisions: %w", err)
+	}
+	return revisions, nil
 }
 
 func (s *TicketService) UpdateResponseFile(ctx context.Context, id int64, fileURL string) error {
@@ -305,51 +833,44 @@ func (s *TicketService) UpdateResponseFile(ctx context.Context, id int64, fileUR
 	return nil
 }
 
-func (s *TicketService) DeleteResponse(ctx context.Context, id int64) error {
-	logger.Info("Deleting response", "responseID", id)
-
-	// Получаем текущий ответ
-	responses, _, err := s.responseRepo.GetByTicketIDWithPagination(ctx, id, 1, 1)
-	if err != nil {
-		logger.Error("Failed to get response", "error", err, "responseID", id)
-		return fmt.Errorf("failed to get response: %w", err)
-	}
-	if len(responses) == 0 {
-		logger.Error("Response not found", "responseID", id)
-		return fmt.Errorf("response not found")
-	}
+// DeleteResponse мягко удаляет ответ: он скрывается из выдачи, но остается в базе и журнале аудита
+func (s *TicketService) DeleteResponse(ctx context.Context, id int64, actorID uuid.UUID, isSenior bool) error {
+	logger.Info("Deleting response", "responseID", id, "actorID", actorID)
 
-	response := responses[0]
-	
-	// Проверяем, что тикет не закрыт
-	ticket, err := s.ticketRepo.GetByID(ctx, response.TicketID)
+	response, ticket, err := s.getModifiableResponse(ctx, id, actorID, isSenior)
 	if err != nil {
-		logger.Error("Failed to get ticket", "error", err, "ticketID", response.TicketID)
-		return fmt.Errorf("failed to get ticket: %w", err)
+		return err
 	}
 
-	if ticket.Status == models.TicketStatusClosed {
-		logger.Error("Cannot delete response in closed ticket", "ticketID", response.TicketID)
-		return fmt.Errorf("cannot delete response in closed ticket")
-	}
+	comment := "Ответ удален"
+	err = s.inTx(ctx, func(repos repositories.TxRepositories) error {
+		deleted, err := repos.Responses.Delete(ctx, id, actorID)
+		if err != nil {
+			logger.Error("Failed to delete response", "error", err, "responseID", id)
+			return fmt.Errorf("failed to delete response: %w", err)
+		}
+		if !deleted {
+			return ErrResponseNotFound
+		}
 
-	if err := s.responseRepo.Delete(ctx, id); err != nil {
-		logger.Error("Failed to delete response", "error", err, "responseID", id)
-		return fmt.Errorf("failed to delete response: %w", err)
+		history := &models.TicketHistory{
+			TicketID: response.TicketID,
+			Status:   ticket.Status,
+			Comment:  &comment,
+			AdminID:  &actorID,
+		}
+		if _, err := repos.History.Create(ctx, history); err != nil {
+			logger.Error("Failed to create history record", "error", err, "ticketID", response.TicketID)
+			return fmt.Errorf("failed to create history record: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	// Создаем запись в истории
-	comment := "Ответ удален"
-	history := &models.TicketHistory{
-		TicketID: response.TicketID,
-		Status:   ticket.Status,
-		Comment:  &comment,
-	}
-	if _, err := s.historyRepo.Create(ctx, history); err != nil {
-		logger.Error("Failed to create history record", "error", err, "ticketID", response.TicketID)
-		// Не возвращаем ошибку, так как основная операция уже выполнена
-	}
+	recordAudit(ctx, s.auditRecorder, response.TicketID, models.AuditResponseDeleted, response, nil)
 
 	logger.Info("Response deleted successfully", "responseID", id)
 	return nil
-} 
\ No newline at end of file
+}
@@ -8,10 +8,19 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
 	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+)
+
+// Идентификаторы пользователей в тестах
+var (
+	testUserID  = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	testOwnerID = uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	testAdminID = uuid.MustParse("00000000-0000-0000-0000-000000000005")
 )
 
 // Mock репозитории
@@ -32,7 +41,15 @@ func (m *MockTicketRepository) GetByID(ctx context.Context, id int64) (*models.T
 	return args.Get(0).(*models.Ticket), args.Error(1)
 }
 
-func (m *MockTicketRepository) GetByUserID(ctx context.Context, userID int64, req models.GetTicketsRequest) ([]*models.Ticket, int64, error) {
+func (m *MockTicketRepository) GetByIDForUpdate(ctx context.Context, id int64) (*models.Ticket, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Ticket), args.Error(1)
+}
+
+func (m *MockTicketRepository) GetByUserID(ctx context.Context, userID uuid.UUID, req models.GetTicketsRequest) ([]*models.Ticket, int64, error) {
 	args := m.Called(ctx, userID, req)
 	return args.Get(0).([]*models.Ticket), args.Get(1).(int64), args.Error(2)
 }
@@ -42,7 +59,7 @@ func (m *MockTicketRepository) GetAll(ctx context.Context, req models.GetTickets
 	return args.Get(0).([]*models.Ticket), args.Get(1).(int64), args.Error(2)
 }
 
-func (m *MockTicketRepository) UpdateStatus(ctx context.Context, id int64, status models.TicketStatus, adminID int64, comment *string) error {
+func (m *MockTicketRepository) UpdateStatus(ctx context.Context, id int64, status models.TicketStatus, adminID uuid.UUID, comment *string) error {
 	args := m.Called(ctx, id, status, adminID, comment)
 	return args.Error(0)
 }
@@ -62,6 +79,99 @@ func (m *MockTicketRepository) Search(ctx context.Context, query string, req mod
 	return args.Get(0).([]*models.Ticket), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockTicketRepository) Assign(ctx context.Context, id int64, adminID uuid.UUID) error {
+	args := m.Called(ctx, id, adminID)
+	return args.Error(0)
+}
+
+func (m *MockTicketRepository) GetWaiting(ctx context.Context) ([]*models.Ticket, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*models.Ticket), args.Error(1)
+}
+
+func (m *MockTicketRepository) MarkReminderSent(ctx context.Context, id int64, sentAt time.Time) error {
+	args := m.Called(ctx, id, sentAt)
+	return args.Error(0)
+}
+
+func (m *MockTicketRepository) CloseWaiting(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTicketRepository) CountByEmailSince(ctx context.Context, email string, since time.Time) (int64, error) {
+	args := m.Called(ctx, email, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTicketRepository) CountByPhoneSince(ctx context.Context, phone string, since time.Time) (int64, error) {
+	args := m.Called(ctx, phone, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTicketRepository) DeleteSpam(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTicketRepository) GetForAnonymization(ctx context.Context, category models.TicketCategory, closedBefore time.Time, limit int) ([]*models.Ticket, error) {
+	args := m.Called(ctx, category, closedBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Ticket), args.Error(1)
+}
+
+func (m *MockTicketRepository) GetByEmail(ctx context.Context, email string) ([]*models.Ticket, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Ticket), args.Error(1)
+}
+
+func (m *MockTicketRepository) GetGuestByEmail(ctx context.Context, email string) ([]*models.Ticket, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Ticket), args.Error(1)
+}
+
+func (m *MockTicketRepository) Claim(ctx context.Context, id int64, userID uuid.UUID, email string) (bool, error) {
+	args := m.Called(ctx, id, userID, email)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTicketRepository) Reopen(ctx context.Context, id int64, status models.TicketStatus) (bool, error) {
+	args := m.Called(ctx, id, status)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTicketRepository) GetReopenStats(ctx context.Context, from, to *time.Time) (*models.ReopenStats, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReopenStats), args.Error(1)
+}
+
+func (m *MockTicketRepository) GetAfterID(ctx context.Context, afterID int64, limit int) ([]*models.Ticket, error) {
+	args := m.Called(ctx, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Ticket), args.Error(1)
+}
+
+func (m *MockTicketRepository) GetWithAttachmentsAfterID(ctx context.Context, afterID int64, limit int) ([]*models.Ticket, error) {
+	args := m.Called(ctx, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Ticket), args.Error(1)
+}
+
 type MockTicketHistoryRepository struct {
 	mock.Mock
 }
@@ -103,9 +213,22 @@ func (m *MockResponseRepository) GetByTicketIDWithPagination(ctx context.Context
 	return args.Get(0).([]*models.Response), args.Get(1).(int), args.Error(2)
 }
 
-func (m *MockResponseRepository) Delete(ctx context.Context, id int64) error {
+func (m *MockResponseRepository) GetByID(ctx context.Context, id int64) (*models.Response, error) {
 	args := m.Called(ctx, id)
-	return args.Error(0)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Response), args.Error(1)
+}
+
+func (m *MockResponseRepository) Delete(ctx context.Context, id int64, deletedBy uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id, deletedBy)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockResponseRepository) GetRevisions(ctx context.Context, responseID int64) ([]*models.ResponseRevision, error) {
+	args := m.Called(ctx, responseID)
+	return args.Get(0).([]*models.ResponseRevision), args.Error(1)
 }
 
 func (m *MockResponseRepository) GetByTicketID(ctx context.Context, ticketID int64) ([]*models.Response, error) {
@@ -118,8 +241,8 @@ func (m *MockResponseRepository) UpdateFileURL(ctx context.Context, id int64, fi
 	return args.Error(0)
 }
 
-func (m *MockResponseRepository) UpdateMessage(ctx context.Context, id int64, message string) error {
-	args := m.Called(ctx, id, message)
+func (m *MockResponseRepository) UpdateMessage(ctx context.Context, id int64, message string, editedBy uuid.UUID) error {
+	args := m.Called(ctx, id, message, editedBy)
 	return args.Error(0)
 }
 
@@ -152,6 +275,11 @@ func (m *MockFileService) UploadFile(ctx context.Context, file io.Reader, folder
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockFileService) UploadFileTx(ctx context.Context, objects repositories.FileObjectRepository, file io.Reader, folder string, id string) (string, error) {
+	args := m.Called(ctx, objects, file, folder, id)
+	return args.String(0), args.Error(1)
+}
+
 func (m *MockFileService) DownloadFile(ctx context.Context, filepath string) (io.ReadCloser, error) {
 	args := m.Called(ctx, filepath)
 	if args.Get(0) == nil {
@@ -175,6 +303,36 @@ func (m *MockFileService) CheckFileExists(ctx context.Context, filepath string)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockFileService) ObjectKey(fileURL string) (string, error) {
+	args := m.Called(fileURL)
+	return args.String(0), args.Error(1)
+}
+
+// fakeUnitOfWork передает в операцию моки репозиториев и считает коммиты и откаты
+type fakeUnitOfWork struct {
+	repos     repositories.TxRepositories
+	commits   int
+	rollbacks int
+}
+
+func (u *fakeUnitOfWork) Do(ctx context.Context, fn func(repos repositories.TxRepositories) error) error {
+	if err := fn(u.repos); err != nil {
+		u.rollbacks++
+		return err
+	}
+	u.commits++
+	return nil
+}
+
+type MockEventPublisher struct {
+	mock.Mock
+}
+
+func (m *MockEventPublisher) Publish(ctx context.Context, event *models.TicketEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
 // Тесты
 func TestCreateTicket(t *testing.T) {
 	tests := []struct {
@@ -187,7 +345,7 @@ func TestCreateTicket(t *testing.T) {
 		{
 			name: "Успешное создание тикета без файла",
 			ticket: &models.Ticket{
-				UserID:    1,
+				UserID:    testUserID,
 				Subject:   "Test Subject",
 				Question:  "Test Question",
 				FullName:  "Test User",
@@ -206,7 +364,7 @@ func TestCreateTicket(t *testing.T) {
 		{
 			name: "Успешное создание тикета с файлом",
 			ticket: &models.Ticket{
-				UserID:    1,
+				UserID:    testUserID,
 				Subject:   "Test Subject",
 				Question:  "Test Question",
 				FullName:  "Test User",
@@ -220,7 +378,7 @@ func TestCreateTicket(t *testing.T) {
 			fileReader: bytes.NewReader([]byte("test content")),
 			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository, av *MockAntivirusService, fs *MockFileService) {
 				av.On("ScanFile", mock.Anything, mock.Anything).Return(true, nil)
-				fs.On("UploadFile", mock.Anything, mock.Anything, "tickets", "1").Return("http://example.com/file.txt", nil)
+				fs.On("UploadFile", mock.Anything, mock.Anything, "tickets", testUserID.String()).Return("http://example.com/file.txt", nil)
 				tr.On("Create", mock.Anything, mock.Anything).Return(int64(1), nil)
 				hr.On("Create", mock.Anything, mock.Anything).Return(int64(1), nil)
 			},
@@ -229,7 +387,7 @@ func TestCreateTicket(t *testing.T) {
 		{
 			name: "Ошибка при отсутствии имени файла",
 			ticket: &models.Ticket{
-				UserID:    1,
+				UserID:    testUserID,
 				Subject:   "Test Subject",
 				Question:  "Test Question",
 				FullName:  "Test User",
@@ -246,7 +404,7 @@ func TestCreateTicket(t *testing.T) {
 		{
 			name: "Ошибка при обнаружении вредоносного файла",
 			ticket: &models.Ticket{
-				UserID:    1,
+				UserID:    testUserID,
 				Subject:   "Test Subject",
 				Question:  "Test Question",
 				FullName:  "Test User",
@@ -284,6 +442,12 @@ func TestCreateTicket(t *testing.T) {
 				mockResponseRepo,
 				mockAntivirusService,
 				mockFileService,
+				nil,
+				nil,
+				nil,
+				nil,
+	nil,
+				0,
 			)
 
 			// Выполняем тест
@@ -319,7 +483,7 @@ func TestGetTicket(t *testing.T) {
 			mockSetup: func(tr *MockTicketRepository) {
 				tr.On("GetByID", mock.Anything, int64(1)).Return(&models.Ticket{
 					ID:        1,
-					UserID:    1,
+					UserID:    testUserID,
 					Subject:   "Test Subject",
 					Question:  "Test Question",
 					FullName:  "Test User",
@@ -331,7 +495,7 @@ func TestGetTicket(t *testing.T) {
 			},
 			expectedTicket: &models.Ticket{
 				ID:        1,
-				UserID:    1,
+				UserID:    testUserID,
 				Subject:   "Test Subject",
 				Question:  "Test Question",
 				FullName:  "Test User",
@@ -371,7 +535,13 @@ func TestGetTicket(t *testing.T) {
 				mockHistoryRepo,
 				mockResponseRepo,
 				mockAntivirusService,
-				mockFileService, 
+				mockFileService,
+				nil,
+				nil,
+				nil,
+				nil,
+	nil,
+				0,
 			)
 
 			// Выполняем тест
@@ -401,4 +571,493 @@ func TestGetTicket(t *testing.T) {
 // Вспомогательная функция для создания указателя на строку
 func stringPtr(s string) *string {
 	return &s
-} 
\ No newline at end of file
+}
+
+func TestUpdateResponse(t *testing.T) {
+	const responseID = int64(10)
+	var (
+		authorID = uuid.New()
+		otherID  = uuid.New()
+	)
+
+	tests := []struct {
+		name          string
+		editorID      uuid.UUID
+		isSenior      bool
+		mockSetup     func(*MockTicketRepository, *MockTicketHistoryRepository, *MockResponseRepository)
+		expectedError error
+	}{
+		{
+			name:     "Автор изменяет свой ответ",
+			editorID: authorID,
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository, rr *MockResponseRepository) {
+				rr.On("GetByID", mock.Anything, responseID).Return(&models.Response{ID: responseID, TicketID: 1, AdminID: authorID, Message: "old"}, nil)
+				tr.On("GetByID", mock.Anything, int64(1)).Return(&models.Ticket{ID: 1, Status: models.TicketStatusInProgress}, nil)
+				rr.On("UpdateMessage", mock.Anything, responseID, "new", authorID).Return(nil)
+				hr.On("Create", mock.Anything, mock.MatchedBy(func(h *models.TicketHistory) bool {
+					return h.AdminID != nil && *h.AdminID == authorID
+				})).Return(int64(1), nil)
+			},
+		},
+		{
+			name:     "Старший администратор изменяет чужой ответ",
+			editorID: otherID,
+			isSenior: true,
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository, rr *MockResponseRepository) {
+				rr.On("GetByID", mock.Anything, responseID).Return(&models.Response{ID: responseID, TicketID: 1, AdminID: authorID, Message: "old"}, nil)
+				tr.On("GetByID", mock.Anything, int64(1)).Return(&models.Ticket{ID: 1, Status: models.TicketStatusInProgress}, nil)
+				rr.On("UpdateMessage", mock.Anything, responseID, "new", otherID).Return(nil)
+				hr.On("Create", mock.Anything, mock.Anything).Return(int64(1), nil)
+			},
+		},
+		{
+			name:     "Другой администратор не может изменить ответ",
+			editorID: otherID,
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository, rr *MockResponseRepository) {
+				rr.On("GetByID", mock.Anything, responseID).Return(&models.Response{ID: responseID, TicketID: 1, AdminID: authorID, Message: "old"}, nil)
+			},
+			expectedError: ErrResponseForbidden,
+		},
+		{
+			name:     "Удаленный ответ не найден",
+			editorID: authorID,
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository, rr *MockResponseRepository) {
+				deletedAt := time.Now()
+				rr.On("GetByID", mock.Anything, responseID).Return(&models.Response{ID: responseID, TicketID: 1, AdminID: authorID, DeletedAt: &deletedAt}, nil)
+			},
+			expectedError: ErrResponseNotFound,
+		},
+		{
+			name:     "Ответ в закрытом тикете не изменяется",
+			editorID: authorID,
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository, rr *MockResponseRepository) {
+				rr.On("GetByID", mock.Anything, responseID).Return(&models.Response{ID: responseID, TicketID: 1, AdminID: authorID, Message: "old"}, nil)
+				tr.On("GetByID", mock.Anything, int64(1)).Return(&models.Ticket{ID: 1, Status: models.TicketStatusClosed}, nil)
+			},
+			expectedError: ErrTicketClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTicketRepo := new(MockTicketRepository)
+			mockHistoryRepo := new(MockTicketHistoryRepository)
+			mockResponseRepo := new(MockResponseRepository)
+
+			tt.mockSetup(mockTicketRepo, mockHistoryRepo, mockResponseRepo)
+
+			service := NewTicketService(mockTicketRepo, mockHistoryRepo, mockResponseRepo, nil, nil, nil, nil, nil, nil, nil, 0)
+
+			response, err := service.UpdateResponse(context.Background(), responseID, "new", tt.editorID, tt.isSenior)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "new", response.Message)
+				assert.NotNil(t, response.UpdatedAt)
+			}
+
+			mockTicketRepo.AssertExpectations(t)
+			mockHistoryRepo.AssertExpectations(t)
+			mockResponseRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteResponse(t *testing.T) {
+	mockTicketRepo := new(MockTicketRepository)
+	mockHistoryRepo := new(MockTicketHistoryRepository)
+	mockResponseRepo := new(MockResponseRepository)
+	mockAuditRepo := new(MockAuditRepository)
+
+	mockResponseRepo.On("GetByID", mock.Anything, int64(10)).Return(&models.Response{ID: 10, TicketID: 1, AdminID: testUserID, Message: "text"}, nil)
+	mockTicketRepo.On("GetByID", mock.Anything, int64(1)).Return(&models.Ticket{ID: 1, Status: models.TicketStatusInProgress}, nil)
+	mockResponseRepo.On("Delete", mock.Anything, int64(10), testUserID).Return(true, nil)
+	mockHistoryRepo.On("Create", mock.Anything, mock.Anything).Return(int64(1), nil)
+	mockAuditRepo.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditEntry) bool {
+		return entry.Action == models.AuditResponseDeleted && len(entry.Before) > 0 && entry.After == nil
+	})).Return(int64(1), nil)
+
+	service := NewTicketService(mockTicketRepo, mockHistoryRepo, mockResponseRepo, nil, nil, nil, nil, NewAuditService(mockAuditRepo), nil, nil, 0)
+
+	err := service.DeleteResponse(context.Background(), 10, testUserID, false)
+
+	assert.NoError(t, err)
+	mockResponseRepo.AssertExpectations(t)
+	mockAuditRepo.AssertExpectations(t)
+}
+
+func TestDeleteResponseAlreadyDeleted(t *testing.T) {
+	mockTicketRepo := new(MockTicketRepository)
+	mockHistoryRepo := new(MockTicketHistoryRepository)
+	mockResponseRepo := new(MockResponseRepository)
+	mockAuditRepo := new(MockAuditRepository)
+
+	// Ответ удален параллельным запросом между чтением и UPDATE
+	mockResponseRepo.On("GetByID", mock.Anything, int64(10)).Return(&models.Response{ID: 10, TicketID: 1, AdminID: testUserID, Message: "text"}, nil)
+	mockTicketRepo.On("GetByID", mock.Anything, int64(1)).Return(&models.Ticket{ID: 1, Status: models.TicketStatusInProgress}, nil)
+	mockResponseRepo.On("Delete", mock.Anything, int64(10), testUserID).Return(false, nil)
+
+	service := NewTicketService(mockTicketRepo, mockHistoryRepo, mockResponseRepo, nil, nil, nil, nil, NewAuditService(mockAuditRepo), nil, nil, 0)
+
+	err := service.DeleteResponse(context.Background(), 10, testUserID, false)
+
+	assert.ErrorIs(t, err, ErrResponseNotFound)
+	mockHistoryRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockAuditRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestClaimTickets(t *testing.T) {
+	const email = "user@example.com"
+
+	guestTickets := func() []*models.Ticket {
+		return []*models.Ticket{
+			{ID: 1, Email: email, Status: models.TicketStatusNew},
+			{ID: 2, Email: email, Status: models.TicketStatusClosed},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		ids         []int64
+		mockSetup   func(*MockTicketRepository, *MockTicketHistoryRepository)
+		wantClaimed []int64
+		wantErr     error
+	}{
+		{
+			name: "Привязка всех гостевых тикетов",
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository) {
+				tr.On("GetGuestByEmail", mock.Anything, email).Return(guestTickets(), nil)
+				tr.On("Claim", mock.Anything, int64(1), testUserID, email).Return(true, nil)
+				tr.On("Claim", mock.Anything, int64(2), testUserID, email).Return(true, nil)
+				hr.On("Create", mock.Anything, mock.MatchedBy(func(h *models.TicketHistory) bool {
+					return h.AdminID == nil && h.Comment != nil
+				})).Return(int64(1), nil).Twice()
+			},
+			wantClaimed: []int64{1, 2},
+		},
+		{
+			name: "Привязка выбранного тикета",
+			ids:  []int64{2},
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository) {
+				tr.On("GetGuestByEmail", mock.Anything, email).Return(guestTickets(), nil)
+				tr.On("Claim", mock.Anything, int64(2), testUserID, email).Return(true, nil)
+				hr.On("Create", mock.Anything, mock.Anything).Return(int64(1), nil).Once()
+			},
+			wantClaimed: []int64{2},
+		},
+		{
+			name: "Чужой тикет не привязывается и отменяет всю операцию",
+			ids:  []int64{1, 3},
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository) {
+				tr.On("GetGuestByEmail", mock.Anything, email).Return(guestTickets(), nil)
+			},
+			wantErr: ErrTicketNotClaimable,
+		},
+		{
+			name: "Тикет, привязанный параллельным запросом, пропускается",
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository) {
+				tr.On("GetGuestByEmail", mock.Anything, email).Return(guestTickets(), nil)
+				tr.On("Claim", mock.Anything, int64(1), testUserID, email).Return(false, nil)
+				tr.On("Claim", mock.Anything, int64(2), testUserID, email).Return(true, nil)
+				hr.On("Create", mock.Anything, mock.Anything).Return(int64(1), nil).Once()
+			},
+			wantClaimed: []int64{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTicketRepo := new(MockTicketRepository)
+			mockHistoryRepo := new(MockTicketHistoryRepository)
+			tt.mockSetup(mockTicketRepo, mockHistoryRepo)
+
+			service := NewTicketService(mockTicketRepo, mockHistoryRepo, nil, nil, nil, nil, nil, nil, nil, nil, 0)
+			claimed, err := service.ClaimTickets(context.Background(), testUserID, email, tt.ids)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				mockTicketRepo.AssertNotCalled(t, "Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				var ids []int64
+				for _, ticket := range claimed {
+					assert.Equal(t, testUserID, ticket.UserID)
+					ids = append(ids, ticket.ID)
+				}
+				assert.Equal(t, tt.wantClaimed, ids)
+			}
+			mockTicketRepo.AssertExpectations(t)
+			mockHistoryRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReopenTicket(t *testing.T) {
+	const window = 14 * 24 * time.Hour
+	recentlyClosed := time.Now().Add(-24 * time.Hour)
+	longAgoClosed := time.Now().Add(-30 * 24 * time.Hour)
+
+	tests := []struct {
+		name         string
+		ticket       *models.Ticket
+		mockSetup    func(*MockTicketRepository, *MockTicketHistoryRepository)
+		wantReopened bool
+		wantErr      error
+	}{
+		{
+			name:   "Тикет без исполнителя возвращается в очередь",
+			ticket: &models.Ticket{ID: 1, UserID: testUserID, Status: models.TicketStatusClosed, ClosedAt: &recentlyClosed},
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository) {
+				tr.On("Reopen", mock.Anything, int64(1), models.TicketStatusNew).Return(true, nil)
+				hr.On("Create", mock.Anything, mock.MatchedBy(func(h *models.TicketHistory) bool {
+					return h.Status == models.TicketStatusNew && h.AdminID == nil && h.Comment != nil
+				})).Return(int64(1), nil)
+			},
+			wantReopened: true,
+		},
+		{
+			name:   "Назначенный тикет возвращается исполнителю",
+			ticket: &models.Ticket{ID: 1, UserID: testUserID, Status: models.TicketStatusClosed, ClosedAt: &recentlyClosed, AssignedTo: &testAdminID},
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository) {
+				tr.On("Reopen", mock.Anything, int64(1), models.TicketStatusInProgress).Return(true, nil)
+				hr.On("Create", mock.Anything, mock.Anything).Return(int64(1), nil)
+			},
+			wantReopened: true,
+		},
+		{
+			name:   "После истечения срока создается новый тикет",
+			ticket: &models.Ticket{ID: 1, UserID: testUserID, Subject: "Вопрос", Status: models.TicketStatusClosed, ClosedAt: &longAgoClosed},
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository) {
+				tr.On("Create", mock.Anything, mock.MatchedBy(func(t *models.Ticket) bool {
+					return t.PreviousTicketID != nil && *t.PreviousTicketID == 1 && t.UserID == testUserID &&
+						t.Subject == "Вопрос" && t.Question == "Не помогло"
+				})).Return(int64(2), nil)
+				hr.On("Create", mock.Anything, mock.Anything).Return(int64(1), nil)
+			},
+		},
+		{
+			name:    "Открытый тикет не открывается повторно",
+			ticket:  &models.Ticket{ID: 1, UserID: testUserID, Status: models.TicketStatusWaiting},
+			wantErr: ErrTicketNotClosed,
+		},
+		{
+			name:    "Чужой тикет не найден",
+			ticket:  &models.Ticket{ID: 1, UserID: testOwnerID, Status: models.TicketStatusClosed, ClosedAt: &recentlyClosed},
+			wantErr: ErrTicketNotFound,
+		},
+		{
+			name:   "Тикет, открытый параллельно, не открывается второй раз",
+			ticket: &models.Ticket{ID: 1, UserID: testUserID, Status: models.TicketStatusClosed, ClosedAt: &recentlyClosed},
+			mockSetup: func(tr *MockTicketRepository, hr *MockTicketHistoryRepository) {
+				tr.On("Reopen", mock.Anything, int64(1), models.TicketStatusNew).Return(false, nil)
+			},
+			wantErr: ErrTicketNotClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTicketRepo := new(MockTicketRepository)
+			mockHistoryRepo := new(MockTicketHistoryRepository)
+			mockTicketRepo.On("GetByID", mock.Anything, int64(1)).Return(tt.ticket, nil)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockTicketRepo, mockHistoryRepo)
+			}
+
+			service := NewTicketService(mockTicketRepo, mockHistoryRepo, nil, new(MockAntivirusService), nil, nil, nil, nil, nil, nil, window)
+			ticket, reopened, err := service.ReopenTicket(context.Background(), 1, testUserID, "Не помогло")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, ticket)
+				assert.Equal(t, tt.wantReopened, reopened)
+			}
+			mockTicketRepo.AssertExpectations(t)
+			mockHistoryRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateTicketStatusRejectsInvalidTransition(t *testing.T) {
+	mockTicketRepo := new(MockTicketRepository)
+	mockTicketRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&models.Ticket{ID: 1, Status: models.TicketStatusClosed}, nil)
+
+	uow := &fakeUnitOfWork{repos: repositories.TxRepositories{Tickets: mockTicketRepo, History: new(MockTicketHistoryRepository)}}
+	service := NewTicketService(mockTicketRepo, new(MockTicketHistoryRepository), nil, nil, nil, nil, nil, nil, uow, nil, 0)
+	err := service.UpdateTicketStatus(context.Background(), 1, models.TicketStatusWaiting, testAdminID, nil)
+
+	// Статус проверяется внутри транзакции по заблокированной строке
+	assert.ErrorIs(t, err, ErrStatusTransition)
+	assert.Equal(t, 1, uow.rollbacks)
+	mockTicketRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTicketServiceTransactions(t *testing.T) {
+	dbErr := errors.New("db error")
+	comment := "Взят в работу"
+
+	tests := []struct {
+		name         string
+		mockSetup    func(*MockTicketRepository, *MockTicketHistoryRepository, *MockResponseRepository)
+		run          func(*TicketService) error
+		wantErr      bool
+		wantCommits  int
+		wantRollback int
+	}{
+		{
+			name: "create ticket commits ticket and history",
+			mockSetup: func(ticketRepo *MockTicketRepository, historyRepo *MockTicketHistoryRepository, _ *MockResponseRepository) {
+				ticketRepo.On("Create", mock.Anything, mock.Anything).Return(int64(1), nil)
+				historyRepo.On("Create", mock.Anything, mock.Anything).Return(int64(1), nil)
+			},
+			run: func(s *TicketService) error {
+				return s.CreateTicket(context.Background(), &models.Ticket{UserID: testUserID, Subject: "Test"}, nil)
+			},
+			wantCommits: 1,
+		},
+		{
+			name: "create ticket rolls back when history fails",
+			mockSetup: func(ticketRepo *MockTicketRepository, historyRepo *MockTicketHistoryRepository, _ *MockResponseRepository) {
+				ticketRepo.On("Create", mock.Anything, mock.Anything).Return(int64(1), nil)
+				historyRepo.On("Create", mock.Anything, mock.Anything).Return(int64(0), dbErr)
+			},
+			run: func(s *TicketService) error {
+				return s.CreateTicket(context.Background(), &models.Ticket{UserID: testUserID, Subject: "Test"}, nil)
+			},
+			wantErr:      true,
+			wantRollback: 1,
+		},
+		{
+			name: "status update rolls back when history fails",
+			mockSetup: func(ticketRepo *MockTicketRepository, historyRepo *MockTicketHistoryRepository, _ *MockResponseRepository) {
+				ticketRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&models.Ticket{ID: 1, UserID: testOwnerID, Status: models.TicketStatusNew}, nil)
+				ticketRepo.On("UpdateStatus", mock.Anything, int64(1), models.TicketStatusInProgress, testAdminID, &comment).Return(nil)
+				historyRepo.On("Create", mock.Anything, mock.Anything).Return(int64(0), dbErr)
+			},
+			run: func(s *TicketService) error {
+				return s.UpdateTicketStatus(context.Background(), 1, models.TicketStatusInProgress, testAdminID, &comment)
+			},
+			wantErr:      true,
+			wantRollback: 1,
+		},
+		{
+			name: "response rolls back when history fails",
+			mockSetup: func(ticketRepo *MockTicketRepository, historyRepo *MockTicketHistoryRepository, responseRepo *MockResponseRepository) {
+				ticketRepo.On("GetByID", mock.Anything, int64(1)).Return(&models.Ticket{ID: 1, UserID: testOwnerID, Status: models.TicketStatusInProgress}, nil)
+				responseRepo.On("Create", mock.Anything, mock.Anything).Return(int64(7), nil)
+				historyRepo.On("Create", mock.Anything, mock.Anything).Return(int64(0), dbErr)
+			},
+			run: func(s *TicketService) error {
+				_, err := s.CreateResponse(context.Background(), &models.Response{TicketID: 1, AdminID: testAdminID, Message: "Ответ"})
+				return err
+			},
+			wantErr:      true,
+			wantRollback: 1,
+		},
+		{
+			name: "response update rolls back when history fails",
+			mockSetup: func(ticketRepo *MockTicketRepository, historyRepo *MockTicketHistoryRepository, responseRepo *MockResponseRepository) {
+				responseRepo.On("GetByID", mock.Anything, int64(7)).Return(&models.Response{ID: 7, TicketID: 1, AdminID: testAdminID, Message: "Ответ"}, nil)
+				ticketRepo.On("GetByID", mock.Anything, int64(1)).Return(&models.Ticket{ID: 1, UserID: testOwnerID, Status: models.TicketStatusInProgress}, nil)
+				responseRepo.On("UpdateMessage", mock.Anything, int64(7), "Новый ответ", testAdminID).Return(nil)
+				historyRepo.On("Create", mock.Anything, mock.Anything).Return(int64(0), dbErr)
+			},
+			run: func(s *TicketService) error {
+				_, err := s.UpdateResponse(context.Background(), 7, "Новый ответ", testAdminID, false)
+				return err
+			},
+			wantErr:      true,
+			wantRollback: 1,
+		},
+		{
+			name: "response delete rolls back when history fails",
+			mockSetup: func(ticketRepo *MockTicketRepository, historyRepo *MockTicketHistoryRepository, responseRepo *MockResponseRepository) {
+				responseRepo.On("GetByID", mock.Anything, int64(7)).Return(&models.Response{ID: 7, TicketID: 1, AdminID: testAdminID, Message: "Ответ"}, nil)
+				ticketRepo.On("GetByID", mock.Anything, int64(1)).Return(&models.Ticket{ID: 1, UserID: testOwnerID, Status: models.TicketStatusInProgress}, nil)
+				responseRepo.On("Delete", mock.Anything, int64(7), testAdminID).Return(true, nil)
+				historyRepo.On("Create", mock.Anything, mock.Anything).Return(int64(0), dbErr)
+			},
+			run: func(s *TicketService) error {
+				return s.DeleteResponse(context.Background(), 7, testAdminID, false)
+			},
+			wantErr:      true,
+			wantRollback: 1,
+		},
+		{
+			name: "status update fails before history is written",
+			mockSetup: func(ticketRepo *MockTicketRepository, _ *MockTicketHistoryRepository, _ *MockResponseRepository) {
+				ticketRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&models.Ticket{ID: 1, UserID: testOwnerID, Status: models.TicketStatusNew}, nil)
+				ticketRepo.On("UpdateStatus", mock.Anything, int64(1), models.TicketStatusInProgress, testAdminID, &comment).Return(dbErr)
+			},
+			run: func(s *TicketService) error {
+				return s.UpdateTicketStatus(context.Background(), 1, models.TicketStatusInProgress, testAdminID, &comment)
+			},
+			wantErr:      true,
+			wantRollback: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTicketRepo := new(MockTicketRepository)
+			mockHistoryRepo := new(MockTicketHistoryRepository)
+			mockResponseRepo := new(MockResponseRepository)
+			mockPublisher := new(MockEventPublisher)
+			mockPublisher.On("Publish", mock.Anything, mock.Anything).Return(nil)
+
+			tt.mockSetup(mockTicketRepo, mockHistoryRepo, mockResponseRepo)
+
+			uow := &fakeUnitOfWork{repos: repositories.TxRepositories{
+				Tickets:   mockTicketRepo,
+				History:   mockHistoryRepo,
+				Responses: mockResponseRepo,
+			}}
+			service := NewTicketService(mockTicketRepo, mockHistoryRepo, mockResponseRepo, new(MockAntivirusService), new(MockFileService), nil, mockPublisher, nil, uow, nil, 0)
+
+			err := tt.run(service)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				// После отката подписчики не должны узнать об изменении
+				mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				mockPublisher.AssertNumberOfCalls(t, "Publish", 1)
+			}
+			assert.Equal(t, tt.wantCommits, uow.commits)
+			assert.Equal(t, tt.wantRollback, uow.rollbacks)
+			mockTicketRepo.AssertExpectations(t)
+			mockHistoryRepo.AssertExpectations(t)
+			mockResponseRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateTicketRollbackDeletesUploadedFile(t *testing.T) {
+	mockTicketRepo := new(MockTicketRepository)
+	mockHistoryRepo := new(MockTicketHistoryRepository)
+	mockAntivirusService := new(MockAntivirusService)
+	mockFileService := new(MockFileService)
+
+	mockAntivirusService.On("ScanFile", mock.Anything, mock.Anything).Return(true, nil)
+	mockFileService.On("UploadFile", mock.Anything, mock.Anything, "tickets", testUserID.String()).Return("tickets/1/file.txt", nil)
+	mockFileService.On("DeleteFile", mock.Anything, "tickets/1/file.txt").Return(nil)
+	mockTicketRepo.On("Create", mock.Anything, mock.Anything).Return(int64(0), errors.New("db error"))
+
+	uow := &fakeUnitOfWork{repos: repositories.TxRepositories{Tickets: mockTicketRepo, History: mockHistoryRepo}}
+	service := NewTicketService(mockTicketRepo, mockHistoryRepo, nil, mockAntivirusService, mockFileService, nil, nil, nil, uow, nil, 0)
+
+	fileName, fileType := "file.txt", "text/plain"
+	ticket := &models.Ticket{UserID: testUserID, Subject: "Test", FileName: &fileName, FileType: &fileType}
+	err := service.CreateTicket(context.Background(), ticket, bytes.NewReader([]byte("content")))
+
+	assert.Error(t, err)
+	assert.Equal(t, int64(0), ticket.ID)
+	assert.Equal(t, 1, uow.rollbacks)
+	mockHistoryRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockFileService.AssertExpectations(t)
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+	"ticket-service/internal/logger"
+)
+
+// TranscriptService формирует PDF с перепиской по тикету для передачи в архив. Каждый выданный
+// документ записывается в журнал аудита вместе с sha256 его содержимого, напечатанным в колонтитуле
+type TranscriptService struct {
+	ticketRepo   repositories.TicketRepository
+	historyRepo  repositories.TicketHistoryRepository
+	responseRepo repositories.ResponseRepository
+	// storage хранилище объектов, в котором ключи используются без учета ссылок
+	storage       IFileService
+	renderer      ITranscriptRenderer
+	auditRecorder IAuditRecorder
+}
+
+func NewTranscriptService(
+	ticketRepo repositories.TicketRepository,
+	historyRepo repositories.TicketHistoryRepository,
+	responseRepo repositories.ResponseRepository,
+	storage IFileService,
+	renderer ITranscriptRenderer,
+	auditRecorder IAuditRecorder,
+) *TranscriptService {
+	return &TranscriptService{
+		ticketRepo:    ticketRepo,
+		historyRepo:   historyRepo,
+		responseRepo:  responseRepo,
+		storage:       storage,
+		renderer:      renderer,
+		auditRecorder: auditRecorder,
+	}
+}
+
+// GenerateTranscript собирает метаданные тикета, историю статусов, неудаленные ответы и хеши
+// вложений и формирует из них PDF
+func (s *TranscriptService) GenerateTranscript(ctx context.Context, ticketID int64) (*models.Transcript, []byte, error) {
+	ticket, err := s.ticketRepo.GetByID(ctx, ticketID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ticket == nil {
+		return nil, nil, ErrTicketNotFound
+	}
+
+	history, err := s.historyRepo.GetByTicketID(ctx, ticketID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get ticket history: %w", err)
+	}
+	responses, err := s.responseRepo.GetByTicketID(ctx, ticketID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get ticket responses: %w", err)
+	}
+
+	transcript := &models.Transcript{
+		Ticket:      ticket,
+		History:     history,
+		Responses:   responses,
+		Attachments: []models.TranscriptAttachment{},
+		GeneratedAt: time.Now().UTC().Truncate(time.Second),
+		GeneratedBy: models.ActorFromContext(ctx).ID,
+	}
+
+	if ticket.FileURL != nil {
+		name := path.Base(*ticket.FileURL)
+		if ticket.FileName != nil {
+			name = *ticket.FileName
+		}
+		attachment, err := s.attachment(ctx, *ticket.FileURL, name, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		transcript.Attachments = append(transcript.Attachments, attachment)
+	}
+	for _, response := range responses {
+		if response.FileURL == nil {
+			continue
+		}
+		attachment, err := s.attachment(ctx, *response.FileURL, "", &response.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		transcript.Attachments = append(transcript.Attachments, attachment)
+	}
+
+	content, err := json.Marshal(transcript)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode transcript: %w", err)
+	}
+	digest := sha256.Sum256(content)
+	transcript.Digest = hex.EncodeToString(digest[:])
+
+	pdf, err := s.renderer.Render(transcript)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to render transcript: %w", err)
+	}
+
+	recordAudit(ctx, s.auditRecorder, ticketID, models.AuditTicketTranscriptGenerated, nil,
+		map[string]interface{}{"digest": transcript.Digest, "generated_at": transcript.GeneratedAt})
+
+	logger.Info("Ticket transcript generated", "ticketID", ticketID, "digest", transcript.Digest, "size", len(pdf))
+	return transcript, pdf, nil
+}
+
+// attachment определяет sha256 вложения. У объектов, адресуемых по содержимому, хеш - часть ключа;
+// файлы, загруженные до адресации по содержимому, хешируются при чтении
+func (s *TranscriptService) attachment(ctx context.Context, fileURL, name string, responseID *int64) (models.TranscriptAttachment, error) {
+	attachment := models.TranscriptAttachment{ResponseID: responseID, Name: name}
+
+	key, err := s.storage.ObjectKey(fileURL)
+	if err != nil {
+		return attachment, err
+	}
+	if strings.HasPrefix(key, contentFolder+"/") {
+		attachment.SHA256 = path.Base(key)
+		return attachment, nil
+	}
+
+	file, err := s.storage.DownloadFile(ctx, key)
+	if err != nil {
+		if errors.Is(err, ErrFileNotFound) {
+			return attachment, nil
+		}
+		return attachment, fmt.Errorf("failed to read attachment %s: %w", key, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return attachment, fmt.Errorf("failed to read attachment %s: %w", key, err)
+	}
+	attachment.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	return attachment, nil
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"ticket-service/internal/domain/models"
+)
+
+type MockTranscriptRenderer struct {
+	mock.Mock
+}
+
+func (m *MockTranscriptRenderer) Render(transcript *models.Transcript) ([]byte, error) {
+	args := m.Called(transcript)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func TestTranscriptServiceGenerateTranscript(t *testing.T) {
+	contentSum := strings.Repeat("ab", 32)
+	ticketURL := "url/" + ContentKey(contentSum)
+	legacyURL := "url/responses/1/legacy.pdf"
+	missingURL := "url/responses/2/missing.pdf"
+	fileName := "диплом.pdf"
+	legacySum := sha256.Sum256([]byte("legacy"))
+
+	ticketRepo := new(MockTicketRepository)
+	historyRepo := new(MockTicketHistoryRepository)
+	responseRepo := new(MockResponseRepository)
+	storage := new(MockFileService)
+	renderer := new(MockTranscriptRenderer)
+	auditRepo := new(MockAuditRepository)
+
+	ticketRepo.On("GetByID", mock.Anything, int64(1)).Return(&models.Ticket{ID: 1, FileURL: &ticketURL, FileName: &fileName}, nil)
+	historyRepo.On("GetByTicketID", mock.Anything, int64(1)).Return([]*models.TicketHistory{{ID: 1, TicketID: 1, Status: models.TicketStatusNew}}, nil)
+	responseRepo.On("GetByTicketID", mock.Anything, int64(1)).Return([]*models.Response{
+		{ID: 10, TicketID: 1, Message: "Ответ", FileURL: &legacyURL},
+		{ID: 11, TicketID: 1, Message: "Еще ответ", FileURL: &missingURL},
+		{ID: 12, TicketID: 1, Message: "Без вложения"},
+	}, nil)
+	storage.On("ObjectKey", ticketURL).Return(ContentKey(contentSum), nil)
+	storage.On("ObjectKey", legacyURL).Return("responses/1/legacy.pdf", nil)
+	storage.On("ObjectKey", missingURL).Return("responses/2/missing.pdf", nil)
+	storage.On("DownloadFile", mock.Anything, "responses/1/legacy.pdf").Return(io.NopCloser(strings.NewReader("legacy")), nil)
+	storage.On("DownloadFile", mock.Anything, "responses/2/missing.pdf").Return(nil, ErrFileNotFound)
+	renderer.On("Render", mock.AnythingOfType("*models.Transcript")).Return([]byte("%PDF"), nil)
+	auditRepo.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditEntry) bool {
+		return entry.TicketID == 1 && entry.Action == models.AuditTicketTranscriptGenerated
+	})).Return(int64(1), nil)
+
+	service := NewTranscriptService(ticketRepo, historyRepo, responseRepo, storage, renderer, NewAuditService(auditRepo))
+	transcript, pdf, err := service.GenerateTranscript(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("%PDF"), pdf)
+	assert.Len(t, transcript.Digest, 64)
+	if assert.Len(t, transcript.Attachments, 3) {
+		// Хеш объекта, адресуемого по содержимому, берется из ключа без чтения файла
+		assert.Equal(t, models.TranscriptAttachment{Name: fileName, SHA256: contentSum}, transcript.Attachments[0])
+		assert.Equal(t, hex.EncodeToString(legacySum[:]), transcript.Attachments[1].SHA256)
+		assert.Equal(t, int64(10), *transcript.Attachments[1].ResponseID)
+		assert.Empty(t, transcript.Attachments[2].SHA256)
+	}
+	storage.AssertNotCalled(t, "DownloadFile", mock.Anything, ContentKey(contentSum))
+	auditRepo.AssertExpectations(t)
+}
+
+func TestTranscriptServiceTicketNotFound(t *testing.T) {
+	ticketRepo := new(MockTicketRepository)
+	ticketRepo.On("GetByID", mock.Anything, int64(1)).Return(nil, nil)
+
+	service := NewTranscriptService(ticketRepo, nil, nil, nil, new(MockTranscriptRenderer), nil)
+	_, _, err := service.GenerateTranscript(context.Background(), 1)
+
+	assert.ErrorIs(t, err, ErrTicketNotFound)
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+	"ticket-service/internal/infrastructure/metrics"
+	"ticket-service/internal/logger"
+)
+
+var (
+	ErrUploadNotFound       = errors.New("upload not found")
+	ErrUploadInvalidLength  = errors.New("upload length must be positive")
+	ErrUploadFilenameNeeded = errors.New("filename is required in upload metadata")
+	ErrUploadOffsetMismatch = errors.New("upload offset does not match")
+	ErrUploadLocked         = errors.New("upload is being written by another request")
+	ErrUploadIncomplete     = errors.New("upload is not completed")
+)
+
+// UploadService принимает файлы по частям (протокол tus): загрузку можно продолжить с последнего
+// полученного байта после обрыва соединения. Завершенная загрузка прикрепляется к тикету или
+// ответу по ID и проходит те же проверки, что и файл, загруженный одним запросом
+type UploadService struct {
+	uploadRepo repositories.UploadRepository
+	store      IUploadStore
+	// ttl загрузка истекает, если в течение ttl от нее не пришло ни одной части
+	ttl time.Duration
+}
+
+func NewUploadService(uploadRepo repositories.UploadRepository, store IUploadStore, ttl time.Duration) *UploadService {
+	return &UploadService{
+		uploadRepo: uploadRepo,
+		store:      store,
+		ttl:        ttl,
+	}
+}
+
+// CreateUpload начинает загрузку файла размером length
+func (s *UploadService) CreateUpload(ctx context.Context, ownerID uuid.UUID, length int64, metadata map[string]string) (*models.Upload, error) {
+	if length <= 0 {
+		return nil, ErrUploadInvalidLength
+	}
+	if length > MaxFileSize {
+		return nil, ErrFileTooLarge
+	}
+	if metadata["filename"] == "" {
+		return nil, ErrUploadFilenameNeeded
+	}
+
+	now := time.Now()
+	upload := &models.Upload{
+		ID:        uuid.NewString(),
+		OwnerID:   ownerID,
+		Length:    length,
+		Metadata:  metadata,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.ttl),
+	}
+	if err := s.store.Begin(ctx, upload); err != nil {
+		return nil, err
+	}
+	if err := s.uploadRepo.Save(ctx, upload); err != nil {
+		if removeErr := s.store.Remove(ctx, upload); removeErr != nil {
+			logger.Error("Failed to remove upload data", "error", removeErr, "uploadID", upload.ID)
+		}
+		return nil, err
+	}
+
+	metrics.ResumableUploadsTotal.WithLabelValues("created").Inc()
+	logger.Info("Upload created", "uploadID", upload.ID, "ownerID", ownerID, "length", length)
+	return upload, nil
+}
+
+// GetUpload возвращает загрузку владельца; чужие загрузки не видны
+func (s *UploadService) GetUpload(ctx context.Context, id string, ownerID uuid.UUID) (*models.Upload, error) {
+	upload, err := s.uploadRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if upload == nil || upload.OwnerID != ownerID {
+		return nil, ErrUploadNotFound
+	}
+	return upload, nil
+}
+
+// AppendChunk дописывает часть, начинающуюся со смещения offset. Возвращает загрузку с новым
+// смещением и после ошибки чтения data, если часть данных удалось сохранить
+func (s *UploadService) AppendChunk(ctx context.Context, id string, ownerID uuid.UUID, offset int64, data io.Reader) (*models.Upload, error) {
+	unlock, ok, err := s.uploadRepo.Lock(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrUploadLocked
+	}
+	defer unlock()
+
+	upload, err := s.GetUpload(ctx, id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if offset != upload.Offset {
+		return upload, ErrUploadOffsetMismatch
+	}
+	if upload.Completed() {
+		return upload, nil
+	}
+
+	// Данные сверх заявленного размера не принимаются
+	previous := upload.Offset
+	appendErr := s.store.Append(ctx, upload, io.LimitReader(data, upload.Length-upload.Offset))
+	if upload.Offset == previous && appendErr != nil {
+		return nil, appendErr
+	}
+
+	if upload.Completed() {
+		if err := s.store.Finish(ctx, upload); err != nil {
+			return nil, err
+		}
+		metrics.ResumableUploadsTotal.WithLabelValues("completed").Inc()
+		logger.Info("Upload completed", "uploadID", upload.ID, "length", upload.Length)
+	}
+
+	upload.ExpiresAt = time.Now().Add(s.ttl)
+	if err := s.uploadRepo.Save(ctx, upload); err != nil {
+		return nil, err
+	}
+	return upload, appendErr
+}
+
+// TerminateUpload прерывает загрузку и удаляет полученные данные
+func (s *UploadService) TerminateUpload(ctx context.Context, id string, ownerID uuid.UUID) error {
+	unlock, ok, err := s.uploadRepo.Lock(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrUploadLocked
+	}
+	defer unlock()
+
+	upload, err := s.GetUpload(ctx, id, ownerID)
+	if err != nil {
+		return err
+	}
+	if err := s.discard(ctx, upload); err != nil {
+		return err
+	}
+	metrics.ResumableUploadsTotal.WithLabelValues("terminated").Inc()
+	return nil
+}
+
+// OpenCompleted открывает завершенную загрузку для прикрепления к тикету или ответу
+func (s *UploadService) OpenCompleted(ctx context.Context, id string, ownerID uuid.UUID) (*models.Upload, io.ReadCloser, error) {
+	upload, err := s.GetUpload(ctx, id, ownerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !upload.Completed() {
+		return nil, nil, ErrUploadIncomplete
+	}
+
+	file, err := s.store.Open(ctx, upload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open upload: %w", err)
+	}
+	return upload, file, nil
+}
+
+// ConsumeUpload удаляет загрузку после того, как файл прикреплен и сохранен в хранилище вложений
+func (s *UploadService) ConsumeUpload(ctx context.Context, upload *models.Upload) {
+	if err := s.discard(ctx, upload); err != nil {
+		logger.Error("Failed to remove consumed upload", "error", err, "uploadID", upload.ID)
+	}
+}
+
+// ExpireUploads удаляет данные загрузок, состояние которых истекло в Redis
+func (s *UploadService) ExpireUploads(ctx context.Context) error {
+	stored, err := s.store.List(ctx, time.Now().Add(-s.ttl))
+	if err != nil {
+		return err
+	}
+
+	expired := 0
+	for _, upload := range stored {
+		current, err := s.uploadRepo.Get(ctx, upload.ID)
+		if err != nil {
+			return err
+		}
+		if current != nil {
+			continue
+		}
+		if err := s.store.Remove(ctx, upload); err != nil {
+			logger.Error("Failed to remove expired upload", "error", err, "uploadID", upload.ID)
+			continue
+		}
+		expired++
+		metrics.ResumableUploadsTotal.WithLabelValues("expired").Inc()
+	}
+
+	if expired > 0 {
+		logger.Info("Expired uploads removed", "count", expired)
+	}
+	return nil
+}
+
+func (s *UploadService) discard(ctx context.Context, upload *models.Upload) error {
+	if err := s.store.Remove(ctx, upload); err != nil {
+		return err
+	}
+	return s.uploadRepo.Delete(ctx, upload.ID)
+}
@@ -0,0 +1,323 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"ticket-service/internal/domain/models"
+)
+
+type MockUploadRepository struct {
+	mock.Mock
+}
+
+func (m *MockUploadRepository) Save(ctx context.Context, upload *models.Upload) error {
+	args := m.Called(ctx, upload)
+	return args.Error(0)
+}
+
+func (m *MockUploadRepository) Get(ctx context.Context, id string) (*models.Upload, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Upload), args.Error(1)
+}
+
+func (m *MockUploadRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUploadRepository) Lock(ctx context.Context, id string) (func(), bool, error) {
+	args := m.Called(ctx, id)
+	return func() {}, args.Bool(0), args.Error(1)
+}
+
+type MockUploadStore struct {
+	mock.Mock
+}
+
+func (m *MockUploadStore) Begin(ctx context.Context, upload *models.Upload) error {
+	args := m.Called(ctx, upload)
+	return args.Error(0)
+}
+
+// Append сдвигает смещение на число прочитанных байт, как это делают настоящие хранилища
+func (m *MockUploadStore) Append(ctx context.Context, upload *models.Upload, data io.Reader) error {
+	written, readErr := io.Copy(io.Discard, data)
+	upload.Offset += written
+	args := m.Called(ctx, upload)
+	if readErr != nil {
+		return readErr
+	}
+	return args.Error(0)
+}
+
+func (m *MockUploadStore) Finish(ctx context.Context, upload *models.Upload) error {
+	args := m.Called(ctx, upload)
+	return args.Error(0)
+}
+
+func (m *MockUploadStore) Open(ctx context.Context, upload *models.Upload) (io.ReadCloser, error) {
+	args := m.Called(ctx, upload)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (m *MockUploadStore) Remove(ctx context.Context, upload *models.Upload) error {
+	args := m.Called(ctx, upload)
+	return args.Error(0)
+}
+
+func (m *MockUploadStore) List(ctx context.Context, before time.Time) ([]*models.Upload, error) {
+	args := m.Called(ctx, before)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Upload), args.Error(1)
+}
+
+// failingReader отдает данные, а затем ошибку, как оборванное соединение
+type failingReader struct {
+	data string
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.data == "" {
+		return 0, errors.New("connection reset")
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestUploadServiceCreateUpload(t *testing.T) {
+	tests := []struct {
+		name      string
+		length    int64
+		metadata  map[string]string
+		mockSetup func(*MockUploadRepository, *MockUploadStore)
+		wantErr   error
+	}{
+		{
+			name:     "upload is started in store and saved",
+			length:   1024,
+			metadata: map[string]string{"filename": "report.pdf"},
+			mockSetup: func(repo *MockUploadRepository, store *MockUploadStore) {
+				store.On("Begin", mock.Anything, mock.Anything).Return(nil)
+				repo.On("Save", mock.Anything, mock.MatchedBy(func(u *models.Upload) bool {
+					return u.OwnerID == testUserID && u.Length == 1024 && u.Offset == 0 && u.ID != ""
+				})).Return(nil)
+			},
+		},
+		{
+			name:      "zero length is rejected",
+			length:    0,
+			metadata:  map[string]string{"filename": "report.pdf"},
+			mockSetup: func(repo *MockUploadRepository, store *MockUploadStore) {},
+			wantErr:   ErrUploadInvalidLength,
+		},
+		{
+			name:      "file larger than limit is rejected",
+			length:    MaxFileSize + 1,
+			metadata:  map[string]string{"filename": "report.pdf"},
+			mockSetup: func(repo *MockUploadRepository, store *MockUploadStore) {},
+			wantErr:   ErrFileTooLarge,
+		},
+		{
+			name:      "filename is required",
+			length:    1024,
+			metadata:  map[string]string{"filetype": "application/pdf"},
+			mockSetup: func(repo *MockUploadRepository, store *MockUploadStore) {},
+			wantErr:   ErrUploadFilenameNeeded,
+		},
+		{
+			name:     "store data is removed when state is not saved",
+			length:   1024,
+			metadata: map[string]string{"filename": "report.pdf"},
+			mockSetup: func(repo *MockUploadRepository, store *MockUploadStore) {
+				store.On("Begin", mock.Anything, mock.Anything).Return(nil)
+				repo.On("Save", mock.Anything, mock.Anything).Return(errors.New("redis unavailable"))
+				store.On("Remove", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantErr: errors.New("redis unavailable"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockUploadRepository)
+			store := new(MockUploadStore)
+			tt.mockSetup(repo, store)
+
+			service := NewUploadService(repo, store, time.Hour)
+			upload, err := service.CreateUpload(context.Background(), testUserID, tt.length, tt.metadata)
+
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr.Error())
+				assert.Nil(t, upload)
+			} else {
+				assert.NoError(t, err)
+				assert.WithinDuration(t, time.Now().Add(time.Hour), upload.ExpiresAt, time.Minute)
+			}
+			repo.AssertExpectations(t)
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUploadServiceAppendChunk(t *testing.T) {
+	newUpload := func(offset int64) *models.Upload {
+		return &models.Upload{ID: "upload-1", OwnerID: testUserID, Length: 10, Offset: offset, Metadata: map[string]string{"filename": "a.txt"}}
+	}
+
+	tests := []struct {
+		name       string
+		offset     int64
+		data       io.Reader
+		mockSetup  func(*MockUploadRepository, *MockUploadStore)
+		wantOffset int64
+		wantErr    error
+	}{
+		{
+			name:   "chunk is appended and offset saved",
+			offset: 0,
+			data:   strings.NewReader("hello"),
+			mockSetup: func(repo *MockUploadRepository, store *MockUploadStore) {
+				repo.On("Lock", mock.Anything, "upload-1").Return(true, nil)
+				repo.On("Get", mock.Anything, "upload-1").Return(newUpload(0), nil)
+				store.On("Append", mock.Anything, mock.Anything).Return(nil)
+				repo.On("Save", mock.Anything, mock.MatchedBy(func(u *models.Upload) bool { return u.Offset == 5 })).Return(nil)
+			},
+			wantOffset: 5,
+		},
+		{
+			name:   "last chunk completes upload",
+			offset: 5,
+			data:   strings.NewReader("world and more"),
+			mockSetup: func(repo *MockUploadRepository, store *MockUploadStore) {
+				repo.On("Lock", mock.Anything, "upload-1").Return(true, nil)
+				repo.On("Get", mock.Anything, "upload-1").Return(newUpload(5), nil)
+				store.On("Append", mock.Anything, mock.Anything).Return(nil)
+				store.On("Finish", mock.Anything, mock.MatchedBy(func(u *models.Upload) bool { return u.Completed() })).Return(nil)
+				repo.On("Save", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantOffset: 10,
+		},
+		{
+			name:   "received part is kept when connection breaks",
+			offset: 0,
+			data:   &failingReader{data: "hel"},
+			mockSetup: func(repo *MockUploadRepository, store *MockUploadStore) {
+				repo.On("Lock", mock.Anything, "upload-1").Return(true, nil)
+				repo.On("Get", mock.Anything, "upload-1").Return(newUpload(0), nil)
+				store.On("Append", mock.Anything, mock.Anything).Return(nil)
+				repo.On("Save", mock.Anything, mock.MatchedBy(func(u *models.Upload) bool { return u.Offset == 3 })).Return(nil)
+			},
+			wantOffset: 3,
+			wantErr:    errors.New("connection reset"),
+		},
+		{
+			name:   "offset mismatch is rejected",
+			offset: 3,
+			data:   strings.NewReader("hello"),
+			mockSetup: func(repo *MockUploadRepository, store *MockUploadStore) {
+				repo.On("Lock", mock.Anything, "upload-1").Return(true, nil)
+				repo.On("Get", mock.Anything, "upload-1").Return(newUpload(5), nil)
+			},
+			wantOffset: 5,
+			wantErr:    ErrUploadOffsetMismatch,
+		},
+		{
+			name:   "concurrent write is rejected",
+			offset: 0,
+			data:   strings.NewReader("hello"),
+			mockSetup: func(repo *MockUploadRepository, store *MockUploadStore) {
+				repo.On("Lock", mock.Anything, "upload-1").Return(false, nil)
+			},
+			wantErr: ErrUploadLocked,
+		},
+		{
+			name:   "foreign upload is not found",
+			offset: 0,
+			data:   strings.NewReader("hello"),
+			mockSetup: func(repo *MockUploadRepository, store *MockUploadStore) {
+				repo.On("Lock", mock.Anything, "upload-1").Return(true, nil)
+				foreign := newUpload(0)
+				foreign.OwnerID = testOwnerID
+				repo.On("Get", mock.Anything, "upload-1").Return(foreign, nil)
+			},
+			wantErr: ErrUploadNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockUploadRepository)
+			store := new(MockUploadStore)
+			tt.mockSetup(repo, store)
+
+			service := NewUploadService(repo, store, time.Hour)
+			upload, err := service.AppendChunk(context.Background(), "upload-1", testUserID, tt.offset, tt.data)
+
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.wantOffset > 0 {
+				assert.Equal(t, tt.wantOffset, upload.Offset)
+			}
+			repo.AssertExpectations(t)
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUploadServiceOpenCompleted(t *testing.T) {
+	repo := new(MockUploadRepository)
+	store := new(MockUploadStore)
+	repo.On("Get", mock.Anything, "partial").Return(&models.Upload{ID: "partial", OwnerID: testUserID, Length: 10, Offset: 4}, nil)
+	repo.On("Get", mock.Anything, "missing").Return(nil, nil)
+
+	service := NewUploadService(repo, store, time.Hour)
+
+	_, _, err := service.OpenCompleted(context.Background(), "partial", testUserID)
+	assert.ErrorIs(t, err, ErrUploadIncomplete)
+
+	_, _, err = service.OpenCompleted(context.Background(), "missing", testUserID)
+	assert.ErrorIs(t, err, ErrUploadNotFound)
+
+	store.AssertNotCalled(t, "Open", mock.Anything, mock.Anything)
+}
+
+func TestUploadServiceExpireUploads(t *testing.T) {
+	repo := new(MockUploadRepository)
+	store := new(MockUploadStore)
+
+	active := &models.Upload{ID: "active"}
+	expired := &models.Upload{ID: "expired", StorageID: "multipart-1"}
+	store.On("List", mock.Anything, mock.Anything).Return([]*models.Upload{active, expired}, nil)
+	repo.On("Get", mock.Anything, "active").Return(&models.Upload{ID: "active", OwnerID: testUserID}, nil)
+	repo.On("Get", mock.Anything, "expired").Return(nil, nil)
+	store.On("Remove", mock.Anything, expired).Return(nil)
+
+	service := NewUploadService(repo, store, time.Hour)
+	err := service.ExpireUploads(context.Background())
+
+	assert.NoError(t, err)
+	store.AssertExpectations(t)
+	store.AssertNotCalled(t, "Remove", mock.Anything, active)
+}
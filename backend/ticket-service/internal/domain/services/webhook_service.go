@@ -0,0 +1,427 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+	"ticket-service/internal/infrastructure/metrics"
+	"ticket-service/internal/logger"
+)
+
+const (
+	// webhookBatchSize ограничивает число доставок за один запуск задачи
+	webhookBatchSize = 50
+	// minWebhookSecretLength минимальная длина секрета, заданного вручную
+	minWebhookSecretLength = 16
+)
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook subscription not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrWebhookDisabled         = errors.New("webhook subscription is disabled")
+	ErrInvalidWebhook          = errors.New("invalid webhook subscription")
+)
+
+// WebhookPolicy параметры повторной доставки
+type WebhookPolicy struct {
+	// MaxAttempts после стольких неудачных попыток доставка помечается failed
+	MaxAttempts int
+	// BaseBackoff пауза после первой неудачи; каждая следующая вдвое длиннее, но не больше MaxBackoff
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// WebhookService управляет подписками на вебхуки и доставляет им события тикетов.
+// Publish только сохраняет доставки, отправляет их фоновая задача ProcessDeliveries,
+// поэтому медленный подписчик не задерживает запросы к API
+type WebhookService struct {
+	repo   repositories.WebhookRepository
+	sender IWebhookSender
+	policy WebhookPolicy
+}
+
+func NewWebhookService(repo repositories.WebhookRepository, sender IWebhookSender, policy WebhookPolicy) *WebhookService {
+	return &WebhookService{
+		repo:   repo,
+		sender: sender,
+		policy: policy,
+	}
+}
+
+// Publish ставит событие в очередь доставки всем активным подписчикам.
+// Смена статуса на closed дополнительно доставляется как ticket.closed
+func (s *WebhookService) Publish(ctx context.Context, event *models.TicketEvent) error {
+	eventTypes := []models.TicketEventType{event.Type}
+	if event.Type == models.TicketEventStatusChanged && event.Status == models.TicketStatusClosed {
+		eventTypes = append(eventTypes, models.TicketEventClosed)
+	}
+
+	var errs []error
+	for _, eventType := range eventTypes {
+		subscriptions, err := s.repo.GetActiveSubscriptions(ctx, eventType)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get webhook subscriptions: %w", err))
+			continue
+		}
+		if len(subscriptions) == 0 {
+			continue
+		}
+
+		payload, err := json.Marshal(models.WebhookPayload{
+			Version:    models.WebhookPayloadVersion,
+			ID:         event.ID,
+			Type:       eventType,
+			OccurredAt: event.OccurredAt,
+			Data: models.WebhookPayloadData{
+				TicketID: event.TicketID,
+				Status:   event.Status,
+				OwnerID:  event.OwnerID,
+				ActorID:  event.ActorID,
+				Details:  event.Data,
+			},
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to marshal webhook payload: %w", err))
+			continue
+		}
+
+		for _, subscription := range subscriptions {
+			delivery := &models.WebhookDelivery{
+				SubscriptionID: subscription.ID,
+				EventID:        event.ID,
+				EventType:      eventType,
+				Payload:        payload,
+				Status:         models.WebhookDeliveryPending,
+				NextAttemptAt:  time.Now(),
+			}
+			if _, err := s.repo.CreateDelivery(ctx, delivery); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ProcessDeliveries отправляет доставки, время попытки которых наступило
+func (s *WebhookService) ProcessDeliveries(ctx context.Context) error {
+	deliveries, err := s.repo.GetDueDeliveries(ctx, time.Now(), webhookBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get due webhook deliveries: %w", err)
+	}
+
+	subscriptions := make(map[int64]*models.WebhookSubscription)
+	for _, delivery := range deliveries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = s.repo.GetSubscription(ctx, delivery.SubscriptionID)
+			if err != nil {
+				logger.Error("Failed to get webhook subscription", "error", err, "subscriptionID", delivery.SubscriptionID)
+				continue
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+
+		if err := s.deliver(ctx, delivery, subscription); err != nil {
+			logger.Error("Failed to record webhook delivery attempt", "error", err, "deliveryID", delivery.ID)
+		}
+	}
+
+	return nil
+}
+
+// PurgeDeliveries удаляет журнал завершенных доставок старше olderThan; ожидающие доставки не затрагиваются
+func (s *WebhookService) PurgeDeliveries(ctx context.Context, olderThan time.Duration) (int64, error) {
+	deleted, err := s.repo.DeleteFinishedBefore(ctx, time.Now().Add(-olderThan))
+	if err != nil {
+		return 0, err
+	}
+	logger.Info("Webhook deliveries purged", "count", deleted)
+	return deleted, nil
+}
+
+// deliver выполняет одну попытку доставки и планирует следующую при неудаче
+func (s *WebhookService) deliver(ctx context.Context, delivery *models.WebhookDelivery, subscription *models.WebhookSubscription) error {
+	now := time.Now()
+	attempt := &models.WebhookDeliveryAttempt{DeliveryID: delivery.ID, AttemptedAt: now}
+
+	disabled := subscription == nil || !subscription.Active
+	if disabled {
+		message := ErrWebhookDisabled.Error()
+		attempt.Error = &message
+	} else {
+		statusCode, err := s.sender.Send(ctx, WebhookMessage{
+			URL:        subscription.URL,
+			Secret:     subscription.Secret,
+			DeliveryID: delivery.ID,
+			EventType:  delivery.EventType,
+			Payload:    delivery.Payload,
+		})
+		attempt.DurationMs = time.Since(now).Milliseconds()
+		if err != nil {
+			message := err.Error()
+			attempt.Error = &message
+		} else {
+			attempt.StatusCode = &statusCode
+		}
+	}
+
+	delivery.Attempts++
+	delivery.LastStatusCode = attempt.StatusCode
+	delivery.LastError = attempt.Error
+
+	result := "retry"
+	switch {
+	case attempt.StatusCode != nil && *attempt.StatusCode >= 200 && *attempt.StatusCode < 300:
+		result = "succeeded"
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+	case disabled || delivery.Attempts >= s.policy.MaxAttempts:
+		result = "failed"
+		delivery.Status = models.WebhookDeliveryFailed
+	default:
+		delivery.NextAttemptAt = now.Add(s.backoff(delivery.Attempts))
+	}
+
+	metrics.WebhookDeliveriesTotal.WithLabelValues(string(delivery.EventType), result).Inc()
+	if result == "failed" {
+		logger.Warn("Webhook delivery failed", "deliveryID", delivery.ID, "subscriptionID", delivery.SubscriptionID, "attempts", delivery.Attempts)
+	}
+
+	return s.repo.RecordAttempt(ctx, delivery, attempt)
+}
+
+// backoff пауза перед следующей попыткой после attempts неудачных
+func (s *WebhookService) backoff(attempts int) time.Duration {
+	delay := s.policy.BaseBackoff
+	for i := 1; i < attempts && delay < s.policy.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > s.policy.MaxBackoff {
+		delay = s.policy.MaxBackoff
+	}
+	return delay
+}
+
+// ReplayDelivery повторно отправляет сохраненное событие новой доставкой; исходная доставка и ее журнал не меняются
+func (s *WebhookService) ReplayDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	original, err := s.repo.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if original == nil {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+
+	subscription, err := s.repo.GetSubscription(ctx, original.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, ErrWebhookNotFound
+	}
+	if !subscription.Active {
+		return nil, ErrWebhookDisabled
+	}
+
+	replay := &models.WebhookDelivery{
+		SubscriptionID: original.SubscriptionID,
+		EventID:        original.EventID,
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		Status:         models.WebhookDeliveryPending,
+		NextAttemptAt:  time.Now(),
+		ReplayOf:       &original.ID,
+	}
+	replay.ID, err = s.repo.CreateDelivery(ctx, replay)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Webhook delivery replayed", "deliveryID", id, "replayID", replay.ID)
+	return replay, nil
+}
+
+// CreateSubscription создает подписку; если секрет не задан, он генерируется и возвращается один раз
+func (s *WebhookService) CreateSubscription(ctx context.Context, req *models.CreateWebhookRequest, createdBy uuid.UUID) (*models.CreatedWebhookResponse, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	eventTypes, err := normalizeWebhookEventTypes(req.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		secret, err = generateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+	} else if len(secret) < minWebhookSecretLength {
+		return nil, fmt.Errorf("%w: secret must be at least %d characters", ErrInvalidWebhook, minWebhookSecretLength)
+	}
+
+	subscription := &models.WebhookSubscription{
+		URL:         req.URL,
+		Secret:      secret,
+		EventTypes:  eventTypes,
+		Description: req.Description,
+		Active:      true,
+		CreatedBy:   &createdBy,
+	}
+
+	subscription.ID, err = s.repo.CreateSubscription(ctx, subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Webhook subscription created", "subscriptionID", subscription.ID, "createdBy", createdBy)
+	return &models.CreatedWebhookResponse{WebhookSubscription: *subscription, Secret: secret}, nil
+}
+
+// UpdateSubscription изменяет переданные поля подписки
+func (s *WebhookService) UpdateSubscription(ctx context.Context, id int64, req *models.UpdateWebhookRequest) (*models.WebhookSubscription, error) {
+	subscription, err := s.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		subscription.URL = *req.URL
+	}
+	if req.Secret != nil {
+		if len(*req.Secret) < minWebhookSecretLength {
+			return nil, fmt.Errorf("%w: secret must be at least %d characters", ErrInvalidWebhook, minWebhookSecretLength)
+		}
+		subscription.Secret = *req.Secret
+	}
+	if req.EventTypes != nil {
+		subscription.EventTypes, err = normalizeWebhookEventTypes(req.EventTypes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if req.Description != nil {
+		subscription.Description = req.Description
+	}
+	if req.Active != nil {
+		subscription.Active = *req.Active
+	}
+
+	if err := s.repo.UpdateSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Webhook subscription updated", "subscriptionID", id)
+	return subscription, nil
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id int64) error {
+	deleted, err := s.repo.DeleteSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrWebhookNotFound
+	}
+
+	logger.Info("Webhook subscription deleted", "subscriptionID", id)
+	return nil
+}
+
+func (s *WebhookService) GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	subscription, err := s.repo.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, ErrWebhookNotFound
+	}
+	return subscription, nil
+}
+
+func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	return s.repo.ListSubscriptions(ctx)
+}
+
+// ListDeliveries возвращает журнал доставок подписки
+func (s *WebhookService) ListDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, int64, error) {
+	if _, err := s.GetSubscription(ctx, filter.SubscriptionID); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListDeliveries(ctx, filter)
+}
+
+// GetDelivery возвращает доставку вместе с журналом попыток
+func (s *WebhookService) GetDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	delivery, err := s.repo.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+
+	delivery.AttemptLog, err = s.repo.GetAttempts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// validateWebhookURL принимает только абсолютные http(s) адреса
+func validateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) address", ErrInvalidWebhook)
+	}
+	return nil
+}
+
+// normalizeWebhookEventTypes проверяет типы событий и убирает повторы
+func normalizeWebhookEventTypes(eventTypes []models.TicketEventType) ([]models.TicketEventType, error) {
+	if len(eventTypes) == 0 {
+		return nil, fmt.Errorf("%w: at least one event type is required", ErrInvalidWebhook)
+	}
+
+	seen := make(map[models.TicketEventType]bool, len(eventTypes))
+	result := make([]models.TicketEventType, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !eventType.IsWebhookEventType() {
+			return nil, fmt.Errorf("%w: unknown event type %q", ErrInvalidWebhook, eventType)
+		}
+		if seen[eventType] {
+			continue
+		}
+		seen[eventType] = true
+		result = append(result, eventType)
+	}
+	return result, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"ticket-service/internal/domain/models"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) (int64, error) {
+	args := m.Called(ctx, subscription)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetActiveSubscriptions(ctx context.Context, eventType models.TicketEventType) ([]*models.WebhookSubscription, error) {
+	args := m.Called(ctx, eventType)
+	return args.Get(0).([]*models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	args := m.Called(ctx, subscription)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) DeleteSubscription(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) (int64, error) {
+	args := m.Called(ctx, delivery)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*models.WebhookDelivery), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockWebhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]*models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery, attempt *models.WebhookDeliveryAttempt) error {
+	args := m.Called(ctx, delivery, attempt)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetAttempts(ctx context.Context, deliveryID int64) ([]*models.WebhookDeliveryAttempt, error) {
+	args := m.Called(ctx, deliveryID)
+	return args.Get(0).([]*models.WebhookDeliveryAttempt), args.Error(1)
+}
+
+func (m *MockWebhookRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockWebhookSender struct {
+	mock.Mock
+}
+
+func (m *MockWebhookSender) Send(ctx context.Context, message WebhookMessage) (int, error) {
+	args := m.Called(ctx, message)
+	return args.Int(0), args.Error(1)
+}
+
+var testWebhookPolicy = WebhookPolicy{MaxAttempts: 3, BaseBackoff: time.Minute, MaxBackoff: 3 * time.Minute}
+
+func TestWebhookPublish(t *testing.T) {
+	mockRepo := new(MockWebhookRepository)
+	subscription := &models.WebhookSubscription{ID: 7, Active: true}
+
+	mockRepo.On("GetActiveSubscriptions", mock.Anything, models.TicketEventStatusChanged).Return([]*models.WebhookSubscription{subscription}, nil)
+	mockRepo.On("GetActiveSubscriptions", mock.Anything, models.TicketEventClosed).Return([]*models.WebhookSubscription{subscription}, nil)
+
+	var types []models.TicketEventType
+	mockRepo.On("CreateDelivery", mock.Anything, mock.MatchedBy(func(d *models.WebhookDelivery) bool {
+		var payload models.WebhookPayload
+		if err := json.Unmarshal(d.Payload, &payload); err != nil {
+			return false
+		}
+		types = append(types, payload.Type)
+		return d.SubscriptionID == 7 && d.Status == models.WebhookDeliveryPending &&
+			payload.Version == models.WebhookPayloadVersion && payload.Data.TicketID == 42 && payload.ID == "event-1"
+	})).Return(int64(1), nil)
+
+	service := NewWebhookService(mockRepo, nil, testWebhookPolicy)
+
+	err := service.Publish(context.Background(), &models.TicketEvent{
+		ID:       "event-1",
+		Type:     models.TicketEventStatusChanged,
+		TicketID: 42,
+		Status:   models.TicketStatusClosed,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.TicketEventType{models.TicketEventStatusChanged, models.TicketEventClosed}, types)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProcessWebhookDeliveries(t *testing.T) {
+	tests := []struct {
+		name         string
+		attempts     int
+		active       bool
+		statusCode   int
+		sendErr      error
+		expectSend   bool
+		expectStatus models.WebhookDeliveryStatus
+		expectDelay  time.Duration
+	}{
+		{
+			name:         "Успешная доставка",
+			active:       true,
+			statusCode:   204,
+			expectSend:   true,
+			expectStatus: models.WebhookDeliverySucceeded,
+		},
+		{
+			name:         "Ошибка подписчика - повтор с паузой",
+			attempts:     1,
+			active:       true,
+			statusCode:   500,
+			expectSend:   true,
+			expectStatus: models.WebhookDeliveryPending,
+			expectDelay:  2 * time.Minute,
+		},
+		{
+			name:         "Нет соединения - последняя попытка",
+			attempts:     2,
+			active:       true,
+			sendErr:      errors.New("connection refused"),
+			expectSend:   true,
+			expectStatus: models.WebhookDeliveryFailed,
+		},
+		{
+			name:         "Подписка отключена",
+			active:       false,
+			expectStatus: models.WebhookDeliveryFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockWebhookRepository)
+			mockSender := new(MockWebhookSender)
+
+			delivery := &models.WebhookDelivery{
+				ID:             1,
+				SubscriptionID: 7,
+				EventType:      models.TicketEventCreated,
+				Payload:        []byte(`{"version":"1"}`),
+				Status:         models.WebhookDeliveryPending,
+				Attempts:       tt.attempts,
+			}
+			mockRepo.On("GetDueDeliveries", mock.Anything, mock.Anything, webhookBatchSize).Return([]*models.WebhookDelivery{delivery}, nil)
+			mockRepo.On("GetSubscription", mock.Anything, int64(7)).Return(&models.WebhookSubscription{
+				ID: 7, URL: "https://example.com/hook", Secret: "secret", Active: tt.active,
+			}, nil)
+			if tt.expectSend {
+				mockSender.On("Send", mock.Anything, mock.MatchedBy(func(m WebhookMessage) bool {
+					return m.URL == "https://example.com/hook" && m.Secret == "secret" && m.DeliveryID == 1
+				})).Return(tt.statusCode, tt.sendErr)
+			}
+			mockRepo.On("RecordAttempt", mock.Anything, delivery, mock.Anything).Return(nil)
+
+			service := NewWebhookService(mockRepo, mockSender, testWebhookPolicy)
+			started := time.Now()
+
+			err := service.ProcessDeliveries(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectStatus, delivery.Status)
+			assert.Equal(t, tt.attempts+1, delivery.Attempts)
+			if tt.expectDelay > 0 {
+				assert.WithinDuration(t, started.Add(tt.expectDelay), delivery.NextAttemptAt, time.Second)
+			}
+			mockRepo.AssertExpectations(t)
+			mockSender.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	service := NewWebhookService(nil, nil, testWebhookPolicy)
+
+	assert.Equal(t, time.Minute, service.backoff(1))
+	assert.Equal(t, 2*time.Minute, service.backoff(2))
+	assert.Equal(t, 3*time.Minute, service.backoff(3))
+	assert.Equal(t, 3*time.Minute, service.backoff(50))
+}
+
+func TestReplayDelivery(t *testing.T) {
+	mockRepo := new(MockWebhookRepository)
+
+	original := &models.WebhookDelivery{
+		ID:             5,
+		SubscriptionID: 7,
+		EventID:        "event-1",
+		EventType:      models.TicketEventAssigned,
+		Payload:        []byte(`{"version":"1"}`),
+		Status:         models.WebhookDeliveryFailed,
+	}
+	mockRepo.On("GetDelivery", mock.Anything, int64(5)).Return(original, nil)
+	mockRepo.On("GetSubscription", mock.Anything, int64(7)).Return(&models.WebhookSubscription{ID: 7, Active: true}, nil)
+	mockRepo.On("CreateDelivery", mock.Anything, mock.MatchedBy(func(d *models.WebhookDelivery) bool {
+		return d.ReplayOf != nil && *d.ReplayOf == 5 && d.EventID == "event-1" &&
+			d.Status == models.WebhookDeliveryPending && string(d.Payload) == `{"version":"1"}`
+	})).Return(int64(6), nil)
+
+	service := NewWebhookService(mockRepo, nil, testWebhookPolicy)
+
+	replay, err := service.ReplayDelivery(context.Background(), 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), replay.ID)
+	assert.Equal(t, models.WebhookDeliveryFailed, original.Status)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// acquireScript продлевает блокировку, если она уже наша, иначе пытается её захватить
+var acquireScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 1
+end
+return 0
+`)
+
+// releaseScript снимает блокировку, только если она принадлежит нам
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Lock распределенная блокировка в Redis с истечением по TTL
+type Lock struct {
+	client *redis.Client
+	key    string
+	owner  string
+	ttl    time.Duration
+}
+
+// NewLock создает блокировку с уникальным для процесса владельцем
+func NewLock(client *redis.Client, key string, ttl time.Duration) *Lock {
+	hostname, _ := os.Hostname()
+	return &Lock{
+		client: client,
+		key:    key,
+		owner:  fmt.Sprintf("%s-%s", hostname, uuid.NewString()),
+		ttl:    ttl,
+	}
+}
+
+// Acquire захватывает или продлевает блокировку и сообщает, владеем ли мы ею
+func (l *Lock) Acquire(ctx context.Context) (bool, error) {
+	res, err := acquireScript.Run(ctx, l.client, []string{l.key}, l.owner, l.ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lock %s: %w", l.key, err)
+	}
+	return res == 1, nil
+}
+
+// Release освобождает блокировку, если она принадлежит нам
+func (l *Lock) Release(ctx context.Context) error {
+	if err := releaseScript.Run(ctx, l.client, []string{l.key}, l.owner).Err(); err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", l.key, err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+	"ticket-service/internal/logger"
+)
+
+const (
+	uploadKeyPrefix = "ticket-service:uploads:"
+	// uploadLockTTL с запасом на передачу части по медленному соединению; блокировка снимается по завершении запроса
+	uploadLockTTL = 10 * time.Minute
+)
+
+type uploadRepository struct {
+	client *redis.Client
+}
+
+// NewUploadRepository хранит состояние загрузок в Redis; ключ истекает вместе с загрузкой
+func NewUploadRepository(client *redis.Client) repositories.UploadRepository {
+	return &uploadRepository{client: client}
+}
+
+func (r *uploadRepository) Save(ctx context.Context, upload *models.Upload) error {
+	ttl := time.Until(upload.ExpiresAt)
+	if ttl <= 0 {
+		return r.Delete(ctx, upload.ID)
+	}
+
+	data, err := json.Marshal(upload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal upload: %w", err)
+	}
+	if err := r.client.Set(ctx, uploadKeyPrefix+upload.ID, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save upload: %w", err)
+	}
+	return nil
+}
+
+func (r *uploadRepository) Get(ctx context.Context, id string) (*models.Upload, error) {
+	data, err := r.client.Get(ctx, uploadKeyPrefix+id).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get upload: %w", err)
+	}
+
+	var upload models.Upload
+	if err := json.Unmarshal(data, &upload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal upload: %w", err)
+	}
+	return &upload, nil
+}
+
+func (r *uploadRepository) Delete(ctx context.Context, id string) error {
+	if err := r.client.Del(ctx, uploadKeyPrefix+id).Err(); err != nil {
+		return fmt.Errorf("failed to delete upload: %w", err)
+	}
+	return nil
+}
+
+func (r *uploadRepository) Lock(ctx context.Context, id string) (func(), bool, error) {
+	lock := NewLock(r.client, uploadKeyPrefix+id+":lock", uploadLockTTL)
+	ok, err := lock.Acquire(ctx)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	unlock := func() {
+		// Запрос мог быть отменен клиентом, а блокировку нужно снять в любом случае
+		if err := lock.Release(context.Background()); err != nil {
+			logger.Error("Failed to release upload lock", "error", err, "uploadID", id)
+		}
+	}
+	return unlock, true, nil
+}
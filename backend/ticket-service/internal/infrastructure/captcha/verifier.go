@@ -0,0 +1,97 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"ticket-service/internal/domain/services"
+)
+
+const (
+	ProviderRecaptcha = "recaptcha"
+	ProviderHCaptcha  = "hcaptcha"
+	ProviderTurnstile = "turnstile"
+)
+
+// Все три провайдера используют одинаковый протокол siteverify, отличаются только адресом
+var defaultVerifyURLs = map[string]string{
+	ProviderRecaptcha: "https://www.google.com/recaptcha/api/siteverify",
+	ProviderHCaptcha:  "https://api.hcaptcha.com/siteverify",
+	ProviderTurnstile: "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+}
+
+type siteVerifyResponse struct {
+	Success    bool     `json:"success"`
+	Score      *float64 `json:"score,omitempty"`
+	Action     string   `json:"action,omitempty"`
+	ErrorCodes []string `json:"error-codes,omitempty"`
+}
+
+type siteVerifier struct {
+	secret    string
+	verifyURL string
+	client    *http.Client
+}
+
+// NewVerifier создает проверку капчи для провайдера; verifyURL переопределяет адрес провайдера
+func NewVerifier(provider, secret, verifyURL string, timeout time.Duration) (services.ICaptchaVerifier, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("captcha secret key is empty")
+	}
+
+	if verifyURL == "" {
+		defaultURL, ok := defaultVerifyURLs[strings.ToLower(provider)]
+		if !ok {
+			return nil, fmt.Errorf("unknown captcha provider %q", provider)
+		}
+		verifyURL = defaultURL
+	}
+
+	return &siteVerifier{
+		secret:    secret,
+		verifyURL: verifyURL,
+		client:    &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (v *siteVerifier) Verify(ctx context.Context, token, remoteIP string) (*services.CaptchaResult, error) {
+	form := url.Values{}
+	form.Set("secret", v.secret)
+	form.Set("response", token)
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create captcha request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify captcha: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("captcha provider returned status %d", resp.StatusCode)
+	}
+
+	var body siteVerifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode captcha response: %w", err)
+	}
+
+	return &services.CaptchaResult{
+		Success:    body.Success,
+		Score:      body.Score,
+		Action:     body.Action,
+		ErrorCodes: body.ErrorCodes,
+	}, nil
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+)
+
+type auditRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuditRepository(pool *pgxpool.Pool) repositories.AuditRepository {
+	return &auditRepository{pool: pool}
+}
+
+func (r *auditRepository) Create(ctx context.Context, entry *models.AuditEntry) (int64, error) {
+	var id int64
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO ticket_audit_log
+		(ticket_id, action, actor_id, actor_type, ip, before, after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at`,
+		entry.TicketID, entry.Action, entry.ActorID, entry.ActorType, entry.IP,
+		nullableJSON(entry.Before), nullableJSON(entry.After)).Scan(&id, &entry.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create audit entry: %w", err)
+	}
+	return id, nil
+}
+
+func (r *auditRepository) List(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEntry, int64, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 || filter.PageSize > 100 {
+		filter.PageSize = 20
+	}
+
+	var conditions []string
+	var args []interface{}
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.TicketID != nil {
+		addCondition("ticket_id = $%d", *filter.TicketID)
+	}
+	if filter.ActorID != nil {
+		addCondition("actor_id = $%d", *filter.ActorID)
+	}
+	if filter.ActorType != "" {
+		addCondition("actor_type = $%d", filter.ActorType)
+	}
+	if filter.Action != "" {
+		addCondition("action = $%d", filter.Action)
+	}
+	if filter.From != nil {
+		addCondition("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("created_at < $%d", *filter.To)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM ticket_audit_log "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	query := fmt.Sprintf(`
+		SELECT id, ticket_id, action, actor_id, actor_type, ip, before, after, created_at
+		FROM ticket_audit_log
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d`, where, len(args)+1, len(args)+2)
+
+	rows, err := r.pool.Query(ctx, query, append(args, filter.PageSize, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*models.AuditEntry, 0)
+	for rows.Next() {
+		e := &models.AuditEntry{}
+		err := rows.Scan(&e.ID, &e.TicketID, &e.Action, &e.ActorID, &e.ActorType, &e.IP,
+			&e.Before, &e.After, &e.CreatedAt)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating over audit entries: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+// nullableJSON сохраняет пустой diff как NULL, а не как пустую строку
+func nullableJSON(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+)
+
+type fileObjectRepository struct {
+	db DBTX
+}
+
+func NewFileObjectRepository(pool *pgxpool.Pool) repositories.FileObjectRepository {
+	return &fileObjectRepository{db: pool}
+}
+
+func (r *fileObjectRepository) Acquire(ctx context.Context, object *models.FileObject) (bool, error) {
+	// xmax = 0 только у строки, вставленной этим запросом, а не обновленной при конфликте
+	var created bool
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO file_objects (key, sha256, size, ref_count)
+		VALUES ($1, $2, $3, 1)
+		ON CONFLICT (key) DO UPDATE SET ref_count = file_objects.ref_count + 1, released_at = NULL
+		RETURNING xmax = 0, ref_count, created_at`,
+		object.Key, object.SHA256, object.Size,
+	).Scan(&created, &object.RefCount, &object.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire file object: %w", err)
+	}
+	return created, nil
+}
+
+func (r *fileObjectRepository) Register(ctx context.Context, object *models.FileObject) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO file_objects (key, sha256, size, ref_count, released_at)
+		VALUES ($1, $2, $3, 0, NOW())
+		ON CONFLICT (key) DO NOTHING`,
+		object.Key, object.SHA256, object.Size)
+	if err != nil {
+		return fmt.Errorf("failed to register file object: %w", err)
+	}
+	return nil
+}
+
+func (r *fileObjectRepository) Release(ctx context.Context, key string) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE file_objects
+		SET ref_count = GREATEST(ref_count - 1, 0),
+			released_at = CASE WHEN ref_count <= 1 THEN NOW() ELSE released_at END
+		WHERE key = $1`, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to release file object: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Reconcile пересчитывает ссылки по столбцу object_key тикетов и ответов, который вычисляется из
+// file_url, поэтому учитываются и вложения, удаленные без снятия ссылки (например, очистка спама)
+func (r *fileObjectRepository) Reconcile(ctx context.Context) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE file_objects fo
+		SET ref_count = refs.count,
+			released_at = CASE WHEN refs.count = 0 THEN COALESCE(fo.released_at, NOW()) END
+		FROM (
+			SELECT o.key,
+				(SELECT COUNT(*) FROM tickets t WHERE t.object_key = o.key)
+				+ (SELECT COUNT(*) FROM ticket_responses r WHERE r.object_key = o.key) AS count
+			FROM file_objects o
+		) refs
+		WHERE fo.key = refs.key
+			AND (fo.ref_count <> refs.count OR (refs.count = 0 AND fo.released_at IS NULL))`)
+	if err != nil {
+		return fmt.Errorf("failed to reconcile file object references: %w", err)
+	}
+	return nil
+}
+
+func (r *fileObjectRepository) GetUnreferenced(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT key FROM file_objects
+		WHERE ref_count = 0 AND released_at < $1
+		ORDER BY released_at
+		LIMIT $2`, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unreferenced file objects: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make([]string, 0)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan file object: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *fileObjectRepository) GetPendingPreviews(ctx context.Context, limit int) ([]string, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT key FROM file_objects
+		WHERE preview_status = $1 AND ref_count > 0
+		ORDER BY created_at
+		LIMIT $2`, models.FilePreviewPending, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file objects pending preview: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make([]string, 0)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan file object: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *fileObjectRepository) SetPreviewStatus(ctx context.Context, key string, status models.FilePreviewStatus) error {
+	if _, err := r.db.Exec(ctx, `UPDATE file_objects SET preview_status = $2 WHERE key = $1`, key, status); err != nil {
+		return fmt.Errorf("failed to update file object preview status: %w", err)
+	}
+	return nil
+}
+
+func (r *fileObjectRepository) DeleteUnreferenced(ctx context.Context, key string, before time.Time) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		DELETE FROM file_objects
+		WHERE key = $1 AND ref_count = 0 AND released_at < $2`, key, before)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete file object: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
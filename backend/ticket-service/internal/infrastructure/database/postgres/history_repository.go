@@ -11,16 +11,16 @@ import (
 )
 
 type historyRepository struct {
-	pool *pgxpool.Pool
+	db DBTX
 }
 
 func NewHistoryRepository(pool *pgxpool.Pool) repositories.HistoryRepository {
-	return &historyRepository{pool: pool}
+	return &historyRepository{db: pool}
 }
 
 func (r *historyRepository) Create(ctx context.Context, history *models.TicketHistory) (int64, error) {
 	var id int64
-	err := r.pool.QueryRow(ctx, `
+	err := r.db.QueryRow(ctx, `
 		INSERT INTO ticket_history 
 		(ticket_id, status, comment, admin_id) 
 		VALUES ($1, $2, $3, $4) 
@@ -33,7 +33,7 @@ func (r *historyRepository) Create(ctx context.Context, history *models.TicketHi
 }
 
 func (r *historyRepository) GetByTicketID(ctx context.Context, ticketID int64) ([]*models.TicketHistory, error) {
-	rows, err := r.pool.Query(ctx, `
+	rows, err := r.db.Query(ctx, `
 		SELECT id, ticket_id, status, comment, admin_id, created_at 
 		FROM ticket_history 
 		WHERE ticket_id = $1 
@@ -62,7 +62,7 @@ func (r *historyRepository) GetByTicketID(ctx context.Context, ticketID int64) (
 
 func (r *historyRepository) GetLastByTicketID(ctx context.Context, ticketID int64) (*models.TicketHistory, error) {
 	history := &models.TicketHistory{}
-	err := r.pool.QueryRow(ctx, `
+	err := r.db.QueryRow(ctx, `
 		SELECT id, ticket_id, status, comment, admin_id, created_at 
 		FROM ticket_history 
 		WHERE ticket_id = $1 
@@ -87,7 +87,7 @@ func (r *historyRepository) GetByTicketIDWithPagination(ctx context.Context, tic
 
 	// Get total count
 	var total int
-	err := r.pool.QueryRow(ctx, `
+	err := r.db.QueryRow(ctx, `
 		SELECT COUNT(*) 
 		FROM ticket_history 
 		WHERE ticket_id = $1`, ticketID).Scan(&total)
@@ -96,7 +96,7 @@ func (r *historyRepository) GetByTicketIDWithPagination(ctx context.Context, tic
 	}
 
 	// Get history records with pagination
-	rows, err := r.pool.Query(ctx, `
+	rows, err := r.db.Query(ctx, `
 		SELECT id, ticket_id, status, comment, admin_id, created_at 
 		FROM ticket_history 
 		WHERE ticket_id = $1 
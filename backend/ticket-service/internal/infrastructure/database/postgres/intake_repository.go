@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+)
+
+type intakeRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewIntakeRepository(pool *pgxpool.Pool) repositories.IntakeRepository {
+	return &intakeRepository{pool: pool}
+}
+
+func (r *intakeRepository) Get(ctx context.Context, category models.TicketCategory) (*models.CategoryIntake, error) {
+	intake := &models.CategoryIntake{}
+	err := r.pool.QueryRow(ctx, `
+		SELECT category, schema, updated_by, updated_at
+		FROM category_intake_schemas
+		WHERE category = $1`, category).Scan(
+		&intake.Category, &intake.Schema, &intake.UpdatedBy, &intake.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get intake schema: %w", err)
+	}
+	return intake, nil
+}
+
+func (r *intakeRepository) List(ctx context.Context) ([]*models.CategoryIntake, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT category, schema, updated_by, updated_at
+		FROM category_intake_schemas
+		ORDER BY category`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list intake schemas: %w", err)
+	}
+	defer rows.Close()
+
+	var intakes []*models.CategoryIntake
+	for rows.Next() {
+		intake := &models.CategoryIntake{}
+		if err := rows.Scan(&intake.Category, &intake.Schema, &intake.UpdatedBy, &intake.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan intake schema: %w", err)
+		}
+		intakes = append(intakes, intake)
+	}
+	return intakes, rows.Err()
+}
+
+func (r *intakeRepository) Upsert(ctx context.Context, intake *models.CategoryIntake) error {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO category_intake_schemas (category, schema, updated_by, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (category) DO UPDATE
+		SET schema = EXCLUDED.schema, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+		RETURNING updated_at`,
+		intake.Category, intake.Schema, intake.UpdatedBy).Scan(&intake.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save intake schema: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"ticket-service/internal/logger"
+)
+
+// searchIndexes индексы полнотекстового поиска тикетов из миграции 000010
+var searchIndexes = []string{"idx_tickets_subject_trgm", "idx_tickets_question_trgm"}
+
+// ReindexSearch перестраивает индексы поиска без блокировки записи и обновляет статистику планировщика
+func ReindexSearch(ctx context.Context, pool *pgxpool.Pool) error {
+	for _, index := range searchIndexes {
+		logger.Info("Reindexing", "index", index)
+		if _, err := pool.Exec(ctx, "REINDEX INDEX CONCURRENTLY "+index); err != nil {
+			return fmt.Errorf("failed to reindex %s: %w", index, err)
+		}
+	}
+
+	if _, err := pool.Exec(ctx, "ANALYZE tickets"); err != nil {
+		return fmt.Errorf("failed to analyze tickets: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	pgxmigrate "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+
+	"ticket-service/internal/logger"
+)
+
+// ErrSchemaBehind возвращается, если в базе применены не все встроенные миграции
+var ErrSchemaBehind = errors.New("database schema is behind the service version")
+
+// ErrSchemaDirty возвращается, если предыдущая миграция завершилась ошибкой и схему нужно исправить вручную
+var ErrSchemaDirty = errors.New("database schema is dirty")
+
+// MigrationStatus состояние схемы относительно встроенных миграций
+type MigrationStatus struct {
+	// Version последняя примененная миграция, 0 - миграции не применялись
+	Version uint
+	Dirty   bool
+	// Latest последняя миграция, встроенная в бинарный файл
+	Latest  uint
+	Pending []uint
+}
+
+// Migrator применяет встроенные миграции. Версия хранится в таблице schema_migrations,
+// как у утилиты migrate, поэтому ранее примененные ею миграции повторно не выполняются
+type Migrator struct {
+	m      *migrate.Migrate
+	source source.Driver
+}
+
+func NewMigrator(pool *pgxpool.Pool, files fs.FS) (*Migrator, error) {
+	src, err := iofs.New(files, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open migrations: %w", err)
+	}
+
+	// Закрытие sql.DB драйвером миграций не закрывает пул
+	driver, err := pgxmigrate.WithInstance(stdlib.OpenDBFromPool(pool), &pgxmigrate.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to init migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "pgx5", driver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init migrator: %w", err)
+	}
+	m.Log = migrateLogger{}
+
+	return &Migrator{m: m, source: src}, nil
+}
+
+// Up применяет все непримененные миграции
+func (m *Migrator) Up() error {
+	if err := m.m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	return nil
+}
+
+// Down откатывает steps последних миграций
+func (m *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive, got %d", steps)
+	}
+	if err := m.m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to roll back migrations: %w", err)
+	}
+	return nil
+}
+
+// Force записывает версию схемы без выполнения миграций и снимает признак dirty
+func (m *Migrator) Force(version int) error {
+	if err := m.m.Force(version); err != nil {
+		return fmt.Errorf("failed to force migration version: %w", err)
+	}
+	return nil
+}
+
+// Status сравнивает примененную версию со встроенными миграциями
+func (m *Migrator) Status() (*MigrationStatus, error) {
+	status := &MigrationStatus{}
+
+	version, dirty, err := m.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	status.Version = version
+	status.Dirty = dirty
+
+	current, err := m.source.First()
+	for err == nil {
+		status.Latest = current
+		if current > status.Version {
+			status.Pending = append(status.Pending, current)
+		}
+		current, err = m.source.Next(current)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	return status, nil
+}
+
+// EnsureCurrent возвращает ошибку, если схема отстает от встроенных миграций или помечена dirty
+func (m *Migrator) EnsureCurrent() error {
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+	if status.Dirty {
+		return fmt.Errorf("%w: version %d", ErrSchemaDirty, status.Version)
+	}
+	if len(status.Pending) > 0 {
+		return fmt.Errorf("%w: version %d, latest %d", ErrSchemaBehind, status.Version, status.Latest)
+	}
+	return nil
+}
+
+func (m *Migrator) Close() error {
+	sourceErr, dbErr := m.m.Close()
+	if sourceErr != nil {
+		return sourceErr
+	}
+	return dbErr
+}
+
+// migrateLogger выводит ход применения миграций в логгер сервиса
+type migrateLogger struct{}
+
+func (migrateLogger) Printf(format string, v ...interface{}) {
+	logger.Info("Migration: " + strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+func (migrateLogger) Verbose() bool {
+	return false
+}
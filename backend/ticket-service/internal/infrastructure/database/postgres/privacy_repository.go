@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"ticket-service/internal/domain/repositories"
+)
+
+// redactedText заменяет свободный текст, который может содержать персональные данные
+const redactedText = "[удалено]"
+
+// piiKeys поля с персональными данными в снимках журнала аудита
+var piiKeys = []string{"full_name", "email", "phone", "telegram_id", "subject", "question", "message", "file_url", "comment", "fields"}
+
+type privacyRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPrivacyRepository(pool *pgxpool.Pool) repositories.PrivacyRepository {
+	return &privacyRepository{pool: pool}
+}
+
+func (r *privacyRepository) GetAttachmentURLs(ctx context.Context, ticketID int64) ([]string, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT file_url FROM tickets WHERE id = $1 AND file_url IS NOT NULL
+		UNION ALL
+		SELECT file_url FROM ticket_responses WHERE ticket_id = $1 AND file_url IS NOT NULL`, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var urls []string
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		urls = append(urls, url)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over attachments: %w", err)
+	}
+
+	return urls, nil
+}
+
+// anonymizeStatement запрос, обезличивающий одну из таблиц с данными тикета
+type anonymizeStatement struct {
+	query string
+	args  []interface{}
+}
+
+// anonymizeStatements запросы Anonymize: свободный текст заменяется redactedText, контакты и вложения удаляются.
+// Комментарии истории обезличиваются целиком: в них попадает сообщение заявителя при повторном открытии
+func anonymizeStatements(ticketID int64, anonymizedAt time.Time) []anonymizeStatement {
+	return []anonymizeStatement{
+		{`UPDATE tickets
+			SET full_name = '', email = '', phone = NULL, telegram_id = NULL,
+				subject = $2, question = $2, file_url = NULL, spam_signals = NULL,
+				notify_email = FALSE, notify_tg = FALSE, fields = '{}', anonymized_at = $3
+			WHERE id = $1`, []interface{}{ticketID, redactedText, anonymizedAt}},
+		{`UPDATE ticket_responses SET message = $2, file_url = NULL WHERE ticket_id = $1`,
+			[]interface{}{ticketID, redactedText}},
+		{`UPDATE ticket_response_revisions SET message = $2
+			WHERE response_id IN (SELECT id FROM ticket_responses WHERE ticket_id = $1)`,
+			[]interface{}{ticketID, redactedText}},
+		{`UPDATE ticket_history SET comment = $2 WHERE ticket_id = $1 AND comment IS NOT NULL`,
+			[]interface{}{ticketID, redactedText}},
+		{`UPDATE ticket_surveys SET comment = NULL WHERE ticket_id = $1`, []interface{}{ticketID}},
+		{`UPDATE ticket_audit_log SET before = before - $2::text[], after = after - $2::text[]
+			WHERE ticket_id = $1`, []interface{}{ticketID, piiKeys}},
+	}
+}
+
+// Anonymize обезличивает тикет, его ответы, ревизии, комментарии истории, отзыв и снимки в журнале аудита
+// одной транзакцией. Статус, категория, даты, назначение и оценка сохраняются для статистики
+func (r *privacyRepository) Anonymize(ctx context.Context, ticketID int64, anonymizedAt time.Time) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	statements := anonymizeStatements(ticketID, anonymizedAt)
+	for _, statement := range statements {
+		if _, err := tx.Exec(ctx, statement.query, statement.args...); err != nil {
+			return fmt.Errorf("failed to anonymize ticket %d: %w", ticketID, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit anonymization: %w", err)
+	}
+	return nil
+}
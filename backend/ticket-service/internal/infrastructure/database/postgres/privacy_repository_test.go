@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnonymizeStatementsCoverFreeText(t *testing.T) {
+	statements := anonymizeStatements(42, time.Now())
+
+	updated := make(map[string]anonymizeStatement, len(statements))
+	table := regexp.MustCompile(`^UPDATE (\w+)\s+SET`)
+	for _, statement := range statements {
+		match := table.FindStringSubmatch(statement.query)
+		if assert.NotNil(t, match, statement.query) {
+			updated[match[1]] = statement
+		}
+		assert.Equal(t, int64(42), statement.args[0], statement.query)
+	}
+
+	// Таблицы, в которых хранится текст заявителя или администратора
+	for _, name := range []string{"tickets", "ticket_responses", "ticket_response_revisions", "ticket_history", "ticket_surveys", "ticket_audit_log"} {
+		assert.Contains(t, updated, name)
+	}
+
+	// Сообщение при повторном открытии хранится в комментарии истории
+	history := updated["ticket_history"]
+	assert.Contains(t, history.query, "comment = $2")
+	assert.Equal(t, redactedText, history.args[1])
+}
@@ -2,8 +2,11 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"ticket-service/internal/domain/models"
@@ -11,21 +14,21 @@ import (
 )
 
 type responseRepository struct {
-	pool *pgxpool.Pool
+	db DBTX
 }
 
 func NewResponseRepository(pool *pgxpool.Pool) repositories.ResponseRepository {
-	return &responseRepository{pool: pool}
+	return &responseRepository{db: pool}
 }
 
 func (r *responseRepository) Create(ctx context.Context, response *models.Response) (int64, error) {
 	var id int64
-	err := r.pool.QueryRow(ctx, `
+	err := r.db.QueryRow(ctx, `
 		INSERT INTO ticket_responses 
-		(ticket_id, admin_id, message) 
-		VALUES ($1, $2, $3) 
+		(ticket_id, admin_id, message, file_url) 
+		VALUES ($1, $2, $3, $4) 
 		RETURNING id`,
-		response.TicketID, response.AdminID, response.Message).Scan(&id)
+		response.TicketID, response.AdminID, response.Message, response.FileURL).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create response: %w", err)
 	}
@@ -33,10 +36,10 @@ func (r *responseRepository) Create(ctx context.Context, response *models.Respon
 }
 
 func (r *responseRepository) GetByTicketID(ctx context.Context, ticketID int64) ([]*models.Response, error) {
-	rows, err := r.pool.Query(ctx, `
-		SELECT id, ticket_id, admin_id, message, file_url, created_at 
+	rows, err := r.db.Query(ctx, `
+		SELECT id, ticket_id, admin_id, message, file_url, created_at, updated_at
 		FROM ticket_responses 
-		WHERE ticket_id = $1 
+		WHERE ticket_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at ASC`, ticketID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query responses: %w", err)
@@ -46,7 +49,7 @@ func (r *responseRepository) GetByTicketID(ctx context.Context, ticketID int64)
 	responses := make([]*models.Response, 0)
 	for rows.Next() {
 		resp := &models.Response{}
-		err := rows.Scan(&resp.ID, &resp.TicketID, &resp.AdminID, &resp.Message, &resp.FileURL, &resp.CreatedAt)
+		err := rows.Scan(&resp.ID, &resp.TicketID, &resp.AdminID, &resp.Message, &resp.FileURL, &resp.CreatedAt, &resp.UpdatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan response: %w", err)
 		}
@@ -72,19 +75,19 @@ func (r *responseRepository) GetByTicketIDWithPagination(ctx context.Context, ti
 
 	// Get total count
 	var total int
-	err := r.pool.QueryRow(ctx, `
+	err := r.db.QueryRow(ctx, `
 		SELECT COUNT(*) 
 		FROM ticket_responses 
-		WHERE ticket_id = $1`, ticketID).Scan(&total)
+		WHERE ticket_id = $1 AND deleted_at IS NULL`, ticketID).Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count responses: %w", err)
 	}
 
 	// Get responses with pagination
-	rows, err := r.pool.Query(ctx, `
-		SELECT id, ticket_id, admin_id, message, file_url, created_at 
+	rows, err := r.db.Query(ctx, `
+		SELECT id, ticket_id, admin_id, message, file_url, created_at, updated_at
 		FROM ticket_responses 
-		WHERE ticket_id = $1 
+		WHERE ticket_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at DESC 
 		LIMIT $2 
 		OFFSET $3`, ticketID, pageSize, offset)
@@ -96,7 +99,7 @@ func (r *responseRepository) GetByTicketIDWithPagination(ctx context.Context, ti
 	responses := make([]*models.Response, 0)
 	for rows.Next() {
 		resp := &models.Response{}
-		err := rows.Scan(&resp.ID, &resp.TicketID, &resp.AdminID, &resp.Message, &resp.FileURL, &resp.CreatedAt)
+		err := rows.Scan(&resp.ID, &resp.TicketID, &resp.AdminID, &resp.Message, &resp.FileURL, &resp.CreatedAt, &resp.UpdatedAt)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan response: %w", err)
 		}
@@ -110,20 +113,48 @@ func (r *responseRepository) GetByTicketIDWithPagination(ctx context.Context, ti
 	return responses, total, nil
 }
 
-func (r *responseRepository) UpdateMessage(ctx context.Context, id int64, message string) error {
-	_, err := r.pool.Exec(ctx, `
-		UPDATE ticket_responses 
-		SET message = $1
-		WHERE id = $2`,
-		message, id)
+// GetByID возвращает ответ, в том числе удаленный; nil, если ответа нет
+func (r *responseRepository) GetByID(ctx context.Context, id int64) (*models.Response, error) {
+	resp := &models.Response{}
+	err := r.db.QueryRow(ctx, `
+		SELECT id, ticket_id, admin_id, message, file_url, created_at, updated_at, deleted_at, deleted_by
+		FROM ticket_responses
+		WHERE id = $1`, id).Scan(&resp.ID, &resp.TicketID, &resp.AdminID, &resp.Message, &resp.FileURL,
+		&resp.CreatedAt, &resp.UpdatedAt, &resp.DeletedAt, &resp.DeletedBy)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get response: %w", err)
+	}
+	return resp, nil
+}
+
+// UpdateMessage сохраняет прежний текст в ревизиях и обновляет ответ одним запросом
+func (r *responseRepository) UpdateMessage(ctx context.Context, id int64, message string, editedBy uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `
+		WITH revision AS (
+			INSERT INTO ticket_response_revisions (response_id, message, edited_by)
+			SELECT id, message, $3
+			FROM ticket_responses
+			WHERE id = $1 AND deleted_at IS NULL
+			RETURNING response_id
+		)
+		UPDATE ticket_responses
+		SET message = $2, updated_at = NOW()
+		WHERE id IN (SELECT response_id FROM revision)`,
+		id, message, editedBy)
 	if err != nil {
 		return fmt.Errorf("failed to update response message: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("response %d not found", id)
+	}
 	return nil
 }
 
 func (r *responseRepository) UpdateFileURL(ctx context.Context, id int64, fileURL string) error {
-	_, err := r.pool.Exec(ctx, `
+	_, err := r.db.Exec(ctx, `
 		UPDATE ticket_responses 
 		SET file_url = $1
 		WHERE id = $2`,
@@ -134,12 +165,42 @@ func (r *responseRepository) UpdateFileURL(ctx context.Context, id int64, fileUR
 	return nil
 }
 
-func (r *responseRepository) Delete(ctx context.Context, id int64) error {
-	_, err := r.pool.Exec(ctx, `
-		DELETE FROM ticket_responses 
-		WHERE id = $1`, id)
+// Delete помечает ответ удаленным; текст сохраняется для журнала аудита.
+// false означает, что ответ не найден или уже удален, в том числе параллельным запросом
+func (r *responseRepository) Delete(ctx context.Context, id int64, deletedBy uuid.UUID) (bool, error) {
+	result, err := r.db.Exec(ctx, `
+		UPDATE ticket_responses
+		SET deleted_at = NOW(), deleted_by = $2
+		WHERE id = $1 AND deleted_at IS NULL`, id, deletedBy)
 	if err != nil {
-		return fmt.Errorf("failed to delete response: %w", err)
+		return false, fmt.Errorf("failed to delete response: %w", err)
 	}
-	return nil
-} 
\ No newline at end of file
+	return result.RowsAffected() == 1, nil
+}
+
+func (r *responseRepository) GetRevisions(ctx context.Context, responseID int64) ([]*models.ResponseRevision, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, response_id, message, edited_by, created_at
+		FROM ticket_response_revisions
+		WHERE response_id = $1
+		ORDER BY created_at ASC, id ASC`, responseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query response revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := make([]*models.ResponseRevision, 0)
+	for rows.Next() {
+		rev := &models.ResponseRevision{}
+		if err := rows.Scan(&rev.ID, &rev.ResponseID, &rev.Message, &rev.EditedBy, &rev.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan response revision: %w", err)
+		}
+		revisions = append(revisions, rev)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over response revisions: %w", err)
+	}
+
+	return revisions, nil
+}
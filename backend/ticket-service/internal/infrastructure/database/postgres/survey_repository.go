@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+)
+
+type surveyRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewSurveyRepository(pool *pgxpool.Pool) repositories.SurveyRepository {
+	return &surveyRepository{pool: pool}
+}
+
+// Upsert создает опрос; при повторном закрытии тикета опрос отправляется заново, прежняя оценка
+// сбрасывается, а новый nonce делает недействительными ссылки из прежних писем
+func (r *surveyRepository) Upsert(ctx context.Context, survey *models.Survey) (int64, error) {
+	var id int64
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO ticket_surveys (ticket_id, admin_id, sent_at, nonce)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (ticket_id) DO UPDATE
+		SET admin_id = EXCLUDED.admin_id, sent_at = EXCLUDED.sent_at, nonce = EXCLUDED.nonce,
+			rating = NULL, comment = NULL, rated_at = NULL
+		RETURNING id`,
+		survey.TicketID, survey.AdminID, survey.SentAt, survey.Nonce).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to upsert survey: %w", err)
+	}
+	return id, nil
+}
+
+func (r *surveyRepository) GetByTicketID(ctx context.Context, ticketID int64) (*models.Survey, error) {
+	survey := &models.Survey{}
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, ticket_id, admin_id, rating, comment, sent_at, rated_at, COALESCE(nonce, '')
+		FROM ticket_surveys
+		WHERE ticket_id = $1`, ticketID).Scan(
+		&survey.ID, &survey.TicketID, &survey.AdminID, &survey.Rating,
+		&survey.Comment, &survey.SentAt, &survey.RatedAt, &survey.Nonce)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get survey: %w", err)
+	}
+	return survey, nil
+}
+
+// SaveRating сохраняет оценку условным UPDATE: опрос, уже оцененный или отправленный заново
+// после выдачи ссылки, не изменяется
+func (r *surveyRepository) SaveRating(ctx context.Context, ticketID int64, nonce string, rating int, comment *string) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE ticket_surveys
+		SET rating = $1, comment = $2, rated_at = NOW()
+		WHERE ticket_id = $3 AND COALESCE(nonce, '') = $4 AND rated_at IS NULL`,
+		rating, comment, ticketID, nonce)
+	if err != nil {
+		return false, fmt.Errorf("failed to save survey rating: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *surveyRepository) GetSummary(ctx context.Context, filter models.CSATFilter) (*models.CSATSummary, error) {
+	summary := &models.CSATSummary{Distribution: make(map[int]int64)}
+
+	err := r.pool.QueryRow(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE ($1::timestamptz IS NULL OR sent_at >= $1)
+				AND ($2::timestamptz IS NULL OR sent_at < $2)),
+			COUNT(rating) FILTER (WHERE ($1::timestamptz IS NULL OR rated_at >= $1)
+				AND ($2::timestamptz IS NULL OR rated_at < $2)),
+			COALESCE(AVG(rating) FILTER (WHERE ($1::timestamptz IS NULL OR rated_at >= $1)
+				AND ($2::timestamptz IS NULL OR rated_at < $2)), 0)
+		FROM ticket_surveys`,
+		filter.From, filter.To).Scan(&summary.Sent, &summary.Rated, &summary.Average)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get survey summary: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT rating, COUNT(*)
+		FROM ticket_surveys
+		WHERE rating IS NOT NULL
+			AND ($1::timestamptz IS NULL OR rated_at >= $1)
+			AND ($2::timestamptz IS NULL OR rated_at < $2)
+		GROUP BY rating`,
+		filter.From, filter.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rating distribution: %w", err)
+	}
+	defer rows.Close()
+
+	for rating := models.MinSurveyRating; rating <= models.MaxSurveyRating; rating++ {
+		summary.Distribution[rating] = 0
+	}
+	for rows.Next() {
+		var rating int
+		var count int64
+		if err := rows.Scan(&rating, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan rating distribution: %w", err)
+		}
+		summary.Distribution[rating] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rating distribution: %w", err)
+	}
+
+	return summary, nil
+}
+
+func (r *surveyRepository) GetAdminStats(ctx context.Context, filter models.CSATFilter) ([]*models.AdminCSATStats, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT admin_id, COUNT(*), AVG(rating)
+		FROM ticket_surveys
+		WHERE admin_id IS NOT NULL AND rating IS NOT NULL
+			AND ($1::timestamptz IS NULL OR rated_at >= $1)
+			AND ($2::timestamptz IS NULL OR rated_at < $2)
+		GROUP BY admin_id
+		ORDER BY AVG(rating) DESC`,
+		filter.From, filter.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query admin survey stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make([]*models.AdminCSATStats, 0)
+	for rows.Next() {
+		s := &models.AdminCSATStats{}
+		if err := rows.Scan(&s.AdminID, &s.Rated, &s.Average); err != nil {
+			return nil, fmt.Errorf("failed to scan admin survey stats: %w", err)
+		}
+		stats = append(stats, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over admin survey stats: %w", err)
+	}
+
+	return stats, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
@@ -13,8 +14,56 @@ import (
 	"ticket-service/internal/logger"
 )
 
+// ticketColumns список колонок тикета в порядке, ожидаемом scanTicket
+const ticketColumns = `id, user_id, category, subject, question, full_name, email, phone, telegram_id,
+			file_url, file_checked, status, notify_email, notify_tg, assigned_to, waiting_since,
+			reminder_sent_at, closed_at, anonymized_at, spam_score, spam_signals, reopen_count,
+			previous_ticket_id, fields, created_at, updated_at`
+
+// scanTicket читает строку, выбранную с ticketColumns
+func scanTicket(row pgx.Row) (*models.Ticket, error) {
+	ticket := &models.Ticket{}
+	err := row.Scan(
+		&ticket.ID, &ticket.UserID, &ticket.Category, &ticket.Subject, &ticket.Question,
+		&ticket.FullName, &ticket.Email, &ticket.Phone, &ticket.TelegramID,
+		&ticket.FileURL, &ticket.FileChecked, &ticket.Status, &ticket.NotifyEmail,
+		&ticket.NotifyTG, &ticket.AssignedTo, &ticket.WaitingSince, &ticket.ReminderSentAt,
+		&ticket.ClosedAt, &ticket.AnonymizedAt, &ticket.SpamScore, &ticket.SpamSignals, &ticket.ReopenCount,
+		&ticket.PreviousTicketID, &ticket.Fields, &ticket.CreatedAt, &ticket.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// statusCondition фильтрует по статусу из $1; пустой статус означает все, кроме спама
+const statusCondition = `(($1::text = '' AND status <> 'spam') OR status::text = $1)`
+
+// fieldsCondition отбирает тикеты, дополнительные поля которых совпадают со всеми парами объекта
+// из параметра $n; значения сравниваются как текст, поэтому фильтр из строки запроса подходит к полям любого типа
+func fieldsCondition(n int) string {
+	return fmt.Sprintf(`NOT EXISTS (SELECT 1 FROM jsonb_each_text($%d::jsonb) f WHERE fields ->> f.key IS DISTINCT FROM f.value)`, n)
+}
+
+// intakeFields сохраняет отсутствие дополнительных полей как пустой объект
+func intakeFields(fields map[string]any) map[string]any {
+	if fields == nil {
+		return map[string]any{}
+	}
+	return fields
+}
+
+// spamSignals сохраняет отсутствие сигналов как NULL
+func spamSignals(signals []models.SpamSignal) interface{} {
+	if len(signals) == 0 {
+		return nil
+	}
+	return signals
+}
+
 type ticketRepository struct {
-	db *pgxpool.Pool
+	db DBTX
 }
 
 func NewTicketRepository(db *pgxpool.Pool) repositories.TicketRepository {
@@ -28,12 +77,14 @@ func (r *ticketRepository) Create(ctx context.Context, ticket *models.Ticket) (i
 	var id int64
 	err := r.db.QueryRow(ctx, `
 		INSERT INTO tickets 
-		(user_id, subject, question, full_name, email, phone, telegram_id, status, notify_email, notify_tg) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		(user_id, category, subject, question, full_name, email, phone, telegram_id, status, notify_email, notify_tg,
+		 spam_score, spam_signals, previous_ticket_id, fields)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 		RETURNING id`,
-		ticket.UserID, ticket.Subject, ticket.Question, ticket.FullName,
+		ticket.UserID, ticket.Category, ticket.Subject, ticket.Question, ticket.FullName,
 		ticket.Email, ticket.Phone, ticket.TelegramID, ticket.Status,
-		ticket.NotifyEmail, ticket.NotifyTG,
+		ticket.NotifyEmail, ticket.NotifyTG, ticket.SpamScore, spamSignals(ticket.SpamSignals),
+		ticket.PreviousTicketID, intakeFields(ticket.Fields),
 	).Scan(&id)
 
 	if err != nil {
@@ -47,16 +98,9 @@ func (r *ticketRepository) Create(ctx context.Context, ticket *models.Ticket) (i
 func (r *ticketRepository) GetByID(ctx context.Context, id int64) (*models.Ticket, error) {
 	logger.Info("Getting ticket by ID", "id", id)
 
-	ticket := &models.Ticket{}
-	err := r.db.QueryRow(ctx, `
-		SELECT id, user_id, subject, question, full_name, email, phone, telegram_id, 
-		file_url, file_checked, status, notify_email, notify_tg, created_at, updated_at 
-		FROM tickets WHERE id = $1`, id).Scan(
-		&ticket.ID, &ticket.UserID, &ticket.Subject, &ticket.Question,
-		&ticket.FullName, &ticket.Email, &ticket.Phone, &ticket.TelegramID,
-		&ticket.FileURL, &ticket.FileChecked, &ticket.Status, &ticket.NotifyEmail,
-		&ticket.NotifyTG, &ticket.CreatedAt, &ticket.UpdatedAt,
-	)
+	ticket, err := scanTicket(r.db.QueryRow(ctx, `
+		SELECT `+ticketColumns+`
+		FROM tickets WHERE id = $1`, id))
 
 	if err == pgx.ErrNoRows {
 		logger.Warn("Ticket not found", "id", id)
@@ -70,12 +114,30 @@ func (r *ticketRepository) GetByID(ctx context.Context, id int64) (*models.Ticke
 	return ticket, nil
 }
 
-func (r *ticketRepository) GetByUserID(ctx context.Context, userID int64, req models.GetTicketsRequest) ([]*models.Ticket, int64, error) {
+// GetByIDForUpdate читает тикет с блокировкой FOR UPDATE, чтобы проверка перехода статуса
+// и его смена в одной транзакции не пересекались с параллельными изменениями
+func (r *ticketRepository) GetByIDForUpdate(ctx context.Context, id int64) (*models.Ticket, error) {
+	ticket, err := scanTicket(r.db.QueryRow(ctx, `
+		SELECT `+ticketColumns+`
+		FROM tickets WHERE id = $1
+		FOR UPDATE`, id))
+
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error("Failed to lock ticket", "error", err)
+		return nil, fmt.Errorf("failed to lock ticket: %w", err)
+	}
+
+	return ticket, nil
+}
+
+func (r *ticketRepository) GetByUserID(ctx context.Context, userID uuid.UUID, req models.GetTicketsRequest) ([]*models.Ticket, int64, error) {
 	logger.Info("Getting tickets by user ID", "userID", userID, "page", req.Page, "pageSize", req.PageSize)
 
 	query := `
-		SELECT id, user_id, subject, question, full_name, email, phone, telegram_id,
-			file_url, file_checked, status, notify_email, notify_tg, created_at, updated_at
+		SELECT `+ticketColumns+`
 		FROM tickets WHERE user_id = $1
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3`
@@ -90,18 +152,12 @@ func (r *ticketRepository) GetByUserID(ctx context.Context, userID int64, req mo
 
 	var tickets []*models.Ticket
 	for rows.Next() {
-		var ticket models.Ticket
-		err := rows.Scan(
-			&ticket.ID, &ticket.UserID, &ticket.Subject, &ticket.Question,
-			&ticket.FullName, &ticket.Email, &ticket.Phone, &ticket.TelegramID,
-			&ticket.FileURL, &ticket.FileChecked, &ticket.Status, &ticket.NotifyEmail,
-			&ticket.NotifyTG, &ticket.CreatedAt, &ticket.UpdatedAt,
-		)
+		ticket, err := scanTicket(rows)
 		if err != nil {
 			logger.Error("Failed to scan ticket", "error", err)
 			return nil, 0, fmt.Errorf("failed to scan ticket: %w", err)
 		}
-		tickets = append(tickets, &ticket)
+		tickets = append(tickets, ticket)
 	}
 
 	// Получаем общее количество тикетов
@@ -118,15 +174,16 @@ func (r *ticketRepository) GetByUserID(ctx context.Context, userID int64, req mo
 func (r *ticketRepository) GetAll(ctx context.Context, req models.GetTicketsRequest) ([]*models.Ticket, int64, error) {
 	logger.Info("Getting all tickets", "page", req.Page, "pageSize", req.PageSize)
 
+	// Без фильтра по статусу задержанные антиспамом тикеты не показываются
 	query := `
-		SELECT id, user_id, subject, question, full_name, email, phone, telegram_id,
-			file_url, file_checked, status, notify_email, notify_tg, created_at, updated_at
+		SELECT `+ticketColumns+`
 		FROM tickets
+		WHERE `+statusCondition+` AND `+fieldsCondition(4)+`
 		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2`
+		LIMIT $2 OFFSET $3`
 
 	offset := (req.Page - 1) * req.PageSize
-	rows, err := r.db.Query(ctx, query, req.PageSize, offset)
+	rows, err := r.db.Query(ctx, query, string(req.Status), req.PageSize, offset, req.Fields)
 	if err != nil {
 		logger.Error("Failed to get all tickets", "error", err)
 		return nil, 0, fmt.Errorf("failed to get all tickets: %w", err)
@@ -135,23 +192,17 @@ func (r *ticketRepository) GetAll(ctx context.Context, req models.GetTicketsRequ
 
 	var tickets []*models.Ticket
 	for rows.Next() {
-		var ticket models.Ticket
-		err := rows.Scan(
-			&ticket.ID, &ticket.UserID, &ticket.Subject, &ticket.Question,
-			&ticket.FullName, &ticket.Email, &ticket.Phone, &ticket.TelegramID,
-			&ticket.FileURL, &ticket.FileChecked, &ticket.Status, &ticket.NotifyEmail,
-			&ticket.NotifyTG, &ticket.CreatedAt, &ticket.UpdatedAt,
-		)
+		ticket, err := scanTicket(rows)
 		if err != nil {
 			logger.Error("Failed to scan ticket", "error", err)
 			return nil, 0, fmt.Errorf("failed to scan ticket: %w", err)
 		}
-		tickets = append(tickets, &ticket)
+		tickets = append(tickets, ticket)
 	}
 
 	// Получаем общее количество тикетов
 	var total int64
-	err = r.db.QueryRow(ctx, "SELECT COUNT(*) FROM tickets").Scan(&total)
+	err = r.db.QueryRow(ctx, "SELECT COUNT(*) FROM tickets WHERE "+statusCondition+" AND "+fieldsCondition(2), string(req.Status), req.Fields).Scan(&total)
 	if err != nil {
 		logger.Error("Failed to get total count", "error", err)
 		return nil, 0, fmt.Errorf("failed to get total count: %w", err)
@@ -160,12 +211,17 @@ func (r *ticketRepository) GetAll(ctx context.Context, req models.GetTicketsRequ
 	return tickets, total, nil
 }
 
-func (r *ticketRepository) UpdateStatus(ctx context.Context, id int64, status models.TicketStatus, adminID int64, comment *string) error {
+func (r *ticketRepository) UpdateStatus(ctx context.Context, id int64, status models.TicketStatus, adminID uuid.UUID, comment *string) error {
 	logger.Info("Updating ticket status", "id", id, "status", status)
 
+	// waiting_since отсчитывает бездействие заявителя, поэтому сбрасывается при любой смене статуса;
+	// closed_at - начало срока хранения персональных данных
 	query := `
 		UPDATE tickets
-		SET status = $1, updated_at = $2
+		SET status = $1, updated_at = $2,
+			waiting_since = CASE WHEN $1 = 'waiting' THEN $2 ELSE NULL END,
+			reminder_sent_at = NULL,
+			closed_at = CASE WHEN $1 = 'closed' THEN $2 ELSE NULL END
 		WHERE id = $3`
 
 	_, err := r.db.Exec(ctx, query, status, time.Now(), id)
@@ -215,16 +271,15 @@ func (r *ticketRepository) Search(ctx context.Context, query string, req models.
 	logger.Info("Searching tickets", "query", query, "page", req.Page, "pageSize", req.PageSize)
 
 	searchQuery := `
-		SELECT id, user_id, subject, question, full_name, email, phone, telegram_id,
-			file_url, file_checked, status, notify_email, notify_tg, created_at, updated_at
+		SELECT `+ticketColumns+`
 		FROM tickets
-		WHERE subject ILIKE $1 OR question ILIKE $1
+		WHERE (subject ILIKE $1 OR question ILIKE $1) AND status <> 'spam' AND `+fieldsCondition(4)+`
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3`
 
 	searchPattern := "%" + query + "%"
 	offset := (req.Page - 1) * req.PageSize
-	rows, err := r.db.Query(ctx, searchQuery, searchPattern, req.PageSize, offset)
+	rows, err := r.db.Query(ctx, searchQuery, searchPattern, req.PageSize, offset, req.Fields)
 	if err != nil {
 		logger.Error("Failed to search tickets", "error", err)
 		return nil, 0, fmt.Errorf("failed to search tickets: %w", err)
@@ -233,27 +288,337 @@ func (r *ticketRepository) Search(ctx context.Context, query string, req models.
 
 	var tickets []*models.Ticket
 	for rows.Next() {
-		var ticket models.Ticket
-		err := rows.Scan(
-			&ticket.ID, &ticket.UserID, &ticket.Subject, &ticket.Question,
-			&ticket.FullName, &ticket.Email, &ticket.Phone, &ticket.TelegramID,
-			&ticket.FileURL, &ticket.FileChecked, &ticket.Status, &ticket.NotifyEmail,
-			&ticket.NotifyTG, &ticket.CreatedAt, &ticket.UpdatedAt,
-		)
+		ticket, err := scanTicket(rows)
 		if err != nil {
 			logger.Error("Failed to scan ticket", "error", err)
 			return nil, 0, fmt.Errorf("failed to scan ticket: %w", err)
 		}
-		tickets = append(tickets, &ticket)
+		tickets = append(tickets, ticket)
 	}
 
 	// Получаем общее количество найденных тикетов
 	var total int64
-	err = r.db.QueryRow(ctx, "SELECT COUNT(*) FROM tickets WHERE subject ILIKE $1 OR question ILIKE $1", searchPattern).Scan(&total)
+	err = r.db.QueryRow(ctx, "SELECT COUNT(*) FROM tickets WHERE (subject ILIKE $1 OR question ILIKE $1) AND status <> 'spam' AND "+fieldsCondition(2), searchPattern, req.Fields).Scan(&total)
 	if err != nil {
 		logger.Error("Failed to get total count", "error", err)
 		return nil, 0, fmt.Errorf("failed to get total count: %w", err)
 	}
 
 	return tickets, total, nil
-}
\ No newline at end of file
+}
+
+func (r *ticketRepository) Assign(ctx context.Context, id int64, adminID uuid.UUID) error {
+	logger.Info("Assigning ticket", "id", id, "adminID", adminID)
+
+	_, err := r.db.Exec(ctx, `
+		UPDATE tickets
+		SET assigned_to = $1, updated_at = $2
+		WHERE id = $3`, adminID, time.Now(), id)
+	if err != nil {
+		logger.Error("Failed to assign ticket", "error", err)
+		return fmt.Errorf("failed to assign ticket: %w", err)
+	}
+
+	return nil
+}
+
+// Reopen возвращает закрытый тикет в статус status и увеличивает счетчик повторных открытий;
+// false, если тикет уже не закрыт
+func (r *ticketRepository) Reopen(ctx context.Context, id int64, status models.TicketStatus) (bool, error) {
+	logger.Info("Reopening ticket", "id", id, "status", status)
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE tickets
+		SET status = $1, updated_at = $2, reopen_count = reopen_count + 1,
+			waiting_since = NULL, reminder_sent_at = NULL, closed_at = NULL
+		WHERE id = $3 AND status = 'closed'`, status, time.Now(), id)
+	if err != nil {
+		logger.Error("Failed to reopen ticket", "error", err)
+		return false, fmt.Errorf("failed to reopen ticket: %w", err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+// GetReopenStats считает повторные открытия тикетов, созданных в периоде [from, to)
+func (r *ticketRepository) GetReopenStats(ctx context.Context, from, to *time.Time) (*models.ReopenStats, error) {
+	stats := &models.ReopenStats{}
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE status = 'closed' OR reopen_count > 0),
+			COUNT(*) FILTER (WHERE reopen_count > 0),
+			COALESCE(SUM(reopen_count), 0),
+			COUNT(*) FILTER (WHERE previous_ticket_id IS NOT NULL)
+		FROM tickets
+		WHERE status <> 'spam'
+			AND ($1::timestamptz IS NULL OR created_at >= $1)
+			AND ($2::timestamptz IS NULL OR created_at < $2)`,
+		from, to).Scan(&stats.Resolved, &stats.Reopened, &stats.Reopens, &stats.FollowUps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reopen stats: %w", err)
+	}
+
+	if stats.Resolved > 0 {
+		stats.ReopenRate = float64(stats.Reopened) / float64(stats.Resolved)
+	}
+	return stats, nil
+}
+
+// GetGuestByEmail возвращает гостевые тикеты заявителя, которые можно привязать к учетной записи;
+// задержанные антиспамом и обезличенные тикеты не возвращаются
+func (r *ticketRepository) GetGuestByEmail(ctx context.Context, email string) ([]*models.Ticket, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+ticketColumns+`
+		FROM tickets
+		WHERE user_id = $1 AND lower(email) = lower($2)
+			AND status <> 'spam' AND anonymized_at IS NULL
+		ORDER BY created_at ASC`, uuid.Nil, email)
+	if err != nil {
+		logger.Error("Failed to get guest tickets by email", "error", err)
+		return nil, fmt.Errorf("failed to get guest tickets by email: %w", err)
+	}
+	defer rows.Close()
+
+	var tickets []*models.Ticket
+	for rows.Next() {
+		ticket, err := scanTicket(rows)
+		if err != nil {
+			logger.Error("Failed to scan ticket", "error", err)
+			return nil, fmt.Errorf("failed to scan ticket: %w", err)
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over tickets: %w", err)
+	}
+
+	return tickets, nil
+}
+
+// Claim привязывает гостевой тикет с адресом email к пользователю. Условия повторяют GetGuestByEmail,
+// поэтому тикет, привязанный параллельным запросом, не перепривязывается
+func (r *ticketRepository) Claim(ctx context.Context, id int64, userID uuid.UUID, email string) (bool, error) {
+	logger.Info("Claiming guest ticket", "id", id, "userID", userID)
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE tickets
+		SET user_id = $1, updated_at = $2
+		WHERE id = $3 AND user_id = $4 AND lower(email) = lower($5)
+			AND status <> 'spam' AND anonymized_at IS NULL`, userID, time.Now(), id, uuid.Nil, email)
+	if err != nil {
+		logger.Error("Failed to claim ticket", "error", err)
+		return false, fmt.Errorf("failed to claim ticket: %w", err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+func (r *ticketRepository) GetWaiting(ctx context.Context) ([]*models.Ticket, error) {
+	logger.Info("Getting tickets waiting on applicant")
+
+	rows, err := r.db.Query(ctx, `
+		SELECT `+ticketColumns+`
+		FROM tickets
+		WHERE status = 'waiting' AND waiting_since IS NOT NULL
+		ORDER BY waiting_since ASC`)
+	if err != nil {
+		logger.Error("Failed to get waiting tickets", "error", err)
+		return nil, fmt.Errorf("failed to get waiting tickets: %w", err)
+	}
+	defer rows.Close()
+
+	var tickets []*models.Ticket
+	for rows.Next() {
+		ticket, err := scanTicket(rows)
+		if err != nil {
+			logger.Error("Failed to scan ticket", "error", err)
+			return nil, fmt.Errorf("failed to scan ticket: %w", err)
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over tickets: %w", err)
+	}
+
+	return tickets, nil
+}
+
+func (r *ticketRepository) MarkReminderSent(ctx context.Context, id int64, sentAt time.Time) error {
+	logger.Info("Marking stale ticket reminder as sent", "id", id)
+
+	_, err := r.db.Exec(ctx, `
+		UPDATE tickets
+		SET reminder_sent_at = $1
+		WHERE id = $2`, sentAt, id)
+	if err != nil {
+		logger.Error("Failed to mark reminder as sent", "error", err)
+		return fmt.Errorf("failed to mark reminder as sent: %w", err)
+	}
+
+	return nil
+}
+
+// CloseWaiting закрывает тикет условным UPDATE, как Reopen: если заявитель успел ответить
+// и статус сменился, тикет не закрывается
+func (r *ticketRepository) CloseWaiting(ctx context.Context, id int64) (bool, error) {
+	logger.Info("Closing stale ticket", "id", id)
+
+	now := time.Now()
+	result, err := r.db.Exec(ctx, `
+		UPDATE tickets
+		SET status = 'closed', updated_at = $1, closed_at = $1,
+			waiting_since = NULL, reminder_sent_at = NULL
+		WHERE id = $2 AND status = 'waiting'`, now, id)
+	if err != nil {
+		logger.Error("Failed to close stale ticket", "error", err)
+		return false, fmt.Errorf("failed to close stale ticket: %w", err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+func (r *ticketRepository) CountByEmailSince(ctx context.Context, email string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM tickets
+		WHERE lower(email) = lower($1) AND created_at >= $2`, email, since).Scan(&count)
+	if err != nil {
+		logger.Error("Failed to count tickets by email", "error", err)
+		return 0, fmt.Errorf("failed to count tickets by email: %w", err)
+	}
+	return count, nil
+}
+
+func (r *ticketRepository) CountByPhoneSince(ctx context.Context, phone string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM tickets
+		WHERE phone = $1 AND created_at >= $2`, phone, since).Scan(&count)
+	if err != nil {
+		logger.Error("Failed to count tickets by phone", "error", err)
+		return 0, fmt.Errorf("failed to count tickets by phone: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteSpam удаляет тикет, только если он задержан антиспамом
+func (r *ticketRepository) DeleteSpam(ctx context.Context, id int64) (bool, error) {
+	logger.Info("Purging spam ticket", "id", id)
+
+	tag, err := r.db.Exec(ctx, `DELETE FROM tickets WHERE id = $1 AND status = 'spam'`, id)
+	if err != nil {
+		logger.Error("Failed to purge spam ticket", "error", err)
+		return false, fmt.Errorf("failed to purge spam ticket: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetForAnonymization возвращает закрытые до closedBefore и еще не обезличенные тикеты категории
+func (r *ticketRepository) GetForAnonymization(ctx context.Context, category models.TicketCategory, closedBefore time.Time, limit int) ([]*models.Ticket, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+ticketColumns+`
+		FROM tickets
+		WHERE status = 'closed' AND anonymized_at IS NULL AND category = $1 AND closed_at < $2
+		ORDER BY closed_at ASC
+		LIMIT $3`, category, closedBefore, limit)
+	if err != nil {
+		logger.Error("Failed to get tickets for anonymization", "error", err)
+		return nil, fmt.Errorf("failed to get tickets for anonymization: %w", err)
+	}
+	defer rows.Close()
+
+	var tickets []*models.Ticket
+	for rows.Next() {
+		ticket, err := scanTicket(rows)
+		if err != nil {
+			logger.Error("Failed to scan ticket", "error", err)
+			return nil, fmt.Errorf("failed to scan ticket: %w", err)
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over tickets: %w", err)
+	}
+
+	return tickets, nil
+}
+
+// GetAfterID возвращает тикеты, включая задержанные антиспамом, с идентификатором больше afterID
+// по возрастанию идентификатора; используется для постраничного обхода всей таблицы
+func (r *ticketRepository) GetAfterID(ctx context.Context, afterID int64, limit int) ([]*models.Ticket, error) {
+	return r.queryBatch(ctx, `
+		SELECT `+ticketColumns+`
+		FROM tickets
+		WHERE id > $1
+		ORDER BY id ASC
+		LIMIT $2`, afterID, limit)
+}
+
+// GetWithAttachmentsAfterID возвращает тикеты с вложениями с идентификатором больше afterID
+func (r *ticketRepository) GetWithAttachmentsAfterID(ctx context.Context, afterID int64, limit int) ([]*models.Ticket, error) {
+	return r.queryBatch(ctx, `
+		SELECT `+ticketColumns+`
+		FROM tickets
+		WHERE id > $1 AND file_url IS NOT NULL
+		ORDER BY id ASC
+		LIMIT $2`, afterID, limit)
+}
+
+func (r *ticketRepository) queryBatch(ctx context.Context, query string, args ...any) ([]*models.Ticket, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		logger.Error("Failed to get tickets batch", "error", err)
+		return nil, fmt.Errorf("failed to get tickets batch: %w", err)
+	}
+	defer rows.Close()
+
+	var tickets []*models.Ticket
+	for rows.Next() {
+		ticket, err := scanTicket(rows)
+		if err != nil {
+			logger.Error("Failed to scan ticket", "error", err)
+			return nil, fmt.Errorf("failed to scan ticket: %w", err)
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over tickets: %w", err)
+	}
+
+	return tickets, nil
+}
+
+// GetByEmail возвращает все тикеты заявителя, включая задержанные антиспамом
+func (r *ticketRepository) GetByEmail(ctx context.Context, email string) ([]*models.Ticket, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+ticketColumns+`
+		FROM tickets
+		WHERE lower(email) = lower($1)
+		ORDER BY created_at ASC`, email)
+	if err != nil {
+		logger.Error("Failed to get tickets by email", "error", err)
+		return nil, fmt.Errorf("failed to get tickets by email: %w", err)
+	}
+	defer rows.Close()
+
+	var tickets []*models.Ticket
+	for rows.Next() {
+		ticket, err := scanTicket(rows)
+		if err != nil {
+			logger.Error("Failed to scan ticket", "error", err)
+			return nil, fmt.Errorf("failed to scan ticket: %w", err)
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over tickets: %w", err)
+	}
+
+	return tickets, nil
+}
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"ticket-service/internal/domain/repositories"
+)
+
+// DBTX общие методы пула и транзакции: репозитории на нем работают как с пулом, так и внутри транзакции
+type DBTX interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type unitOfWork struct {
+	pool *pgxpool.Pool
+}
+
+func NewUnitOfWork(pool *pgxpool.Pool) repositories.UnitOfWork {
+	return &unitOfWork{pool: pool}
+}
+
+// Do открывает транзакцию и передает в fn репозитории, работающие в ней.
+// Откат выполняется при ошибке fn и при панике, коммит - только при успешном завершении
+func (u *unitOfWork) Do(ctx context.Context, fn func(repos repositories.TxRepositories) error) error {
+	tx, err := u.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = fn(repositories.TxRepositories{
+		Tickets:     &ticketRepository{db: tx},
+		History:     &historyRepository{db: tx},
+		Responses:   &responseRepository{db: tx},
+		FileObjects: &fileObjectRepository{db: tx},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
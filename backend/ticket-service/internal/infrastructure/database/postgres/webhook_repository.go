@@ -0,0 +1,312 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/repositories"
+)
+
+const (
+	subscriptionColumns = `id, url, secret, event_types, description, active, created_by, created_at, updated_at`
+	deliveryColumns     = `id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+		last_status_code, last_error, delivered_at, replay_of, created_at`
+)
+
+type webhookRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewWebhookRepository(pool *pgxpool.Pool) repositories.WebhookRepository {
+	return &webhookRepository{pool: pool}
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) (int64, error) {
+	var id int64
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO webhook_subscriptions (url, secret, event_types, description, active, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at`,
+		subscription.URL, subscription.Secret, eventTypesToStrings(subscription.EventTypes),
+		subscription.Description, subscription.Active, subscription.CreatedBy,
+	).Scan(&id, &subscription.CreatedAt, &subscription.UpdatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	return id, nil
+}
+
+func (r *webhookRepository) GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	subscription, err := scanSubscription(r.pool.QueryRow(ctx,
+		"SELECT "+subscriptionColumns+" FROM webhook_subscriptions WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+func (r *webhookRepository) ListSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	return r.querySubscriptions(ctx, "SELECT "+subscriptionColumns+" FROM webhook_subscriptions ORDER BY id")
+}
+
+func (r *webhookRepository) GetActiveSubscriptions(ctx context.Context, eventType models.TicketEventType) ([]*models.WebhookSubscription, error) {
+	return r.querySubscriptions(ctx, `
+		SELECT `+subscriptionColumns+`
+		FROM webhook_subscriptions
+		WHERE active AND $1 = ANY(event_types)
+		ORDER BY id`, string(eventType))
+}
+
+func (r *webhookRepository) querySubscriptions(ctx context.Context, query string, args ...interface{}) ([]*models.WebhookSubscription, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*models.WebhookSubscription, 0)
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over webhook subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func (r *webhookRepository) UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	err := r.pool.QueryRow(ctx, `
+		UPDATE webhook_subscriptions
+		SET url = $2, secret = $3, event_types = $4, description = $5, active = $6, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`,
+		subscription.ID, subscription.URL, subscription.Secret, eventTypesToStrings(subscription.EventTypes),
+		subscription.Description, subscription.Active,
+	).Scan(&subscription.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, id int64) (bool, error) {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM webhook_subscriptions WHERE id = $1", id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) (int64, error) {
+	var id int64
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, status, next_attempt_at, replay_of)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at`,
+		delivery.SubscriptionID, delivery.EventID, delivery.EventType, string(delivery.Payload),
+		delivery.Status, delivery.NextAttemptAt, delivery.ReplayOf,
+	).Scan(&id, &delivery.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	return id, nil
+}
+
+func (r *webhookRepository) GetDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	delivery, err := scanDelivery(r.pool.QueryRow(ctx,
+		"SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	return delivery, nil
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, int64, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 || filter.PageSize > 100 {
+		filter.PageSize = 20
+	}
+
+	where := "WHERE subscription_id = $1"
+	args := []interface{}{filter.SubscriptionID}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		where += " AND status = $2"
+	}
+
+	var total int64
+	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM webhook_deliveries "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM webhook_deliveries
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d`, deliveryColumns, where, len(args)+1, len(args)+2)
+
+	deliveries, err := r.queryDeliveries(ctx, query, append(args, filter.PageSize, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+func (r *webhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	return r.queryDeliveries(ctx, `
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries
+		WHERE status = 'pending' AND next_attempt_at <= $1
+		ORDER BY next_attempt_at, id
+		LIMIT $2`, now, limit)
+}
+
+func (r *webhookRepository) queryDeliveries(ctx context.Context, query string, args ...interface{}) ([]*models.WebhookDelivery, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*models.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *webhookRepository) RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery, attempt *models.WebhookDeliveryAttempt) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO webhook_delivery_attempts (delivery_id, status_code, error, duration_ms, attempted_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`,
+		delivery.ID, attempt.StatusCode, attempt.Error, attempt.DurationMs, attempt.AttemptedAt,
+	).Scan(&attempt.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery attempt: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4,
+			last_status_code = $5, last_error = $6, delivered_at = $7
+		WHERE id = $1`,
+		delivery.ID, delivery.Status, delivery.Attempts, delivery.NextAttemptAt,
+		delivery.LastStatusCode, delivery.LastError, delivery.DeliveredAt)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookRepository) GetAttempts(ctx context.Context, deliveryID int64) ([]*models.WebhookDeliveryAttempt, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, delivery_id, status_code, error, duration_ms, attempted_at
+		FROM webhook_delivery_attempts
+		WHERE delivery_id = $1
+		ORDER BY attempted_at, id`, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook delivery attempts: %w", err)
+	}
+	defer rows.Close()
+
+	attempts := make([]*models.WebhookDeliveryAttempt, 0)
+	for rows.Next() {
+		a := &models.WebhookDeliveryAttempt{}
+		if err := rows.Scan(&a.ID, &a.DeliveryID, &a.StatusCode, &a.Error, &a.DurationMs, &a.AttemptedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery attempt: %w", err)
+		}
+		attempts = append(attempts, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over webhook delivery attempts: %w", err)
+	}
+
+	return attempts, nil
+}
+
+func (r *webhookRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM webhook_deliveries WHERE status <> 'pending' AND created_at < $1", before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func scanSubscription(row pgx.Row) (*models.WebhookSubscription, error) {
+	s := &models.WebhookSubscription{}
+	var eventTypes []string
+	err := row.Scan(&s.ID, &s.URL, &s.Secret, &eventTypes, &s.Description, &s.Active,
+		&s.CreatedBy, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	s.EventTypes = make([]models.TicketEventType, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		s.EventTypes = append(s.EventTypes, models.TicketEventType(eventType))
+	}
+	return s, nil
+}
+
+func scanDelivery(row pgx.Row) (*models.WebhookDelivery, error) {
+	d := &models.WebhookDelivery{}
+	var payload []byte
+	err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.LastStatusCode, &d.LastError, &d.DeliveredAt, &d.ReplayOf, &d.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	d.Payload = payload
+	return d, nil
+}
+
+func eventTypesToStrings(eventTypes []models.TicketEventType) []string {
+	result := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		result = append(result, string(eventType))
+	}
+	return result
+}
@@ -0,0 +1,141 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/infrastructure/metrics"
+	"ticket-service/internal/logger"
+)
+
+const (
+	channelName = "ticket-service:events"
+	// subscriberBuffer сколько событий может накопиться у медленного клиента до пропуска
+	subscriberBuffer = 64
+)
+
+type subscriber struct {
+	ch     chan *models.TicketEvent
+	filter func(event *models.TicketEvent) bool
+}
+
+// Broker публикует события в Redis pub/sub и раздает их локальным подписчикам,
+// поэтому клиент получает события, произошедшие на любой реплике
+type Broker struct {
+	client      *redis.Client
+	mu          sync.RWMutex
+	subscribers map[*subscriber]struct{}
+}
+
+func NewBroker(client *redis.Client) *Broker {
+	return &Broker{
+		client:      client,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Publish отправляет событие всем репликам, включая текущую
+func (b *Broker) Publish(ctx context.Context, event *models.TicketEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	if err := b.client.Publish(ctx, channelName, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	metrics.TicketEventsPublishedTotal.WithLabelValues(string(event.Type)).Inc()
+	return nil
+}
+
+// Run читает события из Redis до отмены ctx. При остановке закрывает каналы подписчиков,
+// чтобы долгие SSE-соединения завершились и не задерживали graceful shutdown
+func (b *Broker) Run(ctx context.Context) {
+	pubsub := b.client.Subscribe(ctx, channelName)
+	defer pubsub.Close()
+	defer b.closeAll()
+
+	logger.Info("Event broker subscribed", "channel", channelName)
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			var event models.TicketEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				logger.Error("Failed to unmarshal event", "error", err)
+				continue
+			}
+			b.dispatch(&event)
+		}
+	}
+}
+
+// Subscribe регистрирует локального подписчика
+func (b *Broker) Subscribe(filter func(event *models.TicketEvent) bool) (<-chan *models.TicketEvent, func()) {
+	sub := &subscriber{
+		ch:     make(chan *models.TicketEvent, subscriberBuffer),
+		filter: filter,
+	}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+	metrics.EventStreamSubscribers.Inc()
+
+	return sub.ch, func() { b.remove(sub) }
+}
+
+func (b *Broker) remove(sub *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.ch)
+	metrics.EventStreamSubscribers.Dec()
+}
+
+func (b *Broker) closeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		delete(b.subscribers, sub)
+		close(sub.ch)
+		metrics.EventStreamSubscribers.Dec()
+	}
+}
+
+func (b *Broker) dispatch(event *models.TicketEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscribers {
+		if sub.filter != nil && !sub.filter(event) {
+			continue
+		}
+
+		// Не блокируем рассылку из-за одного медленного клиента
+		select {
+		case sub.ch <- event:
+		default:
+			metrics.EventStreamDroppedTotal.Inc()
+			logger.Warn("Dropping event for slow subscriber", "type", event.Type, "ticketID", event.TicketID)
+		}
+	}
+}
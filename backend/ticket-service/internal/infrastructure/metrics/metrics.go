@@ -75,6 +75,183 @@ var (
 		[]string{"operation"},
 	)
 
+	// Метрики для напоминаний и автозакрытия
+	StaleTicketRemindersTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "stale_ticket_reminders_total",
+			Help: "Количество напоминаний заявителям о тикетах, ожидающих ответа",
+		},
+		[]string{"category"},
+	)
+
+	StaleTicketsAutoClosedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "stale_tickets_auto_closed_total",
+			Help: "Количество тикетов, закрытых автоматически из-за бездействия заявителя",
+		},
+		[]string{"category"},
+	)
+
+	TicketReopensTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ticket_reopens_total",
+			Help: "Количество просьб заявителей открыть тикет повторно: reopened - тикет открыт, follow_up - создан новый тикет",
+		},
+		[]string{"outcome"},
+	)
+
+	// Метрики для опросов удовлетворенности
+	CSATSurveysSentTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "csat_surveys_sent_total",
+			Help: "Количество отправленных опросов удовлетворенности",
+		},
+	)
+
+	CSATRatingsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "csat_ratings_total",
+			Help: "Количество полученных оценок по значению",
+		},
+		[]string{"rating"},
+	)
+
+	// Метрики для потока событий
+	TicketEventsPublishedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ticket_events_published_total",
+			Help: "Количество опубликованных событий по тикетам",
+		},
+		[]string{"type"},
+	)
+
+	EventStreamSubscribers = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "event_stream_subscribers",
+			Help: "Количество открытых потоков событий на реплике",
+		},
+	)
+
+	EventStreamDroppedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "event_stream_dropped_total",
+			Help: "Количество событий, пропущенных из-за переполненного буфера клиента",
+		},
+	)
+
+	// Метрики для капчи
+	CaptchaVerificationsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "captcha_verifications_total",
+			Help: "Количество проверок капчи по результату",
+		},
+		[]string{"result"},
+	)
+
+	// Метрики для антиспама
+	SpamVerdictsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "spam_verdicts_total",
+			Help: "Количество проверок гостевых тикетов антиспамом по результату",
+		},
+		[]string{"result"},
+	)
+
+	SpamSignalsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "spam_signals_total",
+			Help: "Количество срабатываний признаков спама",
+		},
+		[]string{"signal"},
+	)
+
+	// Метрики для хранения персональных данных
+	TicketsAnonymizedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "tickets_anonymized_total",
+			Help: "Количество обезличенных тикетов по причине",
+		},
+		[]string{"reason"},
+	)
+
+	// Метрики для планировщика
+	SchedulerLeader = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "scheduler_leader",
+			Help: "1, если реплика удерживает блокировку планировщика",
+		},
+	)
+
+	SchedulerJobRunsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "scheduler_job_runs_total",
+			Help: "Количество запусков фоновых задач",
+		},
+		[]string{"job", "result"},
+	)
+
+	IdempotentRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "idempotent_requests_total",
+			Help: "Количество запросов с ключом идемпотентности по результату",
+		},
+		[]string{"result"},
+	)
+
+	RateLimitRejectionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rate_limit_rejections_total",
+			Help: "Количество запросов, отклоненных ограничением частоты, по маршруту и категории инициатора",
+		},
+		[]string{"route", "principal"},
+	)
+
+	UploadsRejectedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "uploads_rejected_total",
+			Help: "Количество отклоненных вложений по причине",
+		},
+		[]string{"reason"},
+	)
+
+	ResumableUploadsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "resumable_uploads_total",
+			Help: "Количество возобновляемых загрузок по исходу: created, completed, terminated, expired",
+		},
+		[]string{"status"},
+	)
+
+	AttachmentPreviewsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "attachment_previews_total",
+			Help: "Количество обработанных вложений по результату построения превью: ready, none, failed",
+		},
+		[]string{"status"},
+	)
+
+	FileObjectsDeduplicatedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "file_objects_deduplicated_total",
+			Help: "Количество загрузок, для которых объект с тем же содержимым уже был в хранилище",
+		},
+	)
+
+	FileObjectsCollectedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "file_objects_collected_total",
+			Help: "Количество объектов вложений без ссылок, удаленных сборщиком мусора",
+		},
+	)
+
+	WebhookDeliveriesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "webhook_deliveries_total",
+			Help: "Количество попыток доставки вебхуков по типу события и результату",
+		},
+		[]string{"event_type", "result"},
+	)
+
 	// Метрики для HTTP
 	HTTPRequestsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -92,4 +269,22 @@ var (
 		},
 		[]string{"method", "path"},
 	)
+
+	// Метрики для gRPC
+	GRPCRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_requests_total",
+			Help: "Общее количество gRPC вызовов",
+		},
+		[]string{"method", "code"},
+	)
+
+	GRPCRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "grpc_request_duration_seconds",
+			Help:    "Время выполнения gRPC вызовов в секундах",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"method"},
+	)
 )
@@ -3,6 +3,7 @@ package email
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"gopkg.in/gomail.v2"
 
@@ -72,4 +73,102 @@ func (s *EmailService) SendTicketResponseNotification(to, ticketSubject, respons
 
 	logger.Info("Email notification sent", "to", to, "subject", m.GetHeader("Subject")[0])
 	return nil
-} 
\ No newline at end of file
+}
+
+func (s *EmailService) SendStaleTicketReminder(to, ticketSubject string, closeAt time.Time) error {
+	m := gomail.NewMessage()
+	m.SetHeader("From", s.from)
+	m.SetHeader("To", to)
+	m.SetHeader("Subject", fmt.Sprintf("Ожидаем вашего ответа по тикету: %s", ticketSubject))
+
+	closeDate := closeAt.Format("02.01.2006")
+
+	htmlBody := fmt.Sprintf(`
+		<html>
+		<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
+			<div style="max-width: 600px; margin: 0 auto; padding: 20px;">
+				<h2 style="color: #2c3e50;">Мы ждем вашего ответа</h2>
+				<p>Здравствуйте!</p>
+				<p>По вашему тикету <strong>"%s"</strong> нам требуется дополнительная информация.</p>
+				<p>Если ответа не будет, тикет будет закрыт автоматически <strong>%s</strong>.</p>
+				<p>С уважением,<br>Служба поддержки</p>
+			</div>
+		</body>
+		</html>
+	`, ticketSubject, closeDate)
+
+	textBody := fmt.Sprintf(`
+		Здравствуйте!
+		
+		По вашему тикету "%s" нам требуется дополнительная информация.
+		Если ответа не будет, тикет будет закрыт автоматически %s.
+		
+		С уважением,
+		Служба поддержки
+	`, ticketSubject, closeDate)
+
+	m.SetBody("text/plain", textBody)
+	m.AddAlternative("text/html", htmlBody)
+
+	d := gomail.NewDialer(s.smtpHost, 587, s.from, s.password)
+
+	if err := d.DialAndSend(m); err != nil {
+		logger.Error("Failed to send email", "error", err, "to", to)
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	logger.Info("Stale ticket reminder sent", "to", to, "subject", m.GetHeader("Subject")[0])
+	return nil
+}
+
+func (s *EmailService) SendSatisfactionSurvey(to, ticketSubject, surveyURL string) error {
+	m := gomail.NewMessage()
+	m.SetHeader("From", s.from)
+	m.SetHeader("To", to)
+	m.SetHeader("Subject", fmt.Sprintf("Оцените ответ по тикету: %s", ticketSubject))
+
+	// Каждая оценка - отдельная ссылка на страницу подтверждения, где оценка уже выбрана
+	var htmlLinks, textLinks string
+	for rating := 1; rating <= 5; rating++ {
+		link := fmt.Sprintf("%s?rating=%d", surveyURL, rating)
+		htmlLinks += fmt.Sprintf(`<a href="%s" style="display: inline-block; margin: 0 4px; padding: 8px 14px; background: #2c3e50; color: #fff; text-decoration: none; border-radius: 4px;">%d</a>`, link, rating)
+		textLinks += fmt.Sprintf("%d - %s\n\t\t", rating, link)
+	}
+
+	htmlBody := fmt.Sprintf(`
+		<html>
+		<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
+			<div style="max-width: 600px; margin: 0 auto; padding: 20px;">
+				<h2 style="color: #2c3e50;">Как мы справились?</h2>
+				<p>Здравствуйте!</p>
+				<p>Ваш тикет <strong>"%s"</strong> закрыт. Пожалуйста, оцените качество поддержки от 1 до 5:</p>
+				<div style="margin: 20px 0;">%s</div>
+				<p>С уважением,<br>Служба поддержки</p>
+			</div>
+		</body>
+		</html>
+	`, ticketSubject, htmlLinks)
+
+	textBody := fmt.Sprintf(`
+		Здравствуйте!
+		
+		Ваш тикет "%s" закрыт. Пожалуйста, оцените качество поддержки от 1 до 5:
+		
+		%s
+		С уважением,
+		Служба поддержки
+	`, ticketSubject, textLinks)
+
+	m.SetBody("text/plain", textBody)
+	m.AddAlternative("text/html", htmlBody)
+
+	d := gomail.NewDialer(s.smtpHost, 587, s.from, s.password)
+
+	if err := d.DialAndSend(m); err != nil {
+		logger.Error("Failed to send email", "error", err, "to", to)
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	logger.Info("Satisfaction survey sent", "to", to, "subject", m.GetHeader("Subject")[0])
+	return nil
+}
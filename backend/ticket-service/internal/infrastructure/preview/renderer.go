@@ -0,0 +1,129 @@
+// Package preview строит PNG-превью вложений средствами Go без внешних программ: изображения
+// уменьшаются, для PDF берется самое крупное изображение первой страницы
+package preview
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+
+	"ticket-service/internal/domain/services"
+)
+
+// maxPixels изображения большего размера не декодируются, чтобы маленький файл не занял гигабайты памяти
+const maxPixels = 50_000_000
+
+type renderer struct {
+	// maxDimension наибольшая сторона превью в пикселях
+	maxDimension int
+}
+
+func NewRenderer(maxDimension int) services.IPreviewRenderer {
+	// pdfcpu по умолчанию создает каталог конфигурации в домашнем каталоге пользователя
+	api.DisableConfigDir()
+	return &renderer{maxDimension: maxDimension}
+}
+
+func (r *renderer) Render(fileType string, data []byte) ([]byte, error) {
+	var (
+		img image.Image
+		err error
+	)
+	switch fileType {
+	case "jpeg", "png", "gif", "webp":
+		img, err = decode(data)
+	case "pdf":
+		img, err = firstPageImage(data)
+	default:
+		return nil, services.ErrPreviewUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, r.scale(img)); err != nil {
+		return nil, fmt.Errorf("failed to encode preview: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// scale уменьшает изображение до maxDimension по большей стороне; меньшие изображения не увеличиваются
+func (r *renderer) scale(img image.Image) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= r.maxDimension && height <= r.maxDimension {
+		rgba := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+		return rgba
+	}
+
+	if width >= height {
+		height = max(1, height*r.maxDimension/width)
+		width = r.maxDimension
+	} else {
+		width = max(1, width*r.maxDimension/height)
+		height = r.maxDimension
+	}
+	thumbnail := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(thumbnail, thumbnail.Bounds(), img, bounds, xdraw.Src, nil)
+	return thumbnail
+}
+
+func decode(data []byte) (image.Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image header: %w", err)
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, fmt.Errorf("image is too large: %dx%d", config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, nil
+}
+
+// firstPageImage возвращает самое крупное изображение первой страницы PDF. Сканы документов
+// состоят из одного изображения на страницу; страницы только с текстом и векторной графикой
+// без растеризатора отрисовать нельзя, для них превью не строится
+func firstPageImage(data []byte) (image.Image, error) {
+	conf := model.NewDefaultConfiguration()
+	conf.ValidationMode = model.ValidationRelaxed
+
+	pages, err := api.ExtractImagesRaw(bytes.NewReader(data), []string{"1"}, conf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+
+	var largest *model.Image
+	for _, images := range pages {
+		for _, candidate := range images {
+			if largest == nil || candidate.Width*candidate.Height > largest.Width*largest.Height {
+				largest = &candidate
+			}
+		}
+	}
+	if largest == nil {
+		return nil, services.ErrPreviewUnsupported
+	}
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(largest); err != nil {
+		return nil, fmt.Errorf("failed to extract PDF image: %w", err)
+	}
+	return decode(buf.Bytes())
+}
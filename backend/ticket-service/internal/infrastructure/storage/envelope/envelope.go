@@ -0,0 +1,249 @@
+// Package envelope шифрует вложения на уровне приложения: каждый объект шифруется собственным
+// ключом данных, а ключ данных хранится в заголовке объекта зашифрованным мастер-ключом
+package envelope
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"ticket-service/internal/domain/services"
+	"ticket-service/internal/infrastructure/storage/signedurl"
+	"ticket-service/internal/logger"
+)
+
+// Service шифрует файлы при загрузке в хранилище и расшифровывает при скачивании. Файлы,
+// сохраненные до включения шифрования, отдаются как есть, пока их не зашифрует команда encrypt.
+// Ссылки на файлы ведут в сам сервис: хранилище отдало бы по ним зашифрованное содержимое
+type Service struct {
+	storage services.IFileService
+	keyring *Keyring
+	signer  *signedurl.Signer
+}
+
+func NewService(storage services.IFileService, keyring *Keyring, signer *signedurl.Signer) *Service {
+	return &Service{
+		storage: storage,
+		keyring: keyring,
+		signer:  signer,
+	}
+}
+
+// MigrationResult итог обхода хранилища командой encrypt
+type MigrationResult struct {
+	// Encrypted файлы, хранившиеся без шифрования
+	Encrypted int
+	// Rewrapped объекты, ключ данных которых перешифрован активным мастер-ключом
+	Rewrapped int
+	Unchanged int
+	Failed    int
+}
+
+func (s *Service) UploadFile(ctx context.Context, file io.Reader, folder string, id string) (string, error) {
+	dataKey, err := newDataKey()
+	if err != nil {
+		return "", err
+	}
+	h, err := s.keyring.wrap(dataKey)
+	if err != nil {
+		return "", err
+	}
+	encrypted, err := newEncryptReader(file, h, dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	// Размер зашифрованного объекта известен заранее, если его знает исходный поток: хранилище
+	// тогда загружает объект, не читая его целиком в память
+	var body io.Reader = encrypted
+	if sized, ok := file.(interface{ Size() int64 }); ok {
+		body = &sizedReader{Reader: encrypted, size: encryptedSize(len(h.marshal()), sized.Size())}
+	}
+
+	if _, err := s.storage.UploadFile(ctx, body, folder, id); err != nil {
+		return "", err
+	}
+	return s.signer.URL(folder + "/" + id), nil
+}
+
+func (s *Service) DownloadFile(ctx context.Context, fileURL string) (io.ReadCloser, error) {
+	key, err := s.ObjectKey(fileURL)
+	if err != nil {
+		return nil, err
+	}
+	object, err := s.storage.DownloadFile(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := s.decrypt(object)
+	if err != nil {
+		object.Close()
+		return nil, fmt.Errorf("failed to decrypt %s: %w", key, err)
+	}
+	return reader, nil
+}
+
+func (s *Service) DeleteFile(ctx context.Context, fileURL string) error {
+	key, err := s.ObjectKey(fileURL)
+	if err != nil {
+		return err
+	}
+	return s.storage.DeleteFile(ctx, key)
+}
+
+func (s *Service) GetFileURL(ctx context.Context, fileURL string) (string, error) {
+	key, err := s.ObjectKey(fileURL)
+	if err != nil {
+		return "", err
+	}
+	return s.signer.URL(key), nil
+}
+
+func (s *Service) CheckFileExists(ctx context.Context, fileURL string) (bool, error) {
+	key, err := s.ObjectKey(fileURL)
+	if err != nil {
+		return false, err
+	}
+	return s.storage.CheckFileExists(ctx, key)
+}
+
+// ObjectKey понимает и ссылки сервиса, и ссылки хранилища, выданные до включения шифрования
+func (s *Service) ObjectKey(fileURL string) (string, error) {
+	if key, err := s.signer.Key(fileURL); err == nil {
+		return key, nil
+	}
+	return s.storage.ObjectKey(fileURL)
+}
+
+// EncryptAll шифрует файлы, сохраненные без шифрования, и перешифровывает активным мастер-ключом
+// ключи данных остальных объектов. Содержимое зашифрованных объектов при этом не меняется,
+// переписывается только заголовок. После завершения старые мастер-ключи можно убрать из конфигурации
+func (s *Service) EncryptAll(ctx context.Context) (*MigrationResult, error) {
+	lister, ok := s.storage.(services.IObjectLister)
+	if !ok {
+		return nil, errors.New("storage does not support listing objects")
+	}
+	keys, err := lister.ListObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MigrationResult{}
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		action, err := s.migrate(ctx, key)
+		if err != nil {
+			logger.Error("Failed to encrypt object", "error", err, "key", key)
+			result.Failed++
+			continue
+		}
+		switch action {
+		case migrationEncrypted:
+			result.Encrypted++
+		case migrationRewrapped:
+			result.Rewrapped++
+		default:
+			result.Unchanged++
+		}
+	}
+
+	logger.Info("Storage encryption finished", "encrypted", result.Encrypted, "rewrapped", result.Rewrapped,
+		"unchanged", result.Unchanged, "failed", result.Failed)
+	return result, nil
+}
+
+type migrationAction int
+
+const (
+	migrationUnchanged migrationAction = iota
+	migrationEncrypted
+	migrationRewrapped
+)
+
+func (s *Service) migrate(ctx context.Context, key string) (migrationAction, error) {
+	slash := strings.LastIndex(key, "/")
+	if slash < 0 {
+		return migrationUnchanged, fmt.Errorf("object key %q has no folder", key)
+	}
+	folder, id := key[:slash], key[slash+1:]
+
+	object, err := s.storage.DownloadFile(ctx, key)
+	if err != nil {
+		return migrationUnchanged, err
+	}
+	defer object.Close()
+
+	reader := bufio.NewReader(object)
+	encrypted, err := isEncrypted(reader)
+	if err != nil {
+		return migrationUnchanged, err
+	}
+	if !encrypted {
+		if _, err := s.UploadFile(ctx, reader, folder, id); err != nil {
+			return migrationUnchanged, err
+		}
+		return migrationEncrypted, nil
+	}
+
+	h, err := readHeader(reader)
+	if err != nil {
+		return migrationUnchanged, err
+	}
+	if h.keyID == s.keyring.ActiveID() {
+		return migrationUnchanged, nil
+	}
+	dataKey, err := s.keyring.unwrap(h)
+	if err != nil {
+		return migrationUnchanged, err
+	}
+	rewrapped, err := s.keyring.wrap(dataKey)
+	if err != nil {
+		return migrationUnchanged, err
+	}
+
+	// Сегменты зашифрованы ключом данных и переносятся без изменений
+	content := io.MultiReader(bytes.NewReader(rewrapped.marshal()), reader)
+	if _, err := s.storage.UploadFile(ctx, content, folder, id); err != nil {
+		return migrationUnchanged, err
+	}
+	return migrationRewrapped, nil
+}
+
+// decrypt возвращает расшифрованное содержимое объекта; объект без заголовка отдается как есть
+func (s *Service) decrypt(object io.ReadCloser) (io.ReadCloser, error) {
+	reader := bufio.NewReaderSize(object, segmentSize)
+	encrypted, err := isEncrypted(reader)
+	if err != nil {
+		return nil, err
+	}
+	if !encrypted {
+		return readCloser{Reader: reader, Closer: object}, nil
+	}
+
+	h, err := readHeader(reader)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := s.keyring.unwrap(h)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := newDecryptReader(reader, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return readCloser{Reader: plain, Closer: object}, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
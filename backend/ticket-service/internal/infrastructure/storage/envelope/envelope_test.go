@@ -0,0 +1,275 @@
+package envelope
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ticket-service/internal/domain/services"
+	"ticket-service/internal/infrastructure/storage/memory"
+	"ticket-service/internal/infrastructure/storage/signedurl"
+)
+
+var (
+	oldMasterKey = bytes.Repeat([]byte{1}, dataKeySize)
+	newMasterKey = bytes.Repeat([]byte{2}, dataKeySize)
+)
+
+// newTestService возвращает сервис шифрования и хранилище под ним, в котором видно зашифрованное содержимое
+func newTestService(t *testing.T, activeID string, keys map[string][]byte) (*Service, services.IFileService) {
+	t.Helper()
+	signer := signedurl.NewSigner("secret", "http://localhost/files", time.Hour)
+	storage := memory.NewMemoryService(signer)
+	return newService(t, storage, signer, activeID, keys), storage
+}
+
+func newService(t *testing.T, storage services.IFileService, signer *signedurl.Signer, activeID string, keys map[string][]byte) *Service {
+	t.Helper()
+	keyring, err := NewKeyring(activeID, keys)
+	require.NoError(t, err)
+	return NewService(storage, keyring, signer)
+}
+
+func randomBytes(t *testing.T, size int) []byte {
+	t.Helper()
+	data := make([]byte, size)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	return data
+}
+
+func download(svc *Service, fileURL string) ([]byte, error) {
+	reader, err := svc.DownloadFile(context.Background(), fileURL)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+func readRaw(t *testing.T, storage services.IFileService, key string) []byte {
+	t.Helper()
+	reader, err := storage.DownloadFile(context.Background(), key)
+	require.NoError(t, err)
+	defer reader.Close()
+	raw, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return raw
+}
+
+func writeRaw(t *testing.T, storage services.IFileService, raw []byte) {
+	t.Helper()
+	_, err := storage.UploadFile(context.Background(), bytes.NewReader(raw), "tickets", "1")
+	require.NoError(t, err)
+}
+
+// headerLength длина заголовка зашифрованного объекта raw
+func headerLength(t *testing.T, raw []byte) int {
+	t.Helper()
+	h, err := readHeader(bufio.NewReader(bytes.NewReader(raw)))
+	require.NoError(t, err)
+	return len(h.marshal())
+}
+
+func TestServiceRoundTrip(t *testing.T) {
+	sizes := map[string]int{
+		"empty file":              0,
+		"one full segment":        segmentSize,
+		"one byte over a segment": segmentSize + 1,
+		"several segments":        3*segmentSize + 17,
+	}
+
+	for name, size := range sizes {
+		t.Run(name, func(t *testing.T) {
+			svc, storage := newTestService(t, "new", map[string][]byte{"new": newMasterKey})
+			content := randomBytes(t, size)
+
+			fileURL, err := svc.UploadFile(context.Background(), bytes.NewReader(content), "tickets", "1")
+			require.NoError(t, err)
+
+			raw := readRaw(t, storage, "tickets/1")
+			assert.True(t, bytes.HasPrefix(raw, []byte(magic)))
+			segments := max(1, (size+segmentSize-1)/segmentSize)
+			assert.Len(t, raw, headerLength(t, raw)+size+segments*16)
+			// Размер, который сообщается хранилищу до загрузки
+			assert.Equal(t, int64(len(raw)), encryptedSize(headerLength(t, raw), int64(size)))
+
+			decrypted, err := download(svc, fileURL)
+			require.NoError(t, err)
+			assert.Equal(t, content, decrypted)
+		})
+	}
+}
+
+func TestServiceRejectsTamperedObjects(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(raw []byte, headerLen int) []byte
+	}{
+		{
+			name: "final segment dropped",
+			tamper: func(raw []byte, headerLen int) []byte {
+				return raw[:headerLen+segmentSize+16]
+			},
+		},
+		{
+			name: "final segment truncated",
+			tamper: func(raw []byte, headerLen int) []byte {
+				return raw[:len(raw)-1]
+			},
+		},
+		{
+			name: "all segments dropped",
+			tamper: func(raw []byte, headerLen int) []byte {
+				return raw[:headerLen]
+			},
+		},
+		{
+			name: "segment byte flipped",
+			tamper: func(raw []byte, headerLen int) []byte {
+				raw[headerLen+10] ^= 0xff
+				return raw
+			},
+		},
+		{
+			name: "segments reordered",
+			tamper: func(raw []byte, headerLen int) []byte {
+				first := raw[headerLen : headerLen+segmentSize+16]
+				rest := raw[headerLen+segmentSize+16:]
+				reordered := append([]byte{}, raw[:headerLen]...)
+				reordered = append(reordered, rest...)
+				return append(reordered, first...)
+			},
+		},
+		{
+			name: "wrapped data key flipped",
+			tamper: func(raw []byte, headerLen int) []byte {
+				raw[headerLen-1] ^= 0xff
+				return raw
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, storage := newTestService(t, "new", map[string][]byte{"new": newMasterKey})
+			fileURL, err := svc.UploadFile(context.Background(), bytes.NewReader(randomBytes(t, 2*segmentSize+5)), "tickets", "1")
+			require.NoError(t, err)
+
+			raw := readRaw(t, storage, "tickets/1")
+			writeRaw(t, storage, tt.tamper(raw, headerLength(t, raw)))
+
+			_, err = download(svc, fileURL)
+			assert.ErrorIs(t, err, ErrCorrupted)
+		})
+	}
+}
+
+func TestServiceBindsDataKeyToMasterKeyID(t *testing.T) {
+	// Оба идентификатора указывают на один мастер-ключ: подмену идентификатора в заголовке
+	// обнаруживает только additional data, привязанная к нему
+	svc, storage := newTestService(t, "new", map[string][]byte{"new": newMasterKey, "alt": newMasterKey})
+	fileURL, err := svc.UploadFile(context.Background(), bytes.NewReader([]byte("content")), "tickets", "1")
+	require.NoError(t, err)
+
+	raw := readRaw(t, storage, "tickets/1")
+	relabeled := bytes.Replace(raw, []byte("\x03new"), []byte("\x03alt"), 1)
+	require.NotEqual(t, raw, relabeled)
+	writeRaw(t, storage, relabeled)
+
+	_, err = download(svc, fileURL)
+	assert.ErrorIs(t, err, ErrCorrupted)
+}
+
+func TestServiceReadsLegacyPlaintext(t *testing.T) {
+	contents := map[string][]byte{
+		"empty file":           {},
+		"shorter than header":  []byte("TSE"),
+		"regular file":         []byte("%PDF-1.4 plain attachment"),
+		"larger than segments": randomBytes(t, segmentSize+100),
+	}
+
+	for name, content := range contents {
+		t.Run(name, func(t *testing.T) {
+			svc, storage := newTestService(t, "new", map[string][]byte{"new": newMasterKey})
+			fileURL, err := storage.UploadFile(context.Background(), bytes.NewReader(content), "tickets", "1")
+			require.NoError(t, err)
+
+			plain, err := download(svc, fileURL)
+			require.NoError(t, err)
+			assert.Equal(t, content, plain)
+		})
+	}
+}
+
+func TestServiceRejectsUnknownMasterKey(t *testing.T) {
+	signer := signedurl.NewSigner("secret", "http://localhost/files", time.Hour)
+	storage := memory.NewMemoryService(signer)
+
+	oldSvc := newService(t, storage, signer, "old", map[string][]byte{"old": oldMasterKey})
+	fileURL, err := oldSvc.UploadFile(context.Background(), bytes.NewReader([]byte("content")), "tickets", "1")
+	require.NoError(t, err)
+
+	newSvc := newService(t, storage, signer, "new", map[string][]byte{"new": newMasterKey})
+	_, err = download(newSvc, fileURL)
+	assert.ErrorIs(t, err, ErrUnknownMasterKey)
+}
+
+func TestServiceEncryptAllRotatesMasterKey(t *testing.T) {
+	ctx := context.Background()
+	signer := signedurl.NewSigner("secret", "http://localhost/files", time.Hour)
+	storage := memory.NewMemoryService(signer)
+
+	legacy := []byte("stored before encryption")
+	_, err := storage.UploadFile(ctx, bytes.NewReader(legacy), "tickets", "legacy")
+	require.NoError(t, err)
+
+	oldContent := randomBytes(t, segmentSize+3)
+	oldSvc := newService(t, storage, signer, "old", map[string][]byte{"old": oldMasterKey})
+	_, err = oldSvc.UploadFile(ctx, bytes.NewReader(oldContent), "tickets", "old")
+	require.NoError(t, err)
+
+	// После смены активного ключа прежние объекты читаются старым ключом из конфигурации
+	rotated := newService(t, storage, signer, "new", map[string][]byte{"old": oldMasterKey, "new": newMasterKey})
+	newContent := []byte("encrypted with new key")
+	_, err = rotated.UploadFile(ctx, bytes.NewReader(newContent), "tickets", "new")
+	require.NoError(t, err)
+
+	plain, err := download(rotated, "tickets/old")
+	require.NoError(t, err)
+	assert.Equal(t, oldContent, plain)
+
+	oldSegments := readRaw(t, storage, "tickets/old")
+	oldSegments = oldSegments[headerLength(t, oldSegments):]
+
+	result, err := rotated.EncryptAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &MigrationResult{Encrypted: 1, Rewrapped: 1, Unchanged: 1}, result)
+
+	// Перешифровывается только заголовок, сегменты переносятся без изменений
+	rewrapped := readRaw(t, storage, "tickets/old")
+	assert.Equal(t, oldSegments, rewrapped[headerLength(t, rewrapped):])
+
+	// Старый ключ больше не нужен
+	newOnly := newService(t, storage, signer, "new", map[string][]byte{"new": newMasterKey})
+	for key, want := range map[string][]byte{"tickets/legacy": legacy, "tickets/old": oldContent, "tickets/new": newContent} {
+		raw := readRaw(t, storage, key)
+		assert.True(t, bytes.HasPrefix(raw, []byte(magic)), key)
+
+		plain, err := download(newOnly, key)
+		require.NoError(t, err, key)
+		assert.Equal(t, want, plain, key)
+	}
+
+	// Повторный запуск ничего не меняет
+	result, err = newOnly.EncryptAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &MigrationResult{Unchanged: 3}, result)
+}
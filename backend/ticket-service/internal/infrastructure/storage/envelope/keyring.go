@@ -0,0 +1,97 @@
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownMasterKey объект зашифрован мастер-ключом, которого нет в конфигурации
+var ErrUnknownMasterKey = errors.New("unknown master key")
+
+// Keyring мастер-ключи, которыми шифруются ключи данных. Новые ключи данных шифруются активным
+// мастер-ключом, остальные остаются в конфигурации, пока объекты не перешифрованы командой encrypt
+type Keyring struct {
+	activeID string
+	keys     map[string]cipher.AEAD
+}
+
+func NewKeyring(activeID string, keys map[string][]byte) (*Keyring, error) {
+	keyring := &Keyring{activeID: activeID, keys: make(map[string]cipher.AEAD, len(keys))}
+	for id, key := range keys {
+		if id == "" || len(id) > maxKeyIDLength {
+			return nil, fmt.Errorf("master key ID %q must be 1-%d bytes long", id, maxKeyIDLength)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid master key %q: %w", id, err)
+		}
+		keyring.keys[id] = aead
+	}
+	if _, ok := keyring.keys[activeID]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownMasterKey, activeID)
+	}
+	return keyring, nil
+}
+
+// ActiveID идентификатор мастер-ключа для новых объектов
+func (k *Keyring) ActiveID() string {
+	return k.activeID
+}
+
+// wrap шифрует ключ данных активным мастер-ключом
+func (k *Keyring) wrap(dataKey []byte) (*header, error) {
+	aead := k.keys[k.activeID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return &header{
+		keyID:      k.activeID,
+		wrappedKey: aead.Seal(nonce, nonce, dataKey, wrapAdditionalData(k.activeID)),
+	}, nil
+}
+
+// unwrap расшифровывает ключ данных из заголовка объекта
+func (k *Keyring) unwrap(h *header) ([]byte, error) {
+	aead, ok := k.keys[h.keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownMasterKey, h.keyID)
+	}
+	if len(h.wrappedKey) < aead.NonceSize() {
+		return nil, ErrCorrupted
+	}
+	nonce, sealed := h.wrappedKey[:aead.NonceSize()], h.wrappedKey[aead.NonceSize():]
+	dataKey, err := aead.Open(nil, nonce, sealed, wrapAdditionalData(h.keyID))
+	if err != nil {
+		return nil, ErrCorrupted
+	}
+	return dataKey, nil
+}
+
+// newDataKey создает ключ данных для нового объекта
+func newDataKey() ([]byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	return dataKey, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != dataKeySize {
+		return nil, fmt.Errorf("key must be %d bytes long", dataKeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// wrapAdditionalData привязывает зашифрованный ключ данных к формату и идентификатору мастер-ключа
+func wrapAdditionalData(keyID string) []byte {
+	return []byte(magic + keyID)
+}
@@ -0,0 +1,242 @@
+package envelope
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Формат зашифрованного объекта:
+//
+//	magic | len(keyID) | keyID | len(wrappedKey) | wrappedKey | сегмент...
+//
+// wrappedKey - nonce и ключ данных, зашифрованный мастер-ключом keyID. Содержимое разбито на
+// сегменты по segmentSize байт, каждый зашифрован ключом данных AES-256-GCM. Nonce сегмента -
+// его номер и признак последнего сегмента, поэтому перестановка и обрезка сегментов обнаруживаются
+const (
+	magic          = "TSENC\x00\x00\x01"
+	segmentSize    = 64 * 1024
+	dataKeySize    = 32
+	maxKeyIDLength = 255
+	gcmTagSize     = 16
+)
+
+// ErrCorrupted объект поврежден или зашифрован не тем ключом
+var ErrCorrupted = errors.New("encrypted object is corrupted")
+
+type header struct {
+	keyID      string
+	wrappedKey []byte
+}
+
+func (h *header) marshal() []byte {
+	buf := make([]byte, 0, len(magic)+2+len(h.keyID)+len(h.wrappedKey))
+	buf = append(buf, magic...)
+	buf = append(buf, byte(len(h.keyID)))
+	buf = append(buf, h.keyID...)
+	buf = append(buf, byte(len(h.wrappedKey)))
+	return append(buf, h.wrappedKey...)
+}
+
+// isEncrypted проверяет, начинается ли объект с заголовка; файлы, сохраненные до включения
+// шифрования, заголовка не имеют
+func isEncrypted(r *bufio.Reader) (bool, error) {
+	prefix, err := r.Peek(len(magic))
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+		return false, err
+	}
+	return bytes.Equal(prefix, []byte(magic)), nil
+}
+
+// readHeader читает заголовок; r должен начинаться с magic
+func readHeader(r *bufio.Reader) (*header, error) {
+	if _, err := r.Discard(len(magic)); err != nil {
+		return nil, ErrCorrupted
+	}
+	keyID, err := readField(r)
+	if err != nil {
+		return nil, err
+	}
+	wrappedKey, err := readField(r)
+	if err != nil {
+		return nil, err
+	}
+	return &header{keyID: string(keyID), wrappedKey: wrappedKey}, nil
+}
+
+func readField(r *bufio.Reader) ([]byte, error) {
+	length, err := r.ReadByte()
+	if err != nil {
+		return nil, ErrCorrupted
+	}
+	field := make([]byte, length)
+	if _, err := io.ReadFull(r, field); err != nil {
+		return nil, ErrCorrupted
+	}
+	return field, nil
+}
+
+func segmentNonce(aead cipher.AEAD, counter uint64, last bool) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce, counter)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+// encryptReader отдает заголовок и зашифрованные сегменты по мере чтения исходного файла
+type encryptReader struct {
+	src     *bufio.Reader
+	aead    cipher.AEAD
+	plain   []byte
+	sealed  []byte
+	out     []byte
+	counter uint64
+	done    bool
+}
+
+// encryptedSize размер зашифрованного объекта с заголовком длиной headerLen и содержимым size байт:
+// каждый сегмент, в том числе единственный пустой, дополняется тегом AES-GCM
+func encryptedSize(headerLen int, size int64) int64 {
+	segments := max(1, (size+segmentSize-1)/segmentSize)
+	return int64(headerLen) + size + segments*gcmTagSize
+}
+
+// sizedReader сообщает хранилищу размер потока, известный заранее
+type sizedReader struct {
+	io.Reader
+	size int64
+}
+
+func (r *sizedReader) Size() int64 {
+	return r.size
+}
+
+func newEncryptReader(src io.Reader, h *header, dataKey []byte) (*encryptReader, error) {
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptReader{
+		src:    bufio.NewReaderSize(src, segmentSize),
+		aead:   aead,
+		plain:  make([]byte, segmentSize),
+		sealed: make([]byte, 0, segmentSize+aead.Overhead()),
+		out:    h.marshal(),
+	}, nil
+}
+
+func (r *encryptReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.nextSegment(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+func (r *encryptReader) nextSegment() error {
+	n, err := io.ReadFull(r.src, r.plain)
+	last := false
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case err != nil:
+		return err
+	default:
+		// Сегмент полный: последний ли он, становится известно только по следующему байту
+		if _, err := r.src.Peek(1); err != nil {
+			if !errors.Is(err, io.EOF) {
+				return err
+			}
+			last = true
+		}
+	}
+
+	r.out = r.aead.Seal(r.sealed[:0], segmentNonce(r.aead, r.counter, last), r.plain[:n], nil)
+	r.counter++
+	r.done = last
+	return nil
+}
+
+// decryptReader расшифровывает сегменты по мере чтения; поврежденный или обрезанный объект
+// дает ErrCorrupted вместо данных
+type decryptReader struct {
+	src     *bufio.Reader
+	aead    cipher.AEAD
+	sealed  []byte
+	plain   []byte
+	out     []byte
+	counter uint64
+	done    bool
+}
+
+func newDecryptReader(src *bufio.Reader, dataKey []byte) (*decryptReader, error) {
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{
+		src:    src,
+		aead:   aead,
+		sealed: make([]byte, segmentSize+aead.Overhead()),
+		plain:  make([]byte, 0, segmentSize),
+	}, nil
+}
+
+func (r *decryptReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.nextSegment(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+func (r *decryptReader) nextSegment() error {
+	n, err := io.ReadFull(r.src, r.sealed)
+	last := false
+	switch {
+	case errors.Is(err, io.EOF):
+		// Последний сегмент есть всегда, даже у пустого файла
+		return ErrCorrupted
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case err != nil:
+		return err
+	default:
+		if _, err := r.src.Peek(1); err != nil {
+			if !errors.Is(err, io.EOF) {
+				return err
+			}
+			last = true
+		}
+	}
+
+	plain, err := r.aead.Open(r.plain[:0], segmentNonce(r.aead, r.counter, last), r.sealed[:n], nil)
+	if err != nil {
+		return fmt.Errorf("%w: segment %d", ErrCorrupted, r.counter)
+	}
+	r.out = plain
+	r.counter++
+	r.done = last
+	return nil
+}
@@ -0,0 +1,182 @@
+// Package local хранит файлы на локальном диске и отдает их по подписанным ссылкам через сам сервис
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ticket-service/internal/domain/services"
+	"ticket-service/internal/infrastructure/storage/signedurl"
+	"ticket-service/internal/logger"
+)
+
+type localService struct {
+	root   string
+	signer *signedurl.Signer
+}
+
+// NewLocalService создает хранилище в каталоге root, создавая его при необходимости
+func NewLocalService(root string, signer *signedurl.Signer) (services.IFileService, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve storage directory: %w", err)
+	}
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	logger.Info("Local file storage initialized", "root", root)
+	return &localService{root: root, signer: signer}, nil
+}
+
+// UploadFile записывает файл атомарно: сначала во временный файл в том же каталоге, затем переименованием
+func (s *localService) UploadFile(ctx context.Context, file io.Reader, folder string, id string) (string, error) {
+	key := folder + "/" + id
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	// После успешного переименования удалять уже нечего
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, io.LimitReader(file, services.MaxStoredFileSize+1))
+	if err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if written > services.MaxStoredFileSize {
+		tmp.Close()
+		return "", services.ErrFileTooLarge
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to sync file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to close file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to move file into place: %w", err)
+	}
+
+	logger.Info("File uploaded successfully", "key", key, "size", written)
+	return s.signer.URL(key), nil
+}
+
+func (s *localService) DownloadFile(ctx context.Context, fileURL string) (io.ReadCloser, error) {
+	path, err := s.pathFromURL(fileURL)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, services.ErrFileNotFound
+		}
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return file, nil
+}
+
+func (s *localService) DeleteFile(ctx context.Context, fileURL string) error {
+	path, err := s.pathFromURL(fileURL)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return services.ErrFileNotFound
+		}
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	logger.Info("File deleted successfully", "path", path)
+	return nil
+}
+
+func (s *localService) GetFileURL(ctx context.Context, fileURL string) (string, error) {
+	key, err := s.signer.Key(fileURL)
+	if err != nil {
+		return "", err
+	}
+	return s.signer.URL(key), nil
+}
+
+func (s *localService) CheckFileExists(ctx context.Context, fileURL string) (bool, error) {
+	path, err := s.pathFromURL(fileURL)
+	if err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check file existence: %w", err)
+	}
+	return true, nil
+}
+
+func (s *localService) pathFromURL(fileURL string) (string, error) {
+	key, err := s.signer.Key(fileURL)
+	if err != nil {
+		return "", err
+	}
+	return s.path(key)
+}
+
+// path переводит ключ в путь внутри root; ключи, выходящие за его пределы, отклоняются
+func (s *localService) path(key string) (string, error) {
+	path := filepath.Join(s.root, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, s.root+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid file key %q", key)
+	}
+	return path, nil
+}
+
+func (s *localService) ObjectKey(fileURL string) (string, error) {
+	return s.signer.Key(fileURL)
+}
+
+// ListObjects перечисляет файлы хранилища, пропуская незавершенные загрузки и временные файлы
+func (s *localService) ListObjects(ctx context.Context) ([]string, error) {
+	keys := make([]string, 0)
+	err := filepath.WalkDir(s.root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if entry.Name() == uploadsDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(entry.Name(), ".upload-") {
+			return nil
+		}
+		key, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, filepath.ToSlash(key))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	return keys, nil
+}
@@ -0,0 +1,129 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/services"
+)
+
+// uploadsDir каталог незавершенных загрузок внутри корня хранилища
+const uploadsDir = ".uploads"
+
+type uploadStore struct {
+	dir string
+}
+
+// NewUploadStore хранит возобновляемые загрузки файлами в root/.uploads и дописывает части в конец файла
+func NewUploadStore(root string) (services.IUploadStore, error) {
+	dir, err := filepath.Abs(filepath.Join(root, uploadsDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve uploads directory: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create uploads directory: %w", err)
+	}
+	return &uploadStore{dir: dir}, nil
+}
+
+func (s *uploadStore) Begin(ctx context.Context, upload *models.Upload) error {
+	path, err := s.path(upload.ID)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to create upload file: %w", err)
+	}
+	return file.Close()
+}
+
+func (s *uploadStore) Append(ctx context.Context, upload *models.Upload, data io.Reader) error {
+	path, err := s.path(upload.ID)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open upload file: %w", err)
+	}
+	defer file.Close()
+
+	// Байты после сохраненного смещения могли остаться от прерванной записи
+	if err := file.Truncate(upload.Offset); err != nil {
+		return fmt.Errorf("failed to truncate upload file: %w", err)
+	}
+	if _, err := file.Seek(upload.Offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek upload file: %w", err)
+	}
+
+	written, copyErr := io.Copy(file, data)
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync upload file: %w", err)
+	}
+	upload.Offset += written
+	return copyErr
+}
+
+func (s *uploadStore) Finish(ctx context.Context, upload *models.Upload) error {
+	return nil
+}
+
+func (s *uploadStore) Open(ctx context.Context, upload *models.Upload) (io.ReadCloser, error) {
+	path, err := s.path(upload.ID)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, services.ErrFileNotFound
+		}
+		return nil, fmt.Errorf("failed to open upload file: %w", err)
+	}
+	return file, nil
+}
+
+func (s *uploadStore) Remove(ctx context.Context, upload *models.Upload) error {
+	path, err := s.path(upload.ID)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete upload file: %w", err)
+	}
+	return nil
+}
+
+func (s *uploadStore) List(ctx context.Context, before time.Time) ([]*models.Upload, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list uploads: %w", err)
+	}
+
+	uploads := make([]*models.Upload, 0)
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || !info.ModTime().Before(before) {
+			continue
+		}
+		uploads = append(uploads, &models.Upload{ID: entry.Name()})
+	}
+	return uploads, nil
+}
+
+// path отклоняет ID, не являющиеся UUID, чтобы ID из запроса не вывел за пределы каталога
+func (s *uploadStore) path(id string) (string, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return "", fmt.Errorf("invalid upload ID %q", id)
+	}
+	return filepath.Join(s.dir, id), nil
+}
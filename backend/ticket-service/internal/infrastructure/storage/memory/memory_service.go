@@ -0,0 +1,106 @@
+// Package memory хранит файлы в памяти процесса; предназначен для тестов и локальной разработки
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"ticket-service/internal/domain/services"
+	"ticket-service/internal/infrastructure/storage/signedurl"
+)
+
+type memoryService struct {
+	mu     sync.RWMutex
+	files  map[string][]byte
+	signer *signedurl.Signer
+}
+
+func NewMemoryService(signer *signedurl.Signer) services.IFileService {
+	return &memoryService{
+		files:  make(map[string][]byte),
+		signer: signer,
+	}
+}
+
+func (s *memoryService) UploadFile(ctx context.Context, file io.Reader, folder string, id string) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(file, services.MaxStoredFileSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(data) > services.MaxStoredFileSize {
+		return "", services.ErrFileTooLarge
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := folder + "/" + id
+	s.files[key] = data
+	return s.signer.URL(key), nil
+}
+
+func (s *memoryService) DownloadFile(ctx context.Context, fileURL string) (io.ReadCloser, error) {
+	key, err := s.signer.Key(fileURL)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, ok := s.files[key]
+	if !ok {
+		return nil, services.ErrFileNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memoryService) DeleteFile(ctx context.Context, fileURL string) error {
+	key, err := s.signer.Key(fileURL)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.files[key]; !ok {
+		return services.ErrFileNotFound
+	}
+	delete(s.files, key)
+	return nil
+}
+
+func (s *memoryService) GetFileURL(ctx context.Context, fileURL string) (string, error) {
+	key, err := s.signer.Key(fileURL)
+	if err != nil {
+		return "", err
+	}
+	return s.signer.URL(key), nil
+}
+
+func (s *memoryService) CheckFileExists(ctx context.Context, fileURL string) (bool, error) {
+	key, err := s.signer.Key(fileURL)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.files[key]
+	return ok, nil
+}
+
+func (s *memoryService) ObjectKey(fileURL string) (string, error) {
+	return s.signer.Key(fileURL)
+}
+
+func (s *memoryService) ListObjects(ctx context.Context) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0, len(s.files))
+	for key := range s.files {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/services"
+)
+
+type memoryUpload struct {
+	data      []byte
+	updatedAt time.Time
+}
+
+// uploadReader отдает загрузку с произвольным доступом, как файл локального хранилища
+type uploadReader struct {
+	*bytes.Reader
+}
+
+func (uploadReader) Close() error {
+	return nil
+}
+
+type uploadStore struct {
+	mu      sync.Mutex
+	uploads map[string]*memoryUpload
+}
+
+func NewUploadStore() services.IUploadStore {
+	return &uploadStore{uploads: make(map[string]*memoryUpload)}
+}
+
+func (s *uploadStore) Begin(ctx context.Context, upload *models.Upload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.uploads[upload.ID] = &memoryUpload{updatedAt: time.Now()}
+	return nil
+}
+
+func (s *uploadStore) Append(ctx context.Context, upload *models.Upload, data io.Reader) error {
+	// Читаем вне блокировки: тело запроса может приходить долго
+	chunk, readErr := io.ReadAll(data)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.uploads[upload.ID]
+	if !ok {
+		return services.ErrFileNotFound
+	}
+	stored.data = append(stored.data[:upload.Offset], chunk...)
+	stored.updatedAt = time.Now()
+	upload.Offset += int64(len(chunk))
+	return readErr
+}
+
+func (s *uploadStore) Finish(ctx context.Context, upload *models.Upload) error {
+	return nil
+}
+
+func (s *uploadStore) Open(ctx context.Context, upload *models.Upload) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.uploads[upload.ID]
+	if !ok {
+		return nil, services.ErrFileNotFound
+	}
+	return uploadReader{bytes.NewReader(stored.data)}, nil
+}
+
+func (s *uploadStore) Remove(ctx context.Context, upload *models.Upload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.uploads, upload.ID)
+	return nil
+}
+
+func (s *uploadStore) List(ctx context.Context, before time.Time) ([]*models.Upload, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	uploads := make([]*models.Upload, 0)
+	for id, stored := range s.uploads {
+		if stored.updatedAt.Before(before) {
+			uploads = append(uploads, &models.Upload{ID: id})
+		}
+	}
+	return uploads, nil
+}
@@ -1,14 +1,18 @@
 package s3
 
-import "fmt"
+import (
+	"fmt"
+
+	"ticket-service/internal/domain/services"
+)
 
 const (
-	MaxFileSize = 100 * 1024 * 1024 // 100 MB
+	MaxFileSize = services.MaxFileSize
 )
 
 var (
-	ErrFileTooLarge = fmt.Errorf("file size exceeds maximum allowed size of %d MB", MaxFileSize/1024/1024)
-	ErrFileNotFound = fmt.Errorf("file not found")
+	ErrFileTooLarge = services.ErrFileTooLarge
+	ErrFileNotFound = services.ErrFileNotFound
 	ErrBucketNotFound = fmt.Errorf("bucket not found")
 	ErrInvalidCredentials = fmt.Errorf("invalid credentials")
 	ErrConnectionFailed = fmt.Errorf("failed to connect to S3")
@@ -1,10 +1,12 @@
 package s3
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/minio/minio-go/v7"
@@ -65,24 +67,32 @@ func NewS3Service(cfg *Config) (services.IFileService, error) {
 func (s *s3Service) UploadFile(ctx context.Context, file io.Reader, folder string, id string) (string, error) {
 	logger.Info("Starting file upload", "folder", folder, "id", id)
 
-	// Проверяем размер файла
-	fileSize, err := getFileSize(file)
-	if err != nil {
-		logger.Error("Failed to get file size", "error", err)
-		return "", fmt.Errorf("failed to get file size: %w", err)
-	}
-
-	if fileSize > MaxFileSize {
-		logger.Warn("File too large", "size", fileSize, "maxSize", MaxFileSize)
-		return "", ErrFileTooLarge
+	// PutObject нужен размер. Вложения передаются потоком, знающим свой размер, и загружаются
+	// без копии в памяти; остальные потоки читаются целиком, потому что прочитанный для подсчета поток уже пуст
+	var body io.Reader
+	var fileSize int64
+	if sized, ok := file.(interface{ Size() int64 }); ok {
+		fileSize = sized.Size()
+		if fileSize > services.MaxStoredFileSize {
+			logger.Warn("File too large", "size", fileSize, "maxSize", services.MaxStoredFileSize)
+			return "", ErrFileTooLarge
+		}
+		body = file
+	} else {
+		data, err := readFile(file)
+		if err != nil {
+			logger.Error("Failed to read file", "error", err)
+			return "", err
+		}
+		body, fileSize = bytes.NewReader(data), int64(len(data))
 	}
 
-	// Генерируем уникальное имя файла
-	objectName := fmt.Sprintf("%s/%s/%s", folder, id, time.Now().Format("20060102150405"))
+	// Уникальность ключа обеспечивает вызывающий: вложения хранятся по sha256 содержимого
+	objectName := fmt.Sprintf("%s/%s", folder, id)
 	logger.Info("Generated object name", "objectName", objectName)
 
 	// Загружаем файл
-	_, err = s.client.PutObject(ctx, s.bucketName, objectName, file, fileSize, minio.PutObjectOptions{})
+	_, err := s.client.PutObject(ctx, s.bucketName, objectName, body, fileSize, minio.PutObjectOptions{})
 	if err != nil {
 		logger.Error("Failed to upload file", "objectName", objectName, "error", err)
 		return "", fmt.Errorf("%w: %v", ErrUploadFailed, err)
@@ -102,16 +112,10 @@ func (s *s3Service) UploadFile(ctx context.Context, file io.Reader, folder strin
 func (s *s3Service) DeleteFile(ctx context.Context, fileURL string) error {
 	logger.Info("Starting file deletion", "fileURL", fileURL)
 
-	// Извлекаем путь к файлу из URL
-	parsedURL, err := url.Parse(fileURL)
+	filepath, err := s.objectKey(fileURL)
 	if err != nil {
 		logger.Error("Failed to parse file URL", "fileURL", fileURL, "error", err)
-		return fmt.Errorf("failed to parse file URL: %w", err)
-	}
-
-	filepath := parsedURL.Path
-	if filepath[0] == '/' {
-		filepath = filepath[1:]
+		return err
 	}
 
 	// Проверяем существование файла
@@ -174,30 +178,66 @@ func (s *s3Service) CheckFileExists(ctx context.Context, filepath string) (bool,
 	return true, nil
 }
 
-// getFileSize возвращает размер файла
-func getFileSize(reader io.Reader) (int64, error) {
+// readFile читает файл не больше MaxStoredFileSize
+func readFile(reader io.Reader) ([]byte, error) {
 	// Создаем ограниченный ридер для проверки размера
-	limitedReader := io.LimitReader(reader, MaxFileSize+1)
-
-	// Читаем весь файл для подсчета размера
-	size, err := io.Copy(io.Discard, limitedReader)
+	data, err := io.ReadAll(io.LimitReader(reader, services.MaxStoredFileSize+1))
 	if err != nil {
-		return 0, fmt.Errorf("failed to read file: %w", err)
+		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
 	// Если размер больше максимального, возвращаем ошибку
-	if size > MaxFileSize {
-		return 0, ErrFileTooLarge
+	if len(data) > services.MaxStoredFileSize {
+		logger.Warn("File too large", "size", len(data), "maxSize", services.MaxStoredFileSize)
+		return nil, ErrFileTooLarge
 	}
 
-	return size, nil
+	return data, nil
 }
 
-// DownloadFile скачивает файл из S3
-func (s *s3Service) DownloadFile(ctx context.Context, filepath string) (io.ReadCloser, error) {
+// ObjectKey возвращает ключ объекта по presigned URL или ключу
+func (s *s3Service) ObjectKey(fileURL string) (string, error) {
+	return s.objectKey(fileURL)
+}
+
+// objectKey извлекает ключ объекта из сохраненного в тикете URL; ключ без схемы возвращается как есть
+func (s *s3Service) objectKey(fileURL string) (string, error) {
+	parsedURL, err := url.Parse(fileURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse file URL: %w", err)
+	}
+	if parsedURL.Scheme == "" {
+		return strings.TrimPrefix(fileURL, "/"), nil
+	}
+
+	// Presigned URL в path-style содержит имя бакета перед ключом объекта
+	filepath := strings.TrimPrefix(parsedURL.Path, "/")
+	return strings.TrimPrefix(filepath, s.bucketName+"/"), nil
+}
+
+// DownloadFile скачивает файл из S3 по ключу объекта или URL, выданному UploadFile
+func (s *s3Service) DownloadFile(ctx context.Context, fileURL string) (io.ReadCloser, error) {
+	filepath, err := s.objectKey(fileURL)
+	if err != nil {
+		return nil, err
+	}
 	result, err := s.client.GetObject(ctx, s.bucketName, filepath, minio.GetObjectOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get object from S3: %w", err)
 	}
 	return result, nil
-} 
\ No newline at end of file
+} 
+// ListObjects перечисляет вложения в бакете; незавершенные загрузки uploads/ в список не входят
+func (s *s3Service) ListObjects(ctx context.Context) ([]string, error) {
+	keys := make([]string, 0)
+	for object := range s.client.ListObjects(ctx, s.bucketName, minio.ListObjectsOptions{Recursive: true}) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", object.Err)
+		}
+		if strings.HasPrefix(object.Key, uploadPrefix) {
+			continue
+		}
+		keys = append(keys, object.Key)
+	}
+	return keys, nil
+}
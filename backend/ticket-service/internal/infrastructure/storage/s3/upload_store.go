@@ -0,0 +1,196 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/services"
+)
+
+const (
+	uploadPrefix = "uploads/"
+	// uploadPartSize минимальный размер части multipart upload в S3, кроме последней
+	uploadPartSize = 5 * 1024 * 1024
+	// pendingSuffix объект с хвостом загрузки, которого пока не хватает на целую часть
+	pendingSuffix = ".pending"
+)
+
+type uploadStore struct {
+	core       minio.Core
+	bucketName string
+}
+
+// NewUploadStore создает хранилище возобновляемых загрузок на multipart upload. Части меньше
+// uploadPartSize S3 не принимает, поэтому хвост каждого запроса хранится отдельным объектом
+// и дописывается к следующей части
+func NewUploadStore(cfg *Config) (services.IUploadStore, error) {
+	core, err := minio.NewCore(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure: cfg.UseSSL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrConnectionFailed, err)
+	}
+	return &uploadStore{core: *core, bucketName: cfg.BucketName}, nil
+}
+
+func (s *uploadStore) Begin(ctx context.Context, upload *models.Upload) error {
+	storageID, err := s.core.NewMultipartUpload(ctx, s.bucketName, uploadPrefix+upload.ID, minio.PutObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to start multipart upload: %w", err)
+	}
+	upload.StorageID = storageID
+	return nil
+}
+
+func (s *uploadStore) Append(ctx context.Context, upload *models.Upload, data io.Reader) error {
+	var pending []byte
+	if upload.PendingSize > 0 {
+		object, _, _, err := s.core.GetObject(ctx, s.bucketName, s.pendingKey(upload), minio.GetObjectOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to read pending upload data: %w", err)
+		}
+		pending, err = io.ReadAll(object)
+		object.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read pending upload data: %w", err)
+		}
+		if int64(len(pending)) != upload.PendingSize {
+			return fmt.Errorf("pending upload data has %d bytes, expected %d", len(pending), upload.PendingSize)
+		}
+	}
+
+	reader := io.MultiReader(bytes.NewReader(pending), data)
+	stored := upload.Offset - upload.PendingSize
+	buf := make([]byte, uploadPartSize)
+	for {
+		n, readErr := io.ReadFull(reader, buf)
+		chunk := buf[:n]
+		if readErr != nil && !errors.Is(readErr, io.EOF) && !errors.Is(readErr, io.ErrUnexpectedEOF) {
+			// Соединение оборвалось: сохраняем прочитанное, чтобы клиент продолжил с этого места
+			if err := s.savePending(ctx, upload, stored, chunk); err != nil {
+				return err
+			}
+			return readErr
+		}
+
+		if n == uploadPartSize || (n > 0 && stored+int64(n) == upload.Length) {
+			part, err := s.core.PutObjectPart(ctx, s.bucketName, uploadPrefix+upload.ID, upload.StorageID,
+				len(upload.Parts)+1, bytes.NewReader(chunk), int64(n), minio.PutObjectPartOptions{})
+			if err != nil {
+				// Неотправленная часть сохраняется как хвост, полученное ранее не теряется
+				if saveErr := s.savePending(ctx, upload, stored, chunk); saveErr != nil {
+					return saveErr
+				}
+				return fmt.Errorf("%w: %v", ErrUploadFailed, err)
+			}
+			upload.Parts = append(upload.Parts, models.UploadPart{Number: part.PartNumber, ETag: part.ETag, Size: int64(n)})
+			stored += int64(n)
+			chunk = nil
+		}
+
+		if n < uploadPartSize || stored == upload.Length {
+			return s.savePending(ctx, upload, stored, chunk)
+		}
+	}
+}
+
+// savePending сохраняет хвост загрузки, идущий после stored байт, отправленных частями
+func (s *uploadStore) savePending(ctx context.Context, upload *models.Upload, stored int64, tail []byte) error {
+	if len(tail) > 0 {
+		_, err := s.core.PutObject(ctx, s.bucketName, s.pendingKey(upload), bytes.NewReader(tail), int64(len(tail)), "", "", minio.PutObjectOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to save pending upload data: %w", err)
+		}
+	} else if upload.PendingSize > 0 {
+		if err := s.core.RemoveObject(ctx, s.bucketName, s.pendingKey(upload), minio.RemoveObjectOptions{}); err != nil {
+			return fmt.Errorf("failed to remove pending upload data: %w", err)
+		}
+	}
+
+	upload.PendingSize = int64(len(tail))
+	upload.Offset = stored + upload.PendingSize
+	return nil
+}
+
+func (s *uploadStore) Finish(ctx context.Context, upload *models.Upload) error {
+	parts := make([]minio.CompletePart, 0, len(upload.Parts))
+	for _, part := range upload.Parts {
+		parts = append(parts, minio.CompletePart{PartNumber: part.Number, ETag: part.ETag})
+	}
+	_, err := s.core.CompleteMultipartUpload(ctx, s.bucketName, uploadPrefix+upload.ID, upload.StorageID, parts, minio.PutObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	return nil
+}
+
+func (s *uploadStore) Open(ctx context.Context, upload *models.Upload) (io.ReadCloser, error) {
+	object, _, _, err := s.core.GetObject(ctx, s.bucketName, uploadPrefix+upload.ID, minio.GetObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrFileNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", ErrDownloadFailed, err)
+	}
+	return object, nil
+}
+
+func (s *uploadStore) Remove(ctx context.Context, upload *models.Upload) error {
+	if upload.StorageID != "" {
+		err := s.core.AbortMultipartUpload(ctx, s.bucketName, uploadPrefix+upload.ID, upload.StorageID)
+		if err != nil && minio.ToErrorResponse(err).Code != "NoSuchUpload" {
+			return fmt.Errorf("failed to abort multipart upload: %w", err)
+		}
+	}
+	for _, key := range []string{uploadPrefix + upload.ID, s.pendingKey(upload)} {
+		if err := s.core.RemoveObject(ctx, s.bucketName, key, minio.RemoveObjectOptions{}); err != nil {
+			return fmt.Errorf("%w: %v", ErrDeleteFailed, err)
+		}
+	}
+	return nil
+}
+
+// List находит незавершенные multipart upload и собранные, но не прикрепленные файлы
+func (s *uploadStore) List(ctx context.Context, before time.Time) ([]*models.Upload, error) {
+	uploads := make(map[string]*models.Upload)
+
+	for info := range s.core.ListIncompleteUploads(ctx, s.bucketName, uploadPrefix, true) {
+		if info.Err != nil {
+			return nil, fmt.Errorf("failed to list multipart uploads: %w", info.Err)
+		}
+		if info.Initiated.Before(before) {
+			id := strings.TrimPrefix(info.Key, uploadPrefix)
+			uploads[id] = &models.Upload{ID: id, StorageID: info.UploadID}
+		}
+	}
+
+	for object := range s.core.Client.ListObjects(ctx, s.bucketName, minio.ListObjectsOptions{Prefix: uploadPrefix, Recursive: true}) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("failed to list uploads: %w", object.Err)
+		}
+		id := strings.TrimSuffix(strings.TrimPrefix(object.Key, uploadPrefix), pendingSuffix)
+		if _, ok := uploads[id]; !ok && object.LastModified.Before(before) {
+			uploads[id] = &models.Upload{ID: id}
+		}
+	}
+
+	result := make([]*models.Upload, 0, len(uploads))
+	for _, upload := range uploads {
+		result = append(result, upload)
+	}
+	return result, nil
+}
+
+func (s *uploadStore) pendingKey(upload *models.Upload) string {
+	return uploadPrefix + upload.ID + pendingSuffix
+}
@@ -0,0 +1,83 @@
+// Package signedurl подписывает ссылки на скачивание файлов, которые отдает сам сервис
+package signedurl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid download link signature")
+	ErrExpired          = errors.New("download link has expired")
+)
+
+// Signer выдает ссылки вида <baseURL>/<key>?expires=<unix>&signature=<hmac>, действующие ttl
+type Signer struct {
+	secret  []byte
+	baseURL string
+	ttl     time.Duration
+}
+
+func NewSigner(secret, baseURL string, ttl time.Duration) *Signer {
+	return &Signer{
+		secret:  []byte(secret),
+		baseURL: strings.TrimRight(baseURL, "/"),
+		ttl:     ttl,
+	}
+}
+
+// URL возвращает подписанную ссылку на файл с ключом key
+func (s *Signer) URL(key string) string {
+	expires := time.Now().Add(s.ttl).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.sign(key, expires))
+	return fmt.Sprintf("%s/%s?%s", s.baseURL, (&url.URL{Path: key}).EscapedPath(), query.Encode())
+}
+
+// Verify проверяет подпись и срок действия ссылки
+func (s *Signer) Verify(key string, expires int64, signature string) error {
+	if !hmac.Equal([]byte(signature), []byte(s.sign(key, expires))) {
+		return ErrInvalidSignature
+	}
+	if time.Now().Unix() > expires {
+		return ErrExpired
+	}
+	return nil
+}
+
+// Key извлекает ключ файла из ссылки, выданной URL; значение без схемы считается ключом
+func (s *Signer) Key(fileURL string) (string, error) {
+	parsed, err := url.Parse(fileURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse file URL: %w", err)
+	}
+	if parsed.Scheme == "" {
+		return strings.TrimPrefix(parsed.Path, "/"), nil
+	}
+
+	base, err := url.Parse(s.baseURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse base URL: %w", err)
+	}
+	key, ok := strings.CutPrefix(parsed.Path, base.Path+"/")
+	if !ok {
+		return "", fmt.Errorf("file URL %q does not belong to this storage", fileURL)
+	}
+	return key, nil
+}
+
+func (s *Signer) sign(key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,128 @@
+package transcript
+
+import (
+	_ "embed"
+	"fmt"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// Шрифты DejaVu покрывают кириллицу вместе с буквами казахского алфавита; лицензия в fonts/LICENSE
+var (
+	//go:embed fonts/DejaVuSans.ttf
+	regularTTF []byte
+	//go:embed fonts/DejaVuSans-Bold.ttf
+	boldTTF []byte
+)
+
+// fontFace разобранный TrueType-шрифт; общий для всех документов и только читается
+type fontFace struct {
+	name       string
+	data       []byte
+	font       *sfnt.Font
+	unitsPerEm int
+	// ascent, descent, capHeight и bbox в тысячных долях кегля, как их ждет PDF
+	ascent    int
+	descent   int
+	capHeight int
+	bbox      [4]int
+}
+
+func parseFace(name string, data []byte) (*fontFace, error) {
+	f, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font %s: %w", name, err)
+	}
+
+	face := &fontFace{name: name, data: data, font: f, unitsPerEm: int(f.UnitsPerEm())}
+	var buf sfnt.Buffer
+	ppem := fixed.I(face.unitsPerEm)
+	metrics, err := f.Metrics(&buf, ppem, font.HintingNone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics of font %s: %w", name, err)
+	}
+	bounds, err := f.Bounds(&buf, ppem, font.HintingNone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bounds of font %s: %w", name, err)
+	}
+
+	// sfnt направляет ось Y вниз, в PDF она направлена вверх
+	face.ascent = face.scale(metrics.Ascent)
+	face.descent = -face.scale(metrics.Descent)
+	face.capHeight = face.scale(metrics.CapHeight)
+	face.bbox = [4]int{face.scale(bounds.Min.X), -face.scale(bounds.Max.Y), face.scale(bounds.Max.X), -face.scale(bounds.Min.Y)}
+	return face, nil
+}
+
+// scale переводит значение в единицах шрифта в тысячные доли кегля
+func (f *fontFace) scale(v fixed.Int26_6) int {
+	return v.Round() * 1000 / f.unitsPerEm
+}
+
+// fontUse шрифт в одном документе: запоминает использованные глифы для подмножества
+// и таблицы ToUnicode. Не используется из нескольких горутин
+type fontUse struct {
+	face   *fontFace
+	buf    sfnt.Buffer
+	glyphs map[rune]uint16
+	widths map[uint16]int
+	// runes символ, которым глиф впервые встретился в тексте
+	runes map[uint16]rune
+}
+
+func newFontUse(face *fontFace) *fontUse {
+	return &fontUse{
+		face:   face,
+		glyphs: make(map[rune]uint16),
+		widths: make(map[uint16]int),
+		runes:  make(map[uint16]rune),
+	}
+}
+
+// glyph возвращает глиф символа и его ширину в тысячных долях кегля; символы, которых
+// нет в шрифте, заменяются глифом .notdef
+func (u *fontUse) glyph(r rune) (uint16, int) {
+	if gid, ok := u.glyphs[r]; ok {
+		return gid, u.widths[gid]
+	}
+
+	index, err := u.face.font.GlyphIndex(&u.buf, r)
+	if err != nil {
+		index = 0
+	}
+	gid := uint16(index)
+	if _, ok := u.widths[gid]; !ok {
+		advance, err := u.face.font.GlyphAdvance(&u.buf, index, fixed.I(u.face.unitsPerEm), font.HintingNone)
+		if err == nil {
+			u.widths[gid] = u.face.scale(advance)
+		}
+	}
+	if _, ok := u.runes[gid]; !ok && gid != 0 {
+		u.runes[gid] = r
+	}
+	u.glyphs[r] = gid
+	return gid, u.widths[gid]
+}
+
+// width ширина строки в пунктах при кегле size
+func (u *fontUse) width(s string, size float64) float64 {
+	total := 0
+	for _, r := range s {
+		_, w := u.glyph(r)
+		total += w
+	}
+	return float64(total) * size / 1000
+}
+
+// encode кодирует строку номерами глифов для шрифта с кодировкой Identity-H
+func (u *fontUse) encode(s string) string {
+	out := make([]byte, 0, len(s)*4+2)
+	out = append(out, '<')
+	for _, r := range s {
+		gid, _ := u.glyph(r)
+		out = fmt.Appendf(out, "%04X", gid)
+	}
+	return string(append(out, '>'))
+}
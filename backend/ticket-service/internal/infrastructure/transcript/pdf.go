@@ -0,0 +1,205 @@
+package transcript
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// document минимальный PDF 1.7: страницы A4 с текстом и линиями и встроенные подмножества
+// TrueType-шрифтов в кодировке Identity-H
+type document struct {
+	fonts []*fontUse
+	pages []*bytes.Buffer
+
+	title   string
+	created time.Time
+}
+
+// page содержимое одной страницы в операторах PDF
+type page struct {
+	content *bytes.Buffer
+	doc     *document
+}
+
+func (d *document) addPage() *page {
+	content := new(bytes.Buffer)
+	d.pages = append(d.pages, content)
+	return &page{content: content, doc: d}
+}
+
+func (d *document) fontID(f *fontUse) int {
+	for i, candidate := range d.fonts {
+		if candidate == f {
+			return i + 1
+		}
+	}
+	d.fonts = append(d.fonts, f)
+	return len(d.fonts)
+}
+
+// text выводит строку от точки (x, y) на базовой линии
+func (p *page) text(f *fontUse, size, x, y float64, gray float64, s string) {
+	fmt.Fprintf(p.content, "BT %.2f g /F%d %.1f Tf %.2f %.2f Td %s Tj ET\n", gray, p.doc.fontID(f), size, x, y, f.encode(s))
+}
+
+// line проводит горизонтальную линию
+func (p *page) line(x1, x2, y, width, gray float64) {
+	fmt.Fprintf(p.content, "%.2f G %.2f w %.2f %.2f m %.2f %.2f l S\n", gray, width, x1, y, x2, y)
+}
+
+// pdfWriter нумерует объекты и запоминает их смещения для таблицы xref
+type pdfWriter struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+func (w *pdfWriter) reserve() int {
+	w.offsets = append(w.offsets, 0)
+	return len(w.offsets)
+}
+
+func (w *pdfWriter) object(n int, body string) {
+	w.offsets[n-1] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", n, body)
+}
+
+func (w *pdfWriter) stream(n int, dict string, data []byte) {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write(data)
+	zw.Close()
+
+	w.offsets[n-1] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n<< %s /Filter /FlateDecode /Length %d >>\nstream\n", n, dict, compressed.Len())
+	w.buf.Write(compressed.Bytes())
+	w.buf.WriteString("\nendstream\nendobj\n")
+}
+
+// bytes собирает файл PDF
+func (d *document) bytes() ([]byte, error) {
+	w := &pdfWriter{}
+	w.buf.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+
+	catalog, pagesRoot, info := w.reserve(), w.reserve(), w.reserve()
+
+	fontRefs := make([]string, len(d.fonts))
+	for i, f := range d.fonts {
+		ref, err := d.writeFont(w, f)
+		if err != nil {
+			return nil, err
+		}
+		fontRefs[i] = fmt.Sprintf("/F%d %d 0 R", i+1, ref)
+	}
+	resources := "<< /Font << " + strings.Join(fontRefs, " ") + " >> >>"
+
+	kids := make([]string, len(d.pages))
+	for i, content := range d.pages {
+		contentRef, pageRef := w.reserve(), w.reserve()
+		w.stream(contentRef, "", content.Bytes())
+		w.object(pageRef, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.2f %.2f] /Resources %s /Contents %d 0 R >>",
+			pagesRoot, pageWidth, pageHeight, resources, contentRef))
+		kids[i] = fmt.Sprintf("%d 0 R", pageRef)
+	}
+
+	w.object(pagesRoot, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	w.object(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesRoot))
+	created := d.created.UTC().Format("D:20060102150405Z")
+	w.object(info, fmt.Sprintf("<< /Title %s /Producer (ticket-service) /CreationDate (%s) >>", textString(d.title), created))
+
+	xref := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, offset := range w.offsets {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", offset)
+	}
+	id := sha256.Sum256(w.buf.Bytes())
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R /ID [<%x> <%x>] >>\nstartxref\n%d\n%%%%EOF\n",
+		len(w.offsets)+1, catalog, info, id[:16], id[:16], xref)
+	return w.buf.Bytes(), nil
+}
+
+// writeFont записывает шрифт Type0 с потомком CIDFontType2 и возвращает номер его объекта
+func (d *document) writeFont(w *pdfWriter, f *fontUse) (int, error) {
+	used := make(map[uint16]bool, len(f.widths))
+	gids := make([]int, 0, len(f.widths))
+	for gid := range f.widths {
+		used[gid] = true
+		gids = append(gids, int(gid))
+	}
+	sort.Ints(gids)
+
+	data, err := subsetFont(f.face.data, used)
+	if err != nil {
+		return 0, fmt.Errorf("failed to subset font %s: %w", f.face.name, err)
+	}
+
+	// Префикс подмножества по ISO 32000-1, 9.6.4: шесть заглавных букв, разные для разных подмножеств
+	sum := sha256.Sum256(data)
+	var tag [6]byte
+	for i := range tag {
+		tag[i] = 'A' + sum[i]%26
+	}
+	baseFont := string(tag[:]) + "+" + f.face.name
+
+	type0, cidFont, descriptor, fontFile, toUnicode := w.reserve(), w.reserve(), w.reserve(), w.reserve(), w.reserve()
+	w.stream(fontFile, fmt.Sprintf("/Length1 %d", len(data)), data)
+	w.object(descriptor, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		baseFont, f.face.bbox[0], f.face.bbox[1], f.face.bbox[2], f.face.bbox[3], f.face.ascent, f.face.descent, f.face.capHeight, fontFile))
+
+	var widths strings.Builder
+	for _, gid := range gids {
+		fmt.Fprintf(&widths, "%d [%d] ", gid, f.widths[uint16(gid)])
+	}
+	w.object(cidFont, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /CIDToGIDMap /Identity /W [%s] >>",
+		baseFont, descriptor, strings.TrimSpace(widths.String())))
+	w.stream(toUnicode, "", toUnicodeCMap(f.runes))
+	w.object(type0, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		baseFont, cidFont, toUnicode))
+	return type0, nil
+}
+
+// toUnicodeCMap сопоставляет глифы символам, чтобы текст из PDF можно было искать и копировать
+func toUnicodeCMap(runes map[uint16]rune) []byte {
+	gids := make([]int, 0, len(runes))
+	for gid := range runes {
+		gids = append(gids, int(gid))
+	}
+	sort.Ints(gids)
+
+	var b bytes.Buffer
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	// В одном блоке bfchar допускается не больше 100 записей
+	for start := 0; start < len(gids); start += 100 {
+		end := min(start+100, len(gids))
+		fmt.Fprintf(&b, "%d beginbfchar\n", end-start)
+		for _, gid := range gids[start:end] {
+			fmt.Fprintf(&b, "<%04X> <", gid)
+			for _, unit := range utf16.Encode([]rune{runes[uint16(gid)]}) {
+				fmt.Fprintf(&b, "%04X", unit)
+			}
+			b.WriteString(">\n")
+		}
+		b.WriteString("endbfchar\n")
+	}
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return b.Bytes()
+}
+
+// textString строка PDF в UTF-16BE с меткой порядка байтов для полей документа
+func textString(s string) string {
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, unit := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", unit)
+	}
+	b.WriteString(">")
+	return b.String()
+}
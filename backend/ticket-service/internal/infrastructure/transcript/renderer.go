@@ -0,0 +1,289 @@
+// Package transcript формирует PDF с перепиской по тикету средствами Go без внешних программ.
+// Текст набирается встроенными шрифтами DejaVu, в документ встраивается только использованное
+// подмножество глифов
+package transcript
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"ticket-service/internal/domain/models"
+	"ticket-service/internal/domain/services"
+)
+
+// Размеры страницы A4 и полей в пунктах
+const (
+	pageWidth    = 595.28
+	pageHeight   = 841.89
+	margin       = 50.0
+	footerHeight = 44.0
+	labelWidth   = 160.0
+)
+
+const (
+	titleSize   = 15.0
+	headingSize = 12.0
+	bodySize    = 10.0
+	smallSize   = 8.5
+	footerSize  = 7.0
+	leading     = 1.35
+)
+
+const timeLayout = "02.01.2006 15:04 UTC"
+
+var statusLabels = map[models.TicketStatus]string{
+	models.TicketStatusNew:        "Новый",
+	models.TicketStatusInProgress: "В работе",
+	models.TicketStatusWaiting:    "Ожидает ответа заявителя",
+	models.TicketStatusClosed:     "Закрыт",
+	models.TicketStatusSpam:       "Задержан антиспамом",
+}
+
+type renderer struct {
+	regular *fontFace
+	bold    *fontFace
+}
+
+func NewRenderer() (services.ITranscriptRenderer, error) {
+	regular, err := parseFace("DejaVuSans", regularTTF)
+	if err != nil {
+		return nil, err
+	}
+	bold, err := parseFace("DejaVuSans-Bold", boldTTF)
+	if err != nil {
+		return nil, err
+	}
+	return &renderer{regular: regular, bold: bold}, nil
+}
+
+func (r *renderer) Render(transcript *models.Transcript) ([]byte, error) {
+	return r.layout(transcript).bytes()
+}
+
+// layout раскладывает переписку по страницам документа
+func (r *renderer) layout(transcript *models.Transcript) *document {
+	ticket := transcript.Ticket
+	l := &layout{
+		doc:     &document{title: fmt.Sprintf("Обращение №%d", ticket.ID), created: transcript.GeneratedAt},
+		regular: newFontUse(r.regular),
+		bold:    newFontUse(r.bold),
+	}
+	l.newPage()
+
+	l.paragraph(l.bold, titleSize, 0, fmt.Sprintf("Переписка по обращению №%d", ticket.ID))
+	l.space(6)
+
+	l.field("Тема", ticket.Subject)
+	l.field("Категория", string(ticket.Category))
+	l.field("Статус", statusLabel(ticket.Status))
+	l.field("Заявитель", ticket.FullName)
+	l.field("Email", ticket.Email)
+	if ticket.Phone != nil {
+		l.field("Телефон", *ticket.Phone)
+	}
+	if ticket.TelegramID != nil {
+		l.field("Telegram", *ticket.TelegramID)
+	}
+	l.field("Создан", formatTime(ticket.CreatedAt))
+	if ticket.ClosedAt != nil {
+		l.field("Закрыт", formatTime(*ticket.ClosedAt))
+	}
+	if ticket.AssignedTo != nil {
+		l.field("Исполнитель", ticket.AssignedTo.String())
+	}
+	if ticket.ReopenCount > 0 {
+		l.field("Повторных открытий", strconv.Itoa(ticket.ReopenCount))
+	}
+	if ticket.PreviousTicketID != nil {
+		l.field("Предыдущее обращение", fmt.Sprintf("№%d", *ticket.PreviousTicketID))
+	}
+	if ticket.AnonymizedAt != nil {
+		l.field("Обезличен", formatTime(*ticket.AnonymizedAt))
+	}
+
+	l.heading("История статусов")
+	if len(transcript.History) == 0 {
+		l.paragraph(l.regular, bodySize, 0, "Статус не менялся")
+	}
+	for _, entry := range transcript.History {
+		line := formatTime(entry.CreatedAt) + " — " + statusLabel(entry.Status)
+		if entry.AdminID != nil {
+			line += " (администратор " + entry.AdminID.String() + ")"
+		}
+		l.paragraph(l.regular, bodySize, 0, line)
+		if entry.Comment != nil && *entry.Comment != "" {
+			l.paragraph(l.regular, smallSize, 12, *entry.Comment)
+		}
+		l.space(2)
+	}
+
+	l.heading("Переписка")
+	l.message(fmt.Sprintf("Обращение заявителя · %s", formatTime(ticket.CreatedAt)), ticket.Question)
+	for _, response := range transcript.Responses {
+		header := fmt.Sprintf("Ответ №%d · %s · администратор %s", response.ID, formatTime(response.CreatedAt), response.AdminID)
+		if response.UpdatedAt != nil {
+			header += " · изменен " + formatTime(*response.UpdatedAt)
+		}
+		l.message(header, response.Message)
+	}
+
+	l.heading("Вложения")
+	if len(transcript.Attachments) == 0 {
+		l.paragraph(l.regular, bodySize, 0, "Вложений нет")
+	}
+	for _, attachment := range transcript.Attachments {
+		owner := "к обращению"
+		if attachment.ResponseID != nil {
+			owner = fmt.Sprintf("к ответу №%d", *attachment.ResponseID)
+		}
+		name := attachment.Name
+		if name == "" {
+			name = "Файл"
+		}
+		l.paragraph(l.regular, bodySize, 0, name+" ("+owner+")")
+		hash := "SHA-256: " + attachment.SHA256
+		if attachment.SHA256 == "" {
+			hash = "Файл отсутствует в хранилище"
+		}
+		l.paragraph(l.regular, smallSize, 12, hash)
+		l.space(2)
+	}
+
+	l.footers(ticket.ID, transcript)
+	return l.doc
+}
+
+// layout раскладывает текст по страницам сверху вниз
+type layout struct {
+	doc     *document
+	regular *fontUse
+	bold    *fontUse
+	page    *page
+	y       float64
+}
+
+func (l *layout) newPage() {
+	l.page = l.doc.addPage()
+	l.y = pageHeight - margin
+}
+
+// ensure начинает новую страницу, если до нижнего колонтитула осталось меньше height
+func (l *layout) ensure(height float64) {
+	if l.y-height < margin+footerHeight {
+		l.newPage()
+	}
+}
+
+func (l *layout) space(height float64) {
+	l.y -= height
+}
+
+func (l *layout) heading(text string) {
+	l.space(10)
+	// Заголовок не остается внизу страницы без следующей за ним строки
+	l.ensure(headingSize*leading + bodySize*leading*2)
+	l.paragraph(l.bold, headingSize, 0, text)
+	l.page.line(margin, pageWidth-margin, l.y+headingSize*0.25, 0.5, 0.6)
+	l.space(4)
+}
+
+// paragraph выводит текст с переносом по ширине страницы за вычетом отступа indent
+func (l *layout) paragraph(f *fontUse, size, indent float64, text string) {
+	for _, line := range wrap(f, size, pageWidth-2*margin-indent, text) {
+		l.ensure(size * leading)
+		l.y -= size * leading
+		l.page.text(f, size, margin+indent, l.y+size*(leading-1), 0, line)
+	}
+}
+
+// field выводит строку метаданных: подпись слева, значение с переносом справа
+func (l *layout) field(label, value string) {
+	lines := wrap(l.regular, bodySize, pageWidth-2*margin-labelWidth, value)
+	for i, line := range lines {
+		l.ensure(bodySize * leading)
+		l.y -= bodySize * leading
+		baseline := l.y + bodySize*(leading-1)
+		if i == 0 {
+			l.page.text(l.bold, bodySize, margin, baseline, 0.3, label)
+		}
+		l.page.text(l.regular, bodySize, margin+labelWidth, baseline, 0, line)
+	}
+}
+
+func (l *layout) message(header, text string) {
+	l.space(4)
+	l.ensure(smallSize*leading + bodySize*leading)
+	l.paragraph(l.bold, smallSize, 0, header)
+	l.paragraph(l.regular, bodySize, 0, text)
+	l.space(4)
+}
+
+// footers выводит на каждой странице нижний колонтитул с контрольной суммой документа и номером страницы.
+// Колонтитулы пишутся после раскладки, когда известно число страниц
+func (l *layout) footers(ticketID int64, transcript *models.Transcript) {
+	generated := fmt.Sprintf("Обращение №%d · сформировано %s", ticketID, formatTime(transcript.GeneratedAt))
+	digest := "SHA-256: " + transcript.Digest
+	note := "Сверка: запись ticket.transcript_generated с той же суммой в журнале аудита тикета"
+	for i, content := range l.doc.pages {
+		p := &page{content: content, doc: l.doc}
+		p.line(margin, pageWidth-margin, margin+footerHeight-6, 0.5, 0.6)
+		p.text(l.regular, footerSize, margin, margin+footerHeight-16, 0.4, generated)
+		p.text(l.regular, footerSize, margin, margin+footerHeight-25, 0.4, digest)
+		p.text(l.regular, footerSize, margin, margin+footerHeight-34, 0.4, note)
+		number := fmt.Sprintf("Стр. %d из %d", i+1, len(l.doc.pages))
+		p.text(l.regular, footerSize, pageWidth-margin-l.regular.width(number, footerSize), margin+footerHeight-16, 0.4, number)
+	}
+}
+
+// wrap разбивает текст на строки не шире width; слово длиннее строки разрывается посимвольно
+func wrap(f *fontUse, size, width float64, text string) []string {
+	text = strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\t", "    ")
+
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		paragraph = strings.Map(func(r rune) rune {
+			if r < ' ' {
+				return -1
+			}
+			return r
+		}, paragraph)
+
+		current := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if current != "" {
+				candidate = current + " " + word
+			}
+			if f.width(candidate, size) <= width {
+				current = candidate
+				continue
+			}
+			if current != "" {
+				lines = append(lines, current)
+			}
+			current = ""
+			for _, r := range word {
+				if current != "" && f.width(current+string(r), size) > width {
+					lines = append(lines, current)
+					current = ""
+				}
+				current += string(r)
+			}
+		}
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+func statusLabel(status models.TicketStatus) string {
+	if label, ok := statusLabels[status]; ok {
+		return label
+	}
+	return string(status)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
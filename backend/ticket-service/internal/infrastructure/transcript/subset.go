@@ -0,0 +1,246 @@
+package transcript
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// subsetTables таблицы TrueType, которые нужны для встраивания шрифта в PDF (ISO 32000-1, 9.9).
+// Текст записывается номерами глифов, но cmap и OS/2 небольшие и без них шрифт отвергают
+// некоторые просмотрщики; таблицы кернинга и лигатур отбрасываются
+var subsetTables = []string{"OS/2", "cmap", "cvt ", "fpgm", "glyf", "head", "hhea", "hmtx", "loca", "maxp", "prep"}
+
+// Флаги компонента составного глифа
+const (
+	argsAreWords    = 0x0001
+	haveScale       = 0x0008
+	moreComponents  = 0x0020
+	haveXYScale     = 0x0040
+	haveTwoByTwo    = 0x0080
+	checksumMagic   = 0xB1B0AFBA
+	headAdjustment  = 8
+	headLocaFormat  = 50
+	maxpGlyphsCount = 4
+	postHeaderSize  = 32
+)
+
+var errMalformedFont = errors.New("malformed TrueType font")
+
+// subsetFont оставляет в шрифте только глифы glyphs и компоненты составных глифов из них.
+// Номера глифов не меняются, поэтому остальные глифы остаются пустыми, а CIDToGIDMap - Identity
+func subsetFont(data []byte, glyphs map[uint16]bool) ([]byte, error) {
+	tables, err := readTables(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range []string{"glyf", "head", "loca", "maxp"} {
+		if _, ok := tables[tag]; !ok {
+			return nil, fmt.Errorf("%w: no %q table", errMalformedFont, tag)
+		}
+	}
+	if len(tables["head"]) < headLocaFormat+2 || len(tables["maxp"]) < maxpGlyphsCount+2 {
+		return nil, errMalformedFont
+	}
+
+	glyf, loca := tables["glyf"], tables["loca"]
+	longLoca := binary.BigEndian.Uint16(tables["head"][headLocaFormat:]) == 1
+	numGlyphs := int(binary.BigEndian.Uint16(tables["maxp"][maxpGlyphsCount:]))
+
+	glyphData := func(gid int) ([]byte, error) {
+		var start, end int
+		if longLoca {
+			if len(loca) < (gid+2)*4 {
+				return nil, errMalformedFont
+			}
+			start, end = int(binary.BigEndian.Uint32(loca[gid*4:])), int(binary.BigEndian.Uint32(loca[gid*4+4:]))
+		} else {
+			if len(loca) < (gid+2)*2 {
+				return nil, errMalformedFont
+			}
+			start, end = int(binary.BigEndian.Uint16(loca[gid*2:]))*2, int(binary.BigEndian.Uint16(loca[gid*2+2:]))*2
+		}
+		if start > end || end > len(glyf) {
+			return nil, errMalformedFont
+		}
+		return glyf[start:end], nil
+	}
+
+	// Глиф .notdef обязателен, составные глифы тянут за собой компоненты
+	keep := map[int]bool{0: true}
+	queue := []int{0}
+	for gid := range glyphs {
+		if int(gid) < numGlyphs && !keep[int(gid)] {
+			keep[int(gid)] = true
+			queue = append(queue, int(gid))
+		}
+	}
+	for len(queue) > 0 {
+		gid := queue[0]
+		queue = queue[1:]
+		g, err := glyphData(gid)
+		if err != nil {
+			return nil, err
+		}
+		components, err := glyphComponents(g)
+		if err != nil {
+			return nil, err
+		}
+		for _, component := range components {
+			if component < numGlyphs && !keep[component] {
+				keep[component] = true
+				queue = append(queue, component)
+			}
+		}
+	}
+
+	var newGlyf bytes.Buffer
+	newLoca := make([]byte, (numGlyphs+1)*4)
+	for gid := 0; gid < numGlyphs; gid++ {
+		binary.BigEndian.PutUint32(newLoca[gid*4:], uint32(newGlyf.Len()))
+		if !keep[gid] {
+			continue
+		}
+		g, err := glyphData(gid)
+		if err != nil {
+			return nil, err
+		}
+		newGlyf.Write(g)
+		for newGlyf.Len()%4 != 0 {
+			newGlyf.WriteByte(0)
+		}
+	}
+	binary.BigEndian.PutUint32(newLoca[numGlyphs*4:], uint32(newGlyf.Len()))
+
+	head := bytes.Clone(tables["head"])
+	binary.BigEndian.PutUint16(head[headLocaFormat:], 1)
+	binary.BigEndian.PutUint32(head[headAdjustment:], 0)
+
+	out := map[string][]byte{"glyf": newGlyf.Bytes(), "loca": newLoca, "head": head}
+	// Таблица post версии 3 содержит метрики подчеркивания без имен глифов
+	if post := tables["post"]; len(post) >= postHeaderSize {
+		out["post"] = bytes.Clone(post[:postHeaderSize])
+		binary.BigEndian.PutUint32(out["post"], 0x00030000)
+	}
+	for _, tag := range subsetTables {
+		if _, ok := out[tag]; !ok && tables[tag] != nil {
+			out[tag] = tables[tag]
+		}
+	}
+	return writeFont(out), nil
+}
+
+// glyphComponents номера глифов, из которых состоит составной глиф
+func glyphComponents(g []byte) ([]int, error) {
+	if len(g) < 10 || int16(binary.BigEndian.Uint16(g)) >= 0 {
+		return nil, nil
+	}
+
+	var components []int
+	offset := 10
+	for {
+		if len(g) < offset+4 {
+			return nil, errMalformedFont
+		}
+		flags := binary.BigEndian.Uint16(g[offset:])
+		components = append(components, int(binary.BigEndian.Uint16(g[offset+2:])))
+		offset += 4
+
+		if flags&argsAreWords != 0 {
+			offset += 4
+		} else {
+			offset += 2
+		}
+		switch {
+		case flags&haveScale != 0:
+			offset += 2
+		case flags&haveXYScale != 0:
+			offset += 4
+		case flags&haveTwoByTwo != 0:
+			offset += 8
+		}
+		if flags&moreComponents == 0 {
+			return components, nil
+		}
+	}
+}
+
+func readTables(data []byte) (map[string][]byte, error) {
+	if len(data) < 12 {
+		return nil, errMalformedFont
+	}
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	if len(data) < 12+numTables*16 {
+		return nil, errMalformedFont
+	}
+
+	tables := make(map[string][]byte, numTables)
+	for i := 0; i < numTables; i++ {
+		record := data[12+i*16:]
+		offset, length := int(binary.BigEndian.Uint32(record[8:])), int(binary.BigEndian.Uint32(record[12:]))
+		if offset+length > len(data) {
+			return nil, errMalformedFont
+		}
+		tables[string(record[:4])] = data[offset : offset+length]
+	}
+	return tables, nil
+}
+
+// writeFont собирает шрифт из таблиц и пересчитывает контрольные суммы
+func writeFont(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	entrySelector := 0
+	for 1<<(entrySelector+1) <= len(tags) {
+		entrySelector++
+	}
+	searchRange := (1 << entrySelector) * 16
+
+	var out bytes.Buffer
+	header := make([]byte, 12+len(tags)*16)
+	binary.BigEndian.PutUint32(header, 0x00010000)
+	binary.BigEndian.PutUint16(header[4:], uint16(len(tags)))
+	binary.BigEndian.PutUint16(header[6:], uint16(searchRange))
+	binary.BigEndian.PutUint16(header[8:], uint16(entrySelector))
+	binary.BigEndian.PutUint16(header[10:], uint16(len(tags)*16-searchRange))
+	out.Write(header)
+
+	headOffset := 0
+	for i, tag := range tags {
+		table := tables[tag]
+		if tag == "head" {
+			headOffset = out.Len()
+		}
+		record := header[12+i*16:]
+		copy(record, tag)
+		binary.BigEndian.PutUint32(record[4:], checksum(table))
+		binary.BigEndian.PutUint32(record[8:], uint32(out.Len()))
+		binary.BigEndian.PutUint32(record[12:], uint32(len(table)))
+
+		out.Write(table)
+		for out.Len()%4 != 0 {
+			out.WriteByte(0)
+		}
+	}
+
+	font := out.Bytes()
+	copy(font, header)
+	binary.BigEndian.PutUint32(font[headOffset+headAdjustment:], checksumMagic-checksum(font))
+	return font
+}
+
+func checksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"ticket-service/internal/infrastructure/metrics"
+	"ticket-service/internal/logger"
+)
+
+// Locker распределенная блокировка, через которую реплики выбирают ведущую
+type Locker interface {
+	Acquire(ctx context.Context) (bool, error)
+	Release(ctx context.Context) error
+}
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+}
+
+// Scheduler запускает периодические задачи только на реплике, удерживающей блокировку
+type Scheduler struct {
+	lock          Locker
+	renewInterval time.Duration
+	jobs          []job
+	leader        atomic.Bool
+}
+
+// NewScheduler создает планировщик; renewInterval должен быть заметно меньше TTL блокировки
+func NewScheduler(lock Locker, renewInterval time.Duration) *Scheduler {
+	return &Scheduler{
+		lock:          lock,
+		renewInterval: renewInterval,
+	}
+}
+
+// AddJob регистрирует задачу, выполняемую раз в interval
+func (s *Scheduler) AddJob(name string, interval time.Duration, run func(ctx context.Context) error) {
+	s.jobs = append(s.jobs, job{name: name, interval: interval, run: run})
+}
+
+// Start запускает выборы ведущего и задачи; всё останавливается при отмене ctx
+func (s *Scheduler) Start(ctx context.Context) {
+	go s.elect(ctx)
+	for _, j := range s.jobs {
+		go s.loop(ctx, j)
+	}
+}
+
+// IsLeader сообщает, выполняет ли эта реплика задачи
+func (s *Scheduler) IsLeader() bool {
+	return s.leader.Load()
+}
+
+func (s *Scheduler) elect(ctx context.Context) {
+	ticker := time.NewTicker(s.renewInterval)
+	defer ticker.Stop()
+
+	for {
+		s.tryAcquire(ctx)
+
+		select {
+		case <-ctx.Done():
+			if s.leader.Load() {
+				releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				if err := s.lock.Release(releaseCtx); err != nil {
+					logger.Error("Failed to release scheduler lock", "error", err)
+				}
+				cancel()
+				s.setLeader(false)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tryAcquire(ctx context.Context) {
+	acquired, err := s.lock.Acquire(ctx)
+	if err != nil {
+		// Без Redis нельзя гарантировать единственность исполнителя
+		logger.Error("Failed to acquire scheduler lock", "error", err)
+		acquired = false
+	}
+
+	if acquired != s.leader.Load() {
+		logger.Info("Scheduler leadership changed", "leader", acquired)
+	}
+	s.setLeader(acquired)
+}
+
+func (s *Scheduler) setLeader(leader bool) {
+	s.leader.Store(leader)
+	if leader {
+		metrics.SchedulerLeader.Set(1)
+	} else {
+		metrics.SchedulerLeader.Set(0)
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, j job) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !s.leader.Load() {
+				continue
+			}
+
+			start := time.Now()
+			if err := j.run(ctx); err != nil {
+				logger.Error("Scheduled job failed", "job", j.name, "error", err)
+				metrics.SchedulerJobRunsTotal.WithLabelValues(j.name, "error").Inc()
+				continue
+			}
+			metrics.SchedulerJobRunsTotal.WithLabelValues(j.name, "success").Inc()
+			logger.Info("Scheduled job completed", "job", j.name, "duration", time.Since(start))
+		}
+	}
+}
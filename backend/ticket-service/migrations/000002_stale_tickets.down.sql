@@ -0,0 +1,21 @@
+DROP INDEX IF EXISTS idx_tickets_waiting_since;
+DROP INDEX IF EXISTS idx_tickets_category;
+
+ALTER TABLE tickets
+    DROP COLUMN IF EXISTS reminder_sent_at,
+    DROP COLUMN IF EXISTS waiting_since,
+    DROP COLUMN IF EXISTS category;
+
+-- PostgreSQL не умеет удалять значения из enum, поэтому пересоздаем тип
+UPDATE tickets SET status = 'in_progress' WHERE status = 'waiting';
+UPDATE ticket_history SET status = 'in_progress' WHERE status = 'waiting';
+
+ALTER TYPE ticket_status RENAME TO ticket_status_old;
+CREATE TYPE ticket_status AS ENUM ('new', 'in_progress', 'closed');
+
+ALTER TABLE tickets ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE tickets ALTER COLUMN status TYPE ticket_status USING status::text::ticket_status;
+ALTER TABLE tickets ALTER COLUMN status SET DEFAULT 'new';
+ALTER TABLE ticket_history ALTER COLUMN status TYPE ticket_status USING status::text::ticket_status;
+
+DROP TYPE ticket_status_old;
@@ -0,0 +1,9 @@
+ALTER TYPE ticket_status ADD VALUE IF NOT EXISTS 'waiting' BEFORE 'closed';
+
+ALTER TABLE tickets
+    ADD COLUMN category VARCHAR(50) NOT NULL DEFAULT 'general',
+    ADD COLUMN waiting_since TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN reminder_sent_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_tickets_category ON tickets(category);
+CREATE INDEX idx_tickets_waiting_since ON tickets(waiting_since) WHERE waiting_since IS NOT NULL;
@@ -0,0 +1,12 @@
+CREATE TABLE ticket_surveys (
+    id SERIAL PRIMARY KEY,
+    ticket_id INTEGER NOT NULL UNIQUE REFERENCES tickets(id) ON DELETE CASCADE,
+    admin_id INTEGER,
+    rating SMALLINT CHECK (rating BETWEEN 1 AND 5),
+    comment TEXT,
+    sent_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    rated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_ticket_surveys_admin_id ON ticket_surveys(admin_id);
+CREATE INDEX idx_ticket_surveys_rated_at ON ticket_surveys(rated_at);
@@ -0,0 +1,3 @@
+ALTER TABLE tickets ADD COLUMN assigned_to INTEGER;
+
+CREATE INDEX idx_tickets_assigned_to ON tickets(assigned_to);
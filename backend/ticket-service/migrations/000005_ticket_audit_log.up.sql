@@ -0,0 +1,16 @@
+-- Журнал не ссылается на tickets по внешнему ключу, чтобы записи переживали удаление тикета
+CREATE TABLE ticket_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    ticket_id INTEGER NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    actor_id INTEGER,
+    actor_type VARCHAR(20) NOT NULL,
+    ip VARCHAR(45),
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_ticket_audit_log_ticket_id ON ticket_audit_log(ticket_id, created_at);
+CREATE INDEX idx_ticket_audit_log_actor ON ticket_audit_log(actor_type, actor_id, created_at);
+CREATE INDEX idx_ticket_audit_log_created_at ON ticket_audit_log(created_at);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS ticket_response_revisions;
+
+-- Мягко удаленные ответы при откате удаляются окончательно
+DELETE FROM ticket_responses WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE ticket_responses
+    DROP COLUMN IF EXISTS deleted_by,
+    DROP COLUMN IF EXISTS deleted_at,
+    DROP COLUMN IF EXISTS updated_at;
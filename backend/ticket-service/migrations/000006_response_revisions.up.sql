@@ -0,0 +1,15 @@
+ALTER TABLE ticket_responses
+    ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN deleted_by INTEGER;
+
+-- Предыдущие версии текста ответа, сохраняются при каждом редактировании
+CREATE TABLE ticket_response_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    response_id INTEGER NOT NULL REFERENCES ticket_responses(id) ON DELETE CASCADE,
+    message TEXT NOT NULL,
+    edited_by INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_ticket_response_revisions_response_id ON ticket_response_revisions(response_id, created_at);
@@ -0,0 +1,21 @@
+DROP INDEX IF EXISTS idx_tickets_phone_created_at;
+DROP INDEX IF EXISTS idx_tickets_email_created_at;
+
+ALTER TABLE tickets
+    DROP COLUMN IF EXISTS spam_signals,
+    DROP COLUMN IF EXISTS spam_score;
+
+-- PostgreSQL не умеет удалять значения из enum, поэтому пересоздаем тип;
+-- задержанные тикеты при откате удаляются
+DELETE FROM tickets WHERE status = 'spam';
+UPDATE ticket_history SET status = 'new' WHERE status = 'spam';
+
+ALTER TYPE ticket_status RENAME TO ticket_status_old;
+CREATE TYPE ticket_status AS ENUM ('new', 'in_progress', 'waiting', 'closed');
+
+ALTER TABLE tickets ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE tickets ALTER COLUMN status TYPE ticket_status USING status::text::ticket_status;
+ALTER TABLE tickets ALTER COLUMN status SET DEFAULT 'new';
+ALTER TABLE ticket_history ALTER COLUMN status TYPE ticket_status USING status::text::ticket_status;
+
+DROP TYPE ticket_status_old;
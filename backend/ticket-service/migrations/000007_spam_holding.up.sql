@@ -0,0 +1,10 @@
+ALTER TYPE ticket_status ADD VALUE IF NOT EXISTS 'spam';
+
+-- Сигналы антиспама сохраняются у тикета, чтобы по ним можно было подбирать пороги
+ALTER TABLE tickets
+    ADD COLUMN spam_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN spam_signals JSONB;
+
+-- Квоты на подачу считаются по контактам за последнее окно
+CREATE INDEX idx_tickets_email_created_at ON tickets(lower(email), created_at);
+CREATE INDEX idx_tickets_phone_created_at ON tickets(phone, created_at) WHERE phone IS NOT NULL;
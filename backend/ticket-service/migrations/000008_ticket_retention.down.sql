@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_tickets_retention;
+
+ALTER TABLE tickets
+    DROP COLUMN IF EXISTS anonymized_at,
+    DROP COLUMN IF EXISTS closed_at;
@@ -0,0 +1,9 @@
+ALTER TABLE tickets
+    ADD COLUMN closed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN anonymized_at TIMESTAMP WITH TIME ZONE;
+
+-- Для уже закрытых тикетов момент закрытия берем из последнего обновления
+UPDATE tickets SET closed_at = updated_at WHERE status = 'closed';
+
+CREATE INDEX idx_tickets_retention ON tickets(category, closed_at)
+    WHERE status = 'closed' AND anonymized_at IS NULL;
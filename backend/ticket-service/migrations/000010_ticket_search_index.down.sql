@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tickets_question_trgm;
+DROP INDEX IF EXISTS idx_tickets_subject_trgm;
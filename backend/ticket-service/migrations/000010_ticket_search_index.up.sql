@@ -0,0 +1,5 @@
+-- Триграммные индексы ускоряют поиск тикетов по ILIKE; перестраиваются командой reindex
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_tickets_subject_trgm ON tickets USING GIN (subject gin_trgm_ops);
+CREATE INDEX idx_tickets_question_trgm ON tickets USING GIN (question gin_trgm_ops);
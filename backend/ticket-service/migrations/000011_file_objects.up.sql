@@ -0,0 +1,13 @@
+-- Объекты вложений хранятся по sha256 содержимого; одинаковые файлы хранятся один раз.
+-- ref_count - число тикетов и ответов, ссылающихся на объект; объекты без ссылок
+-- удаляет сборщик мусора по истечении released_at + STORAGE_GC_GRACE
+CREATE TABLE file_objects (
+    key VARCHAR(255) PRIMARY KEY,
+    sha256 CHAR(64) NOT NULL UNIQUE,
+    size BIGINT NOT NULL,
+    ref_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    released_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_file_objects_unreferenced ON file_objects(released_at) WHERE ref_count = 0;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_file_objects_preview_pending;
+ALTER TABLE file_objects DROP COLUMN IF EXISTS preview_status;
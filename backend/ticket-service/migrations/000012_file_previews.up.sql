@@ -0,0 +1,4 @@
+-- Превью вложений строит фоновая задача; существующие объекты тоже получат превью
+ALTER TABLE file_objects ADD COLUMN preview_status VARCHAR(20) NOT NULL DEFAULT 'pending';
+
+CREATE INDEX idx_file_objects_preview_pending ON file_objects(created_at) WHERE preview_status = 'pending';
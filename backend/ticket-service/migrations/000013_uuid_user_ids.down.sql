@@ -0,0 +1,24 @@
+-- Обратно переводятся только UUID, полученные из числовых ID; остальные не имеют числового
+-- представления и сбрасываются (в NOT NULL колонках в 0)
+CREATE OR REPLACE FUNCTION pg_temp.legacy_user_id(id UUID) RETURNS INTEGER AS $$
+    SELECT CASE WHEN id::text LIKE '00000000-0000-0000-0000-%' THEN right(id::text, 12)::integer END
+$$ LANGUAGE SQL IMMUTABLE;
+
+ALTER TABLE webhook_subscriptions ALTER COLUMN created_by TYPE INTEGER USING pg_temp.legacy_user_id(created_by);
+
+ALTER TABLE ticket_audit_log ALTER COLUMN actor_id TYPE INTEGER USING pg_temp.legacy_user_id(actor_id);
+
+ALTER TABLE ticket_surveys ALTER COLUMN admin_id TYPE INTEGER USING pg_temp.legacy_user_id(admin_id);
+
+ALTER TABLE ticket_response_revisions
+    ALTER COLUMN edited_by TYPE INTEGER USING COALESCE(pg_temp.legacy_user_id(edited_by), 0);
+
+ALTER TABLE ticket_responses
+    ALTER COLUMN admin_id TYPE INTEGER USING COALESCE(pg_temp.legacy_user_id(admin_id), 0),
+    ALTER COLUMN deleted_by TYPE INTEGER USING pg_temp.legacy_user_id(deleted_by);
+
+ALTER TABLE ticket_history ALTER COLUMN admin_id TYPE INTEGER USING pg_temp.legacy_user_id(admin_id);
+
+ALTER TABLE tickets
+    ALTER COLUMN user_id TYPE INTEGER USING COALESCE(pg_temp.legacy_user_id(user_id), 0),
+    ALTER COLUMN assigned_to TYPE INTEGER USING pg_temp.legacy_user_id(assigned_to);
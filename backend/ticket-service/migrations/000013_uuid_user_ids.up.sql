@@ -0,0 +1,24 @@
+-- Пользователи и администраторы идентифицируются UUID из private-service. Прежние числовые ID
+-- переносятся детерминированно (N -> 00000000-0000-0000-0000-00000000000N), гостевой 0 становится
+-- нулевым UUID, поэтому гостевые тикеты продолжают работать
+CREATE OR REPLACE FUNCTION pg_temp.legacy_user_uuid(id INTEGER) RETURNS UUID AS $$
+    SELECT ('00000000-0000-0000-0000-' || lpad(id::text, 12, '0'))::uuid
+$$ LANGUAGE SQL IMMUTABLE;
+
+ALTER TABLE tickets
+    ALTER COLUMN user_id TYPE UUID USING pg_temp.legacy_user_uuid(user_id),
+    ALTER COLUMN assigned_to TYPE UUID USING pg_temp.legacy_user_uuid(assigned_to);
+
+ALTER TABLE ticket_history ALTER COLUMN admin_id TYPE UUID USING pg_temp.legacy_user_uuid(admin_id);
+
+ALTER TABLE ticket_responses
+    ALTER COLUMN admin_id TYPE UUID USING pg_temp.legacy_user_uuid(admin_id),
+    ALTER COLUMN deleted_by TYPE UUID USING pg_temp.legacy_user_uuid(deleted_by);
+
+ALTER TABLE ticket_response_revisions ALTER COLUMN edited_by TYPE UUID USING pg_temp.legacy_user_uuid(edited_by);
+
+ALTER TABLE ticket_surveys ALTER COLUMN admin_id TYPE UUID USING pg_temp.legacy_user_uuid(admin_id);
+
+ALTER TABLE ticket_audit_log ALTER COLUMN actor_id TYPE UUID USING pg_temp.legacy_user_uuid(actor_id);
+
+ALTER TABLE webhook_subscriptions ALTER COLUMN created_by TYPE UUID USING pg_temp.legacy_user_uuid(created_by);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_tickets_previous_ticket_id;
+ALTER TABLE tickets
+    DROP COLUMN IF EXISTS previous_ticket_id,
+    DROP COLUMN IF EXISTS reopen_count;
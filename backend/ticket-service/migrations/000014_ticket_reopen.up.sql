@@ -0,0 +1,6 @@
+-- Повторные открытия тикетов заявителями и обращения, созданные вместо открытия после истечения срока
+ALTER TABLE tickets
+    ADD COLUMN reopen_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN previous_ticket_id INTEGER REFERENCES tickets(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_tickets_previous_ticket_id ON tickets(previous_ticket_id) WHERE previous_ticket_id IS NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE tickets DROP COLUMN IF EXISTS fields;
+
+DROP TABLE IF EXISTS category_intake_schemas;
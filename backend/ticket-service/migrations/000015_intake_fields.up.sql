@@ -0,0 +1,9 @@
+-- Дополнительные поля заявки: схема полей категории задается администратором, значения хранятся в тикете
+CREATE TABLE category_intake_schemas (
+    category VARCHAR(50) PRIMARY KEY,
+    schema JSONB NOT NULL,
+    updated_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE tickets ADD COLUMN fields JSONB NOT NULL DEFAULT '{}';
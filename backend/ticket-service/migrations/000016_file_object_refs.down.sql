@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_ticket_responses_object_key;
+DROP INDEX IF EXISTS idx_tickets_object_key;
+ALTER TABLE ticket_responses DROP COLUMN IF EXISTS object_key;
+ALTER TABLE tickets DROP COLUMN IF EXISTS object_key;
@@ -0,0 +1,12 @@
+-- Ключ объекта вложения хранится отдельно от file_url, чтобы сборщик мусора пересчитывал ссылки
+-- соединением по индексу, а не поиском подстроки. Столбец вычисляется из file_url, поэтому
+-- заполняется для уже сохраненных вложений и очищается вместе с ним
+ALTER TABLE tickets
+    ADD COLUMN object_key VARCHAR(255)
+    GENERATED ALWAYS AS (substring(file_url FROM 'objects/[0-9a-f]{2}/[0-9a-f]{64}(?![0-9a-f])')) STORED;
+ALTER TABLE ticket_responses
+    ADD COLUMN object_key VARCHAR(255)
+    GENERATED ALWAYS AS (substring(file_url FROM 'objects/[0-9a-f]{2}/[0-9a-f]{64}(?![0-9a-f])')) STORED;
+
+CREATE INDEX idx_tickets_object_key ON tickets(object_key) WHERE object_key IS NOT NULL;
+CREATE INDEX idx_ticket_responses_object_key ON ticket_responses(object_key) WHERE object_key IS NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE ticket_surveys DROP COLUMN IF EXISTS nonce;
@@ -0,0 +1,3 @@
+-- Ссылка на опрос привязана к конкретной отправке: nonce меняется при каждой отправке опроса,
+-- и ссылки из прежних писем перестают действовать. У опросов, отправленных до миграции, nonce пустой
+ALTER TABLE ticket_surveys ADD COLUMN nonce VARCHAR(64);
@@ -0,0 +1,9 @@
+// Package migrations содержит SQL-миграции схемы, встроенные в бинарный файл сервиса
+package migrations
+
+import "embed"
+
+// FS файлы миграций в формате golang-migrate: <версия>_<название>.<up|down>.sql
+//
+//go:embed *.sql
+var FS embed.FS